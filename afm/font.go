@@ -16,6 +16,12 @@ type Font struct {
 	FontInfo
 	serif       bool
 	CharMetrics CharMetrics
+	kernPairs   map[kernPair]int
+}
+
+// kernPair is a pair of adjacent characters.
+type kernPair struct {
+	left, right rune
 }
 
 // 3,956,700 ns/3.957 ms
@@ -44,6 +50,7 @@ var (
 	reSerif          = regexp.MustCompile("^Serif[ ]+([A-Za-z]+)")
 	reAfmExt         = regexp.MustCompile(`\.afm$`)
 	reCharMetrics    = regexp.MustCompile("^C[ ]+(-?[0-9]+)[ ]*;[ ]*WX[ ]+([0-9]+)[ ]*;[ ]*N[ ]+([A-Za-z0-9]+)")
+	reKernPair       = regexp.MustCompile(`^KPX[ ]+([A-Za-z0-9._]+)[ ]+([A-Za-z0-9._]+)[ ]+(-?[0-9]+)`)
 )
 
 func (font *Font) init(file *bufio.Reader) (err error) {
@@ -90,6 +97,33 @@ func (font *Font) init(file *bufio.Reader) (err error) {
 		line, err = file.ReadSlice('\n')
 	}
 	sort.Sort(font.CharMetrics)
+	if err == nil {
+		err = font.initKernPairs(file)
+	}
+	return
+}
+
+// initKernPairs reads the KPX pairs of the kerning data following the
+// character metrics. Pairs naming glyphs without codepoints are skipped.
+func (font *Font) initKernPairs(file *bufio.Reader) (err error) {
+	var line []byte
+	line, err = file.ReadSlice('\n')
+	for err == nil {
+		if m := reKernPair.FindSubmatch(line); m != nil {
+			left, lok := GlyphCodepoints[string(m[1])]
+			right, rok := GlyphCodepoints[string(m[2])]
+			if lok && rok {
+				if font.kernPairs == nil {
+					font.kernPairs = make(map[kernPair]int)
+				}
+				font.kernPairs[kernPair{left, right}], _ = strconv.Atoi(string(m[3]))
+			}
+		}
+		line, err = file.ReadSlice('\n')
+	}
+	if err == io.EOF {
+		return nil
+	}
 	return
 }
 
@@ -122,6 +156,12 @@ func (font *Font) AdvanceWidth(codepoint rune) (width int, err bool) {
 	return 0, true
 }
 
+// PairKerning returns the adjustment to the advance width of left when it is
+// followed by right, in 1/1000 em; negative values tighten the pair.
+func (font *Font) PairKerning(left, right rune) int {
+	return font.kernPairs[kernPair{left, right}]
+}
+
 const (
 	flagFixedPitch  = 1 - 1
 	flagSerif       = 2 - 1
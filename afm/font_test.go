@@ -32,6 +32,9 @@ func TestLoadFont_Helvetica(t *testing.T) {
 	// expectI(t, "l-cedilla", 1139, f.AdvanceWidth(0x013B))
 	// expectI(t, "afii57414", 1307, f.AdvanceWidth(0x0626))
 	expectI(t, "trademark", 1000, aw(f.AdvanceWidth(0x2122)))
+	expectI(t, "A V kerning", -70, f.PairKerning('A', 'V'))
+	expectI(t, "T o kerning", -120, f.PairKerning('T', 'o'))
+	expectI(t, "o T kerning", 0, f.PairKerning('o', 'T'))
 	// expectI(t, "reversed-e", 1366, f.AdvanceWidth(0x018E))
 	// expectI(t, "t-with-comma", 1251, f.AdvanceWidth(0x021A))
 	//
@@ -0,0 +1,351 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+// Package bidi implements the Unicode Bidirectional Algorithm (UAX #9),
+// resolving the embedding levels of paragraphs that mix left-to-right and
+// right-to-left text and reordering their lines for display.
+//
+// Bidi classes come from golang.org/x/text/unicode/bidi. Paragraphs are
+// resolved whole; lines, such as those from rich_text's WrapToWidth, are
+// then reordered one at a time with LineLevels and VisualOrder.
+package bidi
+
+import (
+	xbidi "golang.org/x/text/unicode/bidi"
+)
+
+// Direction is the direction of a paragraph.
+type Direction int
+
+const (
+	// Auto takes the direction of the first strong character, or else
+	// left to right (rules P2 and P3).
+	Auto Direction = iota
+	LeftToRight
+	RightToLeft
+)
+
+var directionStrings = []string{"auto", "ltr", "rtl"}
+
+func (d Direction) String() string {
+	if int(d) < len(directionStrings) {
+		return directionStrings[d]
+	}
+	return "unknown"
+}
+
+// ParseDirection returns the direction named s: "ltr", "rtl" or "auto".
+// Other names are Auto.
+func ParseDirection(s string) Direction {
+	switch s {
+	case "ltr":
+		return LeftToRight
+	case "rtl":
+		return RightToLeft
+	}
+	return Auto
+}
+
+// Level is an embedding level: even for left-to-right text and odd for
+// right-to-left text.
+type Level uint8
+
+// IsRightToLeft reports whether text at the level runs right to left.
+func (l Level) IsRightToLeft() bool {
+	return l&1 != 0
+}
+
+// maxDepth is the deepest explicit embedding level (BD2).
+const maxDepth = 125
+
+type class = xbidi.Class
+
+const (
+	classL   = xbidi.L
+	classR   = xbidi.R
+	classEN  = xbidi.EN
+	classES  = xbidi.ES
+	classET  = xbidi.ET
+	classAN  = xbidi.AN
+	classCS  = xbidi.CS
+	classB   = xbidi.B
+	classS   = xbidi.S
+	classWS  = xbidi.WS
+	classON  = xbidi.ON
+	classBN  = xbidi.BN
+	classNSM = xbidi.NSM
+	classAL  = xbidi.AL
+	classLRO = xbidi.LRO
+	classRLO = xbidi.RLO
+	classLRE = xbidi.LRE
+	classRLE = xbidi.RLE
+	classPDF = xbidi.PDF
+	classLRI = xbidi.LRI
+	classRLI = xbidi.RLI
+	classFSI = xbidi.FSI
+	classPDI = xbidi.PDI
+)
+
+func classOf(r rune) class {
+	p, _ := xbidi.LookupRune(r)
+	return p.Class()
+}
+
+func isIsolateInitiator(c class) bool {
+	return c == classLRI || c == classRLI || c == classFSI
+}
+
+// isRemovedByX9 reports whether characters of class c take no part in the
+// resolution of weak and neutral types.
+func isRemovedByX9(c class) bool {
+	switch c {
+	case classLRE, classRLE, classLRO, classRLO, classPDF, classBN:
+		return true
+	}
+	return false
+}
+
+// Paragraph is a paragraph of text with its embedding levels resolved.
+type Paragraph struct {
+	text        []rune
+	initial     []class // bidi classes, as looked up
+	classes     []class // bidi classes, as resolved
+	levels      []Level
+	matchingPDI []int // index of the PDI matching each isolate initiator, or -1
+	base        Level
+}
+
+// NewParagraph resolves the embedding levels of text, a single paragraph,
+// in direction dir.
+func NewParagraph(text []rune, dir Direction) *Paragraph {
+	n := len(text)
+	p := &Paragraph{
+		text:    text,
+		initial: make([]class, n),
+		classes: make([]class, n),
+		levels:  make([]Level, n),
+	}
+	for i, r := range text {
+		p.initial[i] = classOf(r)
+	}
+	copy(p.classes, p.initial)
+	p.matchIsolates()
+	switch dir {
+	case LeftToRight:
+		p.base = 0
+	case RightToLeft:
+		p.base = 1
+	default:
+		if c := p.firstStrong(0, n); c == classR || c == classAL {
+			p.base = 1
+		}
+	}
+	p.resolveExplicit()
+	for _, seq := range p.isolatingRunSequences() {
+		seq.resolveWeak()
+		seq.resolveBrackets()
+		seq.resolveNeutral()
+		seq.resolveImplicit()
+	}
+	p.assignRemovedLevels()
+	return p
+}
+
+// BaseDirection returns the direction of text from its first strong
+// character, or LeftToRight if it has none.
+func BaseDirection(text string) Direction {
+	depth := 0
+	for _, r := range text {
+		switch c := classOf(r); c {
+		case classL, classR, classAL:
+			if depth > 0 {
+				continue
+			}
+			if c == classL {
+				return LeftToRight
+			}
+			return RightToLeft
+		case classLRI, classRLI, classFSI:
+			depth++
+		case classPDI:
+			if depth > 0 {
+				depth--
+			}
+		case classB:
+			return LeftToRight
+		}
+	}
+	return LeftToRight
+}
+
+// HasRightToLeft reports whether text has right-to-left characters, Arabic
+// numbers or explicit right-to-left formatting: whether it could display
+// other than as it is in a left-to-right paragraph.
+func HasRightToLeft(text string) bool {
+	for _, r := range text {
+		switch classOf(r) {
+		case classR, classAL, classAN, classRLE, classRLO, classRLI:
+			return true
+		}
+	}
+	return false
+}
+
+// Direction returns the resolved direction of the paragraph.
+func (p *Paragraph) Direction() Direction {
+	if p.base.IsRightToLeft() {
+		return RightToLeft
+	}
+	return LeftToRight
+}
+
+// Levels returns the embedding level of each character of the paragraph,
+// before any line is reordered.
+func (p *Paragraph) Levels() []Level {
+	return p.levels
+}
+
+// matchIsolates pairs isolate initiators with their PDIs (BD9).
+func (p *Paragraph) matchIsolates() {
+	p.matchingPDI = make([]int, len(p.initial))
+	var stack []int
+	for i, c := range p.initial {
+		p.matchingPDI[i] = -1
+		switch {
+		case isIsolateInitiator(c):
+			stack = append(stack, i)
+		case c == classPDI && len(stack) > 0:
+			p.matchingPDI[stack[len(stack)-1]] = i
+			stack = stack[:len(stack)-1]
+		case c == classB:
+			stack = stack[:0]
+		}
+	}
+}
+
+// firstStrong returns the class of the first strong character from start
+// to end, skipping isolates, or ON if there is none (P2).
+func (p *Paragraph) firstStrong(start, end int) class {
+	for i := start; i < end; i++ {
+		switch c := p.initial[i]; {
+		case c == classL || c == classR || c == classAL:
+			return c
+		case isIsolateInitiator(c):
+			if p.matchingPDI[i] < 0 {
+				return classON
+			}
+			i = p.matchingPDI[i]
+		case c == classB:
+			return classON
+		}
+	}
+	return classON
+}
+
+type directionalStatus struct {
+	level    Level
+	override class // ON for none, or L or R
+	isolate  bool
+}
+
+// resolveExplicit applies the explicit embeddings, overrides and isolates
+// of rules X1 to X8.
+func (p *Paragraph) resolveExplicit() {
+	stack := []directionalStatus{{p.base, classON, false}}
+	overflowIsolates, overflowEmbeddings, validIsolates := 0, 0, 0
+	for i, c := range p.initial {
+		top := stack[len(stack)-1]
+		switch c {
+		case classRLE, classLRE, classRLO, classLRO:
+			p.levels[i] = top.level
+			level := nextLevel(top.level, c == classRLE || c == classRLO)
+			if level <= maxDepth && overflowIsolates == 0 && overflowEmbeddings == 0 {
+				override := classON
+				if c == classRLO {
+					override = classR
+				} else if c == classLRO {
+					override = classL
+				}
+				stack = append(stack, directionalStatus{level, override, false})
+			} else if overflowIsolates == 0 {
+				overflowEmbeddings++
+			}
+		case classRLI, classLRI, classFSI:
+			p.levels[i] = top.level
+			if top.override != classON {
+				p.classes[i] = top.override
+			}
+			rtl := c == classRLI
+			if c == classFSI {
+				end := p.matchingPDI[i]
+				if end < 0 {
+					end = len(p.initial)
+				}
+				s := p.firstStrong(i+1, end)
+				rtl = s == classR || s == classAL
+			}
+			level := nextLevel(top.level, rtl)
+			if level <= maxDepth && overflowIsolates == 0 && overflowEmbeddings == 0 {
+				validIsolates++
+				stack = append(stack, directionalStatus{level, classON, true})
+			} else {
+				overflowIsolates++
+			}
+		case classPDI:
+			if overflowIsolates > 0 {
+				overflowIsolates--
+			} else if validIsolates > 0 {
+				overflowEmbeddings = 0
+				for !stack[len(stack)-1].isolate {
+					stack = stack[:len(stack)-1]
+				}
+				stack = stack[:len(stack)-1]
+				validIsolates--
+			}
+			top = stack[len(stack)-1]
+			p.levels[i] = top.level
+			if top.override != classON {
+				p.classes[i] = top.override
+			}
+		case classPDF:
+			p.levels[i] = top.level
+			if overflowIsolates > 0 {
+			} else if overflowEmbeddings > 0 {
+				overflowEmbeddings--
+			} else if !top.isolate && len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case classB:
+			p.levels[i] = p.base
+		case classBN:
+			p.levels[i] = top.level
+		default:
+			p.levels[i] = top.level
+			if top.override != classON {
+				p.classes[i] = top.override
+			}
+		}
+	}
+}
+
+// nextLevel returns the least odd level above level, for rtl, or else the
+// least even level above it.
+func nextLevel(level Level, rtl bool) Level {
+	if rtl {
+		return (level + 1) | 1
+	}
+	return (level + 2) &^ 1
+}
+
+// assignRemovedLevels gives the characters removed by rule X9 the level of
+// the character before them, so that they stay with it when reordered.
+func (p *Paragraph) assignRemovedLevels() {
+	level := p.base
+	for i, c := range p.initial {
+		if isRemovedByX9(c) {
+			p.levels[i] = level
+		} else {
+			level = p.levels[i]
+		}
+	}
+}
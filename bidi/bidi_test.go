@@ -0,0 +1,119 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package bidi
+
+import (
+	"strings"
+	"testing"
+)
+
+// visual returns text as displayed from left to right, with the explicit
+// formatting characters removed.
+func visual(text string, dir Direction) string {
+	runes := []rune(text)
+	p := NewParagraph(runes, dir)
+	levels := p.LineLevels(0, len(runes))
+	var sb strings.Builder
+	for _, i := range VisualOrder(levels) {
+		r := runes[i]
+		if r >= 0x2066 && r <= 0x2069 || r >= 0x202A && r <= 0x202E {
+			continue
+		}
+		if levels[i].IsRightToLeft() {
+			r = Mirror(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func TestParagraph_Visual(t *testing.T) {
+	cases := []struct {
+		name, text string
+		dir        Direction
+		expected   string
+	}{
+		{"latin", "abc def", Auto, "abc def"},
+		{"hebrew in ltr", "abc אבג def", LeftToRight, "abc גבא def"},
+		{"latin in rtl", "אבג abc", RightToLeft, "abc גבא"},
+		{"numbers in rtl", "אבג 123 דה", RightToLeft, "הד 123 גבא"},
+		{"arabic numbers", "سعر 12", Auto, "12 رعس"},
+		{"terminator", "א $12", RightToLeft, "$12 א"},
+		{"separators", "א 1,000.50", RightToLeft, "1,000.50 א"},
+		{"mirrored brackets", "א(ב)ג", RightToLeft, "ג(ב)א"},
+		{"bracket pair", "אב(גד)", LeftToRight, "(דג)בא"},
+		{"bracket with latin", "a (b) אג", LeftToRight, "a (b) גא"},
+		{"isolate", "א⁦b c⁩ ב", RightToLeft, "ב b cא"},
+		{"override", "‮abc‬ d", LeftToRight, "cba d"},
+		{"embedding", "a ‫אב 1‬", LeftToRight, "a 1 בא"},
+		{"segment separator", "אב\tגד", LeftToRight, "בא\tדג"},
+		{"auto from first strong", "  123 אבג", Auto, "גבא 123  "},
+	}
+	for _, c := range cases {
+		if actual := visual(c.text, c.dir); actual != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.name, c.expected, actual)
+		}
+	}
+}
+
+func TestParagraph_Levels(t *testing.T) {
+	p := NewParagraph([]rune("ab אב 12"), LeftToRight)
+	expected := []Level{0, 0, 0, 1, 1, 1, 2, 2}
+	for i, level := range p.Levels() {
+		if level != expected[i] {
+			t.Errorf("level %d: expected %d, got %d", i, expected[i], level)
+		}
+	}
+	if p.Direction() != LeftToRight {
+		t.Errorf("expected ltr, got %s", p.Direction())
+	}
+	if d := NewParagraph([]rune("אב ab"), Auto).Direction(); d != RightToLeft {
+		t.Errorf("expected rtl, got %s", d)
+	}
+}
+
+func TestBaseDirection(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected Direction
+	}{
+		{"abc", LeftToRight},
+		{"123 אב", RightToLeft},
+		{"سعر", RightToLeft},
+		{"⁧אב⁩ abc", LeftToRight},
+		{"123", LeftToRight},
+		{"", LeftToRight},
+	}
+	for _, c := range cases {
+		if actual := BaseDirection(c.text); actual != c.expected {
+			t.Errorf("%q: expected %s, got %s", c.text, c.expected, actual)
+		}
+	}
+}
+
+func TestVisualOrder(t *testing.T) {
+	order := VisualOrder([]Level{0, 1, 1, 2, 2, 1, 0})
+	expected := []int{0, 5, 3, 4, 2, 1, 6}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, order)
+		}
+	}
+}
+
+func TestMirror(t *testing.T) {
+	for r, m := range map[rune]rune{'(': ')', ']': '[', '<': '>', '«': '»', '≤': '≥', 'a': 'a', '-': '-'} {
+		if actual := Mirror(r); actual != m {
+			t.Errorf("Mirror(%q): expected %q, got %q", r, m, actual)
+		}
+	}
+}
+
+func TestParseDirection(t *testing.T) {
+	for s, d := range map[string]Direction{"ltr": LeftToRight, "rtl": RightToLeft, "auto": Auto, "": Auto} {
+		if actual := ParseDirection(s); actual != d {
+			t.Errorf("%q: expected %s, got %s", s, d, actual)
+		}
+	}
+}
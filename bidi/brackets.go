@@ -0,0 +1,82 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package bidi
+
+// pairedBrackets maps the opening brackets of BidiBrackets.txt to their
+// closing brackets.
+var pairedBrackets = map[rune]rune{
+	0x0028: 0x0029, 0x005B: 0x005D, 0x007B: 0x007D, 0x0F3A: 0x0F3B,
+	0x0F3C: 0x0F3D, 0x169B: 0x169C, 0x2045: 0x2046, 0x207D: 0x207E,
+	0x208D: 0x208E, 0x2308: 0x2309, 0x230A: 0x230B, 0x2329: 0x232A,
+	0x2768: 0x2769, 0x276A: 0x276B, 0x276C: 0x276D, 0x276E: 0x276F,
+	0x2770: 0x2771, 0x2772: 0x2773, 0x2774: 0x2775, 0x27C5: 0x27C6,
+	0x27E6: 0x27E7, 0x27E8: 0x27E9, 0x27EA: 0x27EB, 0x27EC: 0x27ED,
+	0x27EE: 0x27EF, 0x2983: 0x2984, 0x2985: 0x2986, 0x2987: 0x2988,
+	0x2989: 0x298A, 0x298B: 0x298C, 0x298D: 0x2990, 0x298F: 0x298E,
+	0x2991: 0x2992, 0x2993: 0x2994, 0x2995: 0x2996, 0x2997: 0x2998,
+	0x29D8: 0x29D9, 0x29DA: 0x29DB, 0x29FC: 0x29FD, 0x2E22: 0x2E23,
+	0x2E24: 0x2E25, 0x2E26: 0x2E27, 0x2E28: 0x2E29, 0x2E55: 0x2E56,
+	0x2E57: 0x2E58, 0x2E59: 0x2E5A, 0x2E5B: 0x2E5C, 0x3008: 0x3009,
+	0x300A: 0x300B, 0x300C: 0x300D, 0x300E: 0x300F, 0x3010: 0x3011,
+	0x3014: 0x3015, 0x3016: 0x3017, 0x3018: 0x3019, 0x301A: 0x301B,
+	0xFE59: 0xFE5A, 0xFE5B: 0xFE5C, 0xFE5D: 0xFE5E, 0xFF08: 0xFF09,
+	0xFF3B: 0xFF3D, 0xFF5B: 0xFF5D, 0xFF5F: 0xFF60, 0xFF62: 0xFF63,
+}
+
+var closingBrackets = make(map[rune]bool, len(pairedBrackets))
+
+// mirrors maps characters with the Bidi_Mirrored property to their mirror
+// images: the brackets above and the commonest others of BidiMirroring.txt.
+var mirrors = map[rune]rune{}
+
+var otherMirrors = [][2]rune{
+	{0x003C, 0x003E}, {0x00AB, 0x00BB}, {0x2039, 0x203A}, {0x2208, 0x220B},
+	{0x2209, 0x220C}, {0x220A, 0x220D}, {0x2215, 0x29F5}, {0x223C, 0x223D},
+	{0x2243, 0x22CD}, {0x2252, 0x2253}, {0x2254, 0x2255}, {0x2264, 0x2265},
+	{0x2266, 0x2267}, {0x2268, 0x2269}, {0x226A, 0x226B}, {0x226E, 0x226F},
+	{0x2270, 0x2271}, {0x2272, 0x2273}, {0x2274, 0x2275}, {0x2276, 0x2277},
+	{0x2278, 0x2279}, {0x227A, 0x227B}, {0x227C, 0x227D}, {0x227E, 0x227F},
+	{0x2280, 0x2281}, {0x2282, 0x2283}, {0x2284, 0x2285}, {0x2286, 0x2287},
+	{0x2288, 0x2289}, {0x228A, 0x228B}, {0x228F, 0x2290}, {0x2291, 0x2292},
+	{0x2298, 0x29B8}, {0x22A2, 0x22A3}, {0x22A6, 0x2ADE}, {0x22B0, 0x22B1},
+	{0x22B2, 0x22B3}, {0x22B4, 0x22B5}, {0x22B6, 0x22B7}, {0x22C9, 0x22CA},
+	{0x22CB, 0x22CC}, {0x22D0, 0x22D1}, {0x22D6, 0x22D7}, {0x22D8, 0x22D9},
+	{0x22DA, 0x22DB}, {0x22DC, 0x22DD}, {0x22DE, 0x22DF}, {0x22E0, 0x22E1},
+	{0x22E2, 0x22E3}, {0x22E4, 0x22E5}, {0x22E6, 0x22E7}, {0x22E8, 0x22E9},
+	{0x22EA, 0x22EB}, {0x22EC, 0x22ED}, {0x22F0, 0x22F1}, {0x27C3, 0x27C4},
+	{0x27D5, 0x27D6}, {0x29C0, 0x29C1}, {0x29C4, 0x29C5}, {0x2A79, 0x2A7A},
+	{0x2A7D, 0x2A7E}, {0x2A8B, 0x2A8C}, {0x2AA1, 0x2AA2}, {0x2AF7, 0x2AF8},
+	{0xFE64, 0xFE65}, {0xFF1C, 0xFF1E},
+}
+
+func init() {
+	for open, close := range pairedBrackets {
+		closingBrackets[close] = true
+		mirrors[open], mirrors[close] = close, open
+	}
+	for _, pair := range otherMirrors {
+		mirrors[pair[0]], mirrors[pair[1]] = pair[1], pair[0]
+	}
+}
+
+// canonicalBracket returns the canonical equivalent of the angle brackets
+// U+2329 and U+232A, so that they pair with U+3008 and U+3009, or else r.
+func canonicalBracket(r rune) rune {
+	switch r {
+	case 0x2329:
+		return 0x3008
+	case 0x232A:
+		return 0x3009
+	}
+	return r
+}
+
+// Mirror returns the mirror image of r, for display in right-to-left text
+// (rule L4), or r if it has none.
+func Mirror(r rune) rune {
+	if m, ok := mirrors[r]; ok {
+		return m
+	}
+	return r
+}
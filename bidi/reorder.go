@@ -0,0 +1,60 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package bidi
+
+// LineLevels returns the levels of the characters of the paragraph from
+// start to end, a line, with rule L1 applied: segment and paragraph
+// separators, and the whitespace and isolates before them or at the end of
+// the line, take the paragraph's level.
+func (p *Paragraph) LineLevels(start, end int) []Level {
+	levels := append([]Level(nil), p.levels[start:end]...)
+	trailing := true
+	for i := end - 1; i >= start; i-- {
+		switch c := p.initial[i]; {
+		case c == classS || c == classB:
+			levels[i-start] = p.base
+			trailing = true
+		case trailing && (c == classWS || isIsolateInitiator(c) || c == classPDI || isRemovedByX9(c)):
+			levels[i-start] = p.base
+		default:
+			trailing = false
+		}
+	}
+	return levels
+}
+
+// VisualOrder returns the indexes of the characters of a line, whose levels
+// are levels, in the order in which they are displayed from left to right
+// (rule L2).
+func VisualOrder(levels []Level) []int {
+	order := make([]int, len(levels))
+	for i := range order {
+		order[i] = i
+	}
+	highest, lowestOdd := 0, maxDepth+2
+	for _, level := range levels {
+		highest = max(highest, int(level))
+		if level.IsRightToLeft() {
+			lowestOdd = min(lowestOdd, int(level))
+		}
+	}
+	current := append([]Level(nil), levels...)
+	for level := highest; level >= lowestOdd; level-- {
+		for i := 0; i < len(current); i++ {
+			if int(current[i]) < level {
+				continue
+			}
+			j := i
+			for j < len(current) && int(current[j]) >= level {
+				j++
+			}
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				order[a], order[b] = order[b], order[a]
+				current[a], current[b] = current[b], current[a]
+			}
+			i = j
+		}
+	}
+	return order
+}
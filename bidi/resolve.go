@@ -0,0 +1,340 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package bidi
+
+import "sort"
+
+// isolatingRunSequence is a sequence of level runs whose weak, neutral and
+// implicit types are resolved together (BD13).
+type isolatingRunSequence struct {
+	p        *Paragraph
+	indexes  []int   // indexes of the characters of the sequence
+	types    []class // classes of the characters, as resolved so far
+	level    Level
+	sos, eos class
+}
+
+// isolatingRunSequences splits the paragraph into level runs, ignoring the
+// characters removed by rule X9, and joins those separated by isolates (X10).
+func (p *Paragraph) isolatingRunSequences() []*isolatingRunSequence {
+	var runs [][]int
+	var run []int
+	for i, c := range p.initial {
+		if isRemovedByX9(c) {
+			continue
+		}
+		if len(run) > 0 && p.levels[i] != p.levels[run[0]] {
+			runs = append(runs, run)
+			run = nil
+		}
+		run = append(run, i)
+	}
+	if len(run) > 0 {
+		runs = append(runs, run)
+	}
+
+	runStartingAt := make(map[int]int, len(runs))
+	for k, run := range runs {
+		runStartingAt[run[0]] = k
+	}
+	matched := make(map[int]bool)
+	for _, pdi := range p.matchingPDI {
+		if pdi >= 0 {
+			matched[pdi] = true
+		}
+	}
+
+	var seqs []*isolatingRunSequence
+	for _, run := range runs {
+		if matched[run[0]] {
+			continue // continues the sequence of its isolate initiator
+		}
+		var indexes []int
+		for {
+			indexes = append(indexes, run...)
+			last := run[len(run)-1]
+			if !isIsolateInitiator(p.initial[last]) || p.matchingPDI[last] < 0 {
+				break
+			}
+			next, ok := runStartingAt[p.matchingPDI[last]]
+			if !ok {
+				break
+			}
+			run = runs[next]
+		}
+		seqs = append(seqs, p.newIsolatingRunSequence(indexes))
+	}
+	return seqs
+}
+
+func (p *Paragraph) newIsolatingRunSequence(indexes []int) *isolatingRunSequence {
+	s := &isolatingRunSequence{
+		p:       p,
+		indexes: indexes,
+		types:   make([]class, len(indexes)),
+		level:   p.levels[indexes[0]],
+	}
+	for j, i := range indexes {
+		s.types[j] = p.classes[i]
+	}
+	prev := p.base
+	for i := indexes[0] - 1; i >= 0; i-- {
+		if !isRemovedByX9(p.initial[i]) {
+			prev = p.levels[i]
+			break
+		}
+	}
+	next := p.base
+	if last := indexes[len(indexes)-1]; !isIsolateInitiator(p.initial[last]) {
+		for i := last + 1; i < len(p.initial); i++ {
+			if !isRemovedByX9(p.initial[i]) {
+				next = p.levels[i]
+				break
+			}
+		}
+	}
+	s.sos = directionOfLevel(max(prev, s.level))
+	s.eos = directionOfLevel(max(next, s.level))
+	return s
+}
+
+func directionOfLevel(level Level) class {
+	if level.IsRightToLeft() {
+		return classR
+	}
+	return classL
+}
+
+// strongDirection returns the direction that a resolved type counts as
+// for rules N0 and N1, where numbers count as right to left, or ON.
+func strongDirection(t class) class {
+	switch t {
+	case classL:
+		return classL
+	case classR, classEN, classAN:
+		return classR
+	}
+	return classON
+}
+
+// resolveWeak applies rules W1 to W7.
+func (s *isolatingRunSequence) resolveWeak() {
+	types := s.types
+	n := len(types)
+	// W1: nonspacing marks take the type of the character before them.
+	for j, t := range types {
+		if t != classNSM {
+			continue
+		}
+		switch {
+		case j == 0:
+			types[j] = s.sos
+		case isIsolateInitiator(types[j-1]) || types[j-1] == classPDI:
+			types[j] = classON
+		default:
+			types[j] = types[j-1]
+		}
+	}
+	// W2 and W3: European numbers after Arabic letters are Arabic numbers,
+	// and Arabic letters are right to left.
+	lastStrong := s.sos
+	for j, t := range types {
+		switch t {
+		case classL, classR, classAL:
+			lastStrong = t
+		case classEN:
+			if lastStrong == classAL {
+				types[j] = classAN
+			}
+		}
+	}
+	for j, t := range types {
+		if t == classAL {
+			types[j] = classR
+		}
+	}
+	// W4: single separators between numbers of the same kind join them.
+	for j := 1; j < n-1; j++ {
+		prev, next := types[j-1], types[j+1]
+		switch types[j] {
+		case classES:
+			if prev == classEN && next == classEN {
+				types[j] = classEN
+			}
+		case classCS:
+			if prev == next && (prev == classEN || prev == classAN) {
+				types[j] = prev
+			}
+		}
+	}
+	// W5: terminators next to European numbers join them.
+	for j := 0; j < n; j++ {
+		if types[j] != classET {
+			continue
+		}
+		end := j
+		for end < n && types[end] == classET {
+			end++
+		}
+		if (j > 0 && types[j-1] == classEN) || (end < n && types[end] == classEN) {
+			for k := j; k < end; k++ {
+				types[k] = classEN
+			}
+		}
+		j = end - 1
+	}
+	// W6: other separators and terminators are neutral.
+	for j, t := range types {
+		if t == classES || t == classET || t == classCS {
+			types[j] = classON
+		}
+	}
+	// W7: European numbers in left-to-right text are left to right.
+	lastStrong = s.sos
+	for j, t := range types {
+		switch t {
+		case classL, classR:
+			lastStrong = t
+		case classEN:
+			if lastStrong == classL {
+				types[j] = classL
+			}
+		}
+	}
+}
+
+// resolveBrackets applies rule N0, giving pairs of brackets the direction
+// of the text they enclose or surround.
+func (s *isolatingRunSequence) resolveBrackets() {
+	embedding := directionOfLevel(s.level)
+	for _, pair := range s.bracketPairs() {
+		open, close := pair[0], pair[1]
+		resolved := classON
+		for j := open + 1; j < close && resolved != embedding; j++ {
+			if d := strongDirection(s.types[j]); d != classON {
+				resolved = d
+			}
+		}
+		if resolved != classON && resolved != embedding {
+			context := s.sos
+			for j := open - 1; j >= 0; j-- {
+				if d := strongDirection(s.types[j]); d != classON {
+					context = d
+					break
+				}
+			}
+			if context != resolved {
+				resolved = embedding
+			}
+		}
+		if resolved == classON {
+			continue
+		}
+		for _, j := range pair {
+			s.types[j] = resolved
+			for k := j + 1; k < len(s.types) && s.p.initial[s.indexes[k]] == classNSM; k++ {
+				s.types[k] = resolved
+			}
+		}
+	}
+}
+
+// maxBracketPairingDepth is the size of the stack of BD16.
+const maxBracketPairingDepth = 63
+
+// bracketPairs returns the positions in the sequence of its pairs of
+// brackets, in order of their opening brackets (BD16).
+func (s *isolatingRunSequence) bracketPairs() [][2]int {
+	type opener struct {
+		closer rune
+		j      int
+	}
+	var stack []opener
+	var pairs [][2]int
+	for j, i := range s.indexes {
+		if s.types[j] != classON {
+			continue
+		}
+		r := s.p.text[i]
+		if closer, ok := pairedBrackets[r]; ok {
+			if len(stack) == maxBracketPairingDepth {
+				break
+			}
+			stack = append(stack, opener{canonicalBracket(closer), j})
+			continue
+		}
+		if !closingBrackets[r] {
+			continue
+		}
+		r = canonicalBracket(r)
+		for k := len(stack) - 1; k >= 0; k-- {
+			if stack[k].closer == r {
+				pairs = append(pairs, [2]int{stack[k].j, j})
+				stack = stack[:k]
+				break
+			}
+		}
+	}
+	// Pairs close innermost first; sort them by their opening brackets.
+	sort.Slice(pairs, func(a, b int) bool { return pairs[a][0] < pairs[b][0] })
+	return pairs
+}
+
+func isNeutralOrIsolate(t class) bool {
+	switch t {
+	case classB, classS, classWS, classON, classLRI, classRLI, classFSI, classPDI:
+		return true
+	}
+	return false
+}
+
+// resolveNeutral applies rules N1 and N2: neutrals between text of one
+// direction take that direction, and others the embedding direction.
+func (s *isolatingRunSequence) resolveNeutral() {
+	types := s.types
+	n := len(types)
+	embedding := directionOfLevel(s.level)
+	for j := 0; j < n; {
+		if !isNeutralOrIsolate(types[j]) {
+			j++
+			continue
+		}
+		end := j
+		for end < n && isNeutralOrIsolate(types[end]) {
+			end++
+		}
+		before, after := s.sos, s.eos
+		if j > 0 {
+			before = strongDirection(types[j-1])
+		}
+		if end < n {
+			after = strongDirection(types[end])
+		}
+		resolved := embedding
+		if before == after && before != classON {
+			resolved = before
+		}
+		for k := j; k < end; k++ {
+			types[k] = resolved
+		}
+		j = end
+	}
+}
+
+// resolveImplicit applies rules I1 and I2, raising the levels of characters
+// whose direction differs from that of their level.
+func (s *isolatingRunSequence) resolveImplicit() {
+	for j, i := range s.indexes {
+		level := s.p.levels[i]
+		switch t := s.types[j]; {
+		case !level.IsRightToLeft() && t == classR:
+			level++
+		case !level.IsRightToLeft() && (t == classAN || t == classEN):
+			level += 2
+		case level.IsRightToLeft() && (t == classL || t == classEN || t == classAN):
+			level++
+		}
+		s.p.levels[i] = level
+	}
+}
@@ -16,12 +16,14 @@ import (
 	"net/textproto"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/rowland/leadtype/internal/overlayfs"
 	"github.com/rowland/leadtype/ltml"
 	"github.com/rowland/leadtype/ltml/ltpdf"
+	"github.com/rowland/leadtype/options"
 )
 
 const defaultPollInterval = 500 * time.Millisecond
@@ -45,6 +47,7 @@ type runConfig struct {
 	outputPath   string
 	submitURL    string
 	extraFiles   []string
+	imposition   options.Options
 	watch        bool
 	batch        bool
 	pollInterval time.Duration
@@ -101,6 +104,8 @@ func displayPath(path string) string {
 func main() {
 	var cfg runConfig
 	var extraFiles multiFlag
+	var nUp, sheetSize, gutter string
+	var booklet, cropMarks bool
 
 	cfg.stderr = os.Stderr
 	cfg.pollInterval = defaultPollInterval
@@ -116,6 +121,11 @@ func main() {
 	flag.BoolVar(&cfg.batch, "b", false, "render multiple input files (shorthand)")
 	flag.Var(&extraFiles, "extra", "additional asset `file` (may be repeated)")
 	flag.Var(&extraFiles, "e", "additional asset `file` (shorthand)")
+	flag.StringVar(&nUp, "n-up", "", "impose `AxD` pages, across by down, on each sheet, such as 2x1")
+	flag.BoolVar(&booklet, "booklet", false, "impose pages two to a side in saddle-stitched booklet order")
+	flag.StringVar(&sheetSize, "sheet", "", "imposed sheet `size`, such as letter, tabloid or 17x11in")
+	flag.StringVar(&gutter, "gutter", "", "space between imposed pages, such as 0.25in")
+	flag.BoolVar(&cropMarks, "crop-marks", false, "draw crop marks around imposed pages")
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: render-ltml [flags] <file>\n")
 		fmt.Fprintf(os.Stderr, "   or: render-ltml -b [flags] <file1> <file2> ...\n\nFlags:\n")
@@ -131,6 +141,11 @@ func main() {
 	}
 
 	cfg.extraFiles = []string(extraFiles)
+	cfg.imposition, err = impositionOptions(nUp, booklet, sheetSize, gutter, cropMarks)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "render-ltml: %v\n", err)
+		os.Exit(2)
+	}
 
 	if err := validateArgs(cfg, flag.Args()); err != nil {
 		flag.Usage()
@@ -144,7 +159,42 @@ func main() {
 	}
 }
 
+// impositionOptions returns the options for pdf.DocWriter.SetImposition
+// given by the imposition flags, or nil if the pages are not to be imposed.
+func impositionOptions(nUp string, booklet bool, sheetSize, gutter string, cropMarks bool) (options.Options, error) {
+	if nUp == "" && !booklet {
+		if sheetSize != "" || gutter != "" || cropMarks {
+			return nil, fmt.Errorf("-sheet, -gutter and -crop-marks require -n-up or -booklet")
+		}
+		return nil, nil
+	}
+	if nUp != "" && booklet {
+		return nil, fmt.Errorf("-n-up and -booklet cannot be combined")
+	}
+	imposition := options.Options{"booklet": booklet, "crop_marks": cropMarks}
+	if nUp != "" {
+		across, down, ok := strings.Cut(strings.ToLower(nUp), "x")
+		a, errA := strconv.Atoi(across)
+		d, errD := strconv.Atoi(down)
+		if !ok || errA != nil || errD != nil || a < 1 || d < 1 {
+			return nil, fmt.Errorf("invalid -n-up %q: expected across x down, such as 2x1", nUp)
+		}
+		imposition["pages_across"] = float64(a)
+		imposition["pages_down"] = float64(d)
+	}
+	if sheetSize != "" {
+		imposition["sheet_size"] = sheetSize
+	}
+	if gutter != "" {
+		imposition["gutter"] = gutter
+	}
+	return imposition, nil
+}
+
 func validateArgs(cfg runConfig, inputFiles []string) error {
+	if cfg.imposition != nil && cfg.submitURL != "" {
+		return fmt.Errorf("remote submission does not support imposition")
+	}
 	if cfg.batch {
 		if len(inputFiles) == 0 {
 			return fmt.Errorf("batch mode requires at least one input file")
@@ -376,7 +426,7 @@ func renderJobToFile(cfg runConfig, job renderJob) (err error) {
 	if cfg.submitURL != "" {
 		err = submitRemote(job.inputPath, cfg.assetsDir, cfg.submitURL, cfg.extraFiles, out)
 	} else {
-		err = renderLocal(job.inputPath, cfg.assetsDir, cfg.extraFiles, out)
+		err = renderLocal(job.inputPath, cfg.assetsDir, cfg.extraFiles, cfg.imposition, out)
 	}
 	if err != nil {
 		return err
@@ -611,7 +661,7 @@ func dirToken(root string) (string, error) {
 	return b.String(), nil
 }
 
-func renderLocal(absInput, assetsDir string, extraFiles []string, out io.Writer) error {
+func renderLocal(absInput, assetsDir string, extraFiles []string, imposition options.Options, out io.Writer) error {
 	doc, err := ltml.ParseFile(absInput)
 	if err != nil {
 		return fmt.Errorf("parsing %s: %w", displayPath(absInput), err)
@@ -629,6 +679,9 @@ func renderLocal(absInput, assetsDir string, extraFiles []string, out io.Writer)
 	if assetFS != nil {
 		w.SetAssetFS(assetFS)
 	}
+	if imposition != nil {
+		w.SetImposition(imposition)
+	}
 	if err := doc.Print(w); err != nil {
 		return fmt.Errorf("rendering: %w", err)
 	}
@@ -463,6 +463,62 @@ func TestRun_LocalModeDefaultOutput(t *testing.T) {
 	}
 }
 
+func TestRun_LocalModeImposesBooklet(t *testing.T) {
+	inputFile := filepath.Join(t.TempDir(), "program.ltml")
+	ltml := `<ltml><page size="A5"><p>One</p></page><page size="A5"><p>Two</p></page><page size="A5"><p>Three</p></page></ltml>`
+	if err := os.WriteFile(inputFile, []byte(ltml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	imposition, err := impositionOptions("", true, "A4", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := runConfig{pollInterval: time.Hour, imposition: imposition}
+	if err := run(context.Background(), cfg, []string{inputFile}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(strings.TrimSuffix(inputFile, ".ltml") + ".pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := bytes.Count(data, []byte("/Type /Page ")); got != 2 {
+		t.Fatalf("imposed page count = %d, want 2", got)
+	}
+	if !bytes.Contains(data, []byte("/Pg3 Do")) {
+		t.Fatal("expected logical pages placed on sheets")
+	}
+}
+
+func TestImpositionOptions(t *testing.T) {
+	if imposition, err := impositionOptions("", false, "", "", false); err != nil || imposition != nil {
+		t.Fatalf("no flags = %v, %v; want nil, nil", imposition, err)
+	}
+	imposition, err := impositionOptions("2x2", false, "tabloid", "0.25in", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imposition["pages_across"] != 2.0 || imposition["pages_down"] != 2.0 ||
+		imposition["sheet_size"] != "tabloid" || imposition["gutter"] != "0.25in" || imposition["crop_marks"] != true {
+		t.Fatalf("imposition = %v", imposition)
+	}
+	for _, tc := range []struct {
+		nUp     string
+		booklet bool
+		sheet   string
+	}{
+		{"2", false, ""},
+		{"0x1", false, ""},
+		{"2x1", true, ""},
+		{"", false, "letter"},
+	} {
+		if _, err := impositionOptions(tc.nUp, tc.booklet, tc.sheet, "", false); err == nil {
+			t.Errorf("impositionOptions(%q, %v, %q) succeeded, want error", tc.nUp, tc.booklet, tc.sheet)
+		}
+	}
+}
+
 func TestRun_BatchModeLocalRendersMultipleFiles(t *testing.T) {
 	root := t.TempDir()
 	first := filepath.Join(root, "one.ltml")
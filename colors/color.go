@@ -6,13 +6,23 @@ package colors
 import (
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 )
 
-type Color int32
+// Color is an RGB color packed as 0xRRGGBB, or a CMYK or spot color as
+// returned by CMYK and Spot.
+type Color int64
 
+// RGB returns the red, green and blue components of the color. CMYK and
+// spot colors are converted naively.
 func (this Color) RGB() (r, g, b uint8) {
+	if this.Space() != DeviceRGB {
+		c, m, y, k := this.CMYK()
+		channel := func(v float64) uint8 { return uint8(math.Round(255 * (1 - v) * (1 - k))) }
+		return channel(c), channel(m), channel(y)
+	}
 	b = uint8(this & 0xFF)
 	g = uint8((this >> 8) & 0xFF)
 	r = uint8((this >> 16) & 0xFF)
@@ -35,7 +45,13 @@ func (this Color) String() string {
 	if s, ok := ColorNames[this]; ok {
 		return s
 	}
-	return fmt.Sprintf("%06X", int32(this))
+	switch this.Space() {
+	case DeviceCMYK:
+		return this.cmykString()
+	case Separation:
+		return this.spotString()
+	}
+	return fmt.Sprintf("%06X", int64(this))
 }
 
 const (
@@ -338,7 +354,14 @@ var NamedColors = map[string]Color{
 	"yellowgreen":          YellowGreen,
 }
 
+// NamedColor parses the name of a color, a numeric value in hex format, or
+// one of the notations cmyk(c, m, y, k), device-cmyk(c m y k) and
+// spot(name, cmyk(c, m, y, k)[, tint]). Components and tints are between 0
+// and 1 or 0% and 100%.
 func NamedColor(name string) (Color, error) {
+	if strings.HasSuffix(name, ")") {
+		return parseColorFunction(strings.TrimSpace(name))
+	}
 	if color, ok := NamedColors[strings.ToLower(name)]; ok {
 		return color, nil
 	}
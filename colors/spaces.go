@@ -0,0 +1,276 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package colors
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Space identifies the color space of a Color.
+type Space int
+
+const (
+	DeviceRGB  Space = iota // packed 0xRRGGBB, as the named colors are
+	DeviceCMYK              // process cyan, magenta, yellow and black
+	Separation              // a tint of a named spot color
+)
+
+// A Color holds its space in its top bits, above the space's components.
+// CMYK components and spot tints are kept in hundredths of a percent, so
+// that values given as percentages to two decimal places are exact.
+const (
+	spaceShift = 56
+	unit       = 10000
+	fieldBits  = 14
+	fieldMask  = 1<<fieldBits - 1
+)
+
+var errBadCMYK = errors.New("Expected cmyk(c, m, y, k) with four components between 0 and 1 or 0% and 100%.")
+var errBadSpot = errors.New("Expected spot(name, cmyk(c, m, y, k)[, tint]).")
+
+// spotColor is a named colorant, with the process color approximating it
+// where it cannot be printed.
+type spotColor struct {
+	name      string
+	alternate Color
+}
+
+// spots lists every distinct spot color, a name together with its alternate,
+// so that a Color can refer to one by its index and remain a comparable
+// value. Spot colors are never looked up by name alone: each use gives the
+// alternate, so that documents cannot see each other's definitions.
+var spots struct {
+	sync.Mutex
+	list []spotColor
+}
+
+func toUnits(value float64) Color {
+	return Color(math.Round(math.Max(0, math.Min(1, value)) * unit))
+}
+
+func fromUnits(value Color) float64 {
+	return float64(value&fieldMask) / unit
+}
+
+// CMYK returns the process color with the given cyan, magenta, yellow and
+// black components, each from 0 to 1.
+func CMYK(c, m, y, k float64) Color {
+	return Color(DeviceCMYK)<<spaceShift |
+		toUnits(c)<<(3*fieldBits) | toUnits(m)<<(2*fieldBits) | toUnits(y)<<fieldBits | toUnits(k)
+}
+
+// Spot returns the spot color name at full tint, defining it with the given
+// alternate, which is converted to CMYK if it is not already. Colors of the
+// same name and alternate are the same color.
+func Spot(name string, alternate Color) Color {
+	alternate = CMYK(alternate.CMYK())
+	spots.Lock()
+	defer spots.Unlock()
+	index := -1
+	for i, s := range spots.list {
+		if s.name == name && s.alternate == alternate {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		index = len(spots.list)
+		spots.list = append(spots.list, spotColor{name, alternate})
+	}
+	return Color(Separation)<<spaceShift | Color(index)<<fieldBits | unit
+}
+
+func (this Color) spot() spotColor {
+	spots.Lock()
+	defer spots.Unlock()
+	return spots.list[(this>>fieldBits)&math.MaxUint32]
+}
+
+// Space returns the color space of the color.
+func (this Color) Space() Space {
+	return Space(this >> spaceShift)
+}
+
+// CMYK returns the cyan, magenta, yellow and black components of the
+// color, converting RGB colors naively and scaling the alternate of a spot
+// color by its tint.
+func (this Color) CMYK() (c, m, y, k float64) {
+	switch this.Space() {
+	case DeviceCMYK:
+		return fromUnits(this >> (3 * fieldBits)), fromUnits(this >> (2 * fieldBits)), fromUnits(this >> fieldBits), fromUnits(this)
+	case Separation:
+		tint := this.Tint()
+		c, m, y, k = this.spot().alternate.CMYK()
+		return c * tint, m * tint, y * tint, k * tint
+	}
+	r, g, b := this.RGB64()
+	k = 1 - math.Max(r, math.Max(g, b))
+	if k == 1 {
+		return 0, 0, 0, 1
+	}
+	return (1 - r - k) / (1 - k), (1 - g - k) / (1 - k), (1 - b - k) / (1 - k), k
+}
+
+// SpotName returns the name of a spot color, or "" for a process color.
+func (this Color) SpotName() string {
+	if this.Space() != Separation {
+		return ""
+	}
+	return this.spot().name
+}
+
+// Alternate returns the CMYK color standing in for a spot color at full
+// tint. Other colors are their own alternates.
+func (this Color) Alternate() Color {
+	if this.Space() != Separation {
+		return this
+	}
+	return this.spot().alternate
+}
+
+// Tint returns the tint of a spot color, from 0 to 1, or 1 for a process
+// color.
+func (this Color) Tint() float64 {
+	if this.Space() != Separation {
+		return 1
+	}
+	return fromUnits(this)
+}
+
+// WithTint returns the spot color at the given tint, from 0 to 1. Process
+// colors are returned unchanged.
+func (this Color) WithTint(tint float64) Color {
+	if this.Space() != Separation {
+		return this
+	}
+	return this&^fieldMask | toUnits(tint)
+}
+
+func formatUnits(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func (this Color) cmykString() string {
+	c, m, y, k := this.CMYK()
+	return fmt.Sprintf("cmyk(%s, %s, %s, %s)", formatUnits(c), formatUnits(m), formatUnits(y), formatUnits(k))
+}
+
+func (this Color) spotString() string {
+	s := this.spot()
+	name := s.name
+	if strings.ContainsAny(name, ",()' ") {
+		name = `"` + name + `"`
+	}
+	if tint := this.Tint(); tint != 1 {
+		return fmt.Sprintf("spot(%s, %s, %s)", name, s.alternate.cmykString(), formatUnits(tint))
+	}
+	return fmt.Sprintf("spot(%s, %s)", name, s.alternate.cmykString())
+}
+
+// functionArgs returns the arguments of text if it is a call of function,
+// such as "cmyk(0, 1, 1, 0)", split at the commas outside nested calls and
+// quotes.
+func functionArgs(text, function string) ([]string, bool) {
+	if len(text) <= len(function) || !strings.EqualFold(text[:len(function)], function) {
+		return nil, false
+	}
+	body := strings.TrimSpace(text[len(function):])
+	if !strings.HasPrefix(body, "(") || !strings.HasSuffix(body, ")") {
+		return nil, false
+	}
+	body = body[1 : len(body)-1]
+	var args []string
+	depth, quote, start := 0, rune(0), 0
+	for i, r := range body {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			args = append(args, strings.TrimSpace(body[start:i]))
+			start = i + 1
+		}
+	}
+	return append(args, strings.TrimSpace(body[start:])), true
+}
+
+// parseFraction parses a component from 0 to 1, or from 0% to 100%.
+func parseFraction(text string) (float64, bool) {
+	scale := 1.0
+	if strings.HasSuffix(text, "%") {
+		text, scale = strings.TrimSuffix(text, "%"), 100
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil || value < 0 || value > scale {
+		return 0, false
+	}
+	return value / scale, true
+}
+
+func parseCMYK(args []string) (Color, error) {
+	if len(args) == 1 {
+		// CSS device-cmyk() separates its components with spaces.
+		args = strings.Fields(args[0])
+	}
+	if len(args) != 4 {
+		return Black, errBadCMYK
+	}
+	var values [4]float64
+	for i, arg := range args {
+		value, ok := parseFraction(arg)
+		if !ok {
+			return Black, errBadCMYK
+		}
+		values[i] = value
+	}
+	return CMYK(values[0], values[1], values[2], values[3]), nil
+}
+
+func parseSpot(args []string) (Color, error) {
+	if len(args) < 2 || len(args) > 3 || args[0] == "" {
+		return Black, errBadSpot
+	}
+	alternate, err := parseColorFunction(args[1])
+	if err != nil {
+		return Black, errBadSpot
+	}
+	color := Spot(strings.Trim(args[0], `"'`), alternate)
+	if len(args) == 3 {
+		tint, ok := parseFraction(args[2])
+		if !ok {
+			return Black, errBadSpot
+		}
+		color = color.WithTint(tint)
+	}
+	return color, nil
+}
+
+// parseColorFunction parses the cmyk(), device-cmyk() and spot() notations.
+func parseColorFunction(text string) (Color, error) {
+	if args, ok := functionArgs(text, "cmyk"); ok {
+		return parseCMYK(args)
+	}
+	if args, ok := functionArgs(text, "device-cmyk"); ok {
+		return parseCMYK(args)
+	}
+	if args, ok := functionArgs(text, "spot"); ok {
+		return parseSpot(args)
+	}
+	if c, ok := NamedColors[strings.ToLower(text)]; ok {
+		return c, nil
+	}
+	return Black, errBadCMYK
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package colors
+
+import (
+	"testing"
+)
+
+func TestCMYK(t *testing.T) {
+	c := CMYK(0.1, 0.2, 0.3, 0.4)
+	check(t, c.Space() == DeviceCMYK, "Expecting DeviceCMYK.")
+	cyan, magenta, yellow, black := c.CMYK()
+	expectF(t, 0.1, cyan)
+	expectF(t, 0.2, magenta)
+	expectF(t, 0.3, yellow)
+	expectF(t, 0.4, black)
+
+	r, g, b := CMYK(0, 1, 1, 0).RGB()
+	expectNI(t, "red", 255, int(r))
+	expectNI(t, "green", 0, int(g))
+	expectNI(t, "blue", 0, int(b))
+
+	check(t, Red.Space() == DeviceRGB, "Expecting DeviceRGB.")
+	cyan, magenta, yellow, black = Red.CMYK()
+	check(t, cyan == 0 && magenta == 1 && yellow == 1 && black == 0, "Expecting Red as CMYK 0 1 1 0.")
+}
+
+func TestSpot(t *testing.T) {
+	brand := Spot("Brand Red", CMYK(0, 0.9, 0.8, 0))
+	check(t, brand.Space() == Separation, "Expecting Separation.")
+	check(t, brand.SpotName() == "Brand Red", "Expecting spot name.")
+	check(t, brand.Alternate() == CMYK(0, 0.9, 0.8, 0), "Expecting CMYK alternate.")
+	check(t, brand == Spot("Brand Red", CMYK(0, 0.9, 0.8, 0)), "Expecting same spot color.")
+	check(t, brand != Spot("Brand Red", CMYK(0, 0.8, 0.8, 0)), "Expecting different spot color.")
+
+	tint := brand.WithTint(0.5)
+	expectF(t, 0.5, tint.Tint())
+	check(t, tint.SpotName() == "Brand Red", "Expecting spot name of tint.")
+	_, magenta, _, _ := tint.CMYK()
+	expectF(t, 0.45, magenta)
+	check(t, tint.WithTint(1) == brand, "Expecting full tint.")
+	check(t, Red.WithTint(0.5) == Red, "Expecting process color unchanged by tint.")
+}
+
+func TestNamedColor_Functions(t *testing.T) {
+	c1, err1 := NamedColor("cmyk(0, 100%, 50%, 0.25)")
+	check(t, err1 == nil, "Error parsing cmyk().")
+	check(t, c1 == CMYK(0, 1, 0.5, 0.25), "Expecting CMYK 0 1 0.5 0.25.")
+
+	c2, err2 := NamedColor("device-cmyk(0 1 0.5 0.25)")
+	check(t, err2 == nil, "Error parsing device-cmyk().")
+	check(t, c2 == c1, "Expecting same color as cmyk().")
+
+	c3, err3 := NamedColor(`spot("Brand, Blue", cmyk(1, 0.5, 0, 0), 40%)`)
+	check(t, err3 == nil, "Error parsing spot().")
+	check(t, c3.SpotName() == "Brand, Blue", "Expecting quoted spot name.")
+	expectF(t, 0.4, c3.Tint())
+
+	c4, err4 := NamedColor(`spot("Brand, Blue", 0.4)`)
+	check(t, err4 != nil, "Expecting error for spot color without alternate.")
+	check(t, c4 == Black, "Expecting Black for spot color without alternate.")
+
+	c5, err5 := NamedColor(`spot("Brand, Blue", cmyk(1, 0.5, 0, 0), 0.4)`)
+	check(t, err5 == nil, "Error parsing spot() again.")
+	check(t, c5 == c3, "Expecting same spot color for same name and alternate.")
+
+	_, err6 := NamedColor("cmyk(0, 1, 1)")
+	check(t, err6 != nil, "Expecting error for three components.")
+	_, err7 := NamedColor("cmyk(0, 1, 1, 2)")
+	check(t, err7 != nil, "Expecting error for component out of range.")
+}
+
+func TestColor_String_Spaces(t *testing.T) {
+	for _, c := range []Color{
+		CMYK(0, 0.91, 0.76, 0),
+		Spot("PANTONE 185 C", CMYK(0, 0.91, 0.76, 0)),
+		Spot("PANTONE 185 C", CMYK(0, 0.91, 0.76, 0)).WithTint(0.25),
+	} {
+		parsed, err := NamedColor(c.String())
+		if err != nil || parsed != c {
+			t.Errorf("Expecting <%s> to parse as itself, got <%s>.", c, parsed)
+		}
+	}
+	if s := CMYK(0, 0.91, 0.76, 0).String(); s != "cmyk(0, 0.91, 0.76, 0)" {
+		t.Errorf("Expecting <%s>, got <%s>.", "cmyk(0, 0.91, 0.76, 0)", s)
+	}
+}
@@ -5,6 +5,7 @@ package font
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/rowland/leadtype/options"
 	"github.com/rowland/leadtype/shaping"
@@ -26,7 +27,17 @@ type Font struct {
 	Ranges       []string
 	RuneSet      RuneSet
 	RelativeSize float64
-	metrics      FontMetrics
+	// Kerning enables pair kerning of text set in the font, where its
+	// metrics have kerning pairs.
+	Kerning bool
+	// Instance and Variations choose the named instance of a variable font,
+	// and then the user-space coordinates of its axes, keyed by tag.
+	Instance   string
+	Variations map[string]float64
+	// Features are OpenType features to turn on or off when text set in the
+	// font is shaped, such as tnum for tabular figures.
+	Features []shaping.Feature
+	metrics  FontMetrics
 	// Shaper is non-nil for fonts whose source supports complex-script shaping
 	// (e.g. Arabic). It is set automatically by New when the winning FontSource
 	// implements ShaperSource.
@@ -39,6 +50,8 @@ func New(family string, options options.Options, fontSources FontSources) (*Font
 		Weight:       options.StringDefault("weight", ""),
 		style:        options.StringDefault("style", ""),
 		RelativeSize: options.FloatDefault("relative_size", 100) / 100.0,
+		Kerning:      options.BoolDefault("kerning", false),
+		Instance:     options.StringDefault("instance", ""),
 	}
 	if Ranges, ok := options["ranges"]; ok {
 		switch Ranges := Ranges.(type) {
@@ -49,8 +62,29 @@ func New(family string, options options.Options, fontSources FontSources) (*Font
 		}
 	}
 	var err error
+	switch variations := options["variations"].(type) {
+	case map[string]float64:
+		font.Variations = variations
+	case string:
+		if font.Variations, err = ParseVariations(variations); err != nil {
+			return nil, err
+		}
+	}
+	switch features := options["features"].(type) {
+	case []shaping.Feature:
+		font.Features = features
+	case string:
+		if font.Features, err = shaping.ParseFeatures(features); err != nil {
+			return nil, err
+		}
+	}
 	for _, fontSource := range fontSources {
 		if font.metrics, err = fontSource.Select(font.family, font.Weight, font.style, font.Ranges); err == nil {
+			if vs, ok := fontSource.(VariationSource); ok && (font.Instance != "" || len(font.Variations) > 0) {
+				if font.metrics, err = vs.Instance(font.metrics, font.Instance, font.Variations); err != nil {
+					continue
+				}
+			}
 			font.subType = fontSource.SubType()
 			if ss, ok := fontSource.(ShaperSource); ok {
 				font.Shaper = ss.Shaper()
@@ -148,6 +182,10 @@ func (font *Font) Matches(other *Font) bool {
 		font.subType == other.subType &&
 		font.RuneSet == other.RuneSet &&
 		font.RelativeSize == other.RelativeSize &&
+		font.Kerning == other.Kerning &&
+		font.Instance == other.Instance &&
+		variationsEqual(font.Variations, other.Variations) &&
+		slices.Equal(font.Features, other.Features) &&
 		stringSlicesEqual(font.Ranges, other.Ranges)
 }
 
@@ -155,6 +193,22 @@ func (font *Font) NumGlyphs() int {
 	return font.metrics.NumGlyphs()
 }
 
+// Kerner is an optional interface implemented by FontMetrics backends that
+// have pair kerning data (AFM KPX pairs, TrueType kern and GPOS tables).
+type Kerner interface {
+	PairKerning(left, right rune) int
+}
+
+// PairKerning returns the adjustment to the advance width of left when it is
+// followed by right, in glyph-space units, or 0 if the font has no kerning
+// data. Negative values tighten the pair.
+func (font *Font) PairKerning(left, right rune) int {
+	if k, ok := font.metrics.(Kerner); ok {
+		return k.PairKerning(left, right)
+	}
+	return 0
+}
+
 func (font *Font) PostScriptName() string {
 	return font.metrics.PostScriptName()
 }
@@ -226,15 +280,32 @@ func (font *Font) Bytes() []byte {
 	return nil
 }
 
+// CFFOutliner is an optional interface implemented by FontMetrics backends
+// that load OpenType fonts, which may have CFF outlines instead of TrueType
+// outlines.
+type CFFOutliner interface {
+	HasCFFOutlines() bool
+}
+
+// HasCFFOutlines reports whether the font is an OpenType font with CFF
+// outlines, whose subsets are embedded as such.
+func (font *Font) HasCFFOutlines() bool {
+	if co, ok := font.metrics.(CFFOutliner); ok {
+		return co.HasCFFOutlines()
+	}
+	return false
+}
+
 // Subsetter is an optional interface implemented by FontMetrics backends that
-// support font subsetting (currently only TTF). When implemented, SubsetBytes
-// returns a self-consistent TTF binary containing only the requested glyphs.
+// support font subsetting (currently only TTF and OTF). When implemented,
+// SubsetBytes returns a self-consistent font binary containing only the
+// requested glyphs.
 type Subsetter interface {
 	Subset(glyphIDs []uint16) ([]byte, error)
 }
 
-// SubsetBytes returns a TTF binary containing only the supplied glyph IDs, or
-// an error if the underlying font type does not support subsetting.
+// SubsetBytes returns a TTF or OTF binary containing only the supplied glyph
+// IDs, or an error if the underlying font type does not support subsetting.
 func (font *Font) SubsetBytes(glyphIDs []uint16) ([]byte, error) {
 	if s, ok := font.metrics.(Subsetter); ok {
 		return s.Subset(glyphIDs)
@@ -4,6 +4,7 @@
 package font
 
 import (
+	"github.com/rowland/leadtype/afm"
 	"github.com/rowland/leadtype/ttf"
 	"testing"
 )
@@ -34,6 +35,18 @@ func TestFont_Matches(t *testing.T) {
 	check(t, f1.Matches(f2), "Fonts should match.")
 }
 
+func TestFont_PairKerning(t *testing.T) {
+	helvetica, err := afm.LoadFont("../afm/data/fonts/Helvetica.afm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &Font{metrics: helvetica}
+	check(t, f.PairKerning('A', 'V') == -70, "Helvetica should kern 'AV'.")
+	check(t, f.PairKerning('A', 'B') == 0, "Helvetica should not kern 'AB'.")
+	k1 := &Font{metrics: helvetica, Kerning: true}
+	check(t, !f.Matches(k1), "Fonts with and without kerning should not match.")
+}
+
 // 55.2 ns
 // 46.1 ns go1.1.1
 // 46.0 ns go1.1.2
@@ -62,6 +75,22 @@ func TestStringSlicesEqual(t *testing.T) {
 	check(t, !stringSlicesEqual(a, d), "Slices a and d should not be equal.")
 }
 
+func TestParseVariations(t *testing.T) {
+	v, err := ParseVariations(`"wght" 650, 'wdth' 80.5,, opsz=12`)
+	check(t, err == nil, "Variations should parse.")
+	check(t, len(v) == 3, "There should be 3 axes.")
+	check(t, v["wght"] == 650 && v["wdth"] == 80.5 && v["opsz"] == 12, "Axis values should match.")
+	check(t, variationsEqual(v, map[string]float64{"wght": 650, "wdth": 80.5, "opsz": 12}), "Variations should be equal.")
+	check(t, !variationsEqual(v, map[string]float64{"wght": 650}), "Variations should not be equal.")
+
+	_, err = ParseVariations("weight 650")
+	check(t, err != nil, "A long tag should not parse.")
+	_, err = ParseVariations("wght bold")
+	check(t, err != nil, "A word value should not parse.")
+	v, err = ParseVariations("")
+	check(t, err == nil && len(v) == 0, "No variations should parse.")
+}
+
 func check(t *testing.T, condition bool, msg string) {
 	if !condition {
 		t.Error(msg)
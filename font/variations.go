@@ -0,0 +1,75 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package font
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VariationSource is an optional interface that FontSource implementations
+// may satisfy to select instances of the variable fonts they load. New uses
+// it for the "instance" and "variations" options, passing the metrics the
+// source selected, the name of an instance, if any, and the coordinates of
+// axes, if any. Metrics of fonts that are not variable are returned as
+// they are.
+type VariationSource interface {
+	Instance(metrics FontMetrics, name string, coords map[string]float64) (FontMetrics, error)
+}
+
+// VariationReader is an optional interface implemented by FontMetrics
+// backends that load instances of variable fonts. Variation returns the
+// user-space coordinates of each axis of the instance, or nil.
+type VariationReader interface {
+	Variation() map[string]float64
+}
+
+// Variation returns the coordinates of the instance of a variable font the
+// font uses, for shapers, or nil if it uses none.
+func (font *Font) Variation() map[string]float64 {
+	if vr, ok := font.metrics.(VariationReader); ok {
+		return vr.Variation()
+	}
+	return nil
+}
+
+// ParseVariations parses axis coordinates in the form of CSS's
+// font-variation-settings, such as `"wght" 650, "wdth" 80`. Quotes are
+// optional, and "=" may separate tags from values.
+func ParseVariations(s string) (map[string]float64, error) {
+	variations := make(map[string]float64)
+	for _, setting := range strings.Split(s, ",") {
+		setting = strings.TrimSpace(setting)
+		if setting == "" {
+			continue
+		}
+		fields := strings.Fields(strings.Replace(setting, "=", " ", 1))
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid variation setting %q", setting)
+		}
+		tag := strings.Trim(fields[0], `"'`)
+		if len(tag) != 4 {
+			return nil, fmt.Errorf("invalid axis tag %q", fields[0])
+		}
+		value, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for axis %s: %q", tag, fields[1])
+		}
+		variations[tag] = value
+	}
+	return variations, nil
+}
+
+func variationsEqual(v1, v2 map[string]float64) bool {
+	if len(v1) != len(v2) {
+		return false
+	}
+	for tag, value := range v1 {
+		if other, ok := v2[tag]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
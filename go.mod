@@ -5,9 +5,7 @@ go 1.21
 require (
 	github.com/go-text/typesetting v0.3.4
 	golang.org/x/image v0.23.0
+	golang.org/x/text v0.21.0
 )
 
-require (
-	github.com/namsral/flag v1.7.4-pre // indirect
-	golang.org/x/text v0.21.0 // indirect
-)
+require github.com/namsral/flag v1.7.4-pre
@@ -32,6 +32,10 @@ type FontStyle struct {
 	underline  bool
 	weight     string
 	lineHeight float64
+	kerning    bool
+	instance   string
+	variations string
+	features   string
 }
 
 func (fs *FontStyle) Apply(w Writer) {
@@ -43,6 +47,18 @@ func (fs *FontStyle) Apply(w Writer) {
 		"weight": fs.weight,
 		"style":  fs.style,
 	}
+	if fs.kerning {
+		baseOpts["kerning"] = true
+	}
+	if fs.instance != "" {
+		baseOpts["instance"] = fs.instance
+	}
+	if fs.variations != "" {
+		baseOpts["variations"] = fs.variations
+	}
+	if fs.features != "" {
+		baseOpts["features"] = fs.features
+	}
 	loadedPrimary := false
 	for _, entry := range fs.entries {
 		opts := applyEntryOptions(entry, baseOpts)
@@ -132,6 +148,10 @@ var defaultFont = &FontStyle{
 //	           normalise fonts that render at visually different sizes for the
 //	           same point size.  Example: "1.0 | 0.9"
 //	size     – shared point size for all fonts in the chain.
+//	kerning  – "true" to apply the fonts' pair kerning.
+//	instance – named instance of a variable font, such as "Condensed Bold".
+//	variations – axis coordinates of a variable font, such as "wght 650, wdth 80".
+//	features – OpenType features to shape text with, such as "tnum,smcp".
 //	color, weight, style, strikeout, underline, line-height – as before.
 func (fs *FontStyle) SetAttrs(prefix string, attrs map[string]string) {
 	if id, ok := attrs[prefix+"id"]; ok {
@@ -197,6 +217,18 @@ func (fs *FontStyle) SetAttrs(prefix string, attrs map[string]string) {
 	if lineHeight, ok := attrs[prefix+"line-height"]; ok {
 		fs.lineHeight, _ = strconv.ParseFloat(lineHeight, 64)
 	}
+	if kerning, ok := attrs[prefix+"kerning"]; ok {
+		fs.kerning = (kerning == "true")
+	}
+	if instance, ok := attrs[prefix+"instance"]; ok {
+		fs.instance = instance
+	}
+	if variations, ok := attrs[prefix+"variations"]; ok {
+		fs.variations = variations
+	}
+	if features, ok := attrs[prefix+"features"]; ok {
+		fs.features = features
+	}
 }
 
 // splitCommaTrimmed splits s by commas and trims whitespace, omitting empties.
@@ -226,8 +258,8 @@ func (fs *FontStyle) String() string {
 	for i, e := range fs.entries {
 		names[i] = e.name
 	}
-	return fmt.Sprintf("FontStyle id=%s name=%s size=%f color=%v strikeout=%t style=%s underline=%t weight=%s line-height=%f",
-		fs.id, strings.Join(names, ","), fs.size, fs.color, fs.strikeout, fs.style, fs.underline, fs.weight, fs.lineHeight)
+	return fmt.Sprintf("FontStyle id=%s name=%s size=%f color=%v strikeout=%t style=%s underline=%t weight=%s line-height=%f kerning=%t",
+		fs.id, strings.Join(names, ","), fs.size, fs.color, fs.strikeout, fs.style, fs.underline, fs.weight, fs.lineHeight, fs.kerning)
 }
 
 func (fs *FontStyle) RichTextOptions() options.Options {
@@ -235,6 +267,7 @@ func (fs *FontStyle) RichTextOptions() options.Options {
 		"color":     fs.color,
 		"strikeout": fs.strikeout,
 		"underline": fs.underline,
+		"kerning":   fs.kerning,
 	}
 }
 
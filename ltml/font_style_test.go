@@ -17,6 +17,7 @@ import (
 type mockWriter struct {
 	setFontName  string
 	setFontSize  float64
+	setFontOpts  options.Options
 	addFontNames []string
 	fonts        []*font.Font
 	setFontCalls []string
@@ -48,6 +49,7 @@ func (m *mockWriter) SetFont(name string, size float64, opts options.Options) ([
 	m.setFontCalls = append(m.setFontCalls, name)
 	m.setFontName = name
 	m.setFontSize = size
+	m.setFontOpts = opts
 	m.addFontNames = nil
 	if err := m.setFontErrs[name]; err != nil {
 		m.fonts = nil
@@ -194,6 +196,38 @@ func TestFontStyle_SetAttrs_Prefix(t *testing.T) {
 	}
 }
 
+func TestFontStyle_SetAttrs_Kerning(t *testing.T) {
+	var fs FontStyle
+	fs.SetAttrs("font.", map[string]string{
+		"font.name":    "Helvetica",
+		"font.kerning": "true",
+	})
+	if !fs.kerning {
+		t.Fatal("expected kerning")
+	}
+	if kerning, _ := fs.RichTextOptions()["kerning"].(bool); !kerning {
+		t.Error("expected kerning in rich text options")
+	}
+	w := &mockWriter{t: t}
+	fs.Apply(w)
+	if kerning, _ := w.setFontOpts["kerning"].(bool); !kerning {
+		t.Errorf("SetFont options = %v, want kerning", w.setFontOpts)
+	}
+}
+
+func TestFontStyle_SetAttrs_Features(t *testing.T) {
+	var fs FontStyle
+	fs.SetAttrs("font.", map[string]string{
+		"font.name":     "Helvetica",
+		"font.features": "tnum,smcp",
+	})
+	w := &mockWriter{t: t}
+	fs.Apply(w)
+	if features, _ := w.setFontOpts["features"].(string); features != "tnum,smcp" {
+		t.Errorf("SetFont options = %v, want features tnum,smcp", w.setFontOpts)
+	}
+}
+
 func TestFontStyle_Clone_DeepCopiesEntries(t *testing.T) {
 	var fs FontStyle
 	fs.SetAttrs("", map[string]string{
@@ -0,0 +1,13 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ltml
+
+// HasRole is implemented by widgets that become elements of a tagged PDF's
+// structure tree. Role returns a standard structure type, such as "P" or
+// "Figure", RoleArtifact for decorative content, or "" for none.
+type HasRole interface {
+	Role() string
+	Alt() string
+	Lang() string
+}
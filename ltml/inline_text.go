@@ -1,6 +1,6 @@
 package ltml
 
-import "fmt"
+import "github.com/rowland/leadtype/pdf"
 
 type inlineText interface {
 	Resolve(*StdDocument) string
@@ -94,6 +94,6 @@ func walkWidgets(root Widget, fn func(Widget) bool) bool {
 	return true
 }
 
-func formatPageNo(value int) string {
-	return fmt.Sprintf("%d", value)
+func formatPageNo(style pageNoStyle, value int) string {
+	return style.prefix + pdf.FormatPageLabel(style.labelStyle(), value)
 }
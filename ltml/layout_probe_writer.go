@@ -61,6 +61,16 @@ func (w *layoutProbeWriter) Print(text string) error { return nil }
 func (w *layoutProbeWriter) PrintImageFile(filename string, x, y float64, width, height *float64) (actualWidth, actualHeight float64, err error) {
 	return 0, 0, nil
 }
+func (w *layoutProbeWriter) PDFPageDimensionsFromFile(filename string, page int) (width, height float64, err error) {
+	if pw, ok := w.base.(PDFPageWriter); ok {
+		return pw.PDFPageDimensionsFromFile(filename, page)
+	}
+	iw, ih, err := w.base.ImageDimensionsFromFile(filename)
+	return float64(iw), float64(ih), err
+}
+func (w *layoutProbeWriter) PrintPDFPageFile(filename string, page int, x, y float64, width, height *float64) (actualWidth, actualHeight float64, err error) {
+	return 0, 0, nil
+}
 func (w *layoutProbeWriter) PrintParagraph(para []*rich_text.RichText, opts options.Options) {}
 func (w *layoutProbeWriter) PrintRichText(text *rich_text.RichText)                          {}
 func (w *layoutProbeWriter) Rectangle(x, y, width, height float64, border bool, fill bool)   {}
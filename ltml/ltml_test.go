@@ -187,6 +187,14 @@ func TestSamples(t *testing.T) {
 		"test_032_label_shrink_to_fit",
 		"test_033_arabic_program",
 		"test_034_svg_image",
+		"test_035_forms",
+		"test_036_tagged_pdf",
+		"test_037_attachments",
+		"test_038_pdf_pages",
+		"test_039_print_colors",
+		"test_040_page_sizes",
+		"test_041_page_labels",
+		"test_042_kerning",
 	}
 
 	for _, sample := range samples {
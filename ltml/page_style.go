@@ -1,5 +1,10 @@
 package ltml
 
+import (
+	"github.com/rowland/leadtype/options"
+	"github.com/rowland/leadtype/pdf"
+)
+
 const (
 	Portrait  = 0
 	Landscape = 270
@@ -7,12 +12,20 @@ const (
 
 type PageSize [2]float64
 
-var PageSizes = map[string]PageSize{
-	"letter": {612, 792},
-	"legal":  {612, 1008},
-	"A4":     {595, 842},
-	"B5":     {499, 708},
-	"C5":     {459, 649},
+// PageSizes lists the named page sizes, portrait, in points. They are the
+// sizes known to the pdf package.
+var PageSizes = func() map[string]PageSize {
+	sizes := make(map[string]PageSize, len(pdf.PageSizes))
+	for name, sz := range pdf.PageSizes {
+		sizes[name] = PageSize{sz.Width, sz.Height}
+	}
+	return sizes
+}()
+
+// PageSizeWriter is implemented by writers that can begin a page of a given
+// size. Without it, every page is the writer's default size.
+type PageSizeWriter interface {
+	NewPageWithOptions(options options.Options) *pdf.PageWriter
 }
 
 type PageStyle struct {
@@ -20,9 +33,15 @@ type PageStyle struct {
 	size        string
 	height      float64
 	width       float64
+	bleed       float64
 	orientation int
 }
 
+// Bleed returns the margin, in points, printed beyond the trimmed page.
+func (ps *PageStyle) Bleed() float64 {
+	return ps.bleed
+}
+
 func (ps *PageStyle) ID() string {
 	return ps.id
 }
@@ -48,13 +67,18 @@ func (ps *PageStyle) SetAttrs(attrs map[string]string) {
 		}
 	}
 	if size, ok := attrs["size"]; ok {
-		if sz, ok := PageSizes[size]; ok {
+		if sz, ok := pdf.LookupPageSize(size); ok {
+			ps.size = size
 			if ps.orientation == Portrait {
-				ps.width, ps.height = sz[0], sz[1]
+				ps.width, ps.height = sz.Width, sz.Height
 			} else {
-				ps.width, ps.height = sz[1], sz[0]
+				ps.width, ps.height = sz.Height, sz.Width
 			}
 		}
+	} else if _, ok := attrs["orientation"]; ok {
+		if (ps.orientation == Landscape) != (ps.width > ps.height) {
+			ps.width, ps.height = ps.height, ps.width
+		}
 	}
 	if height, ok := attrs["height"]; ok {
 		ps.height = ParseMeasurement(height, "pt")
@@ -62,6 +86,14 @@ func (ps *PageStyle) SetAttrs(attrs map[string]string) {
 	if width, ok := attrs["width"]; ok {
 		ps.width = ParseMeasurement(width, "pt")
 	}
+	if bleed, ok := attrs["bleed"]; ok {
+		ps.bleed = ParseMeasurement(bleed, "pt")
+	}
+}
+
+// pageOptions returns the options for a writer to begin a page of the style.
+func (ps *PageStyle) pageOptions() options.Options {
+	return options.Options{"page_width": ps.width, "page_height": ps.height, "bleed": ps.bleed, "units": "pt"}
 }
 
 func (ps *PageStyle) Width() float64 {
@@ -79,7 +111,7 @@ var defaultPageStyles = map[string]*PageStyle{}
 
 func init() {
 	for id, sz := range PageSizes {
-		defaultPageStyles[id] = &PageStyle{id: id, width: sz[0], height: sz[1]}
+		defaultPageStyles[id] = &PageStyle{id: id, size: id, width: sz[0], height: sz[1]}
 	}
 	registerTag(DefaultSpace, "page", func() any { return &PageStyle{} })
 }
@@ -0,0 +1,108 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ltml
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rowland/leadtype/options"
+)
+
+var errAttachmentParent = errors.New("attachment must be a child of ltml")
+
+// AttachmentWriter is implemented by writers that can embed files in the
+// document. Attachments are ignored by writers that do not implement it.
+type AttachmentWriter interface {
+	AttachAssetFile(filename string, options options.Options) error
+}
+
+// StdAttachment declares a file to embed in the document, <attachment>. Its
+// src is resolved by the writer as an image's is, through the asset
+// filesystem when one is set.
+type StdAttachment struct {
+	AParent
+	src          string
+	name         string
+	mimeType     string
+	description  string
+	relationship string
+	modDate      time.Time
+}
+
+func (a *StdAttachment) SetAttrs(attrs map[string]string) {
+	if src, ok := attrs["src"]; ok {
+		a.src = src
+	}
+	if name, ok := attrs["name"]; ok {
+		a.name = name
+	}
+	if mimeType, ok := attrs["mime-type"]; ok {
+		a.mimeType = mimeType
+	}
+	if description, ok := attrs["description"]; ok {
+		a.description = description
+	}
+	if relationship, ok := attrs["relationship"]; ok {
+		a.relationship = relationship
+	}
+	if modDate, ok := attrs["mod-date"]; ok {
+		a.modDate = parseDate(modDate)
+	}
+}
+
+// SetParent registers the attachment with its document.
+func (a *StdAttachment) SetParent(value any) error {
+	d, ok := value.(*StdDocument)
+	if !ok {
+		return errAttachmentParent
+	}
+	a.parent = d
+	d.attachments = append(d.attachments, a)
+	return nil
+}
+
+// attach embeds the file with w, if it is an AttachmentWriter.
+func (a *StdAttachment) attach(w Writer) error {
+	aw, ok := w.(AttachmentWriter)
+	if !ok {
+		return nil
+	}
+	opts := options.Options{}
+	for key, value := range map[string]string{"name": a.name, "mime_type": a.mimeType, "description": a.description, "relationship": a.relationship} {
+		if value != "" {
+			opts[key] = value
+		}
+	}
+	if !a.modDate.IsZero() {
+		opts["mod_date"] = a.modDate
+	}
+	if err := aw.AttachAssetFile(a.src, opts); err != nil {
+		return fmt.Errorf("attaching %s: %w", a.src, err)
+	}
+	return nil
+}
+
+func (a *StdAttachment) String() string {
+	return fmt.Sprintf("StdAttachment src=%s name=%s mime-type=%s", a.src, a.name, a.mimeType)
+}
+
+// parseDate parses an RFC 3339 date and time or a plain date, returning the
+// zero time if s is neither.
+func parseDate(s string) time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func init() {
+	registerTag(DefaultSpace, "attachment", func() any { return &StdAttachment{} })
+}
+
+var _ HasAttrs = (*StdAttachment)(nil)
+var _ HasParent = (*StdAttachment)(nil)
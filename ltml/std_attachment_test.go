@@ -0,0 +1,74 @@
+package ltml
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rowland/leadtype/options"
+)
+
+type attachmentCall struct {
+	filename string
+	options  options.Options
+}
+
+type attachmentTestWriter struct {
+	labelTestWriter
+	attachments []attachmentCall
+}
+
+func (w *attachmentTestWriter) AttachAssetFile(filename string, options options.Options) error {
+	w.attachments = append(w.attachments, attachmentCall{filename, options})
+	return nil
+}
+
+func TestStdAttachment_Print(t *testing.T) {
+	doc, err := Parse([]byte(`
+<ltml>
+  <attachment src="data/invoice.xml" name="factur-x.xml" mime-type="text/xml"
+    description="Invoice data" relationship="Data" mod-date="2026-03-14" />
+  <attachment src="notes.txt" />
+  <page>
+    <attachment src="ignored.txt" />
+  </page>
+</ltml>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &attachmentTestWriter{labelTestWriter: labelTestWriter{t: t}}
+	if err := doc.Print(w); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.attachments) != 2 {
+		t.Fatalf("attachments = %+v, want 2 declared by the document", w.attachments)
+	}
+	invoice := w.attachments[0]
+	if invoice.filename != "data/invoice.xml" {
+		t.Errorf("filename = %q, want data/invoice.xml", invoice.filename)
+	}
+	want := options.Options{
+		"name":         "factur-x.xml",
+		"mime_type":    "text/xml",
+		"description":  "Invoice data",
+		"relationship": "Data",
+		"mod_date":     time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC),
+	}
+	for key, value := range want {
+		if invoice.options[key] != value {
+			t.Errorf("option %s = %v, want %v", key, invoice.options[key], value)
+		}
+	}
+	if len(w.attachments[1].options) != 0 {
+		t.Errorf("options = %v, want none", w.attachments[1].options)
+	}
+}
+
+func TestStdAttachment_IgnoredWithoutAttachmentWriter(t *testing.T) {
+	doc, err := Parse([]byte(`<ltml><attachment src="missing.txt" /><page /></ltml>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Print(&labelTestWriter{t: t}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -15,6 +15,7 @@ type StdContainer struct {
 	StdWidget
 	Children
 	cols            int
+	dir             string
 	layout          *LayoutStyle
 	order           TableOrder
 	paragraphStyle  *ParagraphStyle
@@ -36,8 +37,25 @@ func (c *StdContainer) Container() Container {
 	return c.container
 }
 
+// Dir returns the direction of the container's text, "ltr", "rtl" or "auto",
+// from its dir attribute or else from its container.
+func (c *StdContainer) Dir() string {
+	if c.dir != "" {
+		return c.dir
+	}
+	if c.container != nil {
+		return c.container.Dir()
+	}
+	return "auto"
+}
+
 func (c *StdContainer) DrawContent(w Writer) error {
 	// fmt.Printf("DrawContent %s\n", c)
+	if tw := tagWriterFor(w); tw != nil && c.Role() == "Table" {
+		if grid, err := c.tableGrid(); err == nil {
+			return c.drawTableRows(w, tw, grid)
+		}
+	}
 	children := slices.Clone(c.Widgets())
 	slices.SortStableFunc(children, func(a, b Widget) int {
 		return a.ZIndex() - b.ZIndex()
@@ -53,6 +71,47 @@ func (c *StdContainer) DrawContent(w Writer) error {
 	return nil
 }
 
+// drawTableRows prints a tagged table's cells row by row, each row a TR
+// element and each cell a TH, in the table's header rows, or a TD.
+// Positioned children are printed after the rows.
+func (c *StdContainer) drawTableRows(w Writer, tw TagWriter, grid *WidgetGrid) error {
+	inGrid := map[Widget]bool{}
+	for r := 0; r < grid.Rows(); r++ {
+		cellRole := "TD"
+		if r < c.headerRows {
+			cellRole = "TH"
+		}
+		err := withTag(tw, "TR", nil, func() error {
+			for col := 0; col < grid.Cols(); col++ {
+				cell := grid.Cell(col, r)
+				if cell == nil {
+					continue
+				}
+				inGrid[cell] = true
+				if !cell.Visible() || cell.Disabled() {
+					continue
+				}
+				if err := withTag(tw, cellRole, nil, func() error { return Print(cell, w) }); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, child := range c.Widgets() {
+		if inGrid[child] || !child.Visible() || child.Disabled() {
+			continue
+		}
+		if err := Print(child, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *StdContainer) LayoutStyle() *LayoutStyle {
 	if c.layout == nil {
 		return LayoutStyleFor("vbox", c.scope)
@@ -95,6 +154,13 @@ func (c *StdContainer) ParagraphStyle() *ParagraphStyle {
 	return c.paragraphStyle
 }
 
+func (c *StdContainer) Role() string {
+	if layout := c.LayoutStyle(); c.role == "" && layout != nil && layout.manager == "table" {
+		return "Table"
+	}
+	return c.role
+}
+
 func (c *StdContainer) Rows() int {
 	return c.rows
 }
@@ -104,6 +170,9 @@ func (c *StdContainer) SetAttrs(attrs map[string]string) {
 	if layout, ok := attrs["layout"]; ok {
 		c.layout = LayoutStyleFor(layout, c.scope)
 	}
+	if dir, ok := attrs["dir"]; ok && (dir == "ltr" || dir == "rtl" || dir == "auto") {
+		c.dir = dir
+	}
 	if order, ok := attrs["order"]; ok {
 		if order == "rows" {
 			c.order = TableOrderRows
@@ -207,13 +276,7 @@ type tableSplitMetrics struct {
 }
 
 func (c *StdContainer) tableSplitMetrics(w Writer) (*tableSplitMetrics, error) {
-	var grid *WidgetGrid
-	var err error
-	if c.Order() == TableOrderRows {
-		grid, err = rowGrid(c)
-	} else {
-		grid, err = colGrid(c)
-	}
+	grid, err := c.tableGrid()
 	if err != nil {
 		return nil, err
 	}
@@ -278,6 +341,14 @@ func (c *StdContainer) tableSplitMetrics(w Writer) (*tableSplitMetrics, error) {
 	}, nil
 }
 
+// tableGrid places the container's children in the cells of its table.
+func (c *StdContainer) tableGrid() (*WidgetGrid, error) {
+	if c.Order() == TableOrderRows {
+		return rowGrid(c)
+	}
+	return colGrid(c)
+}
+
 func (c *StdContainer) tableFragmentHeight(metrics *tableSplitMetrics, bodyStart, bodyEnd int) float64 {
 	rows := make([]int, 0, len(metrics.headerRows)+(bodyEnd-bodyStart)+len(metrics.footerRows))
 	rows = append(rows, metrics.headerRows...)
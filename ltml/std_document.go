@@ -14,9 +14,15 @@ type StdDocument struct {
 	documentPageNo        int
 	physicalPageNo        int
 	pendingStart          *int
+	pageNoStyle           pageNoStyle
+	pendingStyle          *pageNoStyle
+	lastLabel             pageLabel
 	compressPages         bool
 	compressToUnicode     bool
 	compressEmbeddedFonts bool
+	compressObjects       bool
+	tagged                bool
+	attachments           []*StdAttachment
 }
 
 func (d *StdDocument) Font() *FontStyle {
@@ -67,11 +73,43 @@ func (d *StdDocument) SetPendingStart(start int) {
 	d.pendingStart = &start
 }
 
+// pageLabel is the style and number of a physical page.
+type pageLabel struct {
+	style  pageNoStyle
+	number int
+}
+
+// labelPage labels the current physical page for PDF viewers when its
+// number does not follow on from the previous page's.
+func (d *StdDocument) labelPage(w Writer) {
+	label := pageLabel{d.pageNoStyle, d.documentPageNo}
+	prev := d.lastLabel
+	d.lastLabel = label
+	if label.style == prev.style && label.number == prev.number+1 {
+		return
+	}
+	if lw, ok := w.(PageLabelWriter); ok {
+		lw.BeginPageLabels(label.style.labelStyle(), label.style.prefix, label.number)
+	}
+}
+
 func (d *StdDocument) Print(w Writer) error {
 	d.applyWriterCompression(w)
+	d.applyWriterLanguage(w)
+	for _, a := range d.attachments {
+		if err := a.attach(w); err != nil {
+			return err
+		}
+	}
 	d.documentPageNo = 0
 	d.physicalPageNo = 0
 	d.pendingStart = nil
+	d.pageNoStyle = pageNoStyle{}
+	d.pendingStyle = nil
+	d.lastLabel = pageLabel{}
+	if tw, ok := w.(TagWriter); ok && d.tagged {
+		return withTag(tw, "Document", nil, func() error { return d.DrawContent(w) })
+	}
 	return d.DrawContent(w)
 }
 
@@ -86,6 +124,12 @@ func (d *StdDocument) SetAttrs(attrs map[string]string) {
 	if value, ok := attrs["compress-embedded-fonts"]; ok {
 		d.compressEmbeddedFonts = value == "true"
 	}
+	if value, ok := attrs["compress-objects"]; ok {
+		d.compressObjects = value == "true"
+	}
+	if value, ok := attrs["tagged"]; ok {
+		d.tagged = value == "true"
+	}
 }
 
 func (d *StdDocument) applyWriterCompression(w Writer) {
@@ -98,6 +142,15 @@ func (d *StdDocument) applyWriterCompression(w Writer) {
 	if cw, ok := w.(interface{ CompressEmbeddedFonts(bool) *pdf.DocWriter }); ok {
 		cw.CompressEmbeddedFonts(d.compressEmbeddedFonts)
 	}
+	if cw, ok := w.(interface{ CompressObjects(bool) *pdf.DocWriter }); ok {
+		cw.CompressObjects(d.compressObjects)
+	}
+}
+
+func (d *StdDocument) applyWriterLanguage(w Writer) {
+	if lw, ok := w.(interface{ SetLanguage(string) *pdf.DocWriter }); ok && d.lang != "" {
+		lw.SetLanguage(d.lang)
+	}
 }
 
 func (d *StdDocument) String() string {
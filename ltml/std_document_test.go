@@ -1,8 +1,10 @@
 package ltml
 
 import (
+	"math"
 	"testing"
 
+	"github.com/rowland/leadtype/options"
 	"github.com/rowland/leadtype/pdf"
 )
 
@@ -11,6 +13,7 @@ type compressionTestWriter struct {
 	compressPages         bool
 	compressToUnicode     bool
 	compressEmbeddedFonts bool
+	compressObjects       bool
 }
 
 func (w *compressionTestWriter) CompressPages(value bool) *pdf.DocWriter {
@@ -28,9 +31,14 @@ func (w *compressionTestWriter) CompressEmbeddedFonts(value bool) *pdf.DocWriter
 	return nil
 }
 
+func (w *compressionTestWriter) CompressObjects(value bool) *pdf.DocWriter {
+	w.compressObjects = value
+	return nil
+}
+
 func TestStdDocument_Print_AppliesCompressionAttrs(t *testing.T) {
 	doc, err := Parse([]byte(`
-<ltml compress-pages="true" compress-to-unicode="true" compress-embedded-fonts="true">
+<ltml compress-pages="true" compress-to-unicode="true" compress-embedded-fonts="true" compress-objects="true">
   <page><label>Hello</label></page>
 </ltml>`))
 	if err != nil {
@@ -41,9 +49,9 @@ func TestStdDocument_Print_AppliesCompressionAttrs(t *testing.T) {
 	if err := doc.Print(w); err != nil {
 		t.Fatal(err)
 	}
-	if !w.compressPages || !w.compressToUnicode || !w.compressEmbeddedFonts {
-		t.Fatalf("compression flags = pages:%t toUnicode:%t embedded:%t, want all true",
-			w.compressPages, w.compressToUnicode, w.compressEmbeddedFonts)
+	if !w.compressPages || !w.compressToUnicode || !w.compressEmbeddedFonts || !w.compressObjects {
+		t.Fatalf("compression flags = pages:%t toUnicode:%t embedded:%t objects:%t, want all true",
+			w.compressPages, w.compressToUnicode, w.compressEmbeddedFonts, w.compressObjects)
 	}
 }
 
@@ -60,8 +68,45 @@ func TestStdDocument_Print_DefaultCompressionAttrsFalse(t *testing.T) {
 	if err := doc.Print(w); err != nil {
 		t.Fatal(err)
 	}
-	if w.compressPages || w.compressToUnicode || w.compressEmbeddedFonts {
-		t.Fatalf("compression flags = pages:%t toUnicode:%t embedded:%t, want all false",
-			w.compressPages, w.compressToUnicode, w.compressEmbeddedFonts)
+	if w.compressPages || w.compressToUnicode || w.compressEmbeddedFonts || w.compressObjects {
+		t.Fatalf("compression flags = pages:%t toUnicode:%t embedded:%t objects:%t, want all false",
+			w.compressPages, w.compressToUnicode, w.compressEmbeddedFonts, w.compressObjects)
+	}
+}
+
+type pageSizeTestWriter struct {
+	labelTestWriter
+	pages []options.Options
+}
+
+func (w *pageSizeTestWriter) NewPageWithOptions(options options.Options) *pdf.PageWriter {
+	w.pages = append(w.pages, options)
+	return nil
+}
+
+func TestStdDocument_Print_PageSizes(t *testing.T) {
+	doc, err := Parse([]byte(`
+<ltml>
+  <page><label>Letter</label></page>
+  <page size="a5" orientation="landscape" bleed="3mm"><label>A5</label></page>
+  <page units="in" width="4" height="6" bleed="0.125"><label>Card</label></page>
+</ltml>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := &pageSizeTestWriter{labelTestWriter: labelTestWriter{t: t}}
+	if err := doc.Print(w); err != nil {
+		t.Fatal(err)
+	}
+	want := [][3]float64{{612, 792, 0}, {595, 420, 8.505}, {288, 432, 9}}
+	if len(w.pages) != len(want) {
+		t.Fatalf("pages = %d, want %d", len(w.pages), len(want))
+	}
+	for i, page := range w.pages {
+		got := [3]float64{page["page_width"].(float64), page["page_height"].(float64), page["bleed"].(float64)}
+		if math.Abs(got[0]-want[i][0]) > 1e-9 || math.Abs(got[1]-want[i][1]) > 1e-9 || math.Abs(got[2]-want[i][2]) > 1e-9 {
+			t.Errorf("page %d width, height, bleed = %v, want %v", i+1, got, want[i])
+		}
 	}
 }
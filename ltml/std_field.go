@@ -0,0 +1,325 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ltml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rowland/leadtype/options"
+)
+
+// FormWriter is implemented by writers that can place fillable form fields.
+// Field widgets draw nothing on writers that do not implement it.
+type FormWriter interface {
+	CheckBox(x, y, width, height float64, fieldName string, options options.Options) error
+	ComboBox(x, y, width, height float64, fieldName string, choices []string, options options.Options) error
+	ListBox(x, y, width, height float64, fieldName string, choices []string, options options.Options) error
+	RadioButton(x, y, width, height float64, groupName, value string, options options.Options) error
+	SignatureField(x, y, width, height float64, fieldName string, options options.Options) error
+	TextField(x, y, width, height float64, fieldName string, options options.Options) error
+}
+
+// StdField holds the attributes shared by the form field widgets.
+type StdField struct {
+	StdWidget
+	name     string
+	value    string
+	tooltip  string
+	readonly bool
+	required bool
+}
+
+func (f *StdField) SetAttrs(attrs map[string]string) {
+	f.StdWidget.SetAttrs(attrs)
+	if name, ok := attrs["name"]; ok {
+		f.name = name
+	}
+	if value, ok := attrs["value"]; ok {
+		f.value = value
+	}
+	if tooltip, ok := attrs["tooltip"]; ok {
+		f.tooltip = tooltip
+	}
+	if readonly, ok := attrs["readonly"]; ok {
+		f.readonly = readonly == "true"
+	}
+	if required, ok := attrs["required"]; ok {
+		f.required = required == "true"
+	}
+}
+
+func (f *StdField) String() string {
+	return fmt.Sprintf("name=%s value=%s %s", f.name, f.value, &f.StdWidget)
+}
+
+// fieldOptions returns the options common to every field. A widget with its
+// own border paints it on the page, so the field does not draw another.
+func (f *StdField) fieldOptions() options.Options {
+	opts := options.Options{
+		"readonly": f.readonly,
+		"required": f.required,
+	}
+	if f.tooltip != "" {
+		opts["tooltip"] = f.tooltip
+	}
+	if f.border != nil {
+		opts["border"] = false
+	}
+	return opts
+}
+
+// place calls fn with the form writer and the field's content box, first
+// applying the field's font so that field text is set in it.
+func (f *StdField) place(w Writer, fn func(fw FormWriter, x, y, width, height float64) error) error {
+	fw, ok := w.(FormWriter)
+	if !ok {
+		return nil
+	}
+	f.Font().Apply(w)
+	return fn(fw, ContentLeft(f), ContentTop(f), ContentWidth(f), ContentHeight(f))
+}
+
+// lineHeight is the height of one line of field text.
+func (f *StdField) lineHeight() float64 {
+	return f.Font().size * 1.5
+}
+
+func (f *StdField) preferredHeight(lines float64) float64 {
+	if f.height != 0 {
+		return f.height
+	}
+	return f.lineHeight()*lines + NonContentHeight(f)
+}
+
+func (f *StdField) preferredWidth(width float64) float64 {
+	if f.width != 0 {
+		return f.width
+	}
+	return width + NonContentWidth(f)
+}
+
+// splitList splits a comma-separated attribute value, trimming spaces.
+func splitList(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	items := strings.Split(s, ",")
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+	return items
+}
+
+// StdTextField is a fillable text field, <textfield>.
+type StdTextField struct {
+	StdField
+	multiline bool
+	password  bool
+	maxLength int
+	textAlign string
+}
+
+func (tf *StdTextField) DrawContent(w Writer) error {
+	return tf.place(w, func(fw FormWriter, x, y, width, height float64) error {
+		opts := tf.fieldOptions()
+		opts["value"] = tf.value
+		opts["multiline"] = tf.multiline
+		opts["password"] = tf.password
+		opts["max_length"] = tf.maxLength
+		opts["align"] = tf.textAlign
+		return fw.TextField(x, y, width, height, tf.name, opts)
+	})
+}
+
+func (tf *StdTextField) PreferredHeight(Writer) float64 {
+	if tf.multiline {
+		return tf.preferredHeight(3)
+	}
+	return tf.preferredHeight(1)
+}
+
+func (tf *StdTextField) PreferredWidth(Writer) float64 {
+	return tf.preferredWidth(144)
+}
+
+func (tf *StdTextField) SetAttrs(attrs map[string]string) {
+	tf.StdField.SetAttrs(attrs)
+	if multiline, ok := attrs["multiline"]; ok {
+		tf.multiline = multiline == "true"
+	}
+	if password, ok := attrs["password"]; ok {
+		tf.password = password == "true"
+	}
+	if maxLength, ok := attrs["max-length"]; ok {
+		tf.maxLength, _ = strconv.Atoi(maxLength)
+	}
+	if textAlign, ok := attrs["text-align"]; ok {
+		tf.textAlign = textAlign
+	}
+}
+
+func (tf *StdTextField) String() string {
+	return fmt.Sprintf("StdTextField multiline=%t %s", tf.multiline, &tf.StdField)
+}
+
+// StdCheckBox is a check box, <checkbox>. Its value is the export value
+// given to the field when checked.
+type StdCheckBox struct {
+	StdField
+	checked bool
+}
+
+func (cb *StdCheckBox) DrawContent(w Writer) error {
+	return cb.place(w, func(fw FormWriter, x, y, width, height float64) error {
+		opts := cb.fieldOptions()
+		opts["checked"] = cb.checked
+		if cb.value != "" {
+			opts["export"] = cb.value
+		}
+		x, y, size := squareBox(x, y, width, height)
+		return fw.CheckBox(x, y, size, size, cb.name, opts)
+	})
+}
+
+func (cb *StdCheckBox) PreferredHeight(Writer) float64 {
+	if cb.height != 0 {
+		return cb.height
+	}
+	return cb.Font().size + NonContentHeight(cb)
+}
+
+func (cb *StdCheckBox) PreferredWidth(Writer) float64 {
+	return cb.preferredWidth(cb.Font().size)
+}
+
+func (cb *StdCheckBox) SetAttrs(attrs map[string]string) {
+	cb.StdField.SetAttrs(attrs)
+	if checked, ok := attrs["checked"]; ok {
+		cb.checked = checked == "true"
+	}
+}
+
+func (cb *StdCheckBox) String() string {
+	return fmt.Sprintf("StdCheckBox checked=%t %s", cb.checked, &cb.StdField)
+}
+
+// squareBox returns the largest square at the left of the given box,
+// centered vertically, in which check boxes and radio buttons are drawn.
+func squareBox(x, y, width, height float64) (float64, float64, float64) {
+	size := min(width, height)
+	return x, y + (height-size)/2, size
+}
+
+// StdRadioButton is one button of a radio group, <radio>. Buttons sharing a
+// name form a group whose value is that of the selected button.
+type StdRadioButton struct {
+	StdCheckBox
+}
+
+func (rb *StdRadioButton) DrawContent(w Writer) error {
+	return rb.place(w, func(fw FormWriter, x, y, width, height float64) error {
+		opts := rb.fieldOptions()
+		opts["checked"] = rb.checked
+		x, y, size := squareBox(x, y, width, height)
+		return fw.RadioButton(x, y, size, size, rb.name, rb.value, opts)
+	})
+}
+
+func (rb *StdRadioButton) String() string {
+	return fmt.Sprintf("StdRadioButton checked=%t %s", rb.checked, &rb.StdField)
+}
+
+// StdChoiceField is a combo box, <combobox>, or list box, <listbox>,
+// offering the comma-separated choices of its "choices" attribute.
+type StdChoiceField struct {
+	StdField
+	list        bool
+	choices     []string
+	editable    bool
+	multiselect bool
+}
+
+func (cf *StdChoiceField) DrawContent(w Writer) error {
+	return cf.place(w, func(fw FormWriter, x, y, width, height float64) error {
+		opts := cf.fieldOptions()
+		if !cf.list {
+			opts["value"] = cf.value
+			opts["editable"] = cf.editable
+			return fw.ComboBox(x, y, width, height, cf.name, cf.choices, opts)
+		}
+		opts["value"] = splitList(cf.value)
+		opts["multiselect"] = cf.multiselect
+		return fw.ListBox(x, y, width, height, cf.name, cf.choices, opts)
+	})
+}
+
+func (cf *StdChoiceField) PreferredHeight(Writer) float64 {
+	if cf.list {
+		return cf.preferredHeight(float64(max(len(cf.choices), 1)))
+	}
+	return cf.preferredHeight(1)
+}
+
+func (cf *StdChoiceField) PreferredWidth(Writer) float64 {
+	return cf.preferredWidth(144)
+}
+
+func (cf *StdChoiceField) SetAttrs(attrs map[string]string) {
+	cf.StdField.SetAttrs(attrs)
+	if choices, ok := attrs["choices"]; ok {
+		cf.choices = splitList(choices)
+	}
+	if editable, ok := attrs["editable"]; ok {
+		cf.editable = editable == "true"
+	}
+	if multiselect, ok := attrs["multiselect"]; ok {
+		cf.multiselect = multiselect == "true"
+	}
+}
+
+func (cf *StdChoiceField) String() string {
+	return fmt.Sprintf("StdChoiceField list=%t choices=%v %s", cf.list, cf.choices, &cf.StdField)
+}
+
+// StdSignatureField is an unsigned signature field, <signature>.
+type StdSignatureField struct {
+	StdField
+}
+
+func (sf *StdSignatureField) DrawContent(w Writer) error {
+	return sf.place(w, func(fw FormWriter, x, y, width, height float64) error {
+		return fw.SignatureField(x, y, width, height, sf.name, sf.fieldOptions())
+	})
+}
+
+func (sf *StdSignatureField) PreferredHeight(Writer) float64 {
+	return sf.preferredHeight(3)
+}
+
+func (sf *StdSignatureField) PreferredWidth(Writer) float64 {
+	return sf.preferredWidth(216)
+}
+
+func (sf *StdSignatureField) String() string {
+	return fmt.Sprintf("StdSignatureField %s", &sf.StdField)
+}
+
+func init() {
+	registerTag(DefaultSpace, "textfield", func() any { return &StdTextField{} })
+	registerTag(DefaultSpace, "checkbox", func() any { return &StdCheckBox{} })
+	registerTag(DefaultSpace, "radio", func() any { return &StdRadioButton{} })
+	registerTag(DefaultSpace, "combobox", func() any { return &StdChoiceField{} })
+	registerTag(DefaultSpace, "listbox", func() any { return &StdChoiceField{list: true} })
+	registerTag(DefaultSpace, "signature", func() any { return &StdSignatureField{} })
+}
+
+var _ HasAttrs = (*StdTextField)(nil)
+var _ HasAttrs = (*StdCheckBox)(nil)
+var _ HasAttrs = (*StdRadioButton)(nil)
+var _ HasAttrs = (*StdChoiceField)(nil)
+var _ HasAttrs = (*StdSignatureField)(nil)
+var _ Printer = (*StdTextField)(nil)
+var _ WantsContainer = (*StdTextField)(nil)
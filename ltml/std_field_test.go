@@ -0,0 +1,139 @@
+package ltml
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rowland/leadtype/options"
+)
+
+type fieldCall struct {
+	kind    string
+	name    string
+	value   string
+	choices []string
+	x, y    float64
+	width   float64
+	height  float64
+	options options.Options
+}
+
+type formTestWriter struct {
+	labelTestWriter
+	fields []fieldCall
+}
+
+func (w *formTestWriter) add(kind string, x, y, width, height float64, name, value string, choices []string, options options.Options) error {
+	w.fields = append(w.fields, fieldCall{kind, name, value, choices, x, y, width, height, options})
+	return nil
+}
+
+func (w *formTestWriter) CheckBox(x, y, width, height float64, fieldName string, options options.Options) error {
+	return w.add("checkbox", x, y, width, height, fieldName, "", nil, options)
+}
+
+func (w *formTestWriter) ComboBox(x, y, width, height float64, fieldName string, choices []string, options options.Options) error {
+	return w.add("combobox", x, y, width, height, fieldName, "", choices, options)
+}
+
+func (w *formTestWriter) ListBox(x, y, width, height float64, fieldName string, choices []string, options options.Options) error {
+	return w.add("listbox", x, y, width, height, fieldName, "", choices, options)
+}
+
+func (w *formTestWriter) RadioButton(x, y, width, height float64, groupName, value string, options options.Options) error {
+	return w.add("radio", x, y, width, height, groupName, value, nil, options)
+}
+
+func (w *formTestWriter) SignatureField(x, y, width, height float64, fieldName string, options options.Options) error {
+	return w.add("signature", x, y, width, height, fieldName, "", nil, options)
+}
+
+func (w *formTestWriter) TextField(x, y, width, height float64, fieldName string, options options.Options) error {
+	return w.add("textfield", x, y, width, height, fieldName, "", nil, options)
+}
+
+func TestStdFields_Print(t *testing.T) {
+	doc, err := Parse([]byte(`
+<ltml>
+  <page margin="1in">
+    <textfield name="full-name" value="Jane" required="true" width="3in" />
+    <checkbox name="agree" value="On" checked="true" />
+    <radio name="plan" value="basic" />
+    <radio name="plan" value="pro" checked="true" />
+    <combobox name="state" choices="CA, NY" value="NY" editable="true" />
+    <listbox name="colors" choices="Red,Green,Blue" value="Red,Blue" multiselect="true" />
+    <signature name="signature" tooltip="Sign here" />
+  </page>
+</ltml>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &formTestWriter{labelTestWriter: labelTestWriter{t: t}}
+	if err := doc.Print(w); err != nil {
+		t.Fatal(err)
+	}
+
+	var kinds []string
+	for _, f := range w.fields {
+		kinds = append(kinds, f.kind+":"+f.name)
+	}
+	want := []string{"textfield:full-name", "checkbox:agree", "radio:plan", "radio:plan", "combobox:state", "listbox:colors", "signature:signature"}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Fatalf("fields = %v, want %v", kinds, want)
+	}
+
+	text := w.fields[0]
+	if text.x != 72 || text.y != 72 || text.width != 216 {
+		t.Errorf("textfield box = %v,%v %v wide, want 72,72 216 wide", text.x, text.y, text.width)
+	}
+	if text.options["value"] != "Jane" || text.options["required"] != true {
+		t.Errorf("textfield options = %v", text.options)
+	}
+	if w.fields[1].options["export"] != "On" || w.fields[1].options["checked"] != true {
+		t.Errorf("checkbox options = %v", w.fields[1].options)
+	}
+	if w.fields[2].value != "basic" || w.fields[3].options["checked"] != true {
+		t.Errorf("radio buttons = %+v", w.fields[2:4])
+	}
+	if !reflect.DeepEqual(w.fields[4].choices, []string{"CA", "NY"}) || w.fields[4].options["editable"] != true {
+		t.Errorf("combobox = %+v", w.fields[4])
+	}
+	if !reflect.DeepEqual(w.fields[5].options["value"], []string{"Red", "Blue"}) || w.fields[5].options["multiselect"] != true {
+		t.Errorf("listbox options = %v", w.fields[5].options)
+	}
+	if w.fields[6].options["tooltip"] != "Sign here" {
+		t.Errorf("signature options = %v", w.fields[6].options)
+	}
+}
+
+func TestStdFields_PreferredSize(t *testing.T) {
+	doc, err := Parse([]byte(`
+<ltml>
+  <page>
+    <textfield name="a" font.size="10" />
+    <textfield name="b" font.size="10" multiline="true" />
+    <listbox name="c" font.size="10" choices="x,y,z,w" />
+  </page>
+</ltml>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := doc.ltmls[0].Page(0)
+	w := &formTestWriter{labelTestWriter: labelTestWriter{t: t}}
+	for i, want := range []float64{15, 45, 60} {
+		got := page.Widgets()[i].PreferredHeight(w)
+		if got != want {
+			t.Errorf("field %d PreferredHeight() = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestStdFields_IgnoredWithoutFormWriter(t *testing.T) {
+	doc, err := Parse([]byte(`<ltml><page><textfield name="a" /></page></ltml>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Print(&labelTestWriter{t: t}); err != nil {
+		t.Fatal(err)
+	}
+}
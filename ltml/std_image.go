@@ -5,17 +5,31 @@ package ltml
 
 import (
 	"fmt"
+	"strconv"
 )
 
+// PDFPageWriter is implemented by writers that can place pages of PDF files
+// as images. Without it, an image's page attribute is ignored and the first
+// page is placed, if the writer can place PDF files at all.
+type PDFPageWriter interface {
+	PDFPageDimensionsFromFile(filename string, page int) (width, height float64, err error)
+	PrintPDFPageFile(filename string, page int, x, y float64, width, height *float64) (actualWidth, actualHeight float64, err error)
+}
+
 type StdImage struct {
 	StdWidget
-	src string
+	src  string
+	page int
 }
 
 func (img *StdImage) DrawContent(w Writer) error {
 	if img.src == "" {
 		return fmt.Errorf("image src must be specified")
 	}
+	if pw, ok := w.(PDFPageWriter); ok && img.page > 0 {
+		_, _, err := pw.PrintPDFPageFile(img.src, img.page, ContentLeft(img), ContentTop(img), img.widthForWriter(), img.heightForWriter())
+		return err
+	}
 	_, _, err := w.PrintImageFile(img.src, ContentLeft(img), ContentTop(img), img.widthForWriter(), img.heightForWriter())
 	return err
 }
@@ -29,9 +43,9 @@ func (img *StdImage) PreferredHeight(w Writer) float64 {
 		return NonContentHeight(img)
 	}
 	if img.width != 0 {
-		return img.width*float64(infoHeight)/float64(infoWidth) + NonContentHeight(img)
+		return img.width*infoHeight/infoWidth + NonContentHeight(img)
 	}
-	return float64(infoHeight) + NonContentHeight(img)
+	return infoHeight + NonContentHeight(img)
 }
 
 func (img *StdImage) PreferredWidth(w Writer) float64 {
@@ -43,13 +57,24 @@ func (img *StdImage) PreferredWidth(w Writer) float64 {
 		return NonContentWidth(img)
 	}
 	if img.height != 0 {
-		return img.height*float64(infoWidth)/float64(infoHeight) + NonContentWidth(img)
+		return img.height*infoWidth/infoHeight + NonContentWidth(img)
+	}
+	return infoWidth + NonContentWidth(img)
+}
+
+func (img *StdImage) imageDimensions(w Writer) (width, height float64, err error) {
+	if pw, ok := w.(PDFPageWriter); ok && img.page > 0 {
+		return pw.PDFPageDimensionsFromFile(img.src, img.page)
 	}
-	return float64(infoWidth) + NonContentWidth(img)
+	iw, ih, err := w.ImageDimensionsFromFile(img.src)
+	return float64(iw), float64(ih), err
 }
 
-func (img *StdImage) imageDimensions(w Writer) (width, height int, err error) {
-	return w.ImageDimensionsFromFile(img.src)
+func (img *StdImage) Role() string {
+	if img.role != "" {
+		return img.role
+	}
+	return "Figure"
 }
 
 func (img *StdImage) SetAttrs(attrs map[string]string) {
@@ -57,6 +82,9 @@ func (img *StdImage) SetAttrs(attrs map[string]string) {
 	if src, ok := attrs["src"]; ok {
 		img.src = src
 	}
+	if page, ok := attrs["page"]; ok {
+		img.page, _ = strconv.Atoi(page)
+	}
 }
 
 func (img *StdImage) String() string {
@@ -122,3 +122,39 @@ func TestParse_ImageTag(t *testing.T) {
 		t.Fatalf("src = %q, want %q", img.src, "../pdf/testdata/testimg.jpg")
 	}
 }
+
+type pdfPageTestWriter struct {
+	imageTestWriter
+	pages []int
+}
+
+func (w *pdfPageTestWriter) PDFPageDimensionsFromFile(filename string, page int) (width, height float64, err error) {
+	if page == 2 {
+		return 792, 612, nil
+	}
+	return 612, 792, nil
+}
+
+func (w *pdfPageTestWriter) PrintPDFPageFile(filename string, page int, x, y float64, width, height *float64) (actualWidth, actualHeight float64, err error) {
+	w.pages = append(w.pages, page)
+	return 0, 0, nil
+}
+
+func TestStdImage_Page_UsesPDFPageWriter(t *testing.T) {
+	w := &pdfPageTestWriter{}
+	img := &StdImage{}
+	img.SetAttrs(map[string]string{"src": "letterhead.pdf", "page": "2", "width": "396"})
+
+	if got := img.PreferredHeight(w); got != 306 {
+		t.Fatalf("PreferredHeight = %v, want 306", got)
+	}
+	if err := img.DrawContent(w); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.pages) != 1 || w.pages[0] != 2 {
+		t.Fatalf("pages = %v, want [2]", w.pages)
+	}
+	if len(w.calls) != 0 {
+		t.Fatalf("PrintImageFile calls = %d, want 0", len(w.calls))
+	}
+}
@@ -9,16 +9,18 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/rowland/leadtype/bidi"
 	"github.com/rowland/leadtype/rich_text"
 )
 
 type StdLabel struct {
 	StdContainer
-	textPieces  []textPiece
-	richText    *rich_text.RichText
-	shrinkToFit bool
-	angle       float64
-	textAlign   HAlign
+	textPieces   []textPiece
+	richText     *rich_text.RichText
+	shrinkToFit  bool
+	angle        float64
+	textAlign    HAlign
+	textAlignSet bool
 }
 
 func (l *StdLabel) AddText(text string) {
@@ -87,6 +89,7 @@ func (l *StdLabel) DrawContent(w Writer) error {
 		return nil
 	}
 	l.Font().Apply(w)
+	rt = rt.Reorder(l.direction(rt))
 	anchorX, anchorY := l.textAnchor(rt)
 	startX := anchorX - l.textAnchorOffset(rt)
 	if l.angle == 0 {
@@ -160,14 +163,22 @@ func (l *StdLabel) RichText(w Writer) *rich_text.RichText {
 	return rt
 }
 
+func (l *StdLabel) Role() string {
+	if l.role != "" || len(l.textPieces) == 0 {
+		return l.role
+	}
+	return "P"
+}
+
 func (l *StdLabel) SetAttrs(attrs map[string]string) {
 	l.StdContainer.SetAttrs(attrs)
 	l.shrinkToFit = attrs["fit"] == "shrink"
 	if angle, ok := attrs["angle"]; ok {
 		l.angle, _ = strconv.ParseFloat(angle, 64)
 	}
-	l.textAlign = HAlignLeft
+	l.textAlign, l.textAlignSet = HAlignLeft, false
 	if textAlign, ok := attrs["text-align"]; ok {
+		l.textAlignSet = true
 		switch textAlign {
 		case "center":
 			l.textAlign = HAlignCenter
@@ -202,8 +213,27 @@ func (l *StdLabel) hasDynamicText() bool {
 	return false
 }
 
-func (l *StdLabel) textAnchorX() float64 {
-	switch l.textAlign {
+// direction returns the direction of the label's text, resolving "auto" from
+// its first strong character.
+func (l *StdLabel) direction(rt *rich_text.RichText) bidi.Direction {
+	dir := bidi.ParseDirection(l.Dir())
+	if dir == bidi.Auto {
+		dir = bidi.BaseDirection(rt.String())
+	}
+	return dir
+}
+
+// alignment returns the label's text-align, or right for right-to-left text
+// without one.
+func (l *StdLabel) alignment(rt *rich_text.RichText) HAlign {
+	if !l.textAlignSet && l.direction(rt) == bidi.RightToLeft {
+		return HAlignRight
+	}
+	return l.textAlign
+}
+
+func (l *StdLabel) textAnchorX(rt *rich_text.RichText) float64 {
+	switch l.alignment(rt) {
 	case HAlignCenter:
 		return (ContentLeft(l) + ContentRight(l)) / 2
 	case HAlignRight:
@@ -214,11 +244,11 @@ func (l *StdLabel) textAnchorX() float64 {
 }
 
 func (l *StdLabel) textAnchor(rt *rich_text.RichText) (x, y float64) {
-	return l.textAnchorX(), ContentTop(l) + rt.Ascent()
+	return l.textAnchorX(rt), ContentTop(l) + rt.Ascent()
 }
 
 func (l *StdLabel) textAnchorOffset(rt *rich_text.RichText) float64 {
-	switch l.textAlign {
+	switch l.alignment(rt) {
 	case HAlignCenter:
 		return rt.Width() / 2
 	case HAlignRight:
@@ -35,6 +35,7 @@ type labelTestWriter struct {
 	underline     bool
 	moves         [][2]float64
 	printed       []*rich_text.RichText
+	paragraphOpts []options.Options
 	printedPages  []int
 	plainPrinted  []string
 	plainPages    []int
@@ -93,6 +94,7 @@ func (w *labelTestWriter) PrintImageFile(filename string, x, y float64, width, h
 	return 0, 0, nil
 }
 func (w *labelTestWriter) PrintParagraph(para []*rich_text.RichText, opts options.Options) {
+	w.paragraphOpts = append(w.paragraphOpts, opts)
 	for _, line := range para {
 		w.printed = append(w.printed, line)
 		w.printedPages = append(w.printedPages, w.pageCount)
@@ -322,6 +324,57 @@ func TestStdLabel_DrawContent_TextAlignAffectsAnchor(t *testing.T) {
 	}
 }
 
+func TestStdLabel_DrawContent_RightToLeft(t *testing.T) {
+	cases := []struct {
+		name      string
+		textAlign string
+		wantStart func(*StdLabel, *rich_text.RichText) float64
+	}{
+		{
+			name: "default",
+			wantStart: func(l *StdLabel, rt *rich_text.RichText) float64 {
+				return ContentRight(l) - rt.Width()
+			},
+		},
+		{
+			name:      "left",
+			textAlign: "left",
+			wantStart: func(l *StdLabel, rt *rich_text.RichText) float64 {
+				return ContentLeft(l)
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			div := &StdContainer{dir: "rtl"}
+			l := &StdLabel{}
+			_ = l.SetContainer(div)
+			l.font = &FontStyle{id: "body", entries: []fontEntry{{name: "Helvetica"}}, size: 12}
+			if tc.textAlign != "" {
+				l.textAlign, l.textAlignSet = HAlignLeft, true
+			}
+			l.SetLeft(10)
+			l.SetTop(20)
+			l.SetWidth(100)
+			l.AddText("Hello, world.")
+
+			w := &labelTestWriter{t: t, fonts: defaultTestFonts(t), lineSpacing: 1.0}
+			rt := l.fittedRichText(w)
+
+			if err := l.DrawContent(w); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := w.printed[0].String(), ".Hello, world"; got != want {
+				t.Fatalf("printed text = %q, want %q", got, want)
+			}
+			if want := tc.wantStart(l, rt); math.Abs(w.moves[0][0]-want) > 0.001 {
+				t.Fatalf("move x = %v, want %v", w.moves[0][0], want)
+			}
+		})
+	}
+}
+
 func TestStdLabel_DrawContent_ShrinksToFitWidth(t *testing.T) {
 	l := &StdLabel{}
 	l.font = &FontStyle{id: "body", entries: []fontEntry{{name: "Helvetica"}}, size: 12}
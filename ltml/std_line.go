@@ -51,6 +51,13 @@ func (l *StdLine) PreferredWidth(Writer) float64 {
 	return math.Abs(math.Cos(degreesToRadians(l.Angle())))*l.Length() + NonContentWidth(l)
 }
 
+func (l *StdLine) Role() string {
+	if l.role != "" {
+		return l.role
+	}
+	return RoleArtifact
+}
+
 func (l *StdLine) SetAttrs(attrs map[string]string) {
 	l.StdWidget.SetAttrs(attrs)
 	if angle, ok := attrs["angle"]; ok {
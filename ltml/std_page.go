@@ -24,6 +24,7 @@ type StdPage struct {
 	flowPageIndex  int
 	flowItems      []*pageItem
 	activeChildren []Widget
+	templates      map[Widget]pageTemplate
 }
 
 type pageItem struct {
@@ -169,11 +170,31 @@ func (p *StdPage) RightIsSet() bool {
 
 var reMargin = regexp.MustCompile(`^margin(-top|-right|-bottom|-left)?$`)
 
+// pageStyleAttrs are the attributes of a page that change its page style.
+var pageStyleAttrs = []string{"size", "orientation", "width", "height", "bleed"}
+
 func (p *StdPage) SetAttrs(attrs map[string]string) {
 	p.StdContainer.SetAttrs(attrs)
 	if style, ok := attrs["style"]; ok {
 		p.pageStyle = PageStyleFor(style, p.scope)
 	}
+	styleAttrs := make(map[string]string)
+	for _, name := range pageStyleAttrs {
+		if value, ok := attrs[name]; ok {
+			styleAttrs[name] = value
+		}
+	}
+	if len(styleAttrs) > 0 {
+		// Measurements are in the page's units; the page style's are points.
+		for _, name := range []string{"width", "height", "bleed"} {
+			if value, ok := styleAttrs[name]; ok {
+				styleAttrs[name] = fmt.Sprintf("%gpt", ParseMeasurement(value, p.Units()))
+			}
+		}
+		ps := *p.PageStyle()
+		ps.SetAttrs(styleAttrs)
+		p.pageStyle = &ps
+	}
 	if grid, ok := attrs["grid"]; ok {
 		switch grid {
 		case "", "false":
@@ -281,7 +302,7 @@ func (p *StdPage) drawVisibleChildren(w Writer) (int, error) {
 			continue
 		}
 		wasPrinted := child.Printed()
-		if err := Print(child, w); err != nil {
+		if err := p.printChild(child, w); err != nil {
 			return printedOnce, err
 		}
 		if item := p.pageItemForCurrent(child); item != nil {
@@ -343,10 +364,14 @@ func (p *StdPage) preparePhysicalPage(w Writer, force bool) error {
 	doc := p.document()
 	var savedDocPageNo, savedPhysicalPageNo int
 	var savedPendingStart *int
+	var savedStyle pageNoStyle
+	var savedPendingStyle *pageNoStyle
 	if doc != nil {
 		savedDocPageNo = doc.documentPageNo
 		savedPhysicalPageNo = doc.physicalPageNo
 		savedPendingStart = doc.pendingStart
+		savedStyle = doc.pageNoStyle
+		savedPendingStyle = doc.pendingStyle
 		if start, ok := p.firstPageNoStartForRender(); ok {
 			doc.SetPendingStart(start)
 		}
@@ -354,13 +379,20 @@ func (p *StdPage) preparePhysicalPage(w Writer, force bool) error {
 			doc.SetCurrentPageStart(*doc.pendingStart)
 			doc.pendingStart = nil
 		}
+		if doc.pendingStyle != nil {
+			doc.pageNoStyle = *doc.pendingStyle
+			doc.pendingStyle = nil
+		}
+		if pageNo := p.firstPageNoForRender((*StdPageNo).hasPageStyle); pageNo != nil {
+			doc.pageNoStyle = doc.pageNoStyle.with(pageNo)
+		}
 		doc.documentPageNo++
 		doc.physicalPageNo++
 	}
 
 	if force {
 		p.rebuildActiveChildren()
-		w.NewPage()
+		p.newPage(w)
 		LayoutContainer(p, w)
 	} else {
 		probe := newLayoutProbeWriter(w)
@@ -371,21 +403,37 @@ func (p *StdPage) preparePhysicalPage(w Writer, force bool) error {
 				doc.documentPageNo = savedDocPageNo
 				doc.physicalPageNo = savedPhysicalPageNo
 				doc.pendingStart = savedPendingStart
+				doc.pageNoStyle = savedStyle
+				doc.pendingStyle = savedPendingStyle
 			}
 			return errNoProgressPage
 		}
 		p.rebuildActiveChildren()
-		w.NewPage()
+		p.newPage(w)
 		LayoutContainer(p, w)
 	}
 	if doc != nil {
-		if reset, ok := p.firstPageNoResetForRender(); ok {
-			doc.SetPendingStart(reset)
+		if pageNo := p.firstPageNoForRender((*StdPageNo).hasReset); pageNo != nil {
+			doc.SetPendingStart(pageNo.reset)
+			if pageNo.hasStyle() {
+				style := doc.pageNoStyle.with(pageNo)
+				doc.pendingStyle = &style
+			}
 		}
+		doc.labelPage(w)
 	}
 	return nil
 }
 
+// newPage begins a physical page of the page's size, when the writer can.
+func (p *StdPage) newPage(w Writer) {
+	if sw, ok := w.(PageSizeWriter); ok {
+		sw.NewPageWithOptions(p.PageStyle().pageOptions())
+		return
+	}
+	w.NewPage()
+}
+
 func (p *StdPage) countVisibleOnceChildren() int {
 	count := 0
 	for _, child := range p.Widgets() {
@@ -396,32 +444,25 @@ func (p *StdPage) countVisibleOnceChildren() int {
 	return count
 }
 
-func (p *StdPage) firstPageNoResetForRender() (int, bool) {
-	var value int
-	var found bool
-	p.walkDisplayWidgets(p, func(widget Widget) bool {
-		if pageNo, ok := widget.(*StdPageNo); ok && pageNo.hasReset() {
-			value = pageNo.reset
-			found = true
-			return false
-		}
-		return true
-	})
-	return value, found
+func (p *StdPage) firstPageNoStartForRender() (int, bool) {
+	if pageNo := p.firstPageNoForRender((*StdPageNo).hasStart); pageNo != nil {
+		return pageNo.start, true
+	}
+	return 0, false
 }
 
-func (p *StdPage) firstPageNoStartForRender() (int, bool) {
-	var value int
-	var found bool
+// firstPageNoForRender returns the first <pageno> to be drawn on the page
+// for which match is true, if any.
+func (p *StdPage) firstPageNoForRender(match func(*StdPageNo) bool) *StdPageNo {
+	var found *StdPageNo
 	p.walkDisplayWidgets(p, func(widget Widget) bool {
-		if pageNo, ok := widget.(*StdPageNo); ok && pageNo.hasStart() {
-			value = pageNo.start
-			found = true
+		if pageNo, ok := widget.(*StdPageNo); ok && match(pageNo) {
+			found = pageNo
 			return false
 		}
 		return true
 	})
-	return value, found
+	return found
 }
 
 func (p *StdPage) walkDisplayWidgets(root Container, fn func(Widget) bool) bool {
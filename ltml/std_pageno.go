@@ -3,13 +3,59 @@ package ltml
 import (
 	"fmt"
 	"strconv"
+
+	"github.com/rowland/leadtype/pdf"
 )
 
+// PageLabelWriter is implemented by writers that label their pages for PDF
+// viewers, so viewers show the same page numbers that <pageno> prints.
+type PageLabelWriter interface {
+	BeginPageLabels(style pdf.PageLabelStyle, prefix string, start int) *pdf.DocWriter
+}
+
+// pageNoFormats maps <pageno> formats to page label styles.
+var pageNoFormats = map[string]pdf.PageLabelStyle{
+	"decimal":     pdf.PageLabelDecimal,
+	"roman":       pdf.PageLabelLowerRoman,
+	"lower-roman": pdf.PageLabelLowerRoman,
+	"upper-roman": pdf.PageLabelUpperRoman,
+	"alpha":       pdf.PageLabelLowerAlpha,
+	"lower-alpha": pdf.PageLabelLowerAlpha,
+	"upper-alpha": pdf.PageLabelUpperAlpha,
+}
+
+// pageNoStyle is how page numbers are printed and labeled: a prefix and a
+// format from pageNoFormats, where "" is decimal.
+type pageNoStyle struct {
+	format string
+	prefix string
+}
+
+// with returns the style with the format and prefix set by pageNo.
+func (s pageNoStyle) with(pageNo *StdPageNo) pageNoStyle {
+	if pageNo.format != nil {
+		s.format = *pageNo.format
+	}
+	if pageNo.prefix != nil {
+		s.prefix = *pageNo.prefix
+	}
+	return s
+}
+
+func (s pageNoStyle) labelStyle() pdf.PageLabelStyle {
+	if style, ok := pageNoFormats[s.format]; ok {
+		return style
+	}
+	return pdf.PageLabelDecimal
+}
+
 type StdPageNo struct {
 	StdSpan
 	start  int
 	reset  int
 	hidden bool
+	format *string
+	prefix *string
 }
 
 func (p *StdPageNo) AddText(text string) {
@@ -24,7 +70,11 @@ func (p *StdPageNo) Resolve(doc *StdDocument) string {
 	if p.hidden || doc == nil {
 		return ""
 	}
-	return formatPageNo(doc.CurrentPageNo())
+	style := doc.pageNoStyle
+	if p.hasPageStyle() {
+		style = style.with(p)
+	}
+	return formatPageNo(style, doc.CurrentPageNo())
 }
 
 func (p *StdPageNo) SetAttrs(attrs map[string]string) {
@@ -42,6 +92,14 @@ func (p *StdPageNo) SetAttrs(attrs map[string]string) {
 	if hidden, ok := attrs["hidden"]; ok {
 		p.hidden = hidden == "true"
 	}
+	if format, ok := attrs["format"]; ok {
+		if _, ok := pageNoFormats[format]; ok {
+			p.format = &format
+		}
+	}
+	if prefix, ok := attrs["prefix"]; ok {
+		p.prefix = &prefix
+	}
 }
 
 func (p *StdPageNo) SetContainer(container Container) error {
@@ -68,6 +126,16 @@ func (p *StdPageNo) hasStart() bool {
 	return p.start > 0
 }
 
+func (p *StdPageNo) hasStyle() bool {
+	return p.format != nil || p.prefix != nil
+}
+
+// hasPageStyle reports whether the page number sets the style of the page
+// it is on. With reset, it sets the style of the next page instead.
+func (p *StdPageNo) hasPageStyle() bool {
+	return p.hasStyle() && !p.hasReset()
+}
+
 func init() {
 	registerTag(DefaultSpace, "pageno", func() any { return &StdPageNo{} })
 }
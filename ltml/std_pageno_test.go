@@ -1,9 +1,12 @@
 package ltml
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/rowland/leadtype/ltml/ltpdf"
+	"github.com/rowland/leadtype/pdf"
 	"github.com/rowland/leadtype/rich_text"
 )
 
@@ -151,3 +154,46 @@ func TestStdPageNo_PageNumberSequence(t *testing.T) {
 		}
 	}
 }
+
+type pageLabelTestWriter struct {
+	labelTestWriter
+	labels []string
+}
+
+func (w *pageLabelTestWriter) BeginPageLabels(style pdf.PageLabelStyle, prefix string, start int) *pdf.DocWriter {
+	w.labels = append(w.labels, fmt.Sprintf("%d:%s%q%d", w.pageCount-1, style, prefix, start))
+	return nil
+}
+
+func TestStdPageNo_FormatsAndPageLabels(t *testing.T) {
+	doc, err := Parse([]byte(`
+<ltml>
+  <page><p>Cover</p></page>
+  <page><p>Page <pageno start="1" format="roman" /></p></page>
+  <page><p>Page <pageno /></p></page>
+  <page><p>Page <pageno /><pageno hidden="true" reset="1" format="decimal" prefix="A-" /></p></page>
+  <page><p>Page <pageno /></p></page>
+  <page><p>Page <pageno /></p></page>
+  <page><p>Page <pageno format="upper-alpha" prefix="" /></p></page>
+</ltml>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := &pageLabelTestWriter{labelTestWriter: labelTestWriter{t: t, fonts: defaultTestFonts(t), lineSpacing: 1.0}}
+	want := []string{"Cover", "Page i", "Page ii", "Page iii", "Page A-1", "Page A-2", "Page C"}
+	for i, expected := range want {
+		page := doc.ltmls[0].Page(i)
+		if err := page.BeforePrint(w); err != nil {
+			t.Fatal(err)
+		}
+		p := page.children[0].(*StdParagraph)
+		if got := p.RichText(w).String(); got != expected {
+			t.Fatalf("page %d text = %q, want %q", i+1, got, expected)
+		}
+	}
+	wantLabels := []string{`1:r""1`, `4:D"A-"1`, `6:A""3`}
+	if !reflect.DeepEqual(w.labels, wantLabels) {
+		t.Fatalf("page labels = %v, want %v", w.labels, wantLabels)
+	}
+}
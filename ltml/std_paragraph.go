@@ -121,10 +121,15 @@ func (p *StdParagraph) DrawContent(w Writer) error {
 		w.Print(b.Text())
 		w.MoveTo(x+b.Width(), y)
 	}
-	w.PrintParagraph(para, options.Options{
-		"text-align": p.ParagraphStyle().textAlign.String(),
-		"width":      ContentWidth(p) - indent,
-	})
+	opts := options.Options{
+		"dir":   p.Dir(),
+		"width": ContentWidth(p) - indent,
+	}
+	// Without an explicit text-align, PrintParagraph aligns right-to-left paragraphs right.
+	if ps := p.ParagraphStyle(); ps.textAlignSet {
+		opts["text-align"] = ps.textAlign.String()
+	}
+	w.PrintParagraph(para, opts)
 	return nil
 }
 
@@ -199,6 +204,13 @@ func (p *StdParagraph) hasDynamicText() bool {
 	return false
 }
 
+func (p *StdParagraph) Role() string {
+	if p.role != "" {
+		return p.role
+	}
+	return "P"
+}
+
 func (p *StdParagraph) SetAttrs(attrs map[string]string) {
 	p.StdContainer.SetAttrs(attrs)
 	p.splitEnabled = true
@@ -41,6 +41,57 @@ func TestStdParagraph_AddTextWithFont_PreservesSpanBoundarySpaces(t *testing.T)
 	}
 }
 
+func TestStdParagraph_DrawContent_Dir(t *testing.T) {
+	cases := []struct {
+		name          string
+		containerDir  string
+		paragraphDir  string
+		textAlign     string
+		wantDir       string
+		wantTextAlign any
+	}{
+		{name: "default", wantDir: "auto"},
+		{name: "inherited", containerDir: "rtl", wantDir: "rtl"},
+		{name: "own", containerDir: "rtl", paragraphDir: "ltr", wantDir: "ltr"},
+		{name: "text-align", containerDir: "rtl", textAlign: "left", wantDir: "rtl", wantTextAlign: "left"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			page := &StdPage{pageStyle: &PageStyle{width: 200, height: 200}}
+			page.layout = defaultLayouts["vbox"].Clone()
+			page.paragraphStyle = defaultParagraphStyle
+			page.dir = tc.containerDir
+
+			p := &StdParagraph{}
+			_ = p.SetContainer(page)
+			p.dir = tc.paragraphDir
+			if tc.textAlign != "" {
+				p.paragraphStyle = &ParagraphStyle{}
+				p.paragraphStyle.SetAttrs("", map[string]string{"text-align": tc.textAlign})
+			}
+			p.font = &FontStyle{id: "body", entries: []fontEntry{{name: "Helvetica"}}, size: 12}
+			p.SetWidth(90)
+			p.AddText("Hello")
+
+			w := &labelTestWriter{t: t, fonts: defaultTestFonts(t), lineSpacing: 1.0}
+			if err := p.DrawContent(w); err != nil {
+				t.Fatal(err)
+			}
+			if len(w.paragraphOpts) != 1 {
+				t.Fatalf("PrintParagraph count = %d, want 1", len(w.paragraphOpts))
+			}
+			opts := w.paragraphOpts[0]
+			if got := opts["dir"]; got != tc.wantDir {
+				t.Errorf("dir = %v, want %v", got, tc.wantDir)
+			}
+			if got := opts["text-align"]; got != tc.wantTextAlign {
+				t.Errorf("text-align = %v, want %v", got, tc.wantTextAlign)
+			}
+		})
+	}
+}
+
 func TestStdParagraph_SplitForHeight_RespectsDefaultsAndSuppressesBullet(t *testing.T) {
 	page := &StdPage{pageStyle: &PageStyle{width: 200, height: 200}}
 	page.layout = defaultLayouts["vbox"].Clone()
@@ -92,6 +92,13 @@ func (p *StdPre) PreferredWidth(w Writer) float64 {
 	return maxWidth + NonContentWidth(p)
 }
 
+func (p *StdPre) Role() string {
+	if p.role != "" {
+		return p.role
+	}
+	return "P"
+}
+
 func (p *StdPre) SetAttrs(attrs map[string]string) {
 	p.StdWidget.SetAttrs(attrs)
 }
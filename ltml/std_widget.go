@@ -30,6 +30,9 @@ type StdWidget struct {
 	shiftY    float64
 	zIndex    int
 	display   DisplayMode
+	role      string
+	alt       string
+	lang      string
 	printed   bool
 	invisible bool
 	disabled  bool
@@ -40,6 +43,10 @@ func (widget *StdWidget) Align() Align {
 	return widget.align
 }
 
+func (widget *StdWidget) Alt() string {
+	return widget.alt
+}
+
 func (widget *StdWidget) BeforePrint(Writer) error {
 	// to be overridden
 	return nil
@@ -109,6 +116,10 @@ func (widget *StdWidget) Font() *FontStyle {
 	return widget.font
 }
 
+func (widget *StdWidget) Lang() string {
+	return widget.lang
+}
+
 func (widget *StdWidget) LayoutWidget(w Writer) {
 	// to be overridden
 }
@@ -245,6 +256,22 @@ func (widget *StdWidget) SetAttrs(attrs map[string]string) {
 	if display, ok := attrs["display"]; ok {
 		widget.display = ParseDisplayMode(display)
 	}
+	if role, ok := attrs["role"]; ok {
+		widget.role = role
+	}
+	if alt, ok := attrs["alt"]; ok {
+		widget.alt = alt
+	}
+	if lang, ok := attrs["lang"]; ok {
+		widget.lang = lang
+	}
+}
+
+// Role returns the structure type given by the widget's "role" attribute.
+// Widgets without one are not tagged, and their content belongs to the
+// nearest tagged container.
+func (widget *StdWidget) Role() string {
+	return widget.role
 }
 
 func (widget *StdWidget) SetContainer(container Container) error {
@@ -0,0 +1,87 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ltml
+
+import (
+	"github.com/rowland/leadtype/options"
+)
+
+// TagWriter is implemented by writers that can produce tagged PDF. Once a
+// document has begun tagging, each widget is printed inside the structure
+// element for its role.
+type TagWriter interface {
+	BeginArtifact(options options.Options) error
+	BeginTag(role string, options options.Options) error
+	EndArtifact() error
+	EndTag() error
+	Tagged() bool
+}
+
+// RoleArtifact is the role of widgets whose content is decorative rather
+// than part of the document's logical structure.
+const RoleArtifact = "Artifact"
+
+// tagWriterFor returns w as a TagWriter if it is tagging the document.
+func tagWriterFor(w Writer) TagWriter {
+	if tw, ok := w.(TagWriter); ok && tw.Tagged() {
+		return tw
+	}
+	return nil
+}
+
+// withTag calls fn inside a structure element of the given role. It just
+// calls fn when tw is nil or role is "".
+func withTag(tw TagWriter, role string, options options.Options, fn func() error) error {
+	if tw == nil || role == "" {
+		return fn()
+	}
+	if role == RoleArtifact {
+		return withArtifact(tw, options, fn)
+	}
+	if err := tw.BeginTag(role, options); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	return tw.EndTag()
+}
+
+// withArtifact calls fn inside an artifact, unless tw is nil.
+func withArtifact(tw TagWriter, options options.Options, fn func() error) error {
+	if tw == nil {
+		return fn()
+	}
+	if err := tw.BeginArtifact(options); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	return tw.EndArtifact()
+}
+
+// printTagged calls fn, which prints widget, inside the structure element
+// for the widget's role. Widgets repeated from page to page, such as running
+// headers and footers, are pagination artifacts instead.
+func printTagged(tw TagWriter, widget Widget, fn func() error) error {
+	if tw == nil {
+		return fn()
+	}
+	if widget.Display() != DisplayOnce {
+		return withArtifact(tw, options.Options{"type": "Pagination"}, fn)
+	}
+	hr, ok := widget.(HasRole)
+	if !ok {
+		return fn()
+	}
+	opts := options.Options{}
+	if alt := hr.Alt(); alt != "" {
+		opts["alt"] = alt
+	}
+	if lang := hr.Lang(); lang != "" {
+		opts["lang"] = lang
+	}
+	return withTag(tw, hr.Role(), opts, fn)
+}
@@ -0,0 +1,100 @@
+package ltml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rowland/leadtype/options"
+)
+
+type tagTestWriter struct {
+	labelTestWriter
+	events []string
+	tagged bool
+}
+
+func (w *tagTestWriter) BeginArtifact(options options.Options) error {
+	w.events = append(w.events, "artifact:"+options.StringDefault("type", ""))
+	return nil
+}
+
+func (w *tagTestWriter) BeginTag(role string, options options.Options) error {
+	event := role
+	if alt := options.StringDefault("alt", ""); alt != "" {
+		event += "(" + alt + ")"
+	}
+	w.events = append(w.events, event)
+	w.tagged = true
+	return nil
+}
+
+func (w *tagTestWriter) EndArtifact() error {
+	w.events = append(w.events, "/artifact")
+	return nil
+}
+
+func (w *tagTestWriter) EndTag() error {
+	w.events = append(w.events, "/")
+	return nil
+}
+
+func (w *tagTestWriter) Tagged() bool {
+	return w.tagged
+}
+
+// structure returns the writer's tag events without the layout artifacts
+// wrapped around every widget's background and border.
+func (w *tagTestWriter) structure() string {
+	var events []string
+	for i := 0; i < len(w.events); i++ {
+		if w.events[i] == "artifact:Layout" && i+1 < len(w.events) && w.events[i+1] == "/artifact" {
+			i++
+			continue
+		}
+		events = append(events, w.events[i])
+	}
+	return strings.Join(events, " ")
+}
+
+func TestPrint_Tagged(t *testing.T) {
+	doc, err := Parse([]byte(`
+<ltml tagged="true">
+  <page margin="1in">
+    <label display="always">Header</label>
+    <h>Report</h>
+    <table cols="2" header-rows="1">
+      <label>Name</label><label>Qty</label>
+      <label>Apples</label><label>3</label>
+    </table>
+    <image src="logo.png" alt="Logo" width="1in" height="1in" />
+    <line length="1in" />
+  </page>
+</ltml>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &tagTestWriter{labelTestWriter: labelTestWriter{t: t}}
+	if err := doc.Print(w); err != nil {
+		t.Fatal(err)
+	}
+	want := "Document artifact:Pagination /artifact H / " +
+		"Table TR TH P / / TH P / / / TR TD P / / TD P / / / / " +
+		"Figure(Logo) / artifact: /artifact /"
+	if got := w.structure(); got != want {
+		t.Errorf("structure =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPrint_Untagged(t *testing.T) {
+	doc, err := Parse([]byte(`<ltml><page><p role="H1">Title</p></page></ltml>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &tagTestWriter{labelTestWriter: labelTestWriter{t: t}}
+	if err := doc.Print(w); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.events) != 0 {
+		t.Errorf("untagged document should not be tagged: %v", w.events)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ltml
+
+import (
+	"github.com/rowland/leadtype/options"
+	"github.com/rowland/leadtype/pdf"
+)
+
+// TemplateWriter is implemented by writers that can capture drawing once as
+// a template and place it many times. Widgets displayed on every page are
+// printed through a template when the writer implements it, so that their
+// content is written to the document only once.
+type TemplateWriter interface {
+	BeginTemplate(width, height float64) (*pdf.PageWriter, error)
+	EndTemplate() (string, error)
+	PrintTemplate(name string, x, y, scale float64) error
+}
+
+// pageTemplate is a template printed for a widget, with the box the widget
+// had and the size of the page it was printed for.
+type pageTemplate struct {
+	name string
+	key  [6]float64
+}
+
+// templateKey returns what must be unchanged for a widget's template to be
+// placed instead of printing the widget again.
+func (p *StdPage) templateKey(widget Widget) [6]float64 {
+	return [6]float64{widget.Left(), widget.Top(), widget.Width(), widget.Height(), p.Width(), p.Height()}
+}
+
+// templatable reports whether widget prints the same on every page: it and
+// everything in it are displayed always, and none of it is a page number
+// or a form field, which must be drawn on each page itself.
+func templatable(widget Widget) bool {
+	if widget.Display() != DisplayAlways {
+		return false
+	}
+	switch widget := widget.(type) {
+	case *StdPageNo, interface{ fieldOptions() options.Options }:
+		return false
+	case interface{ hasDynamicText() bool }:
+		if widget.hasDynamicText() {
+			return false
+		}
+	}
+	if container, ok := widget.(Container); ok {
+		for _, child := range container.Widgets() {
+			if !templatable(child) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// printChild prints a child of the page. A child displayed on every page is
+// printed into a template the first time and the template is placed after
+// that, for as long as the child keeps its place on the page.
+func (p *StdPage) printChild(child Widget, w Writer) error {
+	tw, ok := w.(TemplateWriter)
+	if !ok || !templatable(child) {
+		return Print(child, w)
+	}
+	key := p.templateKey(child)
+	t, ok := p.templates[child]
+	if !ok || t.key != key {
+		if _, err := tw.BeginTemplate(p.Width(), p.Height()); err != nil {
+			return err
+		}
+		err := Print(child, w)
+		name, err2 := tw.EndTemplate()
+		if err != nil {
+			return err
+		}
+		if err2 != nil {
+			return err2
+		}
+		t = pageTemplate{name: name, key: key}
+		if p.templates == nil {
+			p.templates = make(map[Widget]pageTemplate)
+		}
+		p.templates[child] = t
+	}
+	return printTagged(tagWriterFor(w), child, func() error {
+		return tw.PrintTemplate(t.name, 0, 0, 1)
+	})
+}
@@ -0,0 +1,46 @@
+package ltml
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rowland/leadtype/ltml/ltpdf"
+)
+
+func TestPrint_AlwaysPrintedThroughTemplate(t *testing.T) {
+	body := strings.Repeat("<label>Line of body text</label>\n", 80)
+	doc, err := Parse([]byte(`
+<ltml compress-pages="false">
+  <page margin="1in">
+    <label display="always" align="top">Running header</label>
+    <label display="always" align="bottom">Page <pageno /></label>
+    ` + body + `
+  </page>
+</ltml>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := ltpdf.NewDocWriter()
+	if err := doc.Print(w); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := w.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	pages := strings.Count(out, "/Type /Page ")
+	if pages < 2 {
+		t.Fatalf("pages = %d, want at least 2", pages)
+	}
+	if got := strings.Count(out, "(Running header) Tj"); got != 1 {
+		t.Errorf("header drawn %d times, want 1", got)
+	}
+	if got := strings.Count(out, "/Tpl1 Do"); got != pages {
+		t.Errorf("header placed %d times, want %d", got, pages)
+	}
+	if strings.Contains(out, "/Tpl2 ") {
+		t.Error("footer with a page number should not be a template")
+	}
+}
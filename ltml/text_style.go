@@ -8,9 +8,10 @@ import (
 )
 
 type TextStyle struct {
-	id        string
-	textAlign HAlign
-	vAlign    VAlign
+	id           string
+	textAlign    HAlign
+	textAlignSet bool
+	vAlign       VAlign
 }
 
 func (ts *TextStyle) Apply(w Writer) {
@@ -28,13 +29,13 @@ func (ts *TextStyle) SetAttrs(prefix string, attrs map[string]string) {
 	if textAlign, ok := attrs[prefix+"text-align"]; ok {
 		switch textAlign {
 		case "left":
-			ts.textAlign = HAlignLeft
+			ts.textAlign, ts.textAlignSet = HAlignLeft, true
 		case "center":
-			ts.textAlign = HAlignCenter
+			ts.textAlign, ts.textAlignSet = HAlignCenter, true
 		case "right":
-			ts.textAlign = HAlignRight
+			ts.textAlign, ts.textAlignSet = HAlignRight, true
 		case "justify":
-			ts.textAlign = HAlignJustify
+			ts.textAlign, ts.textAlignSet = HAlignJustify, true
 		}
 	}
 	if vAlign, ok := attrs[prefix+"valign"]; ok {
@@ -21,6 +21,7 @@ var UnitConversions = map[Units]float64{
 	"pt": 1,
 	"in": 72,
 	"cm": 28.35,
+	"mm": 2.835,
 }
 
 func FromUnits(measurement float64, units Units) float64 {
@@ -1,5 +1,9 @@
 package ltml
 
+import (
+	"github.com/rowland/leadtype/options"
+)
+
 type Widget interface {
 	Printer
 
@@ -151,11 +155,15 @@ func Print(widget Widget, writer Writer) error {
 	if err := widget.BeforePrint(writer); err != nil {
 		return err
 	}
+	tagWriter := tagWriterFor(writer)
 	render := func() error {
-		if err := widget.PaintBackground(writer); err != nil {
-			return err
-		}
-		if err := widget.DrawBorder(writer); err != nil {
+		// Backgrounds and borders are layout artifacts when tagging.
+		if err := withArtifact(tagWriter, options.Options{"type": "Layout"}, func() error {
+			if err := widget.PaintBackground(writer); err != nil {
+				return err
+			}
+			return widget.DrawBorder(writer)
+		}); err != nil {
 			return err
 		}
 		if err := widget.DrawContent(writer); err != nil {
@@ -163,13 +171,14 @@ func Print(widget Widget, writer Writer) error {
 		}
 		return nil
 	}
-	if tw, ok := widget.(interface {
-		paintWithTransform(Writer, func() error) error
-	}); ok {
-		if err := tw.paintWithTransform(writer, render); err != nil {
-			return err
+	if err := printTagged(tagWriter, widget, func() error {
+		if tw, ok := widget.(interface {
+			paintWithTransform(Writer, func() error) error
+		}); ok {
+			return tw.paintWithTransform(writer, render)
 		}
-	} else if err := render(); err != nil {
+		return render()
+	}); err != nil {
 		return err
 	}
 	widget.SetPrinted(true)
@@ -0,0 +1,198 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/rowland/leadtype/options"
+)
+
+var errEmptyAttachmentName = errors.New("attachment name must not be empty")
+var errDuplicateAttachmentName = errors.New("attachment name already in use")
+
+// embeddedFiles is the name tree of the document's attached files (PDF spec
+// §7.7.4), mapping each file name to its file specification.
+type embeddedFiles struct {
+	dictionaryObject
+	specs map[string]*dictionaryObject
+}
+
+func newEmbeddedFiles(seq, gen int) *embeddedFiles {
+	ef := &embeddedFiles{specs: make(map[string]*dictionaryObject)}
+	ef.dictionaryObject.init(seq, gen)
+	return ef
+}
+
+func (ef *embeddedFiles) write(w io.Writer) {
+	names := make([]string, 0, len(ef.specs))
+	for fileName := range ef.specs {
+		names = append(names, fileName)
+	}
+	sort.Strings(names)
+	entries := make(array, 0, len(names)*2)
+	for _, fileName := range names {
+		entries = append(entries, textString(fileName), &indirectObjectRef{ef.specs[fileName]})
+	}
+	ef.dict["Names"] = entries
+	ef.dictionaryObject.write(w)
+}
+
+// AttachFile embeds data in the document as a file named fileName, shown in the
+// reader's attachments panel. Supported options are "mime_type", such as
+// "text/xml", "description", "mod_date", a time.Time, and "relationship",
+// the file's relationship to the document: "Source", "Data", "Alternative",
+// "Supplement" or "Unspecified". PDF/A-2b documents cannot have attachments;
+// in PDF/A-3b documents each attachment is associated with the document,
+// with a relationship of "Unspecified" unless one is given, and has a MIME
+// type and modification date, "application/octet-stream" and the document's
+// modification date or else the current time unless they are given.
+func (dw *DocWriter) AttachFile(fileName string, data []byte, options options.Options) error {
+	if fileName == "" {
+		return errEmptyAttachmentName
+	}
+	if dw.attachments != nil {
+		if _, ok := dw.attachments.specs[fileName]; ok {
+			return errDuplicateAttachmentName
+		}
+	}
+	spec, err := dw.embedFile(fileName, data, options)
+	if err != nil {
+		return err
+	}
+	if dw.attachments == nil {
+		dw.attachments = newEmbeddedFiles(dw.nextSeq(), 0)
+		dw.file.body.add(dw.attachments)
+		dw.catalog.dict["Names"] = dictionary{"EmbeddedFiles": &indirectObjectRef{dw.attachments}}
+	}
+	dw.attachments.specs[fileName] = spec
+	return nil
+}
+
+// AttachAssetFile attaches the file filename, read from the filesystem set
+// with SetAssetFS or, without one, from the operating system. The attachment
+// is named by the "name" option, or else by the file's base name; other
+// options are as for AttachFile.
+func (dw *DocWriter) AttachAssetFile(filename string, options options.Options) error {
+	data, err := dw.readAssetFile(filename)
+	if err != nil {
+		return err
+	}
+	return dw.AttachFile(options.StringDefault("name", filepath.Base(filename)), data, options)
+}
+
+// embedFile adds data as an embedded file stream and returns the file
+// specification referring to it.
+func (dw *DocWriter) embedFile(fileName string, data []byte, options options.Options) (*dictionaryObject, error) {
+	if dw.conformance == PDFA2B {
+		return nil, errPDFAAttachment
+	}
+	params := dictionary{"Size": integer(len(data))}
+	modDate, _ := options["mod_date"].(time.Time)
+	mimeType := options.StringDefault("mime_type", "")
+	if dw.conformance == PDFA3B {
+		// PDF/A-3 requires both of every embedded file.
+		if modDate.IsZero() {
+			modDate = dw.metadata.modDate
+		}
+		if modDate.IsZero() {
+			modDate = time.Now()
+		}
+		if mimeType == "" {
+			mimeType = "application/octet-stream"
+		}
+	}
+	if !modDate.IsZero() {
+		params["ModDate"] = str(pdfDate(modDate))
+	}
+	file := newStream(dw.nextSeq(), 0, data)
+	file.dict["Type"] = name("EmbeddedFile")
+	file.dict["Params"] = params
+	if mimeType != "" {
+		file.dict["Subtype"] = name(mimeType)
+	}
+	if err := file.compress(); err != nil {
+		return nil, err
+	}
+	dw.file.body.add(file)
+
+	spec := newDictionaryObject(dw.nextSeq(), 0)
+	spec.dict["Type"] = name("Filespec")
+	spec.dict["F"] = textString(fileName)
+	spec.dict["UF"] = textString(fileName)
+	spec.dict["EF"] = dictionary{"F": &indirectObjectRef{file}, "UF": &indirectObjectRef{file}}
+	if description := options.StringDefault("description", ""); description != "" {
+		spec.dict["Desc"] = textString(description)
+	}
+	relationship := options.StringDefault("relationship", "")
+	if relationship == "" && dw.conformance == PDFA3B {
+		relationship = "Unspecified"
+	}
+	if relationship != "" {
+		spec.dict["AFRelationship"] = name(relationship)
+		af, _ := dw.catalog.dict["AF"].(array)
+		dw.catalog.dict["AF"] = append(af, &indirectObjectRef{spec})
+	}
+	dw.file.body.add(spec)
+	dw.requireVersion(1.7)
+	return spec, nil
+}
+
+// FileAttachment embeds data as a file named fileName and places an icon
+// over the given rectangle that opens it. Options are as for AttachFile,
+// with the description also shown as the icon's tooltip, and "icon" names
+// the icon viewers may draw instead of the annotation's own appearance:
+// "PushPin" (the default), "Paperclip", "Graph" or "Tag". Files attached
+// this way belong to the page and are not in the document's name tree.
+func (pw *PageWriter) FileAttachment(x, y, width, height float64, fileName string, data []byte, options options.Options) error {
+	if fileName == "" {
+		return errEmptyAttachmentName
+	}
+	spec, err := pw.dw.embedFile(fileName, data, options)
+	if err != nil {
+		return err
+	}
+	rect := pw.annotRect(x, y, width, height)
+	a := newAnnotation(pw.dw.nextSeq(), 0, "FileAttachment", rect)
+	a.dict["FS"] = &indirectObjectRef{spec}
+	a.dict["Name"] = name(options.StringDefault("icon", "PushPin"))
+	a.dict["Contents"] = textString(options.StringDefault("description", fileName))
+	a.dict["F"] = integer(annotPrint)
+	iconWidth, iconHeight := rect.x2-rect.x1, rect.y2-rect.y1
+	icon := pw.dw.appearanceStream(iconWidth, iconHeight, attachmentIcon(iconWidth, iconHeight))
+	a.dict["AP"] = dictionary{"N": &indirectObjectRef{icon}}
+	pw.addAnnot(a)
+	return nil
+}
+
+// attachmentIcon draws the appearance of a file attachment: a sheet of
+// paper with a folded corner and lines of text, filling the icon's box.
+func attachmentIcon(width, height float64) []byte {
+	var buf bytes.Buffer
+	gw, mw := newGraphWriter(&buf), newMiscWriter(&buf)
+	fold := min(width, height) / 4
+	gw.setLineWidth(min(width, height) / 20)
+	mw.setGrayFill(1)
+	mw.setGrayStroke(0)
+	gw.moveTo(width*0.15, height*0.05)
+	gw.lineTo(width*0.85, height*0.05)
+	gw.lineTo(width*0.85, height*0.95-fold)
+	gw.lineTo(width*0.85-fold, height*0.95)
+	gw.lineTo(width*0.15, height*0.95)
+	gw.closePathFillAndStroke()
+	gw.moveTo(width*0.85-fold, height*0.95)
+	gw.lineTo(width*0.85-fold, height*0.95-fold)
+	gw.lineTo(width*0.85, height*0.95-fold)
+	for _, line := range []float64{0.3, 0.45, 0.6} {
+		gw.moveTo(width*0.3, height*line)
+		gw.lineTo(width*0.7, height*line)
+	}
+	gw.stroke()
+	return buf.Bytes()
+}
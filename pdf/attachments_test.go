@@ -0,0 +1,100 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/rowland/leadtype/options"
+)
+
+func TestDocWriter_AttachFile(t *testing.T) {
+	dw := NewDocWriter()
+	modDate := time.Date(2026, 3, 14, 9, 26, 53, 0, time.UTC)
+	err := dw.AttachFile("invoice.xml", []byte("<invoice/>"), options.Options{
+		"mime_type":    "text/xml",
+		"description":  "Invoice data",
+		"mod_date":     modDate,
+		"relationship": "Data",
+	})
+	check(t, err == nil, "AttachFile should succeed")
+	dw.AttachFile("notes.txt", []byte("notes"), nil)
+	check(t, dw.AttachFile("notes.txt", nil, nil) == errDuplicateAttachmentName, "Names must be unique")
+	check(t, dw.AttachFile("", nil, nil) == errEmptyAttachmentName, "Name is required")
+
+	invoice := dw.attachments.specs["invoice.xml"]
+	notes := dw.attachments.specs["notes.txt"]
+	expectS(t, "<<\n/EmbeddedFiles "+stringFromWriter(&indirectObjectRef{dw.attachments})+"\n>>\n", stringFromWriter(dw.catalog.dict["Names"]))
+	check(t, strings.Contains(stringFromWriter(dw.attachments),
+		"/Names [(invoice.xml) "+stringFromWriter(&indirectObjectRef{invoice})+"(notes.txt) "+stringFromWriter(&indirectObjectRef{notes})+"] "),
+		"Name tree should list the files in order")
+	expectS(t, "(invoice.xml) ", stringFromWriter(invoice.dict["UF"]))
+	expectS(t, "(Invoice data) ", stringFromWriter(invoice.dict["Desc"]))
+	expectS(t, "/Data ", stringFromWriter(invoice.dict["AFRelationship"]))
+	check(t, notes.dict["AFRelationship"] == nil, "Relationship should be optional outside PDF/A-3")
+	expectS(t, "["+stringFromWriter(&indirectObjectRef{invoice})+"] ", stringFromWriter(dw.catalog.dict["AF"]))
+
+	file := invoice.dict["EF"].(dictionary)["F"].(*indirectObjectRef).obj.(*stream)
+	expectS(t, "/text#2Fxml ", stringFromWriter(file.dict["Subtype"]))
+	expectS(t, "<<\n/ModDate (D:20260314092653Z) \n/Size 10 \n>>\n", stringFromWriter(file.dict["Params"]))
+	check(t, dw.file.header.Version >= 1.7, "Attachments should require PDF 1.7")
+}
+
+func TestDocWriter_AttachFile_PDFA(t *testing.T) {
+	dw := NewDocWriter().SetConformance(PDFA2B)
+	check(t, dw.AttachFile("data.csv", []byte("a,b"), nil) == errPDFAAttachment, "PDF/A-2b should not allow attachments")
+
+	dw = NewDocWriter().SetConformance(PDFA3B)
+	check(t, dw.AttachFile("data.csv", []byte("a,b"), nil) == nil, "PDF/A-3b should allow attachments")
+	spec := dw.attachments.specs["data.csv"]
+	expectS(t, "/Unspecified ", stringFromWriter(spec.dict["AFRelationship"]))
+	expectS(t, "["+stringFromWriter(&indirectObjectRef{spec})+"] ", stringFromWriter(dw.catalog.dict["AF"]))
+	file := spec.dict["EF"].(dictionary)["F"].(*indirectObjectRef).obj.(*stream)
+	expectS(t, "/application#2Foctet-stream ", stringFromWriter(file.dict["Subtype"]))
+	check(t, file.dict["Params"].(dictionary)["ModDate"] != nil, "PDF/A-3b attachments should have a modification date")
+
+	modDate := time.Date(2026, 3, 14, 9, 26, 53, 0, time.UTC)
+	dw.SetModDate(modDate)
+	dw.AttachFile("invoice.xml", []byte("<invoice/>"), options.Options{"mime_type": "text/xml"})
+	file = dw.attachments.specs["invoice.xml"].dict["EF"].(dictionary)["F"].(*indirectObjectRef).obj.(*stream)
+	expectS(t, "/text#2Fxml ", stringFromWriter(file.dict["Subtype"]))
+	expectS(t, "<<\n/ModDate (D:20260314092653Z) \n/Size 10 \n>>\n", stringFromWriter(file.dict["Params"]))
+}
+
+func TestDocWriter_AttachAssetFile(t *testing.T) {
+	dw := NewDocWriter()
+	dw.SetAssetFS(fstest.MapFS{"data/invoice.xml": {Data: []byte("<invoice/>")}})
+	check(t, dw.AttachAssetFile("data/invoice.xml", nil) == nil, "AttachAssetFile should succeed")
+	check(t, dw.AttachAssetFile("data/invoice.xml", options.Options{"name": "factur-x.xml"}) == nil, "Name option should rename the file")
+	check(t, dw.AttachAssetFile("missing.xml", nil) != nil, "Missing files should be reported")
+	check(t, dw.attachments.specs["invoice.xml"] != nil, "Attachment should be named by its base name")
+	check(t, dw.attachments.specs["factur-x.xml"] != nil, "Attachment should have the given name")
+}
+
+func TestPageWriter_FileAttachment(t *testing.T) {
+	dw := NewDocWriter()
+	pw := dw.NewPage()
+	pw.SetUnits("in")
+	err := pw.FileAttachment(1, 1, 0.25, 0.25, "data.csv", []byte("a,b"), options.Options{"icon": "Paperclip", "description": "Raw data"})
+	check(t, err == nil, "FileAttachment should succeed")
+	check(t, len(pw.page.annots) == 1, "FileAttachment should add an annotation to the page")
+	a := pw.page.annots[0]
+	expectS(t, "/FileAttachment ", stringFromWriter(a.dict["Subtype"]))
+	expectS(t, "/Paperclip ", stringFromWriter(a.dict["Name"]))
+	expectS(t, "(Raw data) ", stringFromWriter(a.dict["Contents"]))
+	expectS(t, "4 ", stringFromWriter(a.dict["F"]))
+	spec := a.dict["FS"].(*indirectObjectRef).obj.(*dictionaryObject)
+	expectS(t, "(data.csv) ", stringFromWriter(spec.dict["F"]))
+	icon := a.dict["AP"].(dictionary)["N"].(*indirectObjectRef).obj.(*stream)
+	expectS(t, "[0 0 18 18 ] ", stringFromWriter(icon.dict["BBox"]))
+	check(t, strings.Contains(string(icon.data), "b\n"), "Icon should draw a sheet of paper")
+	check(t, dw.attachments == nil, "Page attachments should not be in the name tree")
+
+	check(t, pw.FileAttachment(1, 1, 0.25, 0.25, "", nil, nil) == errEmptyAttachmentName, "Name is required")
+	pdfa := NewDocWriter().SetConformance(PDFA2B)
+	check(t, pdfa.FileAttachment(1, 1, 18, 18, "data.csv", nil, nil) == errPDFAAttachment, "PDF/A-2b should not allow attachments")
+}
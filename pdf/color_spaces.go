@@ -0,0 +1,71 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"fmt"
+
+	"github.com/rowland/leadtype/colors"
+)
+
+// newSeparation returns a Separation color space (PDF spec §8.6.6.4) for the
+// spot color c, which shows its tints as the same tints of its CMYK
+// alternate where the colorant is not available.
+func newSeparation(seq, gen int, c colors.Color) *indirectObject {
+	cyan, magenta, yellow, black := c.Alternate().CMYK()
+	tintTransform := dictionary{
+		"FunctionType": integer(2),
+		"Domain":       array{integer(0), integer(1)},
+		"C0":           array{integer(0), integer(0), integer(0), integer(0)},
+		"C1":           array{real(cyan), real(magenta), real(yellow), real(black)},
+		"N":            integer(1),
+	}
+	return &indirectObject{seq, gen, array{name("Separation"), name(c.SpotName()), name("DeviceCMYK"), tintTransform}}
+}
+
+// separation returns the resource name of the color space for the spot
+// color c, creating it on first use so that each spot color is written only
+// once, whatever tints of it are used.
+func (dw *DocWriter) separation(c colors.Color) string {
+	key := c.WithTint(1)
+	if name, ok := dw.separations[key]; ok {
+		return name
+	}
+	cs := newSeparation(dw.nextSeq(), 0, c)
+	dw.file.body.add(cs)
+	name := fmt.Sprintf("CS%d", len(dw.separations))
+	dw.resources.setColorSpace(name, &indirectObjectRef{cs})
+	dw.separations[key] = name
+	return name
+}
+
+// setColorFill writes c as the nonstroking color in its own color space.
+func (pw *PageWriter) setColorFill(c colors.Color) {
+	switch c.Space() {
+	case colors.DeviceCMYK:
+		pw.dw.usesCMYK = true
+		pw.mw.setCmykColorFill(c.CMYK())
+	case colors.Separation:
+		pw.dw.usesCMYK = true
+		pw.mw.setColorSpaceFill(pw.dw.separation(c))
+		pw.mw.setColorFill([]float64{c.Tint()})
+	default:
+		pw.mw.setRgbColorFill(c.RGB64())
+	}
+}
+
+// setColorStroke writes c as the stroking color in its own color space.
+func (pw *PageWriter) setColorStroke(c colors.Color) {
+	switch c.Space() {
+	case colors.DeviceCMYK:
+		pw.dw.usesCMYK = true
+		pw.mw.setCmykColorStroke(c.CMYK())
+	case colors.Separation:
+		pw.dw.usesCMYK = true
+		pw.mw.setColorSpaceStroke(pw.dw.separation(c))
+		pw.mw.setColorStroke([]float64{c.Tint()})
+	default:
+		pw.mw.setRgbColorStroke(c.RGB64())
+	}
+}
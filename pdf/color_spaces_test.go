@@ -0,0 +1,56 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rowland/leadtype/colors"
+	"github.com/rowland/leadtype/options"
+)
+
+func TestPageWriter_checkSetFillColor_CMYK(t *testing.T) {
+	dw := NewDocWriter()
+	pw := newPageWriter(dw, options.Options{})
+
+	pw.SetFillColor("cmyk(0, 1, 1, 0)")
+	pw.checkSetFillColor()
+	pw.SetLineColor(colors.CMYK(1, 0.5, 0, 0.25))
+	pw.checkSetLineColor()
+
+	expectS(t, "0 1 1 0 k\n1 0.5 0 0.25 K\n", pw.stream.String())
+	check(t, dw.usesCMYK, "CMYK use should be recorded")
+}
+
+func TestPageWriter_checkSetFillColor_Spot(t *testing.T) {
+	dw := NewDocWriter()
+	pw := newPageWriter(dw, options.Options{})
+	brand := colors.Spot("PANTONE 185 C", colors.CMYK(0, 0.91, 0.76, 0))
+
+	pw.SetFillColor(brand)
+	pw.checkSetFillColor()
+	pw.SetFontColor(brand.WithTint(0.5))
+	pw.checkSetFontColor()
+	pw.SetLineColor(brand)
+	pw.checkSetLineColor()
+
+	expectS(t, "/CS0 cs\n1 sc\n/CS0 cs\n0.5 sc\n/CS0 CS\n1 SC\n", pw.stream.String())
+	check(t, len(dw.separations) == 1, "Tints of a spot color should share a color space")
+	ref := dw.resources.colorSpaces["CS0"].(*indirectObjectRef)
+	expectS(t, "[/Separation /PANTONE#20185#20C /DeviceCMYK <<\n/C0 [0 0 0 0 ] \n/C1 [0 0.91 0.76 0 ] \n/Domain [0 1 ] \n/FunctionType 2 \n/N 1 \n>>\n] ",
+		stringFromWriter(ref.obj.(*indirectObject).obj))
+}
+
+func TestDocWriter_SetConformance_CMYK(t *testing.T) {
+	dw := NewDocWriter().SetConformance(PDFA2B)
+	pw := dw.NewPage()
+	pw.SetFillColor(colors.CMYK(0, 0, 0, 1))
+	pw.Rectangle(1, 1, 1, 1, false, true)
+	var buf bytes.Buffer
+	_, err := dw.WriteTo(&buf)
+	check(t, err == errPDFACMYK, "CMYK colors should be rejected")
+	check(t, !strings.Contains(buf.String(), "%PDF"), "Nothing should be written")
+}
@@ -14,7 +14,13 @@ import (
 type cidSystemInfo struct{}
 
 func (c *cidSystemInfo) write(w io.Writer) {
-	fmt.Fprintf(w, "<< /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> ")
+	// Strings are written through str so that they are encrypted with the
+	// rest of the font dictionary.
+	fmt.Fprintf(w, "<< /Registry ")
+	str("Adobe").write(w)
+	fmt.Fprintf(w, "/Ordering ")
+	str("Identity").write(w)
+	fmt.Fprintf(w, "/Supplement 0 >> ")
 }
 
 // ── /W sparse width array ─────────────────────────────────────────────────────
@@ -121,6 +127,13 @@ func newCIDFont(seq, gen int,
 	return new(cidFont).init(seq, gen, baseFont, fontDescriptor, defaultWidth, widths)
 }
 
+// useCFFOutlines makes the font a CIDFontType0, for a font program with CFF
+// outlines, which selects glyphs by CID itself and takes no /CIDToGIDMap.
+func (f *cidFont) useCFFOutlines() {
+	f.dict["Subtype"] = name("CIDFontType0")
+	delete(f.dict, "CIDToGIDMap")
+}
+
 // setWidths replaces the /W entry after initial construction, used when
 // glyph usage is not known until document close.
 func (f *cidFont) setWidths(w writer) {
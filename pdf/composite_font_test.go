@@ -5,9 +5,11 @@ package pdf
 
 import (
 	"bytes"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/rowland/leadtype/font"
 	"github.com/rowland/leadtype/options"
 	"github.com/rowland/leadtype/shaping"
 	"github.com/rowland/leadtype/ttf_fonts"
@@ -271,6 +273,88 @@ func TestUnicodeMode_FontFile2_Compressed(t *testing.T) {
 	}
 }
 
+// TestUnicodeMode_FontFile3_OpenType verifies that a font with CFF outlines
+// is embedded as an OpenType /FontFile3 under a CIDFontType0 descendant.
+func TestUnicodeMode_FontFile3_OpenType(t *testing.T) {
+	for _, fixture := range []struct{ filename, family string }{
+		{"../ttf/testdata/minimal.otf", "MinimalCFF"},
+		{"../ttf/testdata/minimal-cid.otf", "MinimalCID"},
+	} {
+		fc := testFontSource(t, fixture.filename)
+
+		dw := NewDocWriter()
+		dw.AddFontSource(fc)
+
+		pw := dw.NewPage()
+		pw.SetFont(fixture.family, 12, options.Options{})
+		pw.MoveTo(72, 720)
+		pw.Print("ABC")
+
+		var buf bytes.Buffer
+		dw.WriteTo(&buf)
+		pdf := buf.String()
+
+		if !strings.HasPrefix(pdf, "%PDF-1.6") {
+			t.Errorf("%s: expected PDF 1.6 header, got %q", fixture.family, pdf[:8])
+		}
+		if !strings.Contains(pdf, "/Subtype /CIDFontType0") {
+			t.Errorf("%s: expected /CIDFontType0, got pdf containing:\n%s",
+				fixture.family, extractSection(pdf, "/DescendantFonts", 400))
+		}
+		if strings.Contains(pdf, "/CIDToGIDMap") || strings.Contains(pdf, "/CIDFontType2") {
+			t.Errorf("%s: expected no TrueType CIDFont entries", fixture.family)
+		}
+		if !strings.Contains(pdf, "/FontFile3") || strings.Contains(pdf, "/FontFile2") {
+			t.Errorf("%s: expected /FontFile3 in FontDescriptor, got pdf containing:\n%s",
+				fixture.family, extractSection(pdf, "/FontDescriptor", 400))
+		}
+		if !strings.Contains(pdf, "/Subtype /OpenType") || strings.Contains(pdf, "/Length1") {
+			t.Errorf("%s: expected OpenType font stream without /Length1", fixture.family)
+		}
+		if !strings.Contains(pdf, "/ToUnicode") {
+			t.Errorf("%s: expected /ToUnicode", fixture.family)
+		}
+		if count := strings.Count(pdf, "+"+fixture.family); count != 3 {
+			t.Errorf("%s: expected 3 occurrences of the subset tag, got %d", fixture.family, count)
+		}
+	}
+}
+
+// TestUnicodeMode_VariableFontInstances verifies that each instance of a
+// variable font is embedded as its own static subset.
+func TestUnicodeMode_VariableFontInstances(t *testing.T) {
+	fc := testFontSource(t, "../ttf/testdata/minimal-var.ttf")
+
+	dw := NewDocWriter()
+	dw.AddFontSource(fc)
+
+	pw := dw.NewPage()
+	pw.MoveTo(72, 720)
+	for _, opts := range []options.Options{
+		{"weight": "Bold"},
+		{"variations": "wght 650"},
+		{"instance": "Condensed Bold"},
+	} {
+		if _, err := pw.SetFont("MinimalVar", 12, opts); err != nil {
+			t.Fatalf("%v: %v", opts, err)
+		}
+		pw.Print("A")
+	}
+
+	var buf bytes.Buffer
+	dw.WriteTo(&buf)
+	pdf := buf.String()
+
+	for _, name := range []string{"MinimalVarBold", "MinimalVar_650wght", "MinimalVar-CondensedBold"} {
+		if !strings.Contains(pdf, "+"+name) {
+			t.Errorf("expected an embedded subset of %s", name)
+		}
+	}
+	if count := strings.Count(pdf, "/FontFile2"); count != 3 {
+		t.Errorf("expected 3 embedded fonts, got %d", count)
+	}
+}
+
 // TestUnicodeMode_SubsetTag verifies that the embedded font uses the
 // "XXXXXX+FontName" subset tag format in all three name locations:
 // FontDescriptor/FontName, CIDFont/BaseFont, and Type0/BaseFont.
@@ -363,10 +447,108 @@ type offsetShaper struct {
 	glyphs []shaping.GlyphPosition
 }
 
-func (s offsetShaper) Shape(_ []rune, _ shaping.FontReader, _ float32) ([]shaping.GlyphPosition, error) {
+func (s offsetShaper) Shape(_ []rune, _ shaping.FontReader, _ float32, _ []shaping.Feature) ([]shaping.GlyphPosition, error) {
 	return s.glyphs, nil
 }
 
+func TestShapedGlyphSequences(t *testing.T) {
+	nominal := map[rune]uint16{'क': 10, 'ि': 20, 'ष': 30, 'f': 40, 'i': 41}
+	glyphIndex := func(r rune) uint16 { return nominal[r] }
+	tests := []struct {
+		name   string
+		text   string
+		glyphs []shaping.GlyphPosition
+		want   [][]rune
+	}{
+		{
+			name:   "ligature",
+			text:   "fi",
+			glyphs: []shaping.GlyphPosition{{GlyphID: 99, ClusterIndex: 0}},
+			want:   [][]rune{[]rune("fi")},
+		},
+		{
+			name:   "pre-base matra",
+			text:   "किक",
+			glyphs: []shaping.GlyphPosition{{GlyphID: 20, ClusterIndex: 0}, {GlyphID: 10, ClusterIndex: 0}, {GlyphID: 10, ClusterIndex: 2}},
+			want:   [][]rune{[]rune("ि"), []rune("क"), []rune("क")},
+		},
+		{
+			name:   "half form",
+			text:   "क्ष",
+			glyphs: []shaping.GlyphPosition{{GlyphID: 98, ClusterIndex: 0}, {GlyphID: 30, ClusterIndex: 0}},
+			want:   [][]rune{[]rune("क्"), []rune("ष")},
+		},
+		{
+			name:   "split vowel",
+			text:   "f",
+			glyphs: []shaping.GlyphPosition{{GlyphID: 97, ClusterIndex: 0}, {GlyphID: 96, ClusterIndex: 0}},
+			want:   [][]rune{[]rune("f"), nil},
+		},
+	}
+	for _, tt := range tests {
+		got := shapedGlyphSequences(tt.glyphs, []rune(tt.text), glyphIndex)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: shapedGlyphSequences = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// featureShaper returns one nominal glyph per rune, advancing 6 points each,
+// and records the features it is asked to apply.
+type featureShaper struct {
+	font     *font.Font
+	features *[]shaping.Feature
+}
+
+func (s featureShaper) Shape(text []rune, _ shaping.FontReader, _ float32, features []shaping.Feature) ([]shaping.GlyphPosition, error) {
+	*s.features = features
+	glyphs := make([]shaping.GlyphPosition, len(text))
+	for i, r := range text {
+		glyphs[i] = shaping.GlyphPosition{GlyphID: s.font.GlyphIndex(r), XAdvance: 6 * 64, ClusterIndex: i}
+	}
+	return glyphs, nil
+}
+
+func TestUnicodeMode_FeaturesShapeLatinText(t *testing.T) {
+	fc := testFontSource(t, "../ttf/testdata/minimal.ttf")
+
+	dw := NewDocWriter()
+	dw.AddFontSource(fc)
+
+	pw := dw.NewPage()
+	fonts, err := pw.SetFont("Minimal", 12, options.Options{"features": "tnum,smcp"})
+	if err != nil || len(fonts) == 0 {
+		t.Fatalf("SetFont: %v", err)
+	}
+	var features []shaping.Feature
+	fonts[0].Shaper = featureShaper{fonts[0], &features}
+	pw.MoveTo(72, 720)
+	pw.Print("AB")
+
+	var buf bytes.Buffer
+	dw.WriteTo(&buf)
+	pdf := buf.String()
+
+	want := []shaping.Feature{{Tag: "tnum", Value: 1}, {Tag: "smcp", Value: 1}}
+	if !reflect.DeepEqual(features, want) {
+		t.Errorf("shaper features = %v, want %v", features, want)
+	}
+	if got := strings.Count(pdf, " Tm\n"); got < 2 {
+		t.Errorf("expected Latin text with features to be shaped glyph by glyph, got pdf excerpt:\n%s", extractSection(pdf, "BT", 400))
+	}
+}
+
+func TestShapedSpacing(t *testing.T) {
+	glyphs := []shaping.GlyphPosition{{ClusterIndex: 0}, {ClusterIndex: 2}, {ClusterIndex: 2}, {ClusterIndex: 3}}
+	got := shapedSpacing(glyphs, []rune("fi a"), 1, 5)
+	if want := []float64{2, 6, 0, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("shapedSpacing = %v, want %v", got, want)
+	}
+	if got := shapedSpacing(glyphs, []rune("fi a"), 0, 0); got != nil {
+		t.Errorf("shapedSpacing without spacing = %v, want nil", got)
+	}
+}
+
 func TestUnicodeMode_ShapedGlyphOffsetsUsePositioningOperators(t *testing.T) {
 	fc, err := ttf_fonts.New("../shaping/testdata/Amiri-Regular.ttf")
 	if err != nil || len(fc.FontInfos) == 0 {
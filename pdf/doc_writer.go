@@ -9,6 +9,7 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"time"
 
 	"github.com/rowland/leadtype/codepage"
 	"github.com/rowland/leadtype/colors"
@@ -34,12 +35,37 @@ type DocWriter struct {
 	unicodeFonts          map[string]*font.Font      // PostScript name → font, for width lookup at Close
 	cidFonts              map[string]*cidFont        // PostScript name → CID font, for /W update at Close
 	type0Fonts            map[string]*type0Font      // PostScript name → Type0 font, for ToUnicode at Close
-	fontDescriptors       map[string]*fontDescriptor // PostScript name → descriptor, for FontFile2 at Close
+	fontDescriptors       map[string]*fontDescriptor // PostScript name → descriptor, for FontFile2 or FontFile3 at Close
 	images                map[string]*cachedImage
+	importedPDFs          map[string]*importedPDF
+	importedPages         int
+	templates             map[string]*pageTemplate
+	extGStates            map[string]string
+	patterns              map[string]string
+	shadings              map[string]shadingResource
+	separations           map[colors.Color]string
+	namedDests            *dictionaryObject
+	acroForm              *acroForm
+	structTreeRoot        *structTreeRoot
+	tags                  []*openTag
+	metadata              docMetadata
+	encryption            *encryptionSettings
+	conformance           Conformance
+	attachments           *embeddedFiles
+	update                *incrementalUpdate
 	assetFS               fs.FS
 	compressPages         bool
+	usesCMYK              bool  // a CMYK or spot color has been drawn
+	usesType1             bool  // a standard Type1 font, which is not embedded, has been used
+	pageErr               error // the first page given a size that is not known
+	imposition            *imposition
+	pageLabels            map[int]pageLabel // label ranges of streamed pages, by page index
+	compressObjects       bool
 	compressToUnicode     bool
 	compressEmbeddedFonts bool
+	streamedPages         int
+	unstreamedImages      []*pdfImage
+	streamedVersion       float32
 }
 
 type cachedImage struct {
@@ -48,7 +74,10 @@ type cachedImage struct {
 }
 
 func NewDocWriter() *DocWriter {
-	nextSeq := nextSeqFunc()
+	return newDocWriter(nextSeqFunc(1))
+}
+
+func newDocWriter(nextSeq func() int) *DocWriter {
 	file := newFile()
 	pages := newPages(nextSeq(), 0)
 	outlines := newOutlines(nextSeq(), 0)
@@ -76,11 +105,16 @@ func NewDocWriter() *DocWriter {
 		type0Fonts:      make(map[string]*type0Font),
 		fontDescriptors: make(map[string]*fontDescriptor),
 		images:          make(map[string]*cachedImage),
+		extGStates:      make(map[string]string),
+		patterns:        make(map[string]string),
+		shadings:        make(map[string]shadingResource),
+		separations:     make(map[colors.Color]string),
 	}
 }
 
-func nextSeqFunc() func() int {
-	var nextValue = 0
+// nextSeqFunc returns a function allocating object numbers from first.
+func nextSeqFunc(first int) func() int {
+	var nextValue = first - 1
 	return func() int {
 		nextValue++
 		return nextValue
@@ -91,6 +125,35 @@ func (dw *DocWriter) AddFont(family string, options options.Options) ([]*font.Fo
 	return dw.CurPage().AddFont(family, options)
 }
 
+// AddOutline appends a top-level entry to the document outline (bookmarks).
+// The entry scrolls to vertical position y on pw, measured in pw's units from
+// the top of the page; a nil pw means the current page. Recognized options are
+// "open" (show children expanded), "color", "bold", "italic" and "dest", which
+// names a destination registered with AddDestination to use instead of pw and
+// y. Once any entry exists, the document opens with the outline panel visible.
+func (dw *DocWriter) AddOutline(title string, pw *PageWriter, y float64, options options.Options) *Outline {
+	o := dw.newOutline(dw.catalog.outlines, title, pw, y, options)
+	dw.catalog.outlines.add(o.item)
+	return o
+}
+
+// AddDestination registers name as a named destination at vertical position y
+// on the current page.
+func (dw *DocWriter) AddDestination(name string, y float64) {
+	dw.CurPage().AddDestination(name, y)
+}
+
+// addNamedDest records dest under name in the catalog's /Dests dictionary,
+// creating the dictionary on first use.
+func (dw *DocWriter) addNamedDest(destName string, dest array) {
+	if dw.namedDests == nil {
+		dw.namedDests = newDictionaryObject(dw.nextSeq(), 0)
+		dw.file.body.add(dw.namedDests)
+		dw.catalog.dict["Dests"] = &indirectObjectRef{dw.namedDests}
+	}
+	dw.namedDests.dict[destName] = dest
+}
+
 func (dw *DocWriter) AddFontSource(fontSource font.FontSource) {
 	dw.fontSources = append(dw.fontSources, fontSource)
 }
@@ -100,6 +163,14 @@ func (dw *DocWriter) CompressPages(value bool) *DocWriter {
 	return dw
 }
 
+// CompressObjects packs dictionaries and other non-stream objects into
+// compressed object streams and writes a cross-reference stream in place of
+// the xref table. The resulting file requires PDF 1.5.
+func (dw *DocWriter) CompressObjects(value bool) *DocWriter {
+	dw.compressObjects = value
+	return dw
+}
+
 func (dw *DocWriter) CompressToUnicode(value bool) *DocWriter {
 	dw.compressToUnicode = value
 	return dw
@@ -110,6 +181,61 @@ func (dw *DocWriter) CompressEmbeddedFonts(value bool) *DocWriter {
 	return dw
 }
 
+func (dw *DocWriter) SetAuthor(author string) *DocWriter {
+	dw.metadata.author = author
+	return dw
+}
+
+func (dw *DocWriter) SetCreationDate(date time.Time) *DocWriter {
+	dw.metadata.creationDate = date
+	return dw
+}
+
+func (dw *DocWriter) SetCreator(creator string) *DocWriter {
+	dw.metadata.creator = creator
+	return dw
+}
+
+func (dw *DocWriter) SetKeywords(keywords string) *DocWriter {
+	dw.metadata.keywords = keywords
+	return dw
+}
+
+func (dw *DocWriter) SetModDate(date time.Time) *DocWriter {
+	dw.metadata.modDate = date
+	return dw
+}
+
+func (dw *DocWriter) SetProducer(producer string) *DocWriter {
+	dw.metadata.producer = producer
+	return dw
+}
+
+func (dw *DocWriter) SetSubject(subject string) *DocWriter {
+	dw.metadata.subject = subject
+	return dw
+}
+
+func (dw *DocWriter) SetTitle(title string) *DocWriter {
+	dw.metadata.title = title
+	return dw
+}
+
+// SetEncryption password-protects the document. Readers ask for a password
+// before displaying it: the user password grants only the given permissions,
+// while the owner password grants full access. An empty user password lets
+// anyone open the document subject to the permissions. An empty owner
+// password is replaced with a random one.
+func (dw *DocWriter) SetEncryption(method EncryptionMethod, userPassword, ownerPassword string, permissions Permissions) *DocWriter {
+	dw.encryption = &encryptionSettings{
+		method:        method,
+		userPassword:  userPassword,
+		ownerPassword: ownerPassword,
+		permissions:   permissions,
+	}
+	return dw
+}
+
 func (dw *DocWriter) CurPage() *PageWriter {
 	if dw.curPage == nil {
 		return dw.NewPage()
@@ -117,6 +243,18 @@ func (dw *DocWriter) CurPage() *PageWriter {
 	return dw.curPage
 }
 
+func (dw *DocWriter) BlendMode() string {
+	return dw.CurPage().BlendMode()
+}
+
+func (dw *DocWriter) FillGradient() *Gradient {
+	return dw.CurPage().FillGradient()
+}
+
+func (dw *DocWriter) FillOpacity() float64 {
+	return dw.CurPage().FillOpacity()
+}
+
 func (dw *DocWriter) FontColor() colors.Color {
 	return dw.CurPage().FontColor()
 }
@@ -129,7 +267,7 @@ func (dw *DocWriter) AssetFS() fs.FS {
 	return dw.assetFS
 }
 
-func (dw *DocWriter) readImageFile(filename string) ([]byte, error) {
+func (dw *DocWriter) readAssetFile(filename string) ([]byte, error) {
 	if dw.assetFS == nil {
 		return os.ReadFile(filename)
 	}
@@ -185,13 +323,14 @@ func (dw *DocWriter) fontKey(f *font.Font, cpi codepage.CodepageIndex) string {
 		f.Leading(),
 		0, 0) // maxWidth, avgWidth
 	dw.file.body.add(descriptor)
-	key := fmt.Sprintf("F%d", len(dw.fontKeys))
+	key := dw.newFontKey()
 	dw.fontKeys[name] = key
 	widths := dw.widthsForFontCodepage(f, cpi)
 	dw.file.body.add(widths)
 	var font *simpleFont
 	switch f.SubType() {
 	case "Type1":
+		dw.usesType1 = true
 		if useStandardEncoding(f.Family()) {
 			font = newType1Font(
 				dw.nextSeq(), 0,
@@ -241,8 +380,24 @@ func (dw *DocWriter) fontKey(f *font.Font, cpi codepage.CodepageIndex) string {
 	return key
 }
 
+// newFontKey returns the resource key for a font about to be registered,
+// such as "F0", passing over those of an existing form's fonts.
+func (dw *DocWriter) newFontKey() string {
+	for i := len(dw.fontKeys); ; i++ {
+		key := fmt.Sprintf("F%d", i)
+		if dw.resources.fonts[key] != nil {
+			continue
+		}
+		if dw.update != nil && dw.update.formFonts[key] != nil {
+			continue
+		}
+		return key
+	}
+}
+
 // fontKeyUnicode registers a Type0/CIDFontType2 composite font for the given
-// TrueType font and returns the PDF resource key (e.g. "F0"). The /W and
+// TrueType font, or a Type0/CIDFontType0 one for an OpenType font with CFF
+// outlines, and returns the PDF resource key (e.g. "F0"). The /W and
 // ToUnicode entries are left empty and filled in by flushUnicodeFonts at
 // document close, once all glyph usages have been recorded.
 func (dw *DocWriter) fontKeyUnicode(f *font.Font) string {
@@ -268,10 +423,13 @@ func (dw *DocWriter) fontKeyUnicode(f *font.Font) string {
 		0, 0) // maxWidth, avgWidth
 	dw.file.body.add(descriptor)
 
-	key := fmt.Sprintf("F%d", len(dw.fontKeys))
+	key := dw.newFontKey()
 	dw.fontKeys[cacheName] = key
 
 	cid := newCIDFont(dw.nextSeq(), 0, psName, descriptor, 1000, array{})
+	if f.HasCFFOutlines() {
+		cid.useCFFOutlines()
+	}
 	dw.file.body.add(cid)
 
 	t0 := newType0Font(dw.nextSeq(), 0, psName, cid)
@@ -335,21 +493,32 @@ func (dw *DocWriter) flushUnicodeFonts() {
 		dw.file.body.add(tuStream)
 		dw.type0Fonts[psName].setToUnicode(&indirectObjectRef{tuStream})
 
-		// Embed a font subset as /FontFile2 in the descriptor.
+		// Embed a font subset as /FontFile2 in the descriptor, or as an
+		// OpenType /FontFile3 for a font with CFF outlines.
 		glyphIDs := make([]uint16, 0, len(mapping))
 		for gid := range mapping {
 			glyphIDs = append(glyphIDs, gid)
 		}
 		if subsetData, err := f.SubsetBytes(glyphIDs); err == nil {
 			fontStream := newStream(dw.nextSeq(), 0, subsetData)
-			fontStream.setLength1(len(subsetData))
+			cff := f.HasCFFOutlines()
+			if cff {
+				fontStream.dict["Subtype"] = name("OpenType")
+			} else {
+				fontStream.setLength1(len(subsetData))
+			}
 			if dw.compressEmbeddedFonts {
 				if err := fontStream.compress(); err != nil {
 					panic(err)
 				}
 			}
 			dw.file.body.add(fontStream)
-			dw.fontDescriptors[psName].setFontFile2(&indirectObjectRef{fontStream})
+			if cff {
+				dw.fontDescriptors[psName].setFontFile3(&indirectObjectRef{fontStream})
+				dw.requireVersion(1.6)
+			} else {
+				dw.fontDescriptors[psName].setFontFile2(&indirectObjectRef{fontStream})
+			}
 
 			// Apply the 6-char subset tag to all three name occurrences:
 			// FontDescriptor/FontName, CIDFont/BaseFont, Type0/BaseFont.
@@ -378,8 +547,9 @@ func (dw *DocWriter) FontStyle() string {
 }
 
 func (dw *DocWriter) indexOfPage(pw *PageWriter) int {
-	for i, v := range dw.pages {
-		if v == pw {
+	// Search from the end, since new pages usually follow the last one.
+	for i := len(dw.pages) - 1; i >= 0; i-- {
+		if dw.pages[i] == pw {
 			return i
 		}
 	}
@@ -422,6 +592,46 @@ func (dw *DocWriter) MiterLimit() float64 {
 	return dw.CurPage().MiterLimit()
 }
 
+func (dw *DocWriter) LinkToDestination(x, y, width, height float64, dest string) {
+	dw.CurPage().LinkToDestination(x, y, width, height, dest)
+}
+
+func (dw *DocWriter) LinkToPage(x, y, width, height float64, target *PageWriter, targetY float64) {
+	dw.CurPage().LinkToPage(x, y, width, height, target, targetY)
+}
+
+func (dw *DocWriter) LinkToURI(x, y, width, height float64, uri string) {
+	dw.CurPage().LinkToURI(x, y, width, height, uri)
+}
+
+func (dw *DocWriter) FileAttachment(x, y, width, height float64, fileName string, data []byte, options options.Options) error {
+	return dw.CurPage().FileAttachment(x, y, width, height, fileName, data, options)
+}
+
+func (dw *DocWriter) CheckBox(x, y, width, height float64, fieldName string, options options.Options) error {
+	return dw.CurPage().CheckBox(x, y, width, height, fieldName, options)
+}
+
+func (dw *DocWriter) ComboBox(x, y, width, height float64, fieldName string, choices []string, options options.Options) error {
+	return dw.CurPage().ComboBox(x, y, width, height, fieldName, choices, options)
+}
+
+func (dw *DocWriter) ListBox(x, y, width, height float64, fieldName string, choices []string, options options.Options) error {
+	return dw.CurPage().ListBox(x, y, width, height, fieldName, choices, options)
+}
+
+func (dw *DocWriter) RadioButton(x, y, width, height float64, groupName, value string, options options.Options) error {
+	return dw.CurPage().RadioButton(x, y, width, height, groupName, value, options)
+}
+
+func (dw *DocWriter) SignatureField(x, y, width, height float64, fieldName string, options options.Options) error {
+	return dw.CurPage().SignatureField(x, y, width, height, fieldName, options)
+}
+
+func (dw *DocWriter) TextField(x, y, width, height float64, fieldName string, options options.Options) error {
+	return dw.CurPage().TextField(x, y, width, height, fieldName, options)
+}
+
 func (dw *DocWriter) LineTo(x, y float64) {
 	dw.CurPage().LineTo(x, y)
 }
@@ -487,7 +697,7 @@ func (dw *DocWriter) Star(x, y, r1, r2 float64, points int, border, fill, revers
 }
 
 func (dw *DocWriter) NewPage() *PageWriter {
-	if dw.curPage == nil {
+	if dw.curPage == nil || dw.curPage.existing != nil {
 		return dw.NewPageWithOptions(options.Options{})
 	}
 	return dw.NewPageAfter(dw.curPage)
@@ -500,7 +710,16 @@ func (dw *DocWriter) NewPageAfter(pw *PageWriter) *PageWriter {
 	} else {
 		i = dw.indexOfPage(pw)
 	}
+	if dw.file.out != nil && i != len(dw.pages)-1 {
+		// Pages already streamed cannot be followed by an inserted page.
+		return nil
+	}
 	if i >= 0 {
+		if dw.file.out != nil {
+			// Streaming drops the pages written, leaving none before the new one.
+			dw.streamPages()
+			i = -1
+		}
 		dw.curPage = clonePageWriter(pw)
 		dw.insertPage(dw.curPage, i)
 		return dw.curPage
@@ -509,6 +728,7 @@ func (dw *DocWriter) NewPageAfter(pw *PageWriter) *PageWriter {
 }
 
 func (dw *DocWriter) NewPageWithOptions(options options.Options) *PageWriter {
+	dw.streamPages()
 	dw.curPage = newPageWriter(dw, dw.options.Merge(options))
 	dw.pages = append(dw.pages, dw.curPage)
 	return dw.curPage
@@ -540,6 +760,10 @@ func (dw *DocWriter) PageWidth() float64 {
 	return dw.CurPage().PageWidth()
 }
 
+func (dw *DocWriter) PaintGradient(gradient *Gradient) {
+	dw.CurPage().PaintGradient(gradient)
+}
+
 func (dw *DocWriter) Print(text string) (err error) {
 	return dw.CurPage().Print(text)
 }
@@ -565,7 +789,7 @@ func (dw *DocWriter) SVGDimensions(data []byte) (width, height int, err error) {
 }
 
 func (dw *DocWriter) ImageDimensionsFromFile(filename string) (width, height int, err error) {
-	data, err := dw.readImageFile(filename)
+	data, err := dw.readAssetFile(filename)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -573,7 +797,7 @@ func (dw *DocWriter) ImageDimensionsFromFile(filename string) (width, height int
 }
 
 func (dw *DocWriter) SVGDimensionsFromFile(filename string) (width, height int, err error) {
-	data, err := dw.readImageFile(filename)
+	data, err := dw.readAssetFile(filename)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -669,10 +893,12 @@ func (dw *DocWriter) loadImage(data []byte, key string) (*pdfImage, string, erro
 		}
 		dw.file.body.add(mask)
 		image.setSMask(&indirectObjectRef{mask})
+		dw.addUnstreamedImage(mask)
 	}
 
 	name := fmt.Sprintf("Im%d", len(dw.images))
 	dw.file.body.add(image)
+	dw.addUnstreamedImage(image)
 	dw.resources.setXObject(name, &indirectObjectRef{image})
 	dw.images[key] = &cachedImage{image: image, name: name}
 	return image, name, nil
@@ -682,10 +908,22 @@ func (dw *DocWriter) ResetFonts() {
 	dw.CurPage().ResetFonts()
 }
 
+func (dw *DocWriter) SetBlendMode(mode string) (prev string) {
+	return dw.CurPage().SetBlendMode(mode)
+}
+
 func (dw *DocWriter) SetFillColor(color any) (prev colors.Color) {
 	return dw.CurPage().SetFillColor(color)
 }
 
+func (dw *DocWriter) SetFillGradient(gradient *Gradient) (prev *Gradient) {
+	return dw.CurPage().SetFillGradient(gradient)
+}
+
+func (dw *DocWriter) SetFillOpacity(opacity float64) (prev float64) {
+	return dw.CurPage().SetFillOpacity(opacity)
+}
+
 func (dw *DocWriter) SetFont(name string, size float64, options options.Options) ([]*font.Font, error) {
 	return dw.CurPage().SetFont(name, size, options)
 }
@@ -734,6 +972,10 @@ func (dw *DocWriter) SetOptions(options options.Options) {
 	dw.options = options
 }
 
+func (dw *DocWriter) SetStrokeOpacity(opacity float64) (prev float64) {
+	return dw.CurPage().SetStrokeOpacity(opacity)
+}
+
 func (dw *DocWriter) SetUnderline(underline bool) (prev bool) {
 	return dw.CurPage().SetUnderline(underline)
 }
@@ -757,6 +999,10 @@ func (dw *DocWriter) VTextAlign() string {
 	return dw.CurPage().VTextAlign()
 }
 
+func (dw *DocWriter) StrokeOpacity() float64 {
+	return dw.CurPage().StrokeOpacity()
+}
+
 func (dw *DocWriter) Underline() bool {
 	return dw.CurPage().Underline()
 }
@@ -782,16 +1028,121 @@ func (dw *DocWriter) Write(text []byte) (n int, err error) {
 
 // WriteTo implements io.WriterTo.
 func (dw *DocWriter) WriteTo(wr io.Writer) (int64, error) {
-	if len(dw.pages) == 0 {
+	if dw.file.out != nil {
+		return 0, errStreaming
+	}
+	if dw.update != nil {
+		return 0, dw.writeUpdate(wr)
+	}
+	if dw.pageErr != nil {
+		return 0, dw.pageErr
+	}
+	if err := dw.checkImposition(); err != nil {
+		return 0, err
+	}
+	if err := dw.checkConformance(); err != nil {
+		return 0, err
+	}
+	if err := dw.finishBody(); err != nil {
+		return 0, err
+	}
+	dw.file.write(wr)
+	return 0, nil
+}
+
+// finishBody closes every page and adds the objects that can only be built
+// once all pages are complete.
+func (dw *DocWriter) finishBody() error {
+	if len(dw.pages) == 0 && dw.streamedPages == 0 && dw.update == nil {
 		dw.NewPage()
 	}
+	if dw.imposition != nil {
+		dw.impose()
+	}
 	for _, pw := range dw.pages {
 		pw.close()
 	}
+	if dw.update != nil {
+		for _, pw := range dw.update.edited {
+			pw.close()
+		}
+	}
 	dw.curPage = nil
 	dw.flushUnicodeFonts()
-	dw.file.write(wr)
-	return 0, nil
+	if len(dw.catalog.outlines.children) > 0 && dw.catalog.pageMode == "UseNone" {
+		dw.catalog.setPageMode("UseOutlines")
+	}
+	dw.writePageLabels()
+	dw.writeMetadata()
+	dw.writeOutputIntent()
+	dw.writeEncryption()
+	if err := dw.mergeUpdate(); err != nil {
+		return err
+	}
+	dw.writeObjectStreams()
+	return nil
+}
+
+// writeMetadata adds the document information dictionary and the matching
+// XMP metadata stream when any metadata has been set. PDF/A documents always
+// have an XMP stream, which identifies their conformance level.
+func (dw *DocWriter) writeMetadata() {
+	if dw.metadata.isEmpty() && dw.conformance == NoConformance {
+		return
+	}
+	if dw.update != nil && dw.metadata == dw.update.metadata {
+		return
+	}
+	if !dw.metadata.isEmpty() {
+		info := newDictionaryObject(dw.nextSeq(), 0)
+		info.dict = dw.metadata.infoDict()
+		if dw.update != nil {
+			// Keep any custom entries of the existing information dictionary.
+			for k, v := range dw.update.info {
+				if _, ok := info.dict[k]; !ok {
+					info.dict[k] = v
+				}
+			}
+		}
+		dw.file.body.add(info)
+		dw.file.trailer.setInfo(info)
+	}
+
+	schemas := dw.metadata.xmpSchemas()
+	if dw.conformance != NoConformance {
+		schemas = append(schemas, dw.conformance.xmpSchema())
+	}
+	xmp := newMetadataStream(dw.nextSeq(), 0, xmpPacket(schemas))
+	dw.file.body.add(xmp)
+	dw.catalog.dict["Metadata"] = &indirectObjectRef{xmp}
+	dw.requireVersion(1.4)
+}
+
+// writeEncryption adds the /Encrypt dictionary and the file identifier it
+// depends on, and arranges for the body to be encrypted as it is written.
+func (dw *DocWriter) writeEncryption() {
+	if dw.encryption == nil || dw.file.body.security != nil {
+		return
+	}
+	id := randomBytes(16)
+	dw.file.trailer.setID(id)
+	sh := newSecurityHandler(dw.nextSeq(), 0, dw.encryption, id)
+	dw.file.body.add(sh.dict)
+	dw.file.body.security = sh
+	dw.file.trailer.setEncrypt(sh.dict)
+	if sh.revision >= 6 {
+		dw.requireVersion(2.0)
+	} else {
+		dw.requireVersion(1.6)
+	}
+}
+
+// requireVersion raises the version in the file header to at least version,
+// for features introduced after PDF 1.3.
+func (dw *DocWriter) requireVersion(version float32) {
+	if dw.file.header.Version < version {
+		dw.file.header.Version = version
+	}
 }
 
 func (dw *DocWriter) X() float64 {
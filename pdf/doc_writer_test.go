@@ -5,6 +5,7 @@ package pdf
 
 import (
 	"bytes"
+	"fmt"
 	"image"
 	"io/fs"
 	"strings"
@@ -34,6 +35,50 @@ func TestNewDocWriter(t *testing.T) {
 	check(t, !dw.inPage(), "DocWriter should not be in page yet")
 }
 
+func TestDocWriter_AddOutline(t *testing.T) {
+	dw := NewDocWriter()
+	dw.SetUnits("in")
+	p1 := dw.NewPage()
+	p2 := dw.NewPage()
+	chapter := dw.AddOutline("Chapter 1", p1, 1, options.Options{"open": true, "bold": true})
+	section := chapter.AddOutline("Section 1.1", p2, 2, options.Options{"color": "red", "italic": true})
+	dw.AddOutline("Chapter 2", nil, 0, options.Options{})
+	check(t, section.Title() == "Section 1.1", "Outline title should be retained")
+
+	var buf bytes.Buffer
+	if _, err := dw.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.String()
+	for _, fragment := range []string{
+		"/PageMode /UseOutlines \n",
+		"/Count 3 \n/First ",
+		"/Title (Chapter 1) \n",
+		"/Title (Section 1.1) \n",
+		"/Title (Chapter 2) \n",
+		"/C [1 0 0 ] \n",
+		"/F 1 \n",
+		"/F 2 \n",
+		"/Count 1 \n",
+		fmt.Sprintf("/Dest [%d 0 R /XYZ null 720 null ] \n", p1.page.Seq()),
+		fmt.Sprintf("/Dest [%d 0 R /XYZ null 648 null ] \n", p2.page.Seq()),
+		fmt.Sprintf("/Dest [%d 0 R /XYZ null 792 null ] \n", p2.page.Seq()),
+	} {
+		if !strings.Contains(pdf, fragment) {
+			t.Fatalf("expected generated PDF to contain %q, got:\n%s", fragment, pdf)
+		}
+	}
+}
+
+func TestDocWriter_AddOutline_None(t *testing.T) {
+	var buf bytes.Buffer
+	dw := NewDocWriter()
+	if _, err := dw.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	check(t, strings.Contains(buf.String(), "/PageMode /UseNone \n"), "PageMode should stay UseNone without outlines")
+}
+
 func TestDocWriter_AddFontSource(t *testing.T) {
 	dw := NewDocWriter()
 	check(t, len(dw.fontSources) == 0, "No font sources should exist.")
@@ -46,8 +46,12 @@ func (v VerticalTextAlign) String() string {
 }
 
 type drawState struct {
+	blendMode       string
 	charSpacing     float64
 	fillColor       colors.Color
+	fillGradient    *Gradient
+	fillPattern     string
+	fillOpacity     float64
 	fontColor       colors.Color
 	fontKey         string
 	fontSize        float64
@@ -60,6 +64,7 @@ type drawState struct {
 	lineWidth       float64
 	loc             Location
 	strikeout       bool
+	strokeOpacity   float64
 	underline       bool
 	vTextAlign      VerticalTextAlign
 	wordSpacing     float64
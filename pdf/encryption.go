@@ -0,0 +1,339 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rc4"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"unicode/utf8"
+)
+
+// EncryptionMethod selects the cipher used by the standard security handler.
+type EncryptionMethod int
+
+const (
+	// AES128 encrypts with 128-bit AES (security handler revision 4, PDF 1.6).
+	AES128 EncryptionMethod = iota
+	// AES256 encrypts with 256-bit AES (security handler revision 6, PDF 2.0).
+	AES256
+)
+
+// Permissions are the operations allowed when an encrypted document is opened
+// with the user password rather than the owner password (PDF spec §7.6.4.2).
+type Permissions uint32
+
+const (
+	PermitPrint                   Permissions = 1 << 2
+	PermitModify                  Permissions = 1 << 3
+	PermitCopy                    Permissions = 1 << 4
+	PermitAnnotate                Permissions = 1 << 5
+	PermitFillForms               Permissions = 1 << 8
+	PermitExtractForAccessibility Permissions = 1 << 9
+	PermitAssemble                Permissions = 1 << 10
+	PermitPrintHighQuality        Permissions = 1 << 11
+
+	PermitAll = PermitPrint | PermitModify | PermitCopy | PermitAnnotate | PermitFillForms |
+		PermitExtractForAccessibility | PermitAssemble | PermitPrintHighQuality
+)
+
+// pValue returns permissions as the signed /P entry, with the reserved bits
+// set as the spec requires.
+func (p Permissions) pValue() int32 {
+	return int32(0xFFFFF0C0 | uint32(p&PermitAll))
+}
+
+type encryptionSettings struct {
+	method        EncryptionMethod
+	userPassword  string
+	ownerPassword string
+	permissions   Permissions
+}
+
+// securityHandler encrypts the strings and streams of each indirect object
+// with the standard security handler (PDF spec §7.6.4).
+type securityHandler struct {
+	revision int
+	key      []byte
+	dict     *dictionaryObject
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	rand.Read(b)
+	return b
+}
+
+// newSecurityHandler derives the file encryption key and builds the /Encrypt
+// dictionary. id is the first element of the trailer's /ID array. An empty
+// owner password is replaced with a random one so that permissions cannot be
+// bypassed by opening the document as its owner.
+func newSecurityHandler(seq, gen int, settings *encryptionSettings, id []byte) *securityHandler {
+	owner := settings.ownerPassword
+	if owner == "" {
+		owner = hex.EncodeToString(randomBytes(16))
+	}
+	p := settings.permissions.pValue()
+	d := newDictionaryObject(seq, gen)
+	d.dict["Filter"] = name("Standard")
+	d.dict["P"] = integer(p)
+	d.dict["EncryptMetadata"] = boolean(true)
+	d.dict["StmF"] = name("StdCF")
+	d.dict["StrF"] = name("StdCF")
+	sh := &securityHandler{dict: d}
+	switch settings.method {
+	case AES256:
+		sh.revision = 6
+		sh.key = randomBytes(32)
+		user, owner := saslPassword(settings.userPassword), saslPassword(owner)
+		u, ue := userValuesR6(user, sh.key)
+		o, oe := ownerValuesR6(owner, u, sh.key)
+		d.dict["V"] = integer(5)
+		d.dict["R"] = integer(6)
+		d.dict["Length"] = integer(256)
+		d.dict["CF"] = cryptFilters("AESV3", 32)
+		d.dict["U"] = hexString(u)
+		d.dict["UE"] = hexString(ue)
+		d.dict["O"] = hexString(o)
+		d.dict["OE"] = hexString(oe)
+		d.dict["Perms"] = hexString(permsValue(p, sh.key))
+	default:
+		sh.revision = 4
+		user, owner := pdfDocPassword(settings.userPassword), pdfDocPassword(owner)
+		o := ownerValueR4(owner, user)
+		sh.key = fileKeyR4(user, o, p, id)
+		d.dict["V"] = integer(4)
+		d.dict["R"] = integer(4)
+		d.dict["Length"] = integer(128)
+		d.dict["CF"] = cryptFilters("AESV2", 16)
+		d.dict["U"] = hexString(userValueR4(sh.key, id))
+		d.dict["O"] = hexString(o)
+	}
+	return sh
+}
+
+func cryptFilters(method string, length int) dictionary {
+	return dictionary{"StdCF": dictionary{
+		"Type":      name("CryptFilter"),
+		"CFM":       name(method),
+		"AuthEvent": name("DocOpen"),
+		"Length":    integer(length),
+	}}
+}
+
+// objectKey returns the key for the strings and streams of object seq
+// (PDF spec §7.6.2, algorithm 1). Revision 6 uses the file key throughout.
+func (sh *securityHandler) objectKey(seq, gen int) []byte {
+	if sh.revision >= 5 {
+		return sh.key
+	}
+	h := md5.New()
+	h.Write(sh.key)
+	h.Write([]byte{byte(seq), byte(seq >> 8), byte(seq >> 16), byte(gen), byte(gen >> 8)})
+	h.Write([]byte("sAlT"))
+	return h.Sum(nil)
+}
+
+// writerFor returns a writer for the body of object seq whose strings and
+// streams are to be encrypted.
+func (sh *securityHandler) writerFor(w io.Writer, seq, gen int) *encryptingWriter {
+	return &encryptingWriter{Writer: w, key: sh.objectKey(seq, gen)}
+}
+
+// encryptingWriter is handed to the writers of an indirect object in an
+// encrypted document. str, hexString and stream check for it and write
+// their contents encrypted with the object's key.
+type encryptingWriter struct {
+	io.Writer
+	key []byte
+}
+
+// encrypt returns data encrypted with AES in CBC mode, prefixed with a
+// random initialization vector and padded per RFC 8018.
+func (ew *encryptingWriter) encrypt(data []byte) []byte {
+	block, _ := aes.NewCipher(ew.key)
+	pad := aes.BlockSize - len(data)%aes.BlockSize
+	out := make([]byte, aes.BlockSize+len(data)+pad)
+	iv := out[:aes.BlockSize]
+	rand.Read(iv)
+	copy(out[aes.BlockSize:], data)
+	for i := aes.BlockSize + len(data); i < len(out); i++ {
+		out[i] = byte(pad)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(out[aes.BlockSize:], out[aes.BlockSize:])
+	return out
+}
+
+// ── Revision 4 (AES-128) ─────────────────────────────────────────────────────
+
+var passwordPadding = []byte{
+	0x28, 0xBF, 0x4E, 0x5E, 0x4E, 0x75, 0x8A, 0x41, 0x64, 0x00, 0x4E, 0x56, 0xFF, 0xFA, 0x01, 0x08,
+	0x2E, 0x2E, 0x00, 0xB6, 0xD0, 0x68, 0x3E, 0x80, 0x2F, 0x0C, 0xA9, 0xFE, 0x64, 0x53, 0x69, 0x7A,
+}
+
+// pdfDocPassword encodes a revision 4 password. Characters outside Latin-1
+// cannot be typed into a PDFDocEncoding password and are dropped.
+func pdfDocPassword(password string) []byte {
+	b := make([]byte, 0, len(password))
+	for _, r := range password {
+		if r < 0x100 {
+			b = append(b, byte(r))
+		}
+	}
+	return b
+}
+
+func padPassword(password []byte) []byte {
+	padded := make([]byte, 32)
+	n := copy(padded, password)
+	copy(padded[n:], passwordPadding)
+	return padded
+}
+
+// rc4Rounds encrypts data in place 20 times with key XORed with the round
+// number, as used by algorithms 3 and 5.
+func rc4Rounds(key, data []byte) {
+	k := make([]byte, len(key))
+	for i := 0; i < 20; i++ {
+		for j := range key {
+			k[j] = key[j] ^ byte(i)
+		}
+		c, _ := rc4.NewCipher(k)
+		c.XORKeyStream(data, data)
+	}
+}
+
+// ownerValueR4 computes the /O entry (algorithm 3).
+func ownerValueR4(owner, user []byte) []byte {
+	h := md5.Sum(padPassword(owner))
+	for i := 0; i < 50; i++ {
+		h = md5.Sum(h[:])
+	}
+	o := padPassword(user)
+	rc4Rounds(h[:], o)
+	return o
+}
+
+// fileKeyR4 computes the file encryption key (algorithm 2).
+func fileKeyR4(user, o []byte, p int32, id []byte) []byte {
+	h := md5.New()
+	h.Write(padPassword(user))
+	h.Write(o)
+	binary.Write(h, binary.LittleEndian, p)
+	h.Write(id)
+	key := h.Sum(nil)
+	for i := 0; i < 50; i++ {
+		sum := md5.Sum(key)
+		key = sum[:]
+	}
+	return key
+}
+
+// userValueR4 computes the /U entry (algorithm 5). Only the first 16 bytes
+// are significant; the remainder is arbitrary padding.
+func userValueR4(key, id []byte) []byte {
+	h := md5.New()
+	h.Write(passwordPadding)
+	h.Write(id)
+	u := h.Sum(nil)
+	rc4Rounds(key, u)
+	return append(u, passwordPadding[:16]...)
+}
+
+// ── Revision 6 (AES-256) ─────────────────────────────────────────────────────
+
+// saslPassword encodes a revision 6 password as UTF-8 truncated to 127
+// bytes. Passwords are expected to be already normalized; SASLprep
+// (RFC 4013) is not applied.
+func saslPassword(password string) []byte {
+	b := []byte(password)
+	if len(b) <= 127 {
+		return b
+	}
+	b = b[:127]
+	for len(b) > 0 && !utf8.Valid(b) {
+		b = b[:len(b)-1]
+	}
+	return b
+}
+
+// hashR6 computes the revision 6 password hash (PDF 2.0 algorithm 2.B).
+// userKey is the 48-byte /U value when hashing an owner password.
+func hashR6(password, salt, userKey []byte) []byte {
+	h := sha256.New()
+	h.Write(password)
+	h.Write(salt)
+	h.Write(userKey)
+	k := h.Sum(nil)
+	var e []byte
+	for round := 0; round < 64 || int(e[len(e)-1]) > round-32; round++ {
+		seq := make([]byte, 0, len(password)+len(k)+len(userKey))
+		seq = append(append(append(seq, password...), k...), userKey...)
+		k1 := bytes.Repeat(seq, 64)
+		block, _ := aes.NewCipher(k[:16])
+		e = make([]byte, len(k1))
+		cipher.NewCBCEncrypter(block, k[16:32]).CryptBlocks(e, k1)
+		sum := 0
+		for _, b := range e[:16] {
+			sum += int(b)
+		}
+		switch sum % 3 {
+		case 0:
+			s := sha256.Sum256(e)
+			k = s[:]
+		case 1:
+			s := sha512.Sum384(e)
+			k = s[:]
+		default:
+			s := sha512.Sum512(e)
+			k = s[:]
+		}
+	}
+	return k[:32]
+}
+
+// encryptKeyR6 encrypts the file key for /UE and /OE: AES-256 in CBC mode
+// with a zero initialization vector and no padding.
+func encryptKeyR6(intermediate, fileKey []byte) []byte {
+	block, _ := aes.NewCipher(intermediate)
+	out := make([]byte, len(fileKey))
+	cipher.NewCBCEncrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(out, fileKey)
+	return out
+}
+
+// userValuesR6 computes the /U and /UE entries (algorithm 8).
+func userValuesR6(user, fileKey []byte) (u, ue []byte) {
+	validationSalt, keySalt := randomBytes(8), randomBytes(8)
+	u = append(append(hashR6(user, validationSalt, nil), validationSalt...), keySalt...)
+	ue = encryptKeyR6(hashR6(user, keySalt, nil), fileKey)
+	return
+}
+
+// ownerValuesR6 computes the /O and /OE entries (algorithm 9).
+func ownerValuesR6(owner, u, fileKey []byte) (o, oe []byte) {
+	validationSalt, keySalt := randomBytes(8), randomBytes(8)
+	o = append(append(hashR6(owner, validationSalt, u), validationSalt...), keySalt...)
+	oe = encryptKeyR6(hashR6(owner, keySalt, u), fileKey)
+	return
+}
+
+// permsValue computes the /Perms entry (algorithm 10), letting readers
+// detect tampering with /P.
+func permsValue(p int32, fileKey []byte) []byte {
+	perms := make([]byte, 16)
+	binary.LittleEndian.PutUint32(perms, uint32(p))
+	copy(perms[4:], []byte{0xFF, 0xFF, 0xFF, 0xFF, 'T', 'a', 'd', 'b'})
+	rand.Read(perms[12:])
+	block, _ := aes.NewCipher(fileKey)
+	block.Encrypt(perms, perms)
+	return perms
+}
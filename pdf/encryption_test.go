@@ -0,0 +1,184 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rc4"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/rowland/leadtype/afm_fonts"
+)
+
+func aesDecrypt(t *testing.T, key, data []byte) []byte {
+	t.Helper()
+	if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		t.Fatalf("invalid ciphertext length %d", len(data))
+	}
+	block, _ := aes.NewCipher(key)
+	out := make([]byte, len(data)-aes.BlockSize)
+	cipher.NewCBCDecrypter(block, data[:aes.BlockSize]).CryptBlocks(out, data[aes.BlockSize:])
+	pad := int(out[len(out)-1])
+	return out[:len(out)-pad]
+}
+
+func TestPermissions_pValue(t *testing.T) {
+	expectI(t, -3904, int(Permissions(0).pValue()))
+	expectI(t, -3884, int((PermitPrint | PermitCopy).pValue()))
+	expectI(t, -4, int(PermitAll.pValue()))
+}
+
+func TestEncryptingWriter(t *testing.T) {
+	var buf bytes.Buffer
+	key := bytes.Repeat([]byte{7}, 16)
+	ew := &encryptingWriter{Writer: &buf, key: key}
+
+	str("Confidential").write(ew)
+	s := buf.String()
+	check(t, strings.HasPrefix(s, "<") && strings.HasSuffix(s, "> "), "Encrypted strings should be written in hex")
+	expectI(t, 2*32+3, len(s))
+
+	data := []byte("0 0 m 100 100 l S")
+	expectS(t, string(data), string(aesDecrypt(t, key, ew.encrypt(data))))
+	expectI(t, 32, len(ew.encrypt(nil)))
+}
+
+func TestStream_Encrypted(t *testing.T) {
+	var buf bytes.Buffer
+	key := bytes.Repeat([]byte{9}, 32)
+	s := newStream(1, 0, []byte("BT /F1 12 Tf (Secret) Tj ET"))
+	s.writeBody(&encryptingWriter{Writer: &buf, key: key})
+
+	out := buf.String()
+	check(t, !strings.Contains(out, "Secret"), "Stream data should be encrypted")
+	expectS(t, "48 ", stringFromWriter(s.dict["Length"]))
+	i := strings.Index(out, "stream\n") + len("stream\n")
+	expectS(t, "BT /F1 12 Tf (Secret) Tj ET", string(aesDecrypt(t, key, []byte(out[i:i+48]))))
+}
+
+func TestSecurityHandler_AES128(t *testing.T) {
+	id := []byte("0123456789abcdef")
+	settings := &encryptionSettings{method: AES128, userPassword: "user", ownerPassword: "owner", permissions: PermitPrint}
+	sh := newSecurityHandler(1, 0, settings, id)
+	d := sh.dict.dict
+	expectS(t, "4 ", stringFromWriter(d["V"]))
+	expectS(t, "4 ", stringFromWriter(d["R"]))
+	expectS(t, "/AESV2 ", stringFromWriter(d["CF"].(dictionary)["StdCF"].(dictionary)["CFM"]))
+	o, u := []byte(d["O"].(hexString)), []byte(d["U"].(hexString))
+	expectI(t, 32, len(o))
+	expectI(t, 32, len(u))
+
+	// Algorithm 6: the user password yields the file key that reproduces /U.
+	p := PermitPrint.pValue()
+	key := fileKeyR4([]byte("user"), o, p, id)
+	check(t, bytes.Equal(key, sh.key), "User password should yield the file key")
+	check(t, bytes.Equal(userValueR4(key, id)[:16], u[:16]), "User password should authenticate")
+
+	// Algorithm 7: decrypting /O with the owner password yields the user password.
+	h := md5.Sum(padPassword([]byte("owner")))
+	for i := 0; i < 50; i++ {
+		h = md5.Sum(h[:])
+	}
+	userPadded := append([]byte(nil), o...)
+	k := make([]byte, 16)
+	for i := 19; i >= 0; i-- {
+		for j := range k {
+			k[j] = h[j] ^ byte(i)
+		}
+		c, _ := rc4.NewCipher(k)
+		c.XORKeyStream(userPadded, userPadded)
+	}
+	check(t, bytes.Equal(padPassword([]byte("user")), userPadded), "Owner password should recover the user password")
+
+	check(t, !bytes.Equal(sh.objectKey(1, 0), sh.objectKey(2, 0)), "Each object should have its own key")
+	expectI(t, 16, len(sh.objectKey(1, 0)))
+}
+
+func TestSecurityHandler_AES256(t *testing.T) {
+	settings := &encryptionSettings{method: AES256, userPassword: "user", ownerPassword: "owner", permissions: PermitPrint | PermitCopy}
+	sh := newSecurityHandler(1, 0, settings, nil)
+	d := sh.dict.dict
+	expectS(t, "5 ", stringFromWriter(d["V"]))
+	expectS(t, "6 ", stringFromWriter(d["R"]))
+	expectS(t, "/AESV3 ", stringFromWriter(d["CF"].(dictionary)["StdCF"].(dictionary)["CFM"]))
+	u, ue := []byte(d["U"].(hexString)), []byte(d["UE"].(hexString))
+	o, oe := []byte(d["O"].(hexString)), []byte(d["OE"].(hexString))
+	expectI(t, 48, len(u))
+	expectI(t, 48, len(o))
+
+	decryptKey := func(intermediate, encrypted []byte) []byte {
+		block, _ := aes.NewCipher(intermediate)
+		key := make([]byte, len(encrypted))
+		cipher.NewCBCDecrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(key, encrypted)
+		return key
+	}
+
+	// Algorithm 11: authenticate the user password and recover the key.
+	check(t, bytes.Equal(hashR6([]byte("user"), u[32:40], nil), u[:32]), "User password should authenticate")
+	check(t, !bytes.Equal(hashR6([]byte("wrong"), u[32:40], nil), u[:32]), "Wrong password should not authenticate")
+	check(t, bytes.Equal(decryptKey(hashR6([]byte("user"), u[40:48], nil), ue), sh.key), "/UE should hold the file key")
+
+	// Algorithm 12: authenticate the owner password and recover the key.
+	check(t, bytes.Equal(hashR6([]byte("owner"), o[32:40], u), o[:32]), "Owner password should authenticate")
+	check(t, bytes.Equal(decryptKey(hashR6([]byte("owner"), o[40:48], u), oe), sh.key), "/OE should hold the file key")
+
+	// Algorithm 13: /Perms matches /P.
+	perms := make([]byte, 16)
+	block, _ := aes.NewCipher(sh.key)
+	block.Decrypt(perms, []byte(d["Perms"].(hexString)))
+	expectS(t, "Tadb", string(perms[8:12]))
+	expectI(t, int((PermitPrint | PermitCopy).pValue()), int(int32(binary.LittleEndian.Uint32(perms))))
+
+	check(t, bytes.Equal(sh.objectKey(1, 0), sh.objectKey(2, 0)), "Revision 6 should use the file key for every object")
+}
+
+func TestSaslPassword(t *testing.T) {
+	expectS(t, "pässword", string(saslPassword("pässword")))
+	long := strings.Repeat("é", 64)
+	b := saslPassword(long)
+	expectI(t, 126, len(b))
+	expectS(t, strings.Repeat("é", 63), string(b))
+}
+
+func TestDocWriter_SetEncryption(t *testing.T) {
+	for _, tc := range []struct {
+		method  EncryptionMethod
+		version string
+	}{
+		{AES128, "%PDF-1.6\n"},
+		{AES256, "%PDF-2.0\n"},
+	} {
+		dw := NewDocWriter()
+		fonts, err := afm_fonts.Default()
+		if err != nil {
+			t.Fatal(err)
+		}
+		dw.AddFontSource(fonts)
+		dw.CompressPages(false).SetTitle("Payroll").SetEncryption(tc.method, "user", "owner", PermitPrint)
+		dw.NewPage()
+		if _, err := dw.SetFont("Helvetica", 12, nil); err != nil {
+			t.Fatal(err)
+		}
+		dw.Print("Salary")
+
+		var buf bytes.Buffer
+		if _, err := dw.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		pdf := buf.String()
+		check(t, strings.Contains(string(dw.pages[0].page.contents[0].data), "(Salary) Tj"), "Text should be drawn")
+		check(t, strings.HasPrefix(pdf, tc.version), "Should raise version for "+tc.version)
+		check(t, !strings.Contains(pdf, "Payroll"), "Strings should be encrypted")
+		check(t, !strings.Contains(pdf, "Salary"), "Streams should be encrypted")
+		check(t, strings.Contains(pdf, "/Filter /Standard \n"), "Should include the encryption dictionary")
+		check(t, strings.Contains(pdf, "/Encrypt "+stringFromWriter(&indirectObjectRef{dw.file.body.security.dict})),
+			"Trailer should reference the encryption dictionary")
+		check(t, strings.Contains(pdf, "/ID [<"), "Trailer should include the file identifier")
+	}
+}
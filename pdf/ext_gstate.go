@@ -0,0 +1,55 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BlendModes lists the separable and non-separable blend modes defined by the
+// PDF spec (§11.3.5), in the spelling written to the /BM entry.
+var BlendModes = []string{
+	"Normal", "Multiply", "Screen", "Overlay", "Darken", "Lighten",
+	"ColorDodge", "ColorBurn", "HardLight", "SoftLight", "Difference", "Exclusion",
+	"Hue", "Saturation", "Color", "Luminosity",
+}
+
+// lookupBlendMode returns the canonical spelling of mode, ignoring case and
+// accepting CSS-style hyphenated names such as "color-dodge".
+func lookupBlendMode(mode string) (string, bool) {
+	key := strings.ReplaceAll(mode, "-", "")
+	for _, bm := range BlendModes {
+		if strings.EqualFold(bm, key) {
+			return bm, true
+		}
+	}
+	return "", false
+}
+
+func newExtGState(seq, gen int, fillOpacity, strokeOpacity float64, blendMode string) *dictionaryObject {
+	gs := newDictionaryObject(seq, gen)
+	gs.dict["Type"] = name("ExtGState")
+	gs.dict["ca"] = real(fillOpacity)
+	gs.dict["CA"] = real(strokeOpacity)
+	gs.dict["BM"] = name(blendMode)
+	return gs
+}
+
+// extGState returns the resource name of a graphics state parameter dictionary
+// with the given transparency settings, creating it on first use so that each
+// distinct combination is written only once.
+func (dw *DocWriter) extGState(fillOpacity, strokeOpacity float64, blendMode string) string {
+	key := fmt.Sprintf("%s/%s/%s", g(fillOpacity), g(strokeOpacity), blendMode)
+	if name, ok := dw.extGStates[key]; ok {
+		return name
+	}
+	gs := newExtGState(dw.nextSeq(), 0, fillOpacity, strokeOpacity, blendMode)
+	dw.file.body.add(gs)
+	name := fmt.Sprintf("GS%d", len(dw.extGStates))
+	dw.resources.setExtGState(name, &indirectObjectRef{gs})
+	dw.extGStates[key] = name
+	dw.requireVersion(1.4)
+	return name
+}
@@ -0,0 +1,643 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rowland/leadtype/codepage"
+	"github.com/rowland/leadtype/colors"
+	"github.com/rowland/leadtype/options"
+	"github.com/rowland/leadtype/rich_text"
+	"github.com/rowland/leadtype/wordbreaking"
+)
+
+var errDuplicateFieldName = errors.New("form field name already in use")
+var errEmptyFieldName = errors.New("form field name must not be empty")
+var errNoFieldFont = errors.New("form fields require a font; call SetFont first")
+
+// Field flags (PDF spec §12.7.4).
+const (
+	fieldReadOnly      = 1 << 0
+	fieldRequired      = 1 << 1
+	fieldNoExport      = 1 << 2
+	fieldMultiline     = 1 << 12
+	fieldPassword      = 1 << 13
+	fieldNoToggleToOff = 1 << 14
+	fieldRadio         = 1 << 15
+	fieldCombo         = 1 << 17
+	fieldEdit          = 1 << 18
+	fieldSort          = 1 << 19
+	fieldMultiSelect   = 1 << 21
+)
+
+// annotPrint is the annotation flag that makes widgets appear when printed.
+const annotPrint = 1 << 2
+
+// fieldPadding is the gap, in points, between a field's border and its text.
+const fieldPadding = 2
+
+// selectionColor highlights the selected items of a list box.
+const selectionColor = colors.Color(0x99C1DA)
+
+// acroForm is the document's interactive form dictionary (PDF spec §12.7.3).
+// Its default resources are the document's shared resources, so field
+// appearances and viewers editing fields can find the fonts named in /DA.
+type acroForm struct {
+	dictionaryObject
+	fields []seqGen
+	names  map[string]seqGen
+}
+
+func newAcroForm(seq, gen int, resources writer) *acroForm {
+	af := &acroForm{names: make(map[string]seqGen)}
+	af.dictionaryObject.init(seq, gen)
+	af.dict["DR"] = resources
+	return af
+}
+
+func (af *acroForm) add(fieldName string, field seqGen) {
+	af.fields = append(af.fields, field)
+	af.names[fieldName] = field
+}
+
+func (af *acroForm) write(w io.Writer) {
+	fields := make(array, len(af.fields))
+	for i, f := range af.fields {
+		fields[i] = &indirectObjectRef{f}
+	}
+	af.dict["Fields"] = fields
+	af.dictionaryObject.write(w)
+}
+
+// radioGroup is the parent field of a set of radio button widgets, at most
+// one of which is on at a time.
+type radioGroup struct {
+	dictionaryObject
+	kids []*annotation
+}
+
+func newRadioGroup(seq, gen int, groupName string, flags int) *radioGroup {
+	rg := new(radioGroup)
+	rg.dictionaryObject.init(seq, gen)
+	rg.dict["FT"] = name("Btn")
+	rg.dict["T"] = textString(groupName)
+	rg.dict["Ff"] = integer(flags | fieldRadio | fieldNoToggleToOff)
+	rg.dict["V"] = name("Off")
+	return rg
+}
+
+func (rg *radioGroup) write(w io.Writer) {
+	kids := make(array, len(rg.kids))
+	for i, k := range rg.kids {
+		kids[i] = &indirectObjectRef{k}
+	}
+	rg.dict["Kids"] = kids
+	rg.dictionaryObject.write(w)
+}
+
+// form returns the document's interactive form, creating it on first use.
+func (dw *DocWriter) form() *acroForm {
+	if dw.acroForm == nil {
+		dw.acroForm = newAcroForm(dw.nextSeq(), 0, &indirectObjectRef{dw.resources})
+		if dw.update != nil {
+			// Fields of the existing form keep their names.
+			for fieldName := range dw.update.fieldNames {
+				dw.acroForm.names[fieldName] = nil
+			}
+		}
+		dw.file.body.add(dw.acroForm)
+		dw.catalog.dict["AcroForm"] = &indirectObjectRef{dw.acroForm}
+	}
+	return dw.acroForm
+}
+
+// appearanceStream adds a form XObject of the given size, drawn by data with
+// the document's shared resources, for use in a widget's /AP dictionary.
+func (dw *DocWriter) appearanceStream(width, height float64, data []byte) *stream {
+	s := newFormXObject(dw.nextSeq(), 0, rectangle{0, 0, width, height}, data, &indirectObjectRef{dw.resources})
+	if dw.compressPages {
+		if err := s.compress(); err != nil {
+			panic(err)
+		}
+	}
+	dw.file.body.add(s)
+	return s
+}
+
+// fieldStyle holds the border and background of a widget, as given by the
+// "border", "border_color", "border_width" and "background_color" options.
+type fieldStyle struct {
+	border          bool
+	borderColor     colors.Color
+	borderWidth     float64
+	background      bool
+	backgroundColor colors.Color
+}
+
+func newFieldStyle(options options.Options) (fs fieldStyle) {
+	fs.border = options.BoolDefault("border", true)
+	fs.borderColor = options.ColorDefault("border_color", colors.Black)
+	fs.borderWidth = options.FloatDefault("border_width", 1)
+	if fs.borderWidth <= 0 {
+		fs.border = false
+	}
+	if !fs.border {
+		fs.borderWidth = 0
+	}
+	_, fs.background = options["background_color"]
+	fs.backgroundColor = options.ColorDefault("background_color", colors.White)
+	return
+}
+
+// characteristics returns the widget's /MK dictionary, from which viewers
+// regenerate its appearance.
+func (fs fieldStyle) characteristics() dictionary {
+	mk := dictionary{}
+	if fs.border {
+		mk["BC"] = rgbArray(fs.borderColor)
+	}
+	if fs.background {
+		mk["BG"] = rgbArray(fs.backgroundColor)
+	}
+	return mk
+}
+
+// drawBox paints the background and border of a widget of the given size.
+func (fs fieldStyle) drawBox(w io.Writer, width, height float64) {
+	gw, mw := newGraphWriter(w), newMiscWriter(w)
+	if fs.background {
+		mw.setRgbColorFill(fs.backgroundColor.RGB64())
+		gw.rectangle(0, 0, width, height)
+		gw.fill()
+	}
+	if fs.border {
+		mw.setRgbColorStroke(fs.borderColor.RGB64())
+		gw.setLineWidth(fs.borderWidth)
+		gw.rectangle(fs.borderWidth/2, fs.borderWidth/2, width-fs.borderWidth, height-fs.borderWidth)
+		gw.stroke()
+	}
+}
+
+// fieldFlags returns the flags common to every field type.
+func fieldFlags(options options.Options) (flags int) {
+	if options.BoolDefault("readonly", false) {
+		flags |= fieldReadOnly
+	}
+	if options.BoolDefault("required", false) {
+		flags |= fieldRequired
+	}
+	if options.BoolDefault("no_export", false) {
+		flags |= fieldNoExport
+	}
+	return
+}
+
+func fieldQuadding(align string) int {
+	switch align {
+	case "center":
+		return 1
+	case "right":
+		return 2
+	default:
+		return 0
+	}
+}
+
+// stringsOption returns the value of key as a list of strings, accepting
+// either a single string or a []string.
+func stringsOption(options options.Options, key string) []string {
+	switch value := options[key].(type) {
+	case string:
+		return []string{value}
+	case []string:
+		return value
+	}
+	return nil
+}
+
+// checkFieldName reports whether fieldName may be used for a new field.
+func (pw *PageWriter) checkFieldName(fieldName string) error {
+	if fieldName == "" {
+		return errEmptyFieldName
+	}
+	if pw.template != nil {
+		return errTemplateField
+	}
+	if _, ok := pw.dw.form().names[fieldName]; ok {
+		return errDuplicateFieldName
+	}
+	return nil
+}
+
+// newWidget places a widget annotation over the given rectangle of the page.
+func (pw *PageWriter) newWidget(x, y, width, height float64, style fieldStyle, options options.Options) *annotation {
+	a := newAnnotation(pw.dw.nextSeq(), 0, "Widget", pw.annotRect(x, y, width, height))
+	a.dict["F"] = integer(annotPrint)
+	a.dict["P"] = &indirectObjectRef{pw.page}
+	if mk := style.characteristics(); len(mk) > 0 {
+		a.dict["MK"] = mk
+	}
+	if style.border {
+		a.dict["BS"] = dictionary{"W": real(style.borderWidth), "S": name("S")}
+	}
+	if tooltip := options.StringDefault("tooltip", ""); tooltip != "" {
+		a.dict["TU"] = textString(tooltip)
+	}
+	return a
+}
+
+// addField registers widget, which doubles as its field dictionary, under
+// fieldName in the form and on the page.
+func (pw *PageWriter) addField(fieldName, fieldType string, flags int, widget *annotation) {
+	widget.dict["FT"] = name(fieldType)
+	widget.dict["T"] = textString(fieldName)
+	if flags != 0 {
+		widget.dict["Ff"] = integer(flags)
+	}
+	pw.addAnnot(widget)
+	pw.dw.form().add(fieldName, widget)
+}
+
+// defaultAppearance returns the /DA string naming the font, size and color
+// with which viewers draw text typed into a field.
+func (pw *PageWriter) defaultAppearance(size float64) string {
+	f := pw.fonts[0]
+	var key string
+	if f.SubType() == "TrueType" {
+		key = pw.dw.fontKeyUnicode(f)
+	} else {
+		key = pw.dw.fontKey(f, codepage.Idx_ISO_8859_1)
+	}
+	red, green, blue := pw.fontColor.RGB64()
+	return fmt.Sprintf("/%s %s Tf %s %s %s rg", key, g(size), g(red), g(green), g(blue))
+}
+
+// fieldText returns text set in the page's current fonts and font color.
+func (pw *PageWriter) fieldText(text string, size float64) (*rich_text.RichText, error) {
+	return rich_text.New(text, pw.fonts, size, options.Options{"color": pw.fontColor})
+}
+
+// showFieldText writes rt with tw, selecting fonts as flushText does and
+// recording the glyphs used so that embedded font subsets include them.
+func (pw *PageWriter) showFieldText(tw *textWriter, rt *rich_text.RichText) {
+	var buf bytes.Buffer
+	rt.VisitAll(func(p *rich_text.RichText) {
+		if !p.IsLeaf() || p.Text == "" || p.Font == nil {
+			return
+		}
+		if p.Font.SubType() == "TrueType" {
+			// fontKeyUnicode creates the font's glyph recorder on first use.
+			tw.setFontAndSize(pw.dw.fontKeyUnicode(p.Font), p.FontSize)
+			gr := pw.dw.glyphRecorders[p.Font.PostScriptName()]
+			buf.Reset()
+			for _, r := range p.Text {
+				gid := p.Font.GlyphIndex(r)
+				if gr != nil {
+					gr.record(gid, r)
+				}
+				buf.WriteByte(byte(gid >> 8))
+				buf.WriteByte(byte(gid & 0xFF))
+			}
+			tw.show(buf.Bytes())
+			return
+		}
+		p.EachCodepage(func(cpi codepage.CodepageIndex, text string, piece *rich_text.RichText) {
+			buf.Reset()
+			if cpi >= 0 {
+				cp := cpi.Codepage()
+				for _, r := range text {
+					ch, _ := cp.CharForCodepoint(r)
+					buf.WriteByte(byte(ch))
+				}
+			}
+			tw.setFontAndSize(pw.dw.fontKey(piece.Font, cpi), piece.FontSize)
+			tw.show(buf.Bytes())
+		})
+	})
+}
+
+// textAppearance draws lines of text within a field of the given size,
+// clipped to the area inside its border, as a marked /Tx sequence (PDF spec
+// §12.7.4.3). Lines are centered vertically when there is only one and
+// start from the top otherwise.
+func (pw *PageWriter) textAppearance(w io.Writer, style fieldStyle, width, height float64, lines []*rich_text.RichText, quadding int, selected map[int]bool) {
+	gw, mw, tw := newGraphWriter(w), newMiscWriter(w), newTextWriter(w)
+	inset := style.borderWidth + fieldPadding
+	fmt.Fprint(w, "/Tx BMC\n")
+	gw.saveGraphicsState()
+	gw.rectangle(style.borderWidth, style.borderWidth, width-2*style.borderWidth, height-2*style.borderWidth)
+	gw.clip()
+	gw.newPath()
+	y := height - inset
+	if len(lines) == 1 && selected == nil {
+		y = (height+lines[0].Ascent()-lines[0].Descent())/2 - lines[0].Ascent()
+	}
+	for i, line := range lines {
+		leading := line.Leading()
+		if len(lines) > 1 || selected != nil {
+			y -= line.Ascent()
+		}
+		if selected[i] {
+			mw.setRgbColorFill(selectionColor.RGB64())
+			gw.rectangle(style.borderWidth, y+line.Descent(), width-2*style.borderWidth, line.Ascent()-line.Descent())
+			gw.fill()
+		}
+		x := inset
+		switch quadding {
+		case 1:
+			x = (width - line.Width()) / 2
+		case 2:
+			x = width - inset - line.Width()
+		}
+		mw.setRgbColorFill(pw.fontColor.RGB64())
+		tw.open()
+		tw.moveBy(x, y)
+		pw.showFieldText(tw, line)
+		tw.close()
+		y -= leading - line.Ascent()
+	}
+	gw.restoreGraphicsState()
+	fmt.Fprint(w, "EMC\n")
+}
+
+// TextField places a fillable text field named fieldName over the given
+// rectangle. Recognized options are "value", "multiline", "password",
+// "max_length", "align" ("left", "center" or "right"), "font_size",
+// "readonly", "required", "no_export", "tooltip", "border",
+// "border_color", "border_width" and "background_color". Text is set in the
+// current font and font color.
+func (pw *PageWriter) TextField(x, y, width, height float64, fieldName string, options options.Options) error {
+	if err := pw.checkFieldName(fieldName); err != nil {
+		return err
+	}
+	if len(pw.fonts) == 0 {
+		return errNoFieldFont
+	}
+	style := newFieldStyle(options)
+	size := options.FloatDefault("font_size", pw.fontSize)
+	value := options.StringDefault("value", "")
+	quadding := fieldQuadding(options.StringDefault("align", "left"))
+	flags := fieldFlags(options)
+	multiline := options.BoolDefault("multiline", false)
+	if multiline {
+		flags |= fieldMultiline
+	}
+	shown := value
+	if options.BoolDefault("password", false) {
+		flags |= fieldPassword
+		shown = strings.Repeat("*", len([]rune(value)))
+	}
+
+	widget := pw.newWidget(x, y, width, height, style, options)
+	widget.dict["DA"] = str(pw.defaultAppearance(size))
+	if quadding != 0 {
+		widget.dict["Q"] = integer(quadding)
+	}
+	if maxLen := int(options.FloatDefault("max_length", 0)); maxLen > 0 {
+		widget.dict["MaxLen"] = integer(maxLen)
+	}
+	if value != "" {
+		widget.dict["V"] = textString(value)
+	}
+
+	wpts, hpts := pw.units.toPts(width), pw.units.toPts(height)
+	var lines []*rich_text.RichText
+	if shown != "" {
+		rt, err := pw.fieldText(shown, size)
+		if err != nil {
+			return err
+		}
+		if multiline {
+			flags := make([]wordbreaking.Flags, rt.Len())
+			wordbreaking.MarkRuneAttributes(rt.String(), flags)
+			lines = rt.WrapToWidth(wpts-2*(style.borderWidth+fieldPadding), flags, false)
+		} else {
+			lines = []*rich_text.RichText{rt}
+		}
+	}
+	var buf bytes.Buffer
+	style.drawBox(&buf, wpts, hpts)
+	pw.textAppearance(&buf, style, wpts, hpts, lines, quadding, nil)
+	widget.dict["AP"] = dictionary{"N": &indirectObjectRef{pw.dw.appearanceStream(wpts, hpts, buf.Bytes())}}
+
+	pw.addField(fieldName, "Tx", flags, widget)
+	return nil
+}
+
+// choiceField places a combo box or list box; see ComboBox and ListBox.
+func (pw *PageWriter) choiceField(x, y, width, height float64, fieldName string, choices []string, flags int, options options.Options) error {
+	if err := pw.checkFieldName(fieldName); err != nil {
+		return err
+	}
+	if len(pw.fonts) == 0 {
+		return errNoFieldFont
+	}
+	style := newFieldStyle(options)
+	size := options.FloatDefault("font_size", pw.fontSize)
+	values := stringsOption(options, "value")
+	flags |= fieldFlags(options)
+	if options.BoolDefault("sort", false) {
+		flags |= fieldSort
+	}
+
+	widget := pw.newWidget(x, y, width, height, style, options)
+	widget.dict["DA"] = str(pw.defaultAppearance(size))
+	opt := make(array, len(choices))
+	for i, choice := range choices {
+		opt[i] = textString(choice)
+	}
+	widget.dict["Opt"] = opt
+	switch {
+	case len(values) == 1:
+		widget.dict["V"] = textString(values[0])
+	case len(values) > 1:
+		v := make(array, len(values))
+		for i, value := range values {
+			v[i] = textString(value)
+		}
+		widget.dict["V"] = v
+	}
+
+	wpts, hpts := pw.units.toPts(width), pw.units.toPts(height)
+	shown := values
+	var selected map[int]bool
+	if flags&fieldCombo == 0 {
+		// A list box shows every choice, highlighting those selected.
+		shown = choices
+		selected = make(map[int]bool)
+		var indices array
+		for i, choice := range choices {
+			for _, value := range values {
+				if choice == value {
+					selected[i] = true
+					indices = append(indices, integer(i))
+				}
+			}
+		}
+		if len(indices) > 0 {
+			widget.dict["I"] = indices
+		}
+	}
+	var lines []*rich_text.RichText
+	for _, s := range shown {
+		rt, err := pw.fieldText(s, size)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, rt)
+		if flags&fieldCombo != 0 {
+			break
+		}
+	}
+	var buf bytes.Buffer
+	style.drawBox(&buf, wpts, hpts)
+	pw.textAppearance(&buf, style, wpts, hpts, lines, 0, selected)
+	widget.dict["AP"] = dictionary{"N": &indirectObjectRef{pw.dw.appearanceStream(wpts, hpts, buf.Bytes())}}
+
+	pw.addField(fieldName, "Ch", flags, widget)
+	return nil
+}
+
+// ComboBox places a drop-down list named fieldName offering choices. The
+// "editable" option lets users type a value not among the choices; the
+// remaining options are as for TextField, plus "sort".
+func (pw *PageWriter) ComboBox(x, y, width, height float64, fieldName string, choices []string, options options.Options) error {
+	flags := fieldCombo
+	if options.BoolDefault("editable", false) {
+		flags |= fieldEdit
+	}
+	return pw.choiceField(x, y, width, height, fieldName, choices, flags, options)
+}
+
+// ListBox places a scrolling list named fieldName offering choices. With
+// the "multiselect" option, "value" may be a []string selecting several
+// choices; the remaining options are as for TextField, plus "sort".
+func (pw *PageWriter) ListBox(x, y, width, height float64, fieldName string, choices []string, options options.Options) error {
+	flags := 0
+	if options.BoolDefault("multiselect", false) {
+		flags |= fieldMultiSelect
+	}
+	return pw.choiceField(x, y, width, height, fieldName, choices, flags, options)
+}
+
+// buttonAppearances returns a widget's /AP dictionary with "on" and "Off"
+// states: the box alone when off, and the box with a check mark or, for
+// radio buttons, a dot when on.
+func (pw *PageWriter) buttonAppearances(style fieldStyle, width, height float64, on string, radio bool) dictionary {
+	var off, checked bytes.Buffer
+	style.drawBox(&off, width, height)
+	style.drawBox(&checked, width, height)
+	gw, mw := newGraphWriter(&checked), newMiscWriter(&checked)
+	if radio {
+		mw.setRgbColorFill(pw.fontColor.RGB64())
+		r := (min(width, height) - 2*style.borderWidth) / 4
+		circlePath(gw, width/2, height/2, r)
+		gw.fill()
+	} else {
+		mw.setRgbColorStroke(pw.fontColor.RGB64())
+		gw.setLineWidth(min(width, height) / 10)
+		gw.moveTo(width*0.2, height*0.5)
+		gw.lineTo(width*0.42, height*0.25)
+		gw.lineTo(width*0.8, height*0.75)
+		gw.stroke()
+	}
+	return dictionary{"N": dictionary{
+		on:    &indirectObjectRef{pw.dw.appearanceStream(width, height, checked.Bytes())},
+		"Off": &indirectObjectRef{pw.dw.appearanceStream(width, height, off.Bytes())},
+	}}
+}
+
+// circlePath appends a circle of radius r centered on (x, y) to the path,
+// approximated with four Bézier curves.
+func circlePath(gw *graphWriter, x, y, r float64) {
+	k := r * 0.5523
+	gw.moveTo(x+r, y)
+	gw.curveTo(x+r, y+k, x+k, y+r, x, y+r)
+	gw.curveTo(x-k, y+r, x-r, y+k, x-r, y)
+	gw.curveTo(x-r, y-k, x-k, y-r, x, y-r)
+	gw.curveTo(x+k, y-r, x+r, y-k, x+r, y)
+}
+
+// CheckBox places a check box named fieldName. Recognized options are
+// "checked", "export" (the value of the field when checked, "Yes" by
+// default), "readonly", "required", "no_export", "tooltip", "border",
+// "border_color", "border_width" and "background_color". The check mark is
+// drawn in the current font color.
+func (pw *PageWriter) CheckBox(x, y, width, height float64, fieldName string, options options.Options) error {
+	if err := pw.checkFieldName(fieldName); err != nil {
+		return err
+	}
+	style := newFieldStyle(options)
+	on := options.StringDefault("export", "Yes")
+	widget := pw.newWidget(x, y, width, height, style, options)
+	widget.dict["AP"] = pw.buttonAppearances(style, pw.units.toPts(width), pw.units.toPts(height), on, false)
+	state := name("Off")
+	if options.BoolDefault("checked", false) {
+		state = name(on)
+	}
+	widget.dict["V"] = state
+	widget.dict["AS"] = state
+	pw.addField(fieldName, "Btn", fieldFlags(options), widget)
+	return nil
+}
+
+// RadioButton places one button of the radio group named groupName, which
+// is created with the first of its buttons. Selecting the button sets the
+// group's value to value. Recognized options are "checked" and those of
+// CheckBox; "readonly", "required" and "no_export" apply to the whole group
+// and are taken from its first button.
+func (pw *PageWriter) RadioButton(x, y, width, height float64, groupName, value string, options options.Options) error {
+	if groupName == "" {
+		return errEmptyFieldName
+	}
+	if pw.template != nil {
+		return errTemplateField
+	}
+	form := pw.dw.form()
+	var group *radioGroup
+	if field, ok := form.names[groupName]; ok {
+		if group, ok = field.(*radioGroup); !ok {
+			return errDuplicateFieldName
+		}
+	} else {
+		group = newRadioGroup(pw.dw.nextSeq(), 0, groupName, fieldFlags(options))
+		pw.dw.file.body.add(group)
+		form.add(groupName, group)
+	}
+	style := newFieldStyle(options)
+	widget := pw.newWidget(x, y, width, height, style, options)
+	widget.dict["Parent"] = &indirectObjectRef{group}
+	widget.dict["AP"] = pw.buttonAppearances(style, pw.units.toPts(width), pw.units.toPts(height), value, true)
+	widget.dict["AS"] = name("Off")
+	if options.BoolDefault("checked", false) {
+		widget.dict["AS"] = name(value)
+		group.dict["V"] = name(value)
+	}
+	group.kids = append(group.kids, widget)
+	pw.addAnnot(widget)
+	return nil
+}
+
+// SignatureField places an unsigned signature field named fieldName, to be
+// signed later in a viewer or by a signing tool. Recognized options are
+// "readonly", "required", "tooltip", "border", "border_color",
+// "border_width" and "background_color".
+func (pw *PageWriter) SignatureField(x, y, width, height float64, fieldName string, options options.Options) error {
+	if err := pw.checkFieldName(fieldName); err != nil {
+		return err
+	}
+	style := newFieldStyle(options)
+	widget := pw.newWidget(x, y, width, height, style, options)
+	wpts, hpts := pw.units.toPts(width), pw.units.toPts(height)
+	var buf bytes.Buffer
+	style.drawBox(&buf, wpts, hpts)
+	widget.dict["AP"] = dictionary{"N": &indirectObjectRef{pw.dw.appearanceStream(wpts, hpts, buf.Bytes())}}
+	pw.addField(fieldName, "Sig", fieldFlags(options), widget)
+	return nil
+}
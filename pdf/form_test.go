@@ -0,0 +1,191 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rowland/leadtype/afm_fonts"
+	"github.com/rowland/leadtype/options"
+)
+
+func newFormTestPage(t *testing.T) (*DocWriter, *PageWriter) {
+	t.Helper()
+	dw := NewDocWriter().CompressPages(false)
+	fonts, err := afm_fonts.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.AddFontSource(fonts)
+	pw := dw.NewPage()
+	pw.SetUnits("in")
+	if _, err := pw.SetFont("Helvetica", 12, nil); err != nil {
+		t.Fatal(err)
+	}
+	return dw, pw
+}
+
+// normalAppearance returns the content of the /N appearance of widget, or
+// of its named state.
+func normalAppearance(widget *annotation, state string) string {
+	n := widget.dict["AP"].(dictionary)["N"]
+	if state != "" {
+		n = n.(dictionary)[state]
+	}
+	return string(n.(*indirectObjectRef).obj.(*stream).data)
+}
+
+func TestPageWriter_TextField(t *testing.T) {
+	dw, pw := newFormTestPage(t)
+	err := pw.TextField(1, 1, 3, 0.3, "name", options.Options{"value": "Jane", "required": true, "max_length": 20, "align": "right"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(t, len(pw.page.annots) == 1, "TextField should add a widget to the page")
+	widget := pw.page.annots[0]
+	expectS(t, "/Widget ", stringFromWriter(widget.dict["Subtype"]))
+	expectS(t, "/Tx ", stringFromWriter(widget.dict["FT"]))
+	expectS(t, "(name) ", stringFromWriter(widget.dict["T"]))
+	expectS(t, "(Jane) ", stringFromWriter(widget.dict["V"]))
+	expectS(t, "2 ", stringFromWriter(widget.dict["Ff"]))
+	expectS(t, "20 ", stringFromWriter(widget.dict["MaxLen"]))
+	expectS(t, "2 ", stringFromWriter(widget.dict["Q"]))
+	expectS(t, "(/F0 12 Tf 0 0 0 rg) ", stringFromWriter(widget.dict["DA"]))
+	expectS(t, "[72 698.4 288 720 ] ", stringFromWriter(widget.dict["Rect"]))
+
+	ap := normalAppearance(widget, "")
+	check(t, strings.Contains(ap, "/Tx BMC\n"), "Appearance should mark the variable text")
+	check(t, strings.Contains(ap, "/F0 12 Tf\n(Jane) Tj\n"), "Appearance should show the value in the document font")
+	check(t, dw.resources.fonts["F0"] != nil, "Appearance font should be in the shared resources")
+
+	expectS(t, stringFromWriter(&indirectObjectRef{dw.acroForm}), stringFromWriter(dw.catalog.dict["AcroForm"]))
+	expectS(t, stringFromWriter(&indirectObjectRef{dw.resources}), stringFromWriter(dw.acroForm.dict["DR"]))
+	check(t, dw.acroForm.dict["NeedAppearances"] == nil, "Appearances should not need regenerating")
+	af := stringFromWriter(dw.acroForm)
+	check(t, strings.Contains(af, "/Fields ["+stringFromWriter(&indirectObjectRef{widget})+"] "), "Form should list the field")
+}
+
+func TestPageWriter_TextField_Password(t *testing.T) {
+	_, pw := newFormTestPage(t)
+	pw.TextField(1, 1, 3, 0.3, "pin", options.Options{"value": "1234", "password": true})
+	widget := pw.page.annots[0]
+	expectS(t, "8192 ", stringFromWriter(widget.dict["Ff"]))
+	check(t, strings.Contains(normalAppearance(widget, ""), "(****) Tj"), "Password should be masked")
+}
+
+func TestPageWriter_TextField_Multiline(t *testing.T) {
+	_, pw := newFormTestPage(t)
+	pw.TextField(1, 1, 1, 1, "notes", options.Options{"value": "one two three four five six", "multiline": true})
+	widget := pw.page.annots[0]
+	expectS(t, "4096 ", stringFromWriter(widget.dict["Ff"]))
+	check(t, strings.Count(normalAppearance(widget, ""), "Tj") > 1, "Text should wrap to the field width")
+}
+
+func TestPageWriter_TextField_TrueType(t *testing.T) {
+	dw, pw := newFormTestPage(t)
+	dw.AddFontSource(testFontSource(t, "../ttf/testdata/minimal.ttf"))
+	// The fallback font is used only by the field's appearance, not its /DA.
+	fonts, err := pw.AddFont("Minimal", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pw.TextField(1, 1, 3, 0.3, "name", options.Options{"value": "中文"}); err != nil {
+		t.Fatal(err)
+	}
+	gr := dw.glyphRecorders[fonts[1].PostScriptName()]
+	check(t, gr != nil && len(gr.mapping()) == 2, "Appearance glyphs should be recorded")
+	var buf bytes.Buffer
+	if _, err := dw.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.String()
+	check(t, strings.Contains(pdf, "/FontFile2 "), "Field font should be embedded")
+	check(t, strings.Contains(pdf, "/ToUnicode "), "Field font should map glyphs to text")
+	check(t, strings.Contains(pdf, "/DW 512 "), "Field font should have its widths")
+}
+
+func TestPageWriter_TextField_Errors(t *testing.T) {
+	_, pw := newFormTestPage(t)
+	check(t, pw.TextField(1, 1, 3, 0.3, "", nil) == errEmptyFieldName, "Name is required")
+	check(t, pw.TextField(1, 1, 3, 0.3, "name", nil) == nil, "First use of name should succeed")
+	check(t, pw.TextField(1, 2, 3, 0.3, "name", nil) == errDuplicateFieldName, "Names must be unique")
+	check(t, pw.CheckBox(1, 2, 0.2, 0.2, "name", nil) == errDuplicateFieldName, "Names are shared by all field types")
+
+	dw := NewDocWriter()
+	check(t, dw.TextField(1, 1, 100, 20, "name", nil) == errNoFieldFont, "Text fields need a font")
+}
+
+func TestPageWriter_CheckBox(t *testing.T) {
+	_, pw := newFormTestPage(t)
+	pw.CheckBox(1, 1, 0.2, 0.2, "agree", options.Options{"checked": true, "export": "On"})
+	pw.CheckBox(1, 2, 0.2, 0.2, "subscribe", options.Options{"background_color": "yellow"})
+	agree, subscribe := pw.page.annots[0], pw.page.annots[1]
+	expectS(t, "/Btn ", stringFromWriter(agree.dict["FT"]))
+	expectS(t, "/On ", stringFromWriter(agree.dict["V"]))
+	expectS(t, "/On ", stringFromWriter(agree.dict["AS"]))
+	expectS(t, "/Off ", stringFromWriter(subscribe.dict["AS"]))
+	check(t, strings.Contains(normalAppearance(agree, "On"), " l\nS\n"), "On state should draw a check mark")
+	check(t, !strings.Contains(normalAppearance(agree, "Off"), " l\n"), "Off state should not draw a check mark")
+	expectS(t, "<<\n/BC [0 0 0 ] \n/BG [1 1 0 ] \n>>\n", stringFromWriter(subscribe.dict["MK"]))
+}
+
+func TestPageWriter_RadioButton(t *testing.T) {
+	dw, pw := newFormTestPage(t)
+	pw.RadioButton(1, 1, 0.2, 0.2, "size", "S", nil)
+	pw.RadioButton(2, 1, 0.2, 0.2, "size", "M", options.Options{"checked": true})
+	check(t, len(pw.page.annots) == 2, "Each button should be a widget on the page")
+	check(t, len(dw.acroForm.fields) == 1, "The group should be a single field")
+	group := dw.acroForm.fields[0].(*radioGroup)
+	expectS(t, "/M ", stringFromWriter(group.dict["V"]))
+	expectS(t, "49152 ", stringFromWriter(group.dict["Ff"]))
+	small, medium := pw.page.annots[0], pw.page.annots[1]
+	expectS(t, stringFromWriter(&indirectObjectRef{group}), stringFromWriter(small.dict["Parent"]))
+	expectS(t, "/Off ", stringFromWriter(small.dict["AS"]))
+	expectS(t, "/M ", stringFromWriter(medium.dict["AS"]))
+	check(t, small.dict["T"] == nil, "Buttons inherit the group's name")
+	check(t, strings.Contains(normalAppearance(medium, "M"), " c\nf\n"), "On state should draw a dot")
+	check(t, strings.Contains(stringFromWriter(group), "/Kids ["+stringFromWriter(&indirectObjectRef{small})+stringFromWriter(&indirectObjectRef{medium})+"] "),
+		"Group should list its buttons")
+
+	check(t, pw.TextField(1, 2, 1, 0.3, "email", nil) == nil, "Text field should be added")
+	check(t, pw.RadioButton(1, 3, 0.2, 0.2, "email", "x", nil) == errDuplicateFieldName, "Group name must not belong to another field")
+}
+
+func TestPageWriter_ListBox(t *testing.T) {
+	_, pw := newFormTestPage(t)
+	pw.ListBox(1, 1, 2, 1, "colors", []string{"Red", "Green", "Blue"},
+		options.Options{"value": []string{"Red", "Blue"}, "multiselect": true})
+	widget := pw.page.annots[0]
+	expectS(t, "/Ch ", stringFromWriter(widget.dict["FT"]))
+	expectS(t, "2097152 ", stringFromWriter(widget.dict["Ff"]))
+	expectS(t, "[(Red) (Green) (Blue) ] ", stringFromWriter(widget.dict["Opt"]))
+	expectS(t, "[(Red) (Blue) ] ", stringFromWriter(widget.dict["V"]))
+	expectS(t, "[0 2 ] ", stringFromWriter(widget.dict["I"]))
+	ap := normalAppearance(widget, "")
+	for _, choice := range []string{"(Red) Tj", "(Green) Tj", "(Blue) Tj"} {
+		check(t, strings.Contains(ap, choice), "List box should show "+choice)
+	}
+	expectI(t, 2, strings.Count(ap, "0.6 0.7569 0.8549 rg\n"))
+}
+
+func TestPageWriter_ComboBox(t *testing.T) {
+	_, pw := newFormTestPage(t)
+	pw.ComboBox(1, 1, 2, 0.3, "state", []string{"CA", "NY"}, options.Options{"value": "NY", "editable": true})
+	widget := pw.page.annots[0]
+	expectS(t, "393216 ", stringFromWriter(widget.dict["Ff"]))
+	expectS(t, "(NY) ", stringFromWriter(widget.dict["V"]))
+	ap := normalAppearance(widget, "")
+	check(t, strings.Contains(ap, "(NY) Tj") && !strings.Contains(ap, "(CA) Tj"), "Combo box should show only its value")
+}
+
+func TestPageWriter_SignatureField(t *testing.T) {
+	_, pw := newFormTestPage(t)
+	pw.SignatureField(1, 1, 3, 0.5, "signature", options.Options{"tooltip": "Sign here"})
+	widget := pw.page.annots[0]
+	expectS(t, "/Sig ", stringFromWriter(widget.dict["FT"]))
+	expectS(t, "(Sign here) ", stringFromWriter(widget.dict["TU"]))
+	check(t, widget.dict["V"] == nil, "Signature field should be unsigned")
+}
@@ -0,0 +1,214 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/rowland/leadtype/colors"
+)
+
+// GradientStop is a color at a position along a gradient, where Offset runs
+// from 0 at the start of the gradient to 1 at its end.
+type GradientStop struct {
+	Offset float64
+	Color  colors.Color
+}
+
+type gradientKind int
+
+const (
+	linearGradient gradientKind = iota
+	radialGradient
+)
+
+// Gradient is a linear or radial color transition that can be used in place
+// of a solid fill color. Coordinates and radii are interpreted in the units
+// of the PageWriter the gradient is drawn with, measured from the top-left
+// of the page like all other drawing coordinates.
+type Gradient struct {
+	kind   gradientKind
+	coords []float64
+	stops  []GradientStop
+	// ExtendStart and ExtendEnd continue the first and last colors beyond
+	// the start and end of the gradient. Both default to true.
+	ExtendStart, ExtendEnd bool
+}
+
+// NewLinearGradient returns a gradient running from (x1, y1) to (x2, y2).
+func NewLinearGradient(x1, y1, x2, y2 float64, stops ...GradientStop) *Gradient {
+	return &Gradient{
+		kind:        linearGradient,
+		coords:      []float64{x1, y1, x2, y2},
+		stops:       stops,
+		ExtendStart: true,
+		ExtendEnd:   true,
+	}
+}
+
+// NewRadialGradient returns a gradient running outward from a focal circle
+// centered at (fx, fy) with radius fr to the circle centered at (cx, cy) with
+// radius r. For a simple radial gradient, pass the center as the focal point
+// and 0 as the focal radius.
+func NewRadialGradient(cx, cy, r, fx, fy, fr float64, stops ...GradientStop) *Gradient {
+	return &Gradient{
+		kind:        radialGradient,
+		coords:      []float64{fx, fy, fr, cx, cy, r},
+		stops:       stops,
+		ExtendStart: true,
+		ExtendEnd:   true,
+	}
+}
+
+// Stops returns the color stops of the gradient.
+func (gr *Gradient) Stops() []GradientStop {
+	return gr.stops
+}
+
+// normalizedStops returns the stops sorted by offset, clamped to 0..1 and
+// padded so that the first and last stops fall at 0 and 1.
+func (gr *Gradient) normalizedStops() []GradientStop {
+	stops := make([]GradientStop, 0, len(gr.stops)+2)
+	for _, s := range gr.stops {
+		s.Offset = clamp01(s.Offset)
+		stops = append(stops, s)
+	}
+	sort.SliceStable(stops, func(i, j int) bool { return stops[i].Offset < stops[j].Offset })
+	if len(stops) == 0 {
+		stops = append(stops, GradientStop{0, colors.Black})
+	}
+	if stops[0].Offset > 0 {
+		stops = append([]GradientStop{{0, stops[0].Color}}, stops...)
+	}
+	if last := stops[len(stops)-1]; last.Offset < 1 {
+		stops = append(stops, GradientStop{1, last.Color})
+	}
+	return stops
+}
+
+func clamp01(value float64) float64 {
+	if value < 0 {
+		return 0
+	}
+	if value > 1 {
+		return 1
+	}
+	return value
+}
+
+func rgbArray(c colors.Color) array {
+	r, g, b := c.RGB64()
+	return array{real(r), real(g), real(b)}
+}
+
+// interpolationFunction returns a type 2 (exponential) function blending
+// linearly from c0 to c1 (PDF spec §7.10.3).
+func interpolationFunction(c0, c1 colors.Color) dictionary {
+	return dictionary{
+		"FunctionType": integer(2),
+		"Domain":       array{integer(0), integer(1)},
+		"C0":           rgbArray(c0),
+		"C1":           rgbArray(c1),
+		"N":            integer(1),
+	}
+}
+
+// function returns the color function of the shading: a single exponential
+// function for two stops, or a type 3 (stitching) function joining one
+// exponential function per pair of adjacent stops (PDF spec §7.10.4).
+// Coincident stops produce a hard color edge.
+func (gr *Gradient) function() dictionary {
+	stops := gr.normalizedStops()
+	var functions, bounds, encode array
+	for i := 1; i < len(stops); i++ {
+		if stops[i].Offset == stops[i-1].Offset {
+			continue
+		}
+		if len(functions) > 0 {
+			bounds = append(bounds, real(stops[i-1].Offset))
+		}
+		functions = append(functions, interpolationFunction(stops[i-1].Color, stops[i].Color))
+		encode = append(encode, integer(0), integer(1))
+	}
+	if len(functions) == 1 {
+		return functions[0].(dictionary)
+	}
+	return dictionary{
+		"FunctionType": integer(3),
+		"Domain":       array{integer(0), integer(1)},
+		"Functions":    functions,
+		"Bounds":       bounds,
+		"Encode":       encode,
+	}
+}
+
+// shading returns the shading dictionary for the gradient with its
+// coordinates converted to points in pw's default user space.
+func (gr *Gradient) shading(pw *PageWriter) dictionary {
+	toX := func(x float64) writer { return real(pw.units.toPts(x)) }
+	toY := func(y float64) writer { return real(pw.translate(pw.units.toPts(y))) }
+	toR := func(r float64) writer { return real(pw.units.toPts(r)) }
+	sh := dictionary{
+		"ColorSpace": name("DeviceRGB"),
+		"Function":   gr.function(),
+		"Extend":     array{boolean(gr.ExtendStart), boolean(gr.ExtendEnd)},
+	}
+	c := gr.coords
+	switch gr.kind {
+	case radialGradient:
+		sh["ShadingType"] = integer(3)
+		sh["Coords"] = array{toX(c[0]), toY(c[1]), toR(c[2]), toX(c[3]), toY(c[4]), toR(c[5])}
+	default:
+		sh["ShadingType"] = integer(2)
+		sh["Coords"] = array{toX(c[0]), toY(c[1]), toX(c[2]), toY(c[3])}
+	}
+	return sh
+}
+
+type shadingResource struct {
+	name string
+	obj  *dictionaryObject
+}
+
+// shading returns the resource for a shading dictionary, creating it on
+// first use so that identical shadings are written only once.
+func (dw *DocWriter) shading(sh dictionary) shadingResource {
+	var buf bytes.Buffer
+	sh.write(&buf)
+	key := buf.String()
+	if res, ok := dw.shadings[key]; ok {
+		return res
+	}
+	obj := newDictionaryObject(dw.nextSeq(), 0)
+	obj.dict = sh
+	dw.file.body.add(obj)
+	res := shadingResource{name: fmt.Sprintf("Sh%d", len(dw.shadings)), obj: obj}
+	dw.resources.setShading(res.name, &indirectObjectRef{obj})
+	dw.shadings[key] = res
+	return res
+}
+
+// shadingPattern returns the resource name of a type 2 (shading) pattern
+// painting sh, with matrix mapping the pattern into the page's default
+// coordinate space.
+func (dw *DocWriter) shadingPattern(sh shadingResource, matrix transformMatrix) string {
+	key := fmt.Sprintf("%s/%s", sh.name, float64Slice(matrix[:]).join(" "))
+	if name, ok := dw.patterns[key]; ok {
+		return name
+	}
+	pat := newDictionaryObject(dw.nextSeq(), 0)
+	pat.dict["Type"] = name("Pattern")
+	pat.dict["PatternType"] = integer(2)
+	pat.dict["Shading"] = &indirectObjectRef{sh.obj}
+	if matrix != identityMatrix {
+		pat.dict["Matrix"] = matrix.array()
+	}
+	dw.file.body.add(pat)
+	name := fmt.Sprintf("P%d", len(dw.patterns))
+	dw.resources.setPattern(name, &indirectObjectRef{pat})
+	dw.patterns[key] = name
+	return name
+}
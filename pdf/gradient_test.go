@@ -0,0 +1,117 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rowland/leadtype/colors"
+	"github.com/rowland/leadtype/options"
+)
+
+func TestGradient_normalizedStops(t *testing.T) {
+	gr := NewLinearGradient(0, 0, 1, 0,
+		GradientStop{0.75, colors.Blue},
+		GradientStop{0.25, colors.Red},
+		GradientStop{1.5, colors.Green})
+	stops := gr.normalizedStops()
+	expectI(t, 4, len(stops))
+	check(t, stops[0].Offset == 0 && stops[0].Color == colors.Red, "Should pad start with first color")
+	check(t, stops[1].Offset == 0.25 && stops[1].Color == colors.Red, "Should sort by offset")
+	check(t, stops[2].Offset == 0.75 && stops[2].Color == colors.Blue, "Should sort by offset")
+	check(t, stops[3].Offset == 1 && stops[3].Color == colors.Green, "Should clamp offsets to 1")
+}
+
+func TestGradient_function(t *testing.T) {
+	two := NewLinearGradient(0, 0, 1, 0, GradientStop{0, colors.Black}, GradientStop{1, colors.White})
+	expectS(t, "<<\n/C0 [0 0 0 ] \n/C1 [1 1 1 ] \n/Domain [0 1 ] \n/FunctionType 2 \n/N 1 \n>>\n",
+		stringFromWriter(two.function()))
+
+	hardEdge := NewLinearGradient(0, 0, 1, 0,
+		GradientStop{0, colors.Black},
+		GradientStop{0.5, colors.Black},
+		GradientStop{0.5, colors.White},
+		GradientStop{1, colors.White})
+	f := hardEdge.function()
+	expectS(t, "3 ", stringFromWriter(f["FunctionType"]))
+	expectS(t, "[0.5 ] ", stringFromWriter(f["Bounds"]))
+	expectS(t, "[0 1 0 1 ] ", stringFromWriter(f["Encode"]))
+	expectI(t, 2, len(f["Functions"].(array)))
+}
+
+func TestGradient_shading(t *testing.T) {
+	dw := NewDocWriter()
+	pw := newPageWriter(dw, options.Options{"units": "in"})
+
+	linear := NewLinearGradient(1, 1, 2, 1, GradientStop{0, colors.Red}, GradientStop{1, colors.Blue})
+	sh := linear.shading(pw)
+	expectS(t, "2 ", stringFromWriter(sh["ShadingType"]))
+	expectS(t, "[72 720 144 720 ] ", stringFromWriter(sh["Coords"]))
+	expectS(t, "[true true ] ", stringFromWriter(sh["Extend"]))
+
+	radial := NewRadialGradient(1, 1, 0.5, 1, 1, 0, GradientStop{0, colors.Red}, GradientStop{1, colors.Blue})
+	radial.ExtendEnd = false
+	sh = radial.shading(pw)
+	expectS(t, "3 ", stringFromWriter(sh["ShadingType"]))
+	expectS(t, "[72 720 0 72 720 36 ] ", stringFromWriter(sh["Coords"]))
+	expectS(t, "[true false ] ", stringFromWriter(sh["Extend"]))
+}
+
+func TestPageWriter_SetFillGradient(t *testing.T) {
+	dw := NewDocWriter()
+	pw := newPageWriter(dw, options.Options{})
+	gr := NewLinearGradient(0, 0, 100, 0, GradientStop{0, colors.Red}, GradientStop{1, colors.Blue})
+
+	check(t, pw.SetFillGradient(gr) == nil, "Should default to solid fills")
+	check(t, pw.FillGradient() == gr, "Should return gradient")
+	pw.Rectangle(0, 0, 100, 100, false, true)
+	pw.Rectangle(0, 100, 100, 100, false, true)
+	pw.SetFillGradient(nil)
+	pw.Rectangle(0, 200, 100, 100, false, true)
+	expectS(t, "/Pattern cs\n/P0 scn\n0 692 100 100 re\nf\n0 592 100 100 re\nf\n"+
+		"0 0 0 rg\n0 492 100 100 re\nf\n", pw.stream.String())
+
+	var buf bytes.Buffer
+	dw.resources.write(&buf)
+	check(t, strings.Contains(buf.String(), "/Pattern <<\n/P0 "), "Resources should include pattern")
+	check(t, strings.Contains(buf.String(), "/Shading <<\n/Sh0 "), "Resources should include shading")
+}
+
+func TestPageWriter_SetFillGradient_Transform(t *testing.T) {
+	dw := NewDocWriter()
+	pw := newPageWriter(dw, options.Options{})
+	pw.SetFillGradient(NewLinearGradient(0, 0, 100, 0))
+
+	pw.Rectangle(0, 0, 100, 100, false, true)
+	pw.Scale(0, 0, 2, 2, func() {
+		pw.Rectangle(0, 0, 100, 100, false, true)
+	})
+	check(t, len(dw.patterns) == 2, "Scaled fill should need its own pattern matrix")
+	check(t, strings.Contains(pw.stream.String(), "/P1 scn\n"), "Should select scaled pattern")
+	check(t, pw.ctm == identityMatrix, "Should restore transform after scope")
+}
+
+func TestPageWriter_PaintGradient(t *testing.T) {
+	dw := NewDocWriter()
+	pw := newPageWriter(dw, options.Options{})
+	gr := NewRadialGradient(50, 50, 50, 50, 50, 0, GradientStop{0, colors.White}, GradientStop{1, colors.Black})
+
+	pw.Path(func() {
+		pw.Rectangle2(0, 0, 100, 100, false, false, nil, true, false)
+		pw.Clip(func() {
+			pw.PaintGradient(gr)
+		})
+	})
+	expectS(t, "0 792 m\n100 792 l\n100 692 l\n0 692 l\n0 792 l\nq\nW\nn\n/Sh0 sh\nQ\n", pw.stream.String())
+	check(t, len(dw.patterns) == 0, "Shading directly should not create a pattern")
+}
+
+func TestTransformMatrix_concat(t *testing.T) {
+	scale := transformMatrix{2, 0, 0, 2, 0, 0}
+	translate := transformMatrix{1, 0, 0, 1, 10, 20}
+	check(t, translate.concat(scale) == transformMatrix{2, 0, 0, 2, 20, 40}, "Translation should be scaled")
+	check(t, scale.concat(translate) == transformMatrix{2, 0, 0, 2, 10, 20}, "Translation should follow scale")
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// iccTag is a tag of an ICC profile: its signature and its encoded data.
+type iccTag struct {
+	sig  string
+	data []byte
+}
+
+// sRGBProfile returns a version 2 ICC display profile for the sRGB color
+// space (IEC 61966-2.1), used as the output intent of PDF/A documents. It
+// is built here rather than shipped as a binary so that its contents are
+// evident: the sRGB primaries adapted to the D50 connection space and a
+// tabulated sRGB transfer curve shared by the three channels.
+func sRGBProfile() []byte {
+	trc := iccCurve(1024, func(v float64) float64 {
+		if v <= 0.04045 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	})
+	tags := []iccTag{
+		{"desc", iccTextDescription("sRGB IEC61966-2.1")},
+		{"cprt", iccText("No copyright, use freely")},
+		{"wtpt", iccXYZ(0.9505, 1.0, 1.0891)},
+		{"rXYZ", iccXYZ(0.4361, 0.2225, 0.0139)},
+		{"gXYZ", iccXYZ(0.3851, 0.7169, 0.0971)},
+		{"bXYZ", iccXYZ(0.1431, 0.0606, 0.7141)},
+		{"rTRC", trc},
+		{"gTRC", trc},
+		{"bTRC", trc},
+	}
+
+	// Tag data follows the 128-byte header and the tag table, each element
+	// aligned to four bytes. Tags with identical data share it.
+	offset := 128 + 4 + 12*len(tags)
+	var table, data bytes.Buffer
+	binary.Write(&table, binary.BigEndian, uint32(len(tags)))
+	offsets := make(map[*byte]int)
+	for _, tag := range tags {
+		tagOffset, ok := offsets[&tag.data[0]]
+		if !ok {
+			tagOffset = offset + data.Len()
+			offsets[&tag.data[0]] = tagOffset
+			data.Write(tag.data)
+			for data.Len()%4 != 0 {
+				data.WriteByte(0)
+			}
+		}
+		table.WriteString(tag.sig)
+		binary.Write(&table, binary.BigEndian, uint32(tagOffset))
+		binary.Write(&table, binary.BigEndian, uint32(len(tag.data)))
+	}
+
+	size := offset + data.Len()
+	var profile bytes.Buffer
+	binary.Write(&profile, binary.BigEndian, uint32(size))
+	profile.Write(make([]byte, 4))                                  // preferred CMM
+	binary.Write(&profile, binary.BigEndian, uint32(0x02100000))    // version 2.1
+	profile.WriteString("mntrRGB XYZ ")                             // class, color space, PCS
+	binary.Write(&profile, binary.BigEndian, [6]uint16{2026, 1, 1}) // creation date
+	profile.WriteString("acsp")
+	profile.Write(make([]byte, 28)) // platform, flags, manufacturer, model, attributes, intent
+	profile.Write(iccXYZ(0.9642, 1.0, 0.8249)[8:])
+	profile.Write(make([]byte, 48)) // creator, profile ID, reserved
+	profile.Write(table.Bytes())
+	profile.Write(data.Bytes())
+	return profile.Bytes()
+}
+
+// s15Fixed16 encodes v as a signed 15.16 fixed-point number.
+func s15Fixed16(v float64) int32 {
+	return int32(math.Round(v * 65536))
+}
+
+func iccXYZ(x, y, z float64) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("XYZ ")
+	buf.Write(make([]byte, 4))
+	binary.Write(&buf, binary.BigEndian, [3]int32{s15Fixed16(x), s15Fixed16(y), s15Fixed16(z)})
+	return buf.Bytes()
+}
+
+func iccText(s string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("text")
+	buf.Write(make([]byte, 4))
+	buf.WriteString(s)
+	buf.WriteByte(0)
+	return buf.Bytes()
+}
+
+// iccTextDescription encodes s as a version 2 textDescriptionType, with
+// empty Unicode and ScriptCode descriptions.
+func iccTextDescription(s string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("desc")
+	buf.Write(make([]byte, 4))
+	binary.Write(&buf, binary.BigEndian, uint32(len(s)+1))
+	buf.WriteString(s)
+	buf.WriteByte(0)
+	buf.Write(make([]byte, 8+2+1+67))
+	return buf.Bytes()
+}
+
+// iccCurve tabulates fn, mapping [0, 1] to [0, 1], at n evenly spaced points.
+func iccCurve(n int, fn func(float64) float64) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("curv")
+	buf.Write(make([]byte, 4))
+	binary.Write(&buf, binary.BigEndian, uint32(n))
+	for i := 0; i < n; i++ {
+		v := fn(float64(i) / float64(n-1))
+		binary.Write(&buf, binary.BigEndian, uint16(math.Round(v*65535)))
+	}
+	return buf.Bytes()
+}
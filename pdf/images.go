@@ -111,6 +111,8 @@ func imageKey(data []byte) string {
 		return fmt.Sprintf("jpeg:%x", sum)
 	case isPNG(data):
 		return fmt.Sprintf("png:%x", sum)
+	case isPDF(data):
+		return fmt.Sprintf("pdf:%x", sum)
 	default:
 		return fmt.Sprintf("image:%x", sum)
 	}
@@ -243,6 +245,17 @@ func imageInfoForData(data []byte) (imageInfo, error) {
 		}
 		return imageInfo{width: width, height: height}, nil
 	}
+	if isPDF(data) {
+		imp, err := newImportedPDF(data)
+		if err != nil {
+			return imageInfo{}, err
+		}
+		if len(imp.pages) == 0 {
+			return imageInfo{}, errPageIndex
+		}
+		width, height := imp.pages[0].size()
+		return imageInfo{width: int(width + 0.5), height: int(height + 0.5)}, nil
+	}
 	if isJPEG(data) {
 		return jpegInfo(data)
 	}
@@ -261,23 +274,33 @@ func imageDimensions(data []byte) (width, height int, err error) {
 }
 
 func imageSizeInPoints(info imageInfo, units *units, width, height *float64) (float64, float64) {
+	return sizeInPoints(float64(info.width), float64(info.height), units, width, height)
+}
+
+// sizeInPoints returns the size at which to place a graphic of the given
+// natural size: width and height if both are given, the natural size if
+// neither is, and otherwise the one given with the other in proportion.
+func sizeInPoints(naturalWidth, naturalHeight float64, units *units, width, height *float64) (float64, float64) {
 	if width == nil && height == nil {
-		return float64(info.width), float64(info.height)
+		return naturalWidth, naturalHeight
 	}
 	if width == nil {
 		h := units.toPts(*height)
-		return h * float64(info.width) / float64(info.height), h
+		return h * naturalWidth / naturalHeight, h
 	}
 	if height == nil {
 		w := units.toPts(*width)
-		return w, w * float64(info.height) / float64(info.width)
+		return w, w * naturalHeight / naturalWidth
 	}
 	return units.toPts(*width), units.toPts(*height)
 }
 
-func writeImageXObject(mw *miscWriter, gw *graphWriter, name string, x, y, width, height, pageHeight float64) {
+// writeXObject draws XObject name, whose natural size in its own space is
+// naturalWidth by naturalHeight (one by one for an image), scaled to width
+// by height with its top left corner at x, y.
+func writeXObject(mw *miscWriter, gw *graphWriter, name string, x, y, width, height, naturalWidth, naturalHeight, pageHeight float64) {
 	gw.saveGraphicsState()
-	gw.concatMatrix(width, 0, 0, height, x, pageHeight-y-height)
+	gw.concatMatrix(width/naturalWidth, 0, 0, height/naturalHeight, x, pageHeight-y-height)
 	mw.xObject(name)
 	gw.restoreGraphicsState()
 }
@@ -0,0 +1,324 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+var errImportEncrypted = errors.New("pages cannot be imported from encrypted files")
+
+// importedPDF is a file whose pages are placed in the document as form
+// XObjects (PDF spec §8.10). Objects the pages use are copied once, however
+// many of its pages are placed.
+type importedPDF struct {
+	reader *pdfReader
+	pages  []*existingPage
+	// copies maps object numbers of the file to their copies.
+	copies map[int]*indirectObjectRef
+	forms  map[int]*importedPage
+}
+
+// importedPage is a page of an importedPDF added to the document's
+// resources as a form XObject.
+type importedPage struct {
+	name          string
+	width, height float64
+}
+
+func isPDF(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("%PDF-"))
+}
+
+func newImportedPDF(data []byte) (*importedPDF, error) {
+	r, err := newPDFReader(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := r.trailer["Encrypt"]; ok {
+		return nil, errImportEncrypted
+	}
+	catalog, err := r.resolveDict(r.trailer["Root"])
+	if err != nil {
+		return nil, err
+	}
+	pagesRef, ok := catalog["Pages"].(objectRef)
+	if !ok {
+		return nil, errors.New("document catalog has no /Pages")
+	}
+	pagesDict, err := r.resolveDict(pagesRef)
+	if err != nil {
+		return nil, err
+	}
+	_, pages, err := r.readPages(pagesRef, pagesDict)
+	if err != nil {
+		return nil, err
+	}
+	return &importedPDF{reader: r, pages: pages, copies: make(map[int]*indirectObjectRef), forms: make(map[int]*importedPage)}, nil
+}
+
+// page returns page n, counting from one.
+func (imp *importedPDF) page(n int) (*existingPage, error) {
+	if n < 1 || n > len(imp.pages) {
+		return nil, errPageIndex
+	}
+	return imp.pages[n-1], nil
+}
+
+// size returns the width and height of the page as displayed: its crop box,
+// turned by its /Rotate.
+func (ep *existingPage) size() (width, height float64) {
+	width, height = ep.cropBox.x2-ep.cropBox.x1, ep.cropBox.y2-ep.cropBox.y1
+	if ep.rotate == 90 || ep.rotate == 270 {
+		return height, width
+	}
+	return
+}
+
+// matrix returns the form matrix mapping the page's crop box to the
+// rectangle from the origin to its size as displayed, turning it as /Rotate
+// turns the page.
+func (ep *existingPage) matrix() array {
+	b := ep.cropBox
+	w, h := b.x2-b.x1, b.y2-b.y1
+	var m []float64
+	switch ep.rotate {
+	case 90:
+		m = []float64{0, -1, 1, 0, -b.y1, w + b.x1}
+	case 180:
+		m = []float64{-1, 0, 0, -1, w + b.x1, h + b.y1}
+	case 270:
+		m = []float64{0, 1, -1, 0, h + b.y1, -b.x1}
+	default:
+		m = []float64{1, 0, 0, 1, -b.x1, -b.y1}
+	}
+	a := make(array, len(m))
+	for i, v := range m {
+		if v == 0 {
+			v = 0 // not -0
+		}
+		a[i] = real(v)
+	}
+	return a
+}
+
+// PDFPageCount returns the number of pages of the PDF file in data.
+func (dw *DocWriter) PDFPageCount(data []byte) (int, error) {
+	imp, err := dw.importPDF(data)
+	if err != nil {
+		return 0, err
+	}
+	return len(imp.pages), nil
+}
+
+// PDFPageDimensions returns the size in points of page n, counting from one,
+// of the PDF file in data.
+func (dw *DocWriter) PDFPageDimensions(data []byte, n int) (width, height float64, err error) {
+	imp, err := dw.importPDF(data)
+	if err != nil {
+		return 0, 0, err
+	}
+	ep, err := imp.page(n)
+	if err != nil {
+		return 0, 0, err
+	}
+	width, height = ep.size()
+	return
+}
+
+func (dw *DocWriter) PDFPageDimensionsFromFile(filename string, n int) (width, height float64, err error) {
+	data, err := dw.readAssetFile(filename)
+	if err != nil {
+		return 0, 0, err
+	}
+	return dw.PDFPageDimensions(data, n)
+}
+
+// PrintPDFPage places page n, counting from one, of the PDF file in data on
+// the current page, as PageWriter.PrintPDFPage.
+func (dw *DocWriter) PrintPDFPage(data []byte, n int, x, y float64, width, height *float64) (actualWidth, actualHeight float64, err error) {
+	return dw.CurPage().PrintPDFPage(data, n, x, y, width, height)
+}
+
+func (dw *DocWriter) PrintPDFPageFile(filename string, n int, x, y float64, width, height *float64) (actualWidth, actualHeight float64, err error) {
+	return dw.CurPage().PrintPDFPageFile(filename, n, x, y, width, height)
+}
+
+// importPDF returns the file in data, parsing it the first time it is seen.
+func (dw *DocWriter) importPDF(data []byte) (*importedPDF, error) {
+	key := imageKey(data)
+	if imp, ok := dw.importedPDFs[key]; ok {
+		return imp, nil
+	}
+	imp, err := newImportedPDF(data)
+	if err != nil {
+		return nil, err
+	}
+	if dw.importedPDFs == nil {
+		dw.importedPDFs = make(map[string]*importedPDF)
+	}
+	dw.importedPDFs[key] = imp
+	return imp, nil
+}
+
+// importPage adds page n of the file in data to the document as a form
+// XObject, unless it has been already. The page's content streams and the
+// resources they use are copied as they are, without being decoded, if
+// there is a single content stream.
+func (dw *DocWriter) importPage(data []byte, n int) (*importedPage, error) {
+	imp, err := dw.importPDF(data)
+	if err != nil {
+		return nil, err
+	}
+	if ip, ok := imp.forms[n]; ok {
+		return ip, nil
+	}
+	ep, err := imp.page(n)
+	if err != nil {
+		return nil, err
+	}
+	contents, err := imp.reader.contents(ep)
+	if err != nil {
+		return nil, err
+	}
+	var content []byte
+	var filters dictionary
+	for i, c := range contents {
+		ref, ok := c.(objectRef)
+		if !ok {
+			continue
+		}
+		s, err := imp.reader.stream(ref.seq)
+		if err != nil {
+			return nil, err
+		}
+		if len(contents) == 1 {
+			content = s.data
+			filters = dictionary{"Filter": s.dict["Filter"], "DecodeParms": s.dict["DecodeParms"]}
+			break
+		}
+		decoded, err := decodeStream(s)
+		if err != nil {
+			return nil, fmt.Errorf("content stream %d of page %d: %w", i, n, err)
+		}
+		content = append(append(content, decoded...), '\n')
+	}
+	resources, err := dw.importValue(imp, ep.resources)
+	if err != nil {
+		return nil, err
+	}
+	form := newFormXObject(dw.nextSeq(), 0, ep.cropBox, content, resources)
+	form.dict["Matrix"] = ep.matrix()
+	for key, v := range filters {
+		if v != nil {
+			if form.dict[key], err = dw.importValue(imp, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if filters == nil && dw.compressPages {
+		if err := form.compress(); err != nil {
+			return nil, err
+		}
+	}
+	if group, ok := ep.dict["Group"]; ok {
+		// A page's transparency group applies to it as a form as well.
+		if form.dict["Group"], err = dw.importValue(imp, group); err != nil {
+			return nil, err
+		}
+	}
+	dw.file.body.add(form)
+	dw.importedPages++
+	ip := &importedPage{name: fmt.Sprintf("Pg%d", dw.importedPages)}
+	ip.width, ip.height = ep.size()
+	dw.resources.setXObject(ip.name, &indirectObjectRef{form})
+	imp.forms[n] = ip
+	return ip, nil
+}
+
+// importValue returns a copy of v, a value of the file imp, in which the
+// objects v refers to are replaced with copies added to the document.
+// Entries linking objects to the file's page tree and structure tree are
+// dropped, so that only what is needed to draw a page is copied.
+func (dw *DocWriter) importValue(imp *importedPDF, v writer) (writer, error) {
+	switch v := v.(type) {
+	case objectRef:
+		if ref, ok := imp.copies[v.seq]; ok {
+			return ref, nil
+		}
+		obj, s, err := imp.reader.readObject(v.seq)
+		if err != nil {
+			return nil, err
+		}
+		seq := dw.nextSeq()
+		if s != nil {
+			st := newStream(seq, 0, s.data)
+			imp.copies[v.seq] = &indirectObjectRef{st}
+			dict := s.dict.clone()
+			delete(dict, "Length")
+			copied, err := dw.importValue(imp, dict)
+			if err != nil {
+				return nil, err
+			}
+			st.dict = copied.(dictionary)
+			dw.file.body.add(st)
+		} else {
+			indObj := &indirectObject{seq, 0, nil}
+			imp.copies[v.seq] = &indirectObjectRef{indObj}
+			if indObj.obj, err = dw.importValue(imp, obj); err != nil {
+				return nil, err
+			}
+			dw.file.body.add(indObj)
+		}
+		return imp.copies[v.seq], nil
+	case dictionary:
+		d := make(dictionary, len(v))
+		for key, value := range v {
+			switch key {
+			case "Parent", "StructParent", "StructParents":
+				continue
+			}
+			copied, err := dw.importValue(imp, value)
+			if err != nil {
+				return nil, err
+			}
+			d[key] = copied
+		}
+		return d, nil
+	case array:
+		a := make(array, len(v))
+		for i, value := range v {
+			copied, err := dw.importValue(imp, value)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = copied
+		}
+		return a, nil
+	}
+	return v, nil
+}
+
+// PrintPDFPage places page n, counting from one, of the PDF file in data
+// with its top left corner at x, y, as PrintImage places an image. The
+// page's size is that of its crop box, turned as the page is displayed.
+func (pw *PageWriter) PrintPDFPage(data []byte, n int, x, y float64, width, height *float64) (actualWidth, actualHeight float64, err error) {
+	ip, err := pw.dw.importPage(data, n)
+	if err != nil {
+		return 0, 0, err
+	}
+	wpts, hpts := sizeInPoints(ip.width, ip.height, pw.units, width, height)
+	pw.placeXObject(ip.name, pw.units.toPts(x), pw.units.toPts(y), wpts, hpts, ip.width, ip.height)
+	return pw.units.fromPts(wpts), pw.units.fromPts(hpts), nil
+}
+
+func (pw *PageWriter) PrintPDFPageFile(filename string, n int, x, y float64, width, height *float64) (actualWidth, actualHeight float64, err error) {
+	data, err := pw.dw.readAssetFile(filename)
+	if err != nil {
+		return 0, 0, err
+	}
+	return pw.PrintPDFPage(data, n, x, y, width, height)
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rowland/leadtype/afm_fonts"
+	"github.com/rowland/leadtype/options"
+)
+
+// letterheadPDF returns a document with an A4 page of text and a rotated
+// letter page.
+func letterheadPDF(t *testing.T) []byte {
+	dw := NewDocWriter()
+	fonts, err := afm_fonts.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.AddFontSource(fonts)
+	dw.NewPageWithOptions(options.Options{"page_size": "A4"})
+	dw.SetFont("Helvetica", 12, nil)
+	dw.MoveTo(72, 72)
+	dw.Print("Acme Corporation")
+	dw.NewPageWithOptions(options.Options{"rotate": "landscape"}).Rectangle(1, 1, 100, 50, true, false)
+	var buf bytes.Buffer
+	if _, err := dw.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDocWriter_PDFPageDimensions(t *testing.T) {
+	src := letterheadPDF(t)
+	dw := NewDocWriter()
+	count, err := dw.PDFPageCount(src)
+	check(t, err == nil, "PDFPageCount should succeed")
+	expectI(t, 2, count)
+	width, height, err := dw.PDFPageDimensions(src, 1)
+	check(t, err == nil, "PDFPageDimensions should succeed")
+	expectF(t, 595, width)
+	expectF(t, 842, height)
+	width, height, _ = dw.PDFPageDimensions(src, 2)
+	expectF(t, 792, width)
+	expectF(t, 612, height)
+	_, _, err = dw.PDFPageDimensions(src, 3)
+	check(t, err == errPageIndex, "Page numbers should be checked")
+
+	w, h, err := imageDimensions(src)
+	check(t, err == nil, "imageDimensions should read the first page")
+	expectI(t, 595, w)
+	expectI(t, 842, h)
+}
+
+func TestPageWriter_PrintPDFPage(t *testing.T) {
+	src := letterheadPDF(t)
+	dw := NewDocWriter().CompressPages(false)
+	pw := dw.NewPage()
+	width := 297.5
+	actualWidth, actualHeight, err := pw.PrintPDFPage(src, 1, 0, 0, &width, nil)
+	check(t, err == nil, "PrintPDFPage should succeed")
+	expectF(t, 297.5, actualWidth)
+	expectF(t, 421, actualHeight)
+	expectS(t, "q\n0.5 0 0 0.5 0 371 cm\n/Pg1 Do\nQ\n", pw.stream.String())
+
+	// The same page is imported once; the rotated page is turned upright.
+	pw.PrintPDFPage(src, 1, 0, 0, nil, nil)
+	pw.PrintPDFPage(src, 2, 0, 0, nil, nil)
+	check(t, len(dw.resources.xObjects) == 2, "Each page should be imported once")
+
+	form := dw.resources.xObjects["Pg1"].(*indirectObjectRef).obj.(*stream)
+	expectS(t, "/Form ", stringFromWriter(form.dict["Subtype"]))
+	expectS(t, "[0 0 595 842 ] ", stringFromWriter(form.dict["BBox"]))
+	check(t, strings.Contains(string(form.data), "(Acme Corporation) Tj"), "Content should be copied")
+	resources := form.dict["Resources"].(dictionary)
+	fontRef := resources["Font"].(dictionary)["F0"]
+	check(t, fontRef != nil, "Fonts should be copied: "+stringFromWriter(resources))
+	rotated := dw.resources.xObjects["Pg2"].(*indirectObjectRef).obj.(*stream)
+	expectS(t, "[0 1 -1 0 792 0 ] ", stringFromWriter(rotated.dict["Matrix"]))
+
+	var buf bytes.Buffer
+	if _, err := dw.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	r, err := newPDFReader(buf.Bytes())
+	check(t, err == nil, "Output should be readable")
+	check(t, r != nil && len(r.xref) > 10, "Output should include the copied objects")
+
+	_, _, err = pw.PrintPDFPage(src, 0, 0, 0, nil, nil)
+	check(t, err == errPageIndex, "Page numbers count from one")
+}
+
+func TestPageWriter_PrintImage_PDF(t *testing.T) {
+	dw := NewDocWriter()
+	pw := dw.NewPage()
+	_, height, err := pw.PrintImage(letterheadPDF(t), 0, 0, nil, nil)
+	check(t, err == nil, "PrintImage should place the first page of a PDF")
+	expectF(t, 842, height)
+	check(t, dw.resources.xObjects["Pg1"] != nil, "Page should be imported")
+}
@@ -0,0 +1,323 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/rowland/leadtype/options"
+)
+
+var errImposeStreaming = errors.New("an imposed document cannot be streamed")
+var errImposeUpdate = errors.New("an incremental update cannot be imposed")
+var errImposeTagged = errors.New("a tagged document cannot be imposed")
+
+// Crop marks are drawn this far outside the trimmed pages, this long.
+const (
+	cropMarkOffset = 3.0
+	cropMarkLength = 12.0
+)
+
+// imposition arranges the pages of a document on sheets when it is written.
+// Measurements are in points.
+type imposition struct {
+	booklet                 bool
+	cropMarks               bool
+	gutter                  float64
+	sheetWidth, sheetHeight float64 // 0 to fit the pages exactly
+}
+
+// SetImposition prints the pages of the document on larger sheets when it is
+// written, after all pages are laid out, with the options:
+//
+//	pages_across  pages side by side on each sheet (default 1; 2 for a booklet)
+//	pages_down    pages one above another on each sheet (default 1)
+//	booklet       if true, order the pages two to a side for a saddle-stitched
+//	              booklet: the sheets printed on both sides, stacked and
+//	              folded in half read in order. Blank pages are added to
+//	              make a multiple of four.
+//	gutter        space between the pages
+//	crop_marks    if true, mark where the pages are to be cut
+//	sheet_size    name or dimensions of the sheets, as for page_size
+//	orientation   orientation of the sheets, "portrait" or "landscape"
+//
+// Without a sheet size, sheets fit the pages exactly, with room for any crop
+// marks; with one, the pages are scaled down if need be and centered. Links,
+// form fields, outline entries and destinations follow their pages onto the
+// sheets; page labels are dropped. Measurements without units are in the
+// units option.
+func (dw *DocWriter) SetImposition(options options.Options) *DocWriter {
+	u := UnitConversions[options.StringDefault("units", "pt")]
+	if u == nil {
+		u = UnitConversions["pt"]
+	}
+	imp := &imposition{
+		booklet:   options.BoolDefault("booklet", false),
+		cropMarks: options.BoolDefault("crop_marks", false),
+	}
+	imp.gutter, _ = parseMeasurement(options["gutter"], u)
+	if size, ok := options["sheet_size"].(string); ok {
+		if sz, err := parsePageSize(size, u); err == nil {
+			if options.StringDefault("orientation", "portrait") == "landscape" {
+				sz.Width, sz.Height = sz.Height, sz.Width
+			}
+			imp.sheetWidth, imp.sheetHeight = sz.Width, sz.Height
+		} else {
+			dw.pageErr = err
+		}
+	}
+	if imp.booklet {
+		dw.pagesAcross, dw.pagesDown = 2, 1
+	} else {
+		dw.pagesAcross = int(options.FloatDefault("pages_across", 1))
+		dw.pagesDown = int(options.FloatDefault("pages_down", 1))
+	}
+	dw.imposition = imp
+	return dw
+}
+
+// checkImposition returns an error if the document cannot be imposed.
+func (dw *DocWriter) checkImposition() error {
+	switch {
+	case dw.imposition == nil:
+		return nil
+	case dw.file.out != nil:
+		return errImposeStreaming
+	case dw.update != nil:
+		return errImposeUpdate
+	case dw.structTreeRoot != nil:
+		return errImposeTagged
+	}
+	return nil
+}
+
+// placement is where a page is drawn on a sheet: its lower left corner, in
+// the sheet's default user space, and its scale.
+type placement struct {
+	sheet *page
+	x, y  float64
+	scale float64
+}
+
+func (p placement) point(x, y float64) (float64, float64) {
+	return p.x + x*p.scale, p.y + y*p.scale
+}
+
+// sheetOrder returns the pages on each side of each sheet, in order; -1 is a
+// blank.
+func (imp *imposition) sheetOrder(pages, perSheet int) [][]int {
+	var sides [][]int
+	if imp.booklet {
+		n := (pages + 3) / 4 * 4
+		slot := func(i int) int {
+			if i < pages {
+				return i
+			}
+			return -1
+		}
+		for i := 0; i < n/2; i += 2 {
+			sides = append(sides,
+				[]int{slot(n - 1 - i), slot(i)},
+				[]int{slot(i + 1), slot(n - 2 - i)})
+		}
+		return sides
+	}
+	for i := 0; i < pages; i += perSheet {
+		side := make([]int, perSheet)
+		for j := range side {
+			side[j] = -1
+			if i+j < pages {
+				side[j] = i + j
+			}
+		}
+		sides = append(sides, side)
+	}
+	return sides
+}
+
+// impose replaces the pages of the document with sheets on which they are
+// placed as form XObjects.
+func (dw *DocWriter) impose() {
+	imp := dw.imposition
+	across, down := dw.PagesAcross(), dw.PagesDown()
+	logical := dw.pages
+	var cellWidth, cellHeight float64
+	forms := make([]string, len(logical))
+	for i, pw := range logical {
+		pw.endContent()
+		cellWidth = math.Max(cellWidth, pw.pageWidth)
+		cellHeight = math.Max(cellHeight, pw.pageHeight)
+		bbox := rectangle{0, 0, pw.pageWidth, pw.pageHeight}
+		form := newFormXObject(dw.nextSeq(), 0, bbox, pw.stream.Bytes(), &indirectObjectRef{dw.resources})
+		if dw.compressPages {
+			if err := form.compress(); err != nil {
+				panic(err)
+			}
+		}
+		dw.file.body.add(form)
+		forms[i] = fmt.Sprintf("Pg%d", i+1)
+		dw.resources.setXObject(forms[i], &indirectObjectRef{form})
+		dw.file.body.remove(pw.page)
+		pw.stream.Reset()
+		pw.isClosed = true
+	}
+
+	gridWidth := float64(across)*cellWidth + float64(across-1)*imp.gutter
+	gridHeight := float64(down)*cellHeight + float64(down-1)*imp.gutter
+	margin := 0.0
+	if imp.cropMarks {
+		margin = cropMarkOffset + cropMarkLength + cropMarkOffset
+	}
+	sheetWidth, sheetHeight := imp.sheetWidth, imp.sheetHeight
+	scale := 1.0
+	if sheetWidth == 0 || sheetHeight == 0 {
+		sheetWidth, sheetHeight = gridWidth+2*margin, gridHeight+2*margin
+	} else {
+		scale = math.Min(1, math.Min((sheetWidth-2*margin)/gridWidth, (sheetHeight-2*margin)/gridHeight))
+	}
+	left := (sheetWidth - gridWidth*scale) / 2
+	bottom := (sheetHeight - gridHeight*scale) / 2
+	top := bottom + gridHeight*scale
+
+	placements := make(map[*page]placement, len(logical))
+	dw.pages = nil
+	for _, side := range imp.sheetOrder(len(logical), across*down) {
+		sheet := newPageWriter(dw, options.Options{"page_width": sheetWidth, "page_height": sheetHeight, "units": "pt"})
+		dw.pages = append(dw.pages, sheet)
+		for slot, i := range side {
+			if i < 0 {
+				continue
+			}
+			pw := logical[i]
+			col, row := slot%across, slot/across
+			// Pages smaller than the cell are centered in it.
+			cellX := float64(col)*(cellWidth+imp.gutter) + (cellWidth-pw.pageWidth)/2
+			cellY := float64(row)*(cellHeight+imp.gutter) + (cellHeight-pw.pageHeight)/2
+			x, y := left+cellX*scale, top-(cellY+pw.pageHeight)*scale
+			if imp.booklet {
+				// Facing pages meet at the fold.
+				x = left + float64(col)*(cellWidth+imp.gutter)*scale
+				if col == 0 {
+					x += (cellWidth - pw.pageWidth) * scale
+				}
+			}
+			sheet.placeXObject(forms[i], x, sheetHeight-y-pw.pageHeight*scale,
+				pw.pageWidth*scale, pw.pageHeight*scale, pw.pageWidth, pw.pageHeight)
+			p := placement{sheet.page, x, y, scale}
+			placements[pw.page] = p
+			for _, a := range pw.page.annots {
+				a.place(p)
+				sheet.page.addAnnot(a)
+			}
+		}
+		if imp.cropMarks {
+			sheet.drawCropMarks(left, bottom, cellWidth*scale, cellHeight*scale, imp.gutter*scale, across, down)
+		}
+	}
+	dw.remapDestinations(placements)
+	// Labels would number the sheets, not the pages on them.
+	dw.pageLabels = nil
+	dw.curPage = nil
+}
+
+// place moves the annotation from its page onto a sheet.
+func (a *annotation) place(p placement) {
+	if r, ok := a.dict["Rect"].(*rectangle); ok {
+		x1, y1 := p.point(r.x1, r.y1)
+		x2, y2 := p.point(r.x2, r.y2)
+		a.dict["Rect"] = &rectangle{x1, y1, x2, y2}
+	}
+	if _, ok := a.dict["P"]; ok {
+		a.dict["P"] = &indirectObjectRef{p.sheet}
+	}
+}
+
+// drawCropMarks marks the cut lines of a grid of cells outside it, at the
+// edges of the cells.
+func (pw *PageWriter) drawCropMarks(left, bottom, cellWidth, cellHeight, gutter float64, across, down int) {
+	var xs, ys []float64
+	for col := 0; col < across; col++ {
+		x := left + float64(col)*(cellWidth+gutter)
+		xs = append(xs, x, x+cellWidth)
+	}
+	for row := 0; row < down; row++ {
+		y := bottom + float64(row)*(cellHeight+gutter)
+		ys = append(ys, y, y+cellHeight)
+	}
+	right := xs[len(xs)-1]
+	top := ys[len(ys)-1]
+	pw.gw.setLineWidth(0.25)
+	for _, x := range xs {
+		pw.gw.moveTo(x, bottom-cropMarkOffset)
+		pw.gw.lineTo(x, bottom-cropMarkOffset-cropMarkLength)
+		pw.gw.moveTo(x, top+cropMarkOffset)
+		pw.gw.lineTo(x, top+cropMarkOffset+cropMarkLength)
+	}
+	for _, y := range ys {
+		pw.gw.moveTo(left-cropMarkOffset, y)
+		pw.gw.lineTo(left-cropMarkOffset-cropMarkLength, y)
+		pw.gw.moveTo(right+cropMarkOffset, y)
+		pw.gw.lineTo(right+cropMarkOffset+cropMarkLength, y)
+	}
+	pw.gw.stroke()
+}
+
+// remapDestinations points the explicit destinations of outline entries,
+// links and named destinations at the sheets their pages were placed on.
+func (dw *DocWriter) remapDestinations(placements map[*page]placement) {
+	var walk func(items []*outlineItem)
+	walk = func(items []*outlineItem) {
+		for _, item := range items {
+			remapDestination(item.dict, placements)
+			walk(item.children)
+		}
+	}
+	walk(dw.catalog.outlines.children)
+	for _, pw := range dw.pages {
+		for _, a := range pw.page.annots {
+			remapDestination(a.dict, placements)
+		}
+	}
+	if dw.namedDests != nil {
+		for key, dest := range dw.namedDests.dict {
+			if dest, ok := dest.(array); ok {
+				dw.namedDests.dict[key] = remapDest(dest, placements)
+			}
+		}
+	}
+}
+
+func remapDestination(dict dictionary, placements map[*page]placement) {
+	if dest, ok := dict["Dest"].(array); ok {
+		dict["Dest"] = remapDest(dest, placements)
+	}
+}
+
+// remapDest returns an /XYZ destination on a page as the same destination on
+// the sheet the page was placed on.
+func remapDest(dest array, placements map[*page]placement) array {
+	if len(dest) != 5 {
+		return dest
+	}
+	ref, ok := dest[0].(*indirectObjectRef)
+	if !ok {
+		return dest
+	}
+	pg, ok := ref.obj.(*page)
+	if !ok {
+		return dest
+	}
+	p, ok := placements[pg]
+	if !ok {
+		return dest
+	}
+	remapped := array{&indirectObjectRef{p.sheet}, dest[1], dest[2], dest[3], dest[4]}
+	if top, ok := dest[3].(real); ok {
+		_, y := p.point(0, float64(top))
+		remapped[3] = real(y)
+	}
+	return remapped
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rowland/leadtype/options"
+)
+
+func TestImposition_sheetOrder(t *testing.T) {
+	booklet := &imposition{booklet: true}
+	check(t, reflect.DeepEqual([][]int{{-1, 0}, {1, -1}, {5, 2}, {3, 4}}, booklet.sheetOrder(6, 2)),
+		"Booklet sides should pair the outer pages, padded with blanks")
+	nUp := &imposition{}
+	check(t, reflect.DeepEqual([][]int{{0, 1, 2, 3}, {4, -1, -1, -1}}, nUp.sheetOrder(5, 4)),
+		"N-up sides should take the pages in order")
+}
+
+func TestDocWriter_SetImposition_NUp(t *testing.T) {
+	dw := NewDocWriter().SetImposition(options.Options{"pages_across": 2, "pages_down": 2, "gutter": 18, "crop_marks": true})
+	expectI(t, 4, dw.PagesUp())
+	for i := 0; i < 5; i++ {
+		dw.NewPageWithOptions(options.Options{"page_size": "photo-4x6"})
+		dw.MoveTo(36, 36)
+		dw.LineTo(72, 72)
+	}
+	dw.impose()
+	expectI(t, 2, len(dw.pages))
+	sheet := dw.pages[0]
+	expectF(t, 288*2+18+36, sheet.pageWidth)
+	expectF(t, 432*2+18+36, sheet.pageHeight)
+	s := sheet.stream.String()
+	check(t, strings.HasPrefix(s, "q\n1 0 0 1 18 468 cm\n/Pg1 Do\nQ\nq\n1 0 0 1 324 468 cm\n/Pg2 Do\nQ\nq\n1 0 0 1 18 18 cm\n/Pg3 Do\nQ\n"),
+		"Pages should be placed left to right, top to bottom")
+	check(t, strings.Contains(s, "0.25 w\n18 15 m\n18 3 l\n"), "Crop marks should be drawn outside the pages")
+	check(t, strings.Contains(dw.pages[1].stream.String(), "/Pg5 Do"), "Fifth page should be on the second sheet")
+}
+
+func TestDocWriter_SetImposition_Booklet(t *testing.T) {
+	dw := NewDocWriter().SetImposition(options.Options{"booklet": true, "sheet_size": "letter", "orientation": "landscape"})
+	var pages []*PageWriter
+	for i := 0; i < 4; i++ {
+		pages = append(pages, dw.NewPageWithOptions(options.Options{"page_size": "statement"}))
+	}
+	pages[2].LinkToPage(72, 72, 144, 36, pages[0], 0)
+	dw.AddOutline("Last", pages[3], 0, nil)
+	dw.impose()
+	expectI(t, 2, len(dw.pages))
+	expectS(t, "q\n1 0 0 1 0 0 cm\n/Pg4 Do\nQ\nq\n1 0 0 1 396 0 cm\n/Pg1 Do\nQ\n", dw.pages[0].stream.String())
+	expectS(t, "q\n1 0 0 1 0 0 cm\n/Pg2 Do\nQ\nq\n1 0 0 1 396 0 cm\n/Pg3 Do\nQ\n", dw.pages[1].stream.String())
+	link := dw.pages[1].page.annots[0]
+	expectS(t, "[468 504 612 540 ] ", stringFromWriter(link.dict["Rect"]))
+	check(t, link.dict["Dest"].(array)[0].(*indirectObjectRef).obj == dw.pages[0].page, "Link should go to the sheet of its target")
+	check(t, dw.catalog.outlines.children[0].dict["Dest"].(array)[0].(*indirectObjectRef).obj == dw.pages[0].page,
+		"Outline entry should go to the sheet of its page")
+}
+
+func TestDocWriter_SetImposition_Tagged(t *testing.T) {
+	dw := NewDocWriter().SetImposition(options.Options{"booklet": true})
+	dw.NewPage()
+	dw.BeginTag("P", nil)
+	dw.EndTag()
+	var buf bytes.Buffer
+	_, err := dw.WriteTo(&buf)
+	check(t, err == errImposeTagged, "Tagged documents should not be imposed")
+}
@@ -0,0 +1,685 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+var errUpdateEncrypted = errors.New("encrypted files cannot be updated")
+var errUpdateEncryption = errors.New("an incremental update cannot encrypt the document")
+var errUpdateStreaming = errors.New("an incremental update cannot be streamed")
+var errNotUpdate = errors.New("document was not opened for update")
+var errPageIndex = errors.New("page index out of range")
+var errUpdateTreeKids = errors.New("an incremental update cannot add to a tree with /Kids")
+
+// incrementalUpdate holds what is known of an existing file opened by OpenDocWriter.
+// New and changed objects are appended to the file's bytes as an
+// incremental update (PDF spec §7.5.6), leaving the original untouched.
+type incrementalUpdate struct {
+	reader  *pdfReader
+	size    int
+	version float32
+	root    objectRef
+	catalog dictionary
+	// pagesRef and pagesDict are the root of the page tree; kids is its
+	// resolved /Kids.
+	pagesRef  objectRef
+	pagesDict dictionary
+	kids      array
+	pages     []*existingPage
+	// metadata and info are as read from the file, so that unchanged
+	// metadata is not written again.
+	metadata docMetadata
+	info     dictionary
+	edited   []*PageWriter
+	// form is the existing interactive form, if any: its resolved
+	// /Fields, the names of those fields, which new fields may not reuse,
+	// and the fonts of its default resources, whose keys new fonts may not
+	// reuse.
+	form       dictionary
+	fields     array
+	fieldNames map[string]bool
+	formFonts  dictionary
+}
+
+// OpenDocWriter returns a DocWriter that updates the PDF file in data.
+// Pages of the file may be drawn on with EditPage and new pages are
+// appended after them. WriteTo writes data unchanged followed by the new
+// and changed objects, so that earlier revisions, and any signatures over
+// them, remain intact. Form fields, outline entries, page labels,
+// attachments and named destinations are added to those the file has
+// already; WriteTo reports an error if an addition cannot be merged, such as
+// a structure tree for a file that is already tagged. Encrypted files are
+// not supported.
+func OpenDocWriter(data []byte) (*DocWriter, error) {
+	r, err := newPDFReader(data)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := r.trailer["Encrypt"]; ok {
+		return nil, errUpdateEncrypted
+	}
+	u := &incrementalUpdate{reader: r}
+	size, _ := r.trailer["Size"].(integer)
+	u.size = int(size)
+	var ok bool
+	if u.root, ok = r.trailer["Root"].(objectRef); !ok {
+		return nil, errors.New("trailer has no /Root")
+	}
+	if u.catalog, err = r.resolveDict(u.root); err != nil || u.catalog == nil {
+		return nil, fmt.Errorf("reading document catalog: %v", err)
+	}
+	if u.pagesRef, ok = u.catalog["Pages"].(objectRef); !ok {
+		return nil, errors.New("document catalog has no /Pages")
+	}
+	if u.pagesDict, err = r.resolveDict(u.pagesRef); err != nil || u.pagesDict == nil {
+		return nil, fmt.Errorf("reading page tree: %v", err)
+	}
+	if u.kids, u.pages, err = r.readPages(u.pagesRef, u.pagesDict); err != nil {
+		return nil, err
+	}
+	if v, err := strconv.ParseFloat(r.version, 32); err == nil {
+		u.version = float32(v)
+	}
+	if v, ok := u.catalog["Version"].(name); ok {
+		if v, err := strconv.ParseFloat(string(v), 32); err == nil && float32(v) > u.version {
+			u.version = float32(v)
+		}
+	}
+	if u.info, err = r.resolveDict(r.trailer["Info"]); err != nil {
+		return nil, err
+	}
+	u.metadata = metadataFromInfo(u.info)
+	if err = u.readForm(); err != nil {
+		return nil, fmt.Errorf("reading interactive form: %w", err)
+	}
+
+	dw := newDocWriter(nextSeqFunc(u.size))
+	dw.update = u
+	dw.metadata = u.metadata
+	dw.file.header.Version = u.version
+	return dw, nil
+}
+
+// readForm reads the fields and default fonts of the file's interactive
+// form, if it has one.
+func (u *incrementalUpdate) readForm() error {
+	r := u.reader
+	form, err := r.resolveDict(u.catalog["AcroForm"])
+	if err != nil || form == nil {
+		return err
+	}
+	u.form = form
+	v, err := r.resolve(form["Fields"])
+	if err != nil {
+		return err
+	}
+	u.fields, _ = v.(array)
+	u.fieldNames = make(map[string]bool, len(u.fields))
+	for _, f := range u.fields {
+		field, err := r.resolveDict(f)
+		if err != nil {
+			return err
+		}
+		if fieldName := textValue(field["T"]); fieldName != "" {
+			u.fieldNames[fieldName] = true
+		}
+	}
+	dr, err := r.resolveDict(form["DR"])
+	if err != nil {
+		return err
+	}
+	u.formFonts, err = r.resolveDict(dr["Font"])
+	return err
+}
+
+// metadataFromInfo reads the fields of a document information dictionary.
+func metadataFromInfo(info dictionary) (md docMetadata) {
+	md.title = textValue(info["Title"])
+	md.author = textValue(info["Author"])
+	md.subject = textValue(info["Subject"])
+	md.keywords = textValue(info["Keywords"])
+	md.creator = textValue(info["Creator"])
+	md.producer = textValue(info["Producer"])
+	md.creationDate = parsePDFDate(textValue(info["CreationDate"]))
+	md.modDate = parsePDFDate(textValue(info["ModDate"]))
+	return
+}
+
+// textValue decodes a PDF text string: UTF-16BE if it begins with a byte
+// order mark, and otherwise PDFDocEncoding, read here as Latin-1.
+func textValue(v writer) string {
+	var b []byte
+	switch v := v.(type) {
+	case str:
+		b = []byte(v)
+	case hexString:
+		b = []byte(v)
+	default:
+		return ""
+	}
+	if len(b) >= 2 && b[0] == 0xFE && b[1] == 0xFF {
+		units := make([]uint16, (len(b)-2)/2)
+		for i := range units {
+			units[i] = uint16(b[2+2*i])<<8 | uint16(b[3+2*i])
+		}
+		return string(utf16.Decode(units))
+	}
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+// parsePDFDate parses a PDF date string, D:YYYYMMDDHHmmSSOHH'mm, in which
+// all but the year are optional. It returns the zero time if s is not one.
+func parsePDFDate(s string) time.Time {
+	s = strings.TrimPrefix(s, "D:")
+	digits := min(len(s)-len(strings.TrimLeft(s, "0123456789")), 14)
+	if digits < 4 || digits%2 != 0 {
+		return time.Time{}
+	}
+	t, err := time.Parse("20060102150405", s[:digits]+"0101000000"[digits-4:])
+	if err != nil {
+		return time.Time{}
+	}
+	if rest := s[digits:]; len(rest) > 0 && (rest[0] == '+' || rest[0] == '-') {
+		var hours, minutes int
+		fmt.Sscanf(strings.ReplaceAll(rest[1:], "'", " "), "%d %d", &hours, &minutes)
+		offset := hours*3600 + minutes*60
+		if rest[0] == '-' {
+			offset = -offset
+		}
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.FixedZone("", offset))
+	}
+	return t
+}
+
+// ExistingPageCount returns the number of pages of a document opened with
+// OpenDocWriter, not counting pages added since.
+func (dw *DocWriter) ExistingPageCount() int {
+	if dw.update == nil {
+		return 0
+	}
+	return len(dw.update.pages)
+}
+
+// EditPage returns a PageWriter that draws over page index, counting from
+// zero, of a document opened with OpenDocWriter, and makes it the current
+// page. What is drawn is added as a stamp over the page's existing content,
+// with y measured down from the top of its media box as the page is
+// displayed, turned by its /Rotate; annotations are added after the page's
+// own. A page that is neither drawn on nor annotated is left as it was.
+func (dw *DocWriter) EditPage(index int) (*PageWriter, error) {
+	u := dw.update
+	if u == nil {
+		return nil, errNotUpdate
+	}
+	if index < 0 || index >= len(u.pages) {
+		return nil, errPageIndex
+	}
+	ep := u.pages[index]
+	for _, pw := range u.edited {
+		if pw.existing == ep {
+			dw.curPage = pw
+			return pw, nil
+		}
+	}
+	v, err := u.reader.resolve(ep.dict["Annots"])
+	if err != nil {
+		return nil, err
+	}
+	if ep.contents, err = u.reader.contents(ep); err != nil {
+		return nil, fmt.Errorf("reading contents of page %d: %w", index, err)
+	}
+	if ep.xobjects, err = u.reader.resolveDict(ep.resources["XObject"]); err != nil {
+		return nil, fmt.Errorf("reading XObjects of page %d: %w", index, err)
+	}
+	pw := new(PageWriter)
+	pw.initState(dw, dw.options)
+	pw.existing = ep
+	pw.pageWidth, pw.pageHeight = ep.stampSize()
+	pw.page = new(page)
+	pw.page.dictionaryObject.init(ep.ref.seq, ep.ref.gen)
+	pw.page.dict = ep.dict.clone()
+	pw.page.baseAnnots, _ = v.(array)
+	u.edited = append(u.edited, pw)
+	dw.curPage = pw
+	return pw, nil
+}
+
+// stampSize returns the width and height of the page's media box as
+// displayed, turned by its /Rotate.
+func (ep *existingPage) stampSize() (width, height float64) {
+	width, height = ep.mediaBox.x2-ep.mediaBox.x1, ep.mediaBox.y2-ep.mediaBox.y1
+	if ep.rotate == 90 || ep.rotate == 270 {
+		return height, width
+	}
+	return
+}
+
+// stampMatrix returns the matrix mapping the rectangle from the origin to
+// stampSize onto the page's media box, turning it against /Rotate so that
+// what is drawn there appears upright when the page is displayed.
+func (ep *existingPage) stampMatrix() transformMatrix {
+	b := ep.mediaBox
+	switch ep.rotate {
+	case 90:
+		return transformMatrix{0, 1, -1, 0, b.x2, b.y1}
+	case 180:
+		return transformMatrix{-1, 0, 0, -1, b.x2, b.y2}
+	case 270:
+		return transformMatrix{0, -1, 1, 0, b.x1, b.y2}
+	}
+	return transformMatrix{1, 0, 0, 1, b.x1, b.y1}
+}
+
+// stampRect returns r, a rectangle drawn on the page, in the page's default
+// user space, where annotation rectangles are given.
+func (ep *existingPage) stampRect(r rectangle) rectangle {
+	m := ep.stampMatrix()
+	x1, y1 := m[0]*r.x1+m[2]*r.y1+m[4], m[1]*r.x1+m[3]*r.y1+m[5]
+	x2, y2 := m[0]*r.x2+m[2]*r.y2+m[4], m[1]*r.x2+m[3]*r.y2+m[5]
+	return rectangle{min(x1, x2), min(y1, y2), max(x1, x2), max(y1, y2)}
+}
+
+// closeExisting finishes a page being edited. If anything was drawn, its
+// content becomes a form XObject drawn after the page's existing content
+// streams, which are bracketed with q and Q so that the graphics state they
+// leave behind does not affect it. The page is written again only if it
+// changed.
+func (pw *PageWriter) closeExisting(drawn bool) {
+	dw, ep := pw.dw, pw.existing
+	changed := len(pw.page.annots) > 0
+	if drawn {
+		bbox := rectangle{0, 0, pw.pageWidth, pw.pageHeight}
+		stamp := newFormXObject(dw.nextSeq(), 0, bbox, pw.stream.Bytes(), &indirectObjectRef{dw.resources})
+		if m := ep.stampMatrix(); m != identityMatrix {
+			stamp.dict["Matrix"] = m.array()
+		}
+		if dw.compressPages {
+			if err := stamp.compress(); err != nil {
+				panic(err)
+			}
+		}
+		xobjects := ep.xobjects.clone()
+		stampName := "Stamp"
+		for i := 1; xobjects[stampName] != nil; i++ {
+			stampName = fmt.Sprintf("Stamp%d", i)
+		}
+		xobjects[stampName] = &indirectObjectRef{stamp}
+		resources := ep.resources.clone()
+		resources["XObject"] = xobjects
+		pw.page.dict["Resources"] = resources
+
+		var buf bytes.Buffer
+		buf.WriteString("Q\n")
+		name(stampName).write(&buf)
+		buf.WriteString("Do\n")
+		save, restore := newStream(dw.nextSeq(), 0, []byte("q\n")), newStream(dw.nextSeq(), 0, buf.Bytes())
+		dw.file.body.add(stamp, save, restore)
+		pw.page.dict["Contents"] = append(append(array{&indirectObjectRef{save}}, ep.contents...), &indirectObjectRef{restore})
+		changed = true
+	}
+	if changed {
+		dw.file.body.add(pw.page)
+	}
+}
+
+// mergeUpdate joins the objects of the update to those of the existing
+// file: new pages are hung from the existing page tree, additions to the
+// document catalog are merged into the existing one, and the trailer is
+// linked to the existing cross-reference section. It returns an error if an
+// addition cannot be merged with what the file already has.
+func (dw *DocWriter) mergeUpdate() error {
+	u := dw.update
+	if u == nil {
+		return nil
+	}
+	body := &dw.file.body
+	body.remove(dw.catalog)
+	if pages := dw.catalog.pages; len(pages.kids) > 0 {
+		pages.dict["Parent"] = u.pagesRef
+		root := newDictionaryObject(u.pagesRef.seq, u.pagesRef.gen)
+		root.dict = u.pagesDict.clone()
+		root.dict["Kids"] = append(append(array{}, u.kids...), &indirectObjectRef{pages})
+		root.dict["Count"] = integer(len(u.pages) + len(pages.kids))
+		body.add(root)
+	} else {
+		body.remove(pages)
+	}
+
+	catalog := u.catalog.clone()
+	changed := false
+	for k, v := range dw.catalog.dict {
+		old, ok := catalog[k]
+		var err error
+		switch {
+		case k == "Type" || k == "Pages" || k == "Outlines" || k == "PageMode":
+			continue
+		case !ok || k == "Metadata" || k == "Lang" || k == "MarkInfo":
+		case k == "AcroForm":
+			v = dw.mergeForm(old)
+		case k == "PageLabels":
+			v, err = dw.mergePageLabels(old)
+		case k == "Names":
+			v, err = dw.mergeNames(old)
+		case k == "Dests":
+			v, err = dw.mergeDests(old)
+		case k == "AF":
+			v, err = dw.mergeAF(old)
+		default:
+			err = fmt.Errorf("an incremental update cannot replace the document's /%s", k)
+		}
+		if err != nil {
+			return err
+		}
+		if _, ref := v.(objectRef); ref && v == old {
+			// The object it refers to was written again.
+			continue
+		}
+		catalog[k] = v
+		changed = true
+	}
+	if len(dw.catalog.outlines.children) == 0 {
+		body.remove(dw.catalog.outlines)
+	} else if old, ok := catalog["Outlines"]; ok {
+		if err := dw.mergeOutlines(old); err != nil {
+			return err
+		}
+	} else {
+		catalog["Outlines"] = &indirectObjectRef{dw.catalog.outlines}
+		if _, ok := catalog["PageMode"]; !ok {
+			catalog["PageMode"] = name(dw.catalog.pageMode)
+		}
+		changed = true
+	}
+	if dw.file.header.Version > u.version {
+		catalog["Version"] = name(fmt.Sprintf("%1.1f", dw.file.header.Version))
+		changed = true
+	}
+	if changed {
+		root := newDictionaryObject(u.root.seq, u.root.gen)
+		root.dict = catalog
+		body.add(root)
+	}
+
+	trailer := dw.file.trailer.dict
+	trailer["Root"] = u.root
+	if _, ok := trailer["Info"]; !ok {
+		if info, ok := u.reader.trailer["Info"]; ok {
+			trailer["Info"] = info
+		}
+	}
+	trailer["Prev"] = integer(u.reader.startXRef)
+	if id, ok := u.reader.trailer["ID"].(array); ok && len(id) == 2 {
+		// The first half identifies the document; the second, its revision.
+		trailer["ID"] = array{id[0], hexString(randomBytes(16))}
+	}
+	if u.reader.xrefStreams && !dw.compressObjects {
+		// An update must use a cross-reference stream if the file does.
+		dw.file.xrefStreamSeq = dw.nextSeq()
+	}
+	return nil
+}
+
+// revise returns what is to replace old, an entry of the existing file, to
+// change its value to d. If old refers to an object, the object is written
+// again as d and old itself is returned; otherwise d is.
+func (dw *DocWriter) revise(old writer, d dictionary) writer {
+	ref, ok := old.(objectRef)
+	if !ok {
+		return d
+	}
+	obj := newDictionaryObject(ref.seq, ref.gen)
+	obj.dict = d
+	dw.file.body.add(obj)
+	return ref
+}
+
+// mergeForm returns the existing interactive form, old, with the fields of
+// the update appended and the fonts they name added to its default
+// resources.
+func (dw *DocWriter) mergeForm(old writer) writer {
+	u := dw.update
+	form := u.form.clone()
+	fields := append(array{}, u.fields...)
+	for _, f := range dw.acroForm.fields {
+		fields = append(fields, &indirectObjectRef{f})
+	}
+	form["Fields"] = fields
+	fonts := u.formFonts.clone()
+	for k, v := range dw.resources.fonts {
+		fonts[k] = v
+	}
+	dr, _ := u.reader.resolveDict(form["DR"])
+	dr = dr.clone()
+	dr["Font"] = fonts
+	form["DR"] = dr
+	dw.file.body.remove(dw.acroForm)
+	return dw.revise(old, form)
+}
+
+// mergeOutlines appends the top-level items of the update's outline to the
+// existing outline, old.
+func (dw *DocWriter) mergeOutlines(old writer) error {
+	r := dw.update.reader
+	ref, ok := old.(objectRef)
+	if !ok {
+		return errors.New("the document outline is not an indirect object")
+	}
+	root, err := r.resolveDict(ref)
+	if err != nil {
+		return fmt.Errorf("reading document outline: %w", err)
+	}
+	items := dw.catalog.outlines.children
+	for _, item := range items {
+		item.dict["Parent"] = ref
+	}
+	root = root.clone()
+	first, last := root["First"], root["Last"]
+	setOutlineChildLinks(root, items)
+	if last != nil {
+		lastRef, ok := last.(objectRef)
+		if !ok {
+			return errors.New("the last outline item is not an indirect object")
+		}
+		item, err := r.resolveDict(lastRef)
+		if err != nil {
+			return fmt.Errorf("reading document outline: %w", err)
+		}
+		item = item.clone()
+		item["Next"] = &indirectObjectRef{items[0]}
+		items[0].dict["Prev"] = lastRef
+		dw.revise(lastRef, item)
+		root["First"] = first
+	}
+	count, _ := root["Count"].(integer)
+	root["Count"] = count + integer(visibleOutlineItems(items))
+	dw.revise(ref, root)
+	dw.file.body.remove(dw.catalog.outlines)
+	return nil
+}
+
+// mergePageLabels returns the existing page label tree, old, with the
+// ranges of the update's pages added.
+func (dw *DocWriter) mergePageLabels(old writer) (writer, error) {
+	u := dw.update
+	tree, err := u.reader.resolveDict(old)
+	if err != nil {
+		return nil, fmt.Errorf("reading page labels: %w", err)
+	}
+	if _, ok := tree["Kids"]; ok {
+		return nil, errUpdateTreeKids
+	}
+	v, err := u.reader.resolve(tree["Nums"])
+	if err != nil {
+		return nil, fmt.Errorf("reading page labels: %w", err)
+	}
+	nums, _ := v.(array)
+	nums = append(array{}, nums...)
+	added := dw.catalog.dict["PageLabels"].(dictionary)["Nums"].(array)
+	for i := 0; i+1 < len(added); i += 2 {
+		// The existing pages keep their labels, including the first,
+		// which writePageLabels numbers if nothing else does.
+		if added[i].(integer) >= integer(len(u.pages)) {
+			nums = append(nums, added[i], added[i+1])
+		}
+	}
+	tree = tree.clone()
+	tree["Nums"] = nums
+	return dw.revise(old, tree), nil
+}
+
+// mergeNames returns the existing name dictionary, old, with the update's
+// attachments added to its tree of embedded files.
+func (dw *DocWriter) mergeNames(old writer) (writer, error) {
+	names, err := dw.update.reader.resolveDict(old)
+	if err != nil {
+		return nil, fmt.Errorf("reading name dictionary: %w", err)
+	}
+	names = names.clone()
+	if tree, ok := names["EmbeddedFiles"]; ok {
+		if names["EmbeddedFiles"], err = dw.mergeEmbeddedFiles(tree); err != nil {
+			return nil, err
+		}
+	} else {
+		names["EmbeddedFiles"] = &indirectObjectRef{dw.attachments}
+	}
+	return dw.revise(old, names), nil
+}
+
+// mergeEmbeddedFiles returns the existing tree of embedded files, old, with
+// the update's attachments added in order of name.
+func (dw *DocWriter) mergeEmbeddedFiles(old writer) (writer, error) {
+	r := dw.update.reader
+	tree, err := r.resolveDict(old)
+	if err != nil {
+		return nil, fmt.Errorf("reading embedded files: %w", err)
+	}
+	if _, ok := tree["Kids"]; ok {
+		return nil, errUpdateTreeKids
+	}
+	v, err := r.resolve(tree["Names"])
+	if err != nil {
+		return nil, fmt.Errorf("reading embedded files: %w", err)
+	}
+	entries, _ := v.(array)
+	specs := make(map[string]writer, len(entries)/2+len(dw.attachments.specs))
+	for i := 0; i+1 < len(entries); i += 2 {
+		specs[textValue(entries[i])] = entries[i+1]
+	}
+	for fileName, spec := range dw.attachments.specs {
+		if _, ok := specs[fileName]; ok {
+			return nil, errDuplicateAttachmentName
+		}
+		specs[fileName] = &indirectObjectRef{spec}
+	}
+	fileNames := make([]string, 0, len(specs))
+	for fileName := range specs {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	entries = make(array, 0, len(fileNames)*2)
+	for _, fileName := range fileNames {
+		entries = append(entries, textString(fileName), specs[fileName])
+	}
+	tree = tree.clone()
+	tree["Names"] = entries
+	dw.file.body.remove(dw.attachments)
+	return dw.revise(old, tree), nil
+}
+
+// mergeDests returns the existing dictionary of named destinations, old,
+// with those of the update added.
+func (dw *DocWriter) mergeDests(old writer) (writer, error) {
+	dests, err := dw.update.reader.resolveDict(old)
+	if err != nil {
+		return nil, fmt.Errorf("reading named destinations: %w", err)
+	}
+	dests = dests.clone()
+	for destName, dest := range dw.namedDests.dict {
+		if _, ok := dests[destName]; ok {
+			return nil, fmt.Errorf("named destination %q is already in the document", destName)
+		}
+		dests[destName] = dest
+	}
+	dw.file.body.remove(dw.namedDests)
+	return dw.revise(old, dests), nil
+}
+
+// mergeAF returns the existing files associated with the document, old,
+// followed by those of the update.
+func (dw *DocWriter) mergeAF(old writer) (writer, error) {
+	v, err := dw.update.reader.resolve(old)
+	if err != nil {
+		return nil, fmt.Errorf("reading associated files: %w", err)
+	}
+	af, _ := v.(array)
+	return append(append(array{}, af...), dw.catalog.dict["AF"].(array)...), nil
+}
+
+// writeUpdate writes the existing file followed by the update: its objects
+// and a cross-reference section listing only them.
+func (dw *DocWriter) writeUpdate(wr io.Writer) error {
+	if dw.encryption != nil {
+		return errUpdateEncryption
+	}
+	if err := dw.finishBody(); err != nil {
+		return err
+	}
+	f := dw.file
+	data := dw.update.reader.data
+	cw := &countingWriter{w: wr}
+	cw.Write(data)
+	if !bytes.HasSuffix(data, []byte("\n")) && !bytes.HasSuffix(data, []byte("\r")) {
+		cw.Write([]byte("\n"))
+	}
+	ss := newXRefSubSection()
+	f.body.write(cw, ss)
+	f.trailer.xrefTableStart = cw.Len()
+	if f.xrefStreamSeq != 0 {
+		ss.set(f.xrefStreamSeq, &inUseXRefEntry{cw.Len(), 0})
+	}
+	f.trailer.setXrefTableSize(max(dw.update.size, ss.len()))
+	sections := updateSubSections(ss)
+	if f.xrefStreamSeq != 0 {
+		newXRefStreamSections(f.xrefStreamSeq, 0, sections, f.trailer.dict).write(cw)
+		f.trailer.writeStartXRef(cw)
+	} else {
+		table := xRefTable{list: sections}
+		table.write(cw)
+		f.trailer.write(cw)
+	}
+	return cw.err
+}
+
+// updateSubSections splits the entries of ss into subsections of the
+// consecutive objects written, omitting the free entries between them.
+func updateSubSections(ss *xRefSubSection) (sections []*xRefSubSection) {
+	var cur *xRefSubSection
+	for i := 1; i < ss.len(); i++ {
+		e := ss.list[i]
+		if _, free := e.(*freeXRefEntry); free {
+			cur = nil
+			continue
+		}
+		if cur == nil {
+			cur = &xRefSubSection{start: i}
+			sections = append(sections, cur)
+		}
+		cur.add(e)
+	}
+	return
+}
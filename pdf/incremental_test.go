@@ -0,0 +1,355 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rowland/leadtype/afm_fonts"
+	"github.com/rowland/leadtype/options"
+)
+
+// statementPDF returns a two-page document to be updated.
+func statementPDF(t *testing.T, compressObjects bool) []byte {
+	dw := NewDocWriter().CompressObjects(compressObjects)
+	dw.SetTitle("Statement – März").SetCreationDate(time.Date(2026, 1, 31, 12, 0, 0, 0, time.FixedZone("", -5*3600)))
+	dw.NewPage().Rectangle(1, 1, 2, 2, true, false)
+	dw.NewPage().Rectangle(1, 1, 3, 3, true, false)
+	var buf bytes.Buffer
+	if _, err := dw.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func writeUpdated(t *testing.T, dw *DocWriter) []byte {
+	var buf bytes.Buffer
+	if _, err := dw.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestOpenDocWriter_Stamp(t *testing.T) {
+	for _, compressObjects := range []bool{false, true} {
+		original := statementPDF(t, compressObjects)
+		before, err := newPDFReader(original)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dw, err := OpenDocWriter(original)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectI(t, 2, dw.ExistingPageCount())
+		expectS(t, "Statement – März", dw.metadata.title)
+		check(t, dw.metadata.creationDate.Equal(time.Date(2026, 1, 31, 17, 0, 0, 0, time.UTC)), "Creation date should be read")
+		pw, err := dw.EditPage(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, dw.CurPage() == pw, "Edited page should become the current page")
+		fonts, err := afm_fonts.Default()
+		if err != nil {
+			t.Fatal(err)
+		}
+		dw.AddFontSource(fonts)
+		pw.SetFont("Helvetica", 24, nil)
+		pw.MoveTo(72, 72)
+		pw.Print("APPROVED")
+		pw.Rectangle(1, 1, 4, 1, true, false)
+		pw.LinkToURI(1, 1, 4, 1, "https://example.com/approved")
+		dw.NewPage().Rectangle(1, 1, 2, 2, true, false)
+		dw.SetModDate(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+		updated := writeUpdated(t, dw)
+
+		check(t, bytes.HasPrefix(updated, original), "Original bytes should be kept")
+		after, err := newPDFReader(updated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, after.xrefStreams == compressObjects, "Update should use the same kind of cross-reference section")
+		expectI(t, before.startXRef, int(after.trailer["Prev"].(integer)))
+		check(t, after.trailer["Root"] == before.trailer["Root"], "Root should be unchanged")
+		check(t, after.trailer["Info"] != before.trailer["Info"], "Info should be replaced")
+
+		reopened, err := OpenDocWriter(updated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u := reopened.update
+		expectI(t, 3, reopened.ExistingPageCount())
+		expectS(t, "Statement – März", reopened.metadata.title)
+		check(t, !reopened.metadata.modDate.IsZero(), "ModDate should be added")
+
+		first, stamped := u.pages[0], u.pages[1]
+		expectI(t, before.xref[first.ref.seq].offset, after.xref[first.ref.seq].offset)
+		contents := stamped.dict["Contents"].(array)
+		expectI(t, 3, len(contents))
+		save, _ := after.stream(contents[0].(objectRef).seq)
+		expectS(t, "q\n", string(save.data))
+		restore, _ := after.stream(contents[2].(objectRef).seq)
+		expectS(t, "Q\n/Stamp Do\n", string(restore.data))
+		xobjects := stamped.resources["XObject"].(dictionary)
+		stamp, err := after.stream(xobjects["Stamp"].(objectRef).seq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectS(t, "/Form ", stringFromWriter(stamp.dict["Subtype"]))
+		data, err := decodeStream(stamp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, bytes.Contains(data, []byte("(APPROVED) Tj")), "Stamp should show the text")
+		resources, _ := after.resolveDict(stamp.dict["Resources"])
+		fontDict, _ := after.resolveDict(resources["Font"])
+		check(t, len(fontDict) == 1, "Stamp resources should include its font")
+		annots := stamped.dict["Annots"].(array)
+		expectI(t, 1, len(annots))
+	}
+}
+
+func TestOpenDocWriter_Unchanged(t *testing.T) {
+	original := statementPDF(t, false)
+	dw, err := OpenDocWriter(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.EditPage(0)
+	updated := writeUpdated(t, dw)
+	after, err := newPDFReader(updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(updated[len(original):])
+	check(t, !strings.Contains(s, "/Type /Page "), "Unchanged pages should not be written")
+	check(t, !strings.Contains(s, "/Type /Catalog "), "Unchanged catalog should not be written")
+	check(t, !strings.Contains(s, "/Title "), "Unchanged metadata should not be written")
+	_, ok := after.xref[int(after.trailer["Size"].(integer))-1]
+	check(t, ok, "Size should cover the objects of the update")
+}
+
+func TestOpenDocWriter_Errors(t *testing.T) {
+	_, err := NewDocWriter().EditPage(0)
+	check(t, err == errNotUpdate, "EditPage should require an existing document")
+
+	dw, err := OpenDocWriter(statementPDF(t, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = dw.EditPage(2)
+	check(t, err == errPageIndex, "Page index should be checked")
+	dw.SetEncryption(AES128, "user", "owner", PermitPrint)
+	_, err = dw.WriteTo(&bytes.Buffer{})
+	check(t, err == errUpdateEncryption, "Update should not add encryption")
+
+	dw = NewDocWriter().SetEncryption(AES128, "user", "owner", PermitPrint)
+	_, err = OpenDocWriter(writeUpdated(t, dw))
+	check(t, err == errUpdateEncrypted, "Encrypted documents should be rejected")
+
+	_, err = OpenDocWriter([]byte("not a PDF"))
+	check(t, err == errNoStartXRef, "Should report a missing startxref")
+
+	// Break the content stream of the first page.
+	broken := bytes.Replace(statementPDF(t, false), []byte("\n7 0 obj\n"), []byte("\n7 0 xxx\n"), 1)
+	dw, err = OpenDocWriter(broken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = dw.EditPage(0)
+	check(t, err != nil, "Unreadable contents should be reported by EditPage")
+	_, err = dw.EditPage(1)
+	check(t, err == nil, "Other pages should still be editable")
+}
+
+func TestOpenDocWriter_StampRotated(t *testing.T) {
+	for _, tc := range []struct {
+		rotate string // same length as the original entry, keeping the xref valid
+		matrix string
+		rect   string // the displayed top-left inch, whichever corner that is
+	}{
+		{"R \n/Rotate 0 \n", "[1 0 0 1 36 72 ]", "[36 720 108 792 ]"},
+		{"R \n/Rotate 90\n", "[0 1 -1 0 612 72 ]", "[36 72 108 144 ]"},
+		{"R\n/Rotate 180\n", "[-1 0 0 -1 612 792 ]", "[540 72 612 144 ]"},
+		{"R\n/Rotate 270\n", "[0 -1 1 0 36 792 ]", "[540 720 612 792 ]"},
+	} {
+		original := statementPDF(t, false)
+		original = bytes.Replace(original, []byte("/MediaBox [0 0 612 792 ] "), []byte("/MediaBox[36 72 612 792] "), 1)
+		original = bytes.Replace(original, []byte("R \n/Rotate 0 \n"), []byte(tc.rotate), 1)
+		dw, err := OpenDocWriter(original)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pw, err := dw.EditPage(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		width, height := 576.0, 720.0
+		if ep := dw.update.pages[0]; ep.rotate == 90 || ep.rotate == 270 {
+			width, height = height, width
+		}
+		expectF(t, width, pw.PageWidth())
+		expectF(t, height, pw.PageHeight())
+		pw.Rectangle(0, 0, 72, 72, true, false)
+		pw.LinkToURI(0, 0, 72, 72, "https://example.com")
+		updated := string(writeUpdated(t, dw)[len(original):])
+		check(t, strings.Contains(updated, "/Matrix "+tc.matrix), "Stamp should be turned against /Rotate: "+tc.rotate)
+		check(t, strings.Contains(updated, "/Rect "+tc.rect), "Link should be turned against /Rotate: "+tc.rotate)
+	}
+}
+
+func TestParsePDFDate(t *testing.T) {
+	for s, want := range map[string]time.Time{
+		"D:2026":                  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		"D:20260314":              time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC),
+		"D:20260314092653Z":       time.Date(2026, 3, 14, 9, 26, 53, 0, time.UTC),
+		"D:20260314092653+05'30'": time.Date(2026, 3, 14, 3, 56, 53, 0, time.UTC),
+		"20260314092653-08'00":    time.Date(2026, 3, 14, 17, 26, 53, 0, time.UTC),
+		"March":                   {},
+	} {
+		check(t, parsePDFDate(s).Equal(want), s)
+	}
+}
+
+// catalogPDF returns a document with a form, an outline, page labels, an
+// attachment and a named destination, to be updated.
+func catalogPDF(t *testing.T) []byte {
+	dw := NewDocWriter()
+	fonts, err := afm_fonts.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.AddFontSource(fonts)
+	pw := dw.NewPage()
+	if _, err := pw.SetFont("Helvetica", 12, nil); err != nil {
+		t.Fatal(err)
+	}
+	check(t, pw.TextField(72, 72, 144, 18, "name", nil) == nil, "TextField should succeed")
+	dw.AddOutline("Cover", pw, 0, nil)
+	dw.AddDestination("cover", 0)
+	dw.NewPage()
+	dw.SetPageLabels(0, PageLabelLowerRoman, "", 1)
+	check(t, dw.AttachFile("data.csv", []byte("a,b"), options.Options{"relationship": "Data"}) == nil, "AttachFile should succeed")
+	return writeUpdated(t, dw)
+}
+
+func TestOpenDocWriter_MergeCatalog(t *testing.T) {
+	original := catalogPDF(t)
+	before, err := newPDFReader(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldCatalog, _ := before.resolveDict(before.trailer["Root"])
+
+	dw, err := OpenDocWriter(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fonts, err := afm_fonts.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.AddFontSource(fonts)
+	pw := dw.NewPage()
+	if _, err := pw.SetFont("Times-Roman", 12, nil); err != nil {
+		t.Fatal(err)
+	}
+	check(t, pw.TextField(72, 72, 144, 18, "name", nil) == errDuplicateFieldName, "Existing field names should be taken")
+	check(t, pw.TextField(72, 108, 144, 18, "date", nil) == nil, "TextField should succeed")
+	dw.AddOutline("Appendix", pw, 0, nil)
+	dw.AddDestination("appendix", 0)
+	dw.BeginPageLabels(PageLabelUpperAlpha, "A-", 1)
+	check(t, dw.AttachFile("invoice.xml", []byte("<invoice/>"), options.Options{"relationship": "Source"}) == nil, "AttachFile should succeed")
+	updated := writeUpdated(t, dw)
+
+	after, err := newPDFReader(updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog, _ := after.resolveDict(after.trailer["Root"])
+	for _, k := range []string{"AcroForm", "Outlines", "Dests"} {
+		check(t, catalog[k] == oldCatalog[k], "/"+k+" should be written again in place")
+	}
+
+	form, _ := after.resolveDict(catalog["AcroForm"])
+	fields := form["Fields"].(array)
+	expectI(t, 2, len(fields))
+	oldForm, _ := before.resolveDict(oldCatalog["AcroForm"])
+	check(t, fields[0] == oldForm["Fields"].(array)[0], "Existing fields should come first")
+	dr, _ := after.resolveDict(form["DR"])
+	formFonts, _ := after.resolveDict(dr["Font"])
+	expectI(t, 2, len(formFonts))
+
+	outlines, _ := after.resolveDict(catalog["Outlines"])
+	expectS(t, "2 ", stringFromWriter(outlines["Count"]))
+	cover, _ := after.resolveDict(outlines["First"])
+	appendix, _ := after.resolveDict(outlines["Last"])
+	expectS(t, "Cover", textValue(cover["Title"]))
+	expectS(t, "Appendix", textValue(appendix["Title"]))
+	check(t, cover["Next"] == outlines["Last"], "Existing item should link to the new one")
+	check(t, appendix["Prev"] == outlines["First"], "New item should link to the existing one")
+	check(t, appendix["Parent"] == catalog["Outlines"], "New item should belong to the existing outline")
+
+	labels := catalog["PageLabels"].(dictionary)
+	expectS(t, "[0 <<\n/S /r \n>>\n2 <<\n/P (A-) \n/S /A \n>>\n] ", stringFromWriter(labels["Nums"]))
+
+	dests, _ := after.resolveDict(catalog["Dests"])
+	check(t, dests["cover"] != nil && dests["appendix"] != nil, "Named destinations should be merged")
+
+	names, _ := after.resolveDict(catalog["Names"])
+	files, _ := after.resolveDict(names["EmbeddedFiles"])
+	entries := files["Names"].(array)
+	expectI(t, 4, len(entries))
+	expectS(t, "data.csv", textValue(entries[0]))
+	expectS(t, "invoice.xml", textValue(entries[2]))
+	expectI(t, 2, len(catalog["AF"].(array)))
+}
+
+func TestOpenDocWriter_MergeErrors(t *testing.T) {
+	original := catalogPDF(t)
+	dw, err := OpenDocWriter(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.AttachFile("data.csv", []byte("a,b,c"), nil)
+	_, err = dw.WriteTo(&bytes.Buffer{})
+	check(t, err == errDuplicateAttachmentName, "Existing attachment names should be taken")
+
+	dw, err = OpenDocWriter(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.NewPage()
+	dw.AddDestination("cover", 0)
+	_, err = dw.WriteTo(&bytes.Buffer{})
+	check(t, err != nil, "Existing destination names should be taken")
+
+	tagged := NewDocWriter()
+	tagged.NewPage()
+	tagged.BeginTag("P", nil)
+	tagged.EndTag()
+	dw, err = OpenDocWriter(writeUpdated(t, tagged))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.NewPage()
+	dw.BeginTag("P", nil)
+	dw.EndTag()
+	_, err = dw.WriteTo(&bytes.Buffer{})
+	check(t, err != nil, "A structure tree should not be added to a tagged document")
+
+	// Split the tree of embedded files, keeping the length of the entry.
+	split := bytes.Replace(original, []byte("/Names [(data.csv) "), []byte("/Kids  [(data.csv) "), 1)
+	dw, err = OpenDocWriter(split)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.AttachFile("invoice.xml", []byte("<invoice/>"), nil)
+	_, err = dw.WriteTo(&bytes.Buffer{})
+	check(t, err == errUpdateTreeKids, "Trees with /Kids should not be merged")
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// docMetadata holds the descriptive fields written to both the document
+// information dictionary and the XMP metadata stream.
+type docMetadata struct {
+	title        string
+	author       string
+	subject      string
+	keywords     string
+	creator      string
+	producer     string
+	creationDate time.Time
+	modDate      time.Time
+}
+
+func (md *docMetadata) isEmpty() bool {
+	return md.title == "" && md.author == "" && md.subject == "" && md.keywords == "" &&
+		md.creator == "" && md.producer == "" && md.creationDate.IsZero() && md.modDate.IsZero()
+}
+
+// infoDict returns the document information dictionary (PDF spec §14.3.3).
+func (md *docMetadata) infoDict() dictionary {
+	d := dictionary{}
+	setText := func(key, value string) {
+		if value != "" {
+			d[key] = textString(value)
+		}
+	}
+	setText("Title", md.title)
+	setText("Author", md.author)
+	setText("Subject", md.subject)
+	setText("Keywords", md.keywords)
+	setText("Creator", md.creator)
+	setText("Producer", md.producer)
+	if !md.creationDate.IsZero() {
+		d["CreationDate"] = str(pdfDate(md.creationDate))
+	}
+	if !md.modDate.IsZero() {
+		d["ModDate"] = str(pdfDate(md.modDate))
+	}
+	return d
+}
+
+// pdfDate formats t as a PDF date string, D:YYYYMMDDHHmmSSOHH'mm (PDF spec §7.9.4).
+func pdfDate(t time.Time) string {
+	s := t.Format("D:20060102150405")
+	_, offset := t.Zone()
+	if offset == 0 {
+		return s + "Z"
+	}
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	return fmt.Sprintf("%s%c%02d'%02d'", s, sign, offset/3600, (offset%3600)/60)
+}
+
+// xmpDate formats t as an XMP (ISO 8601) date.
+func xmpDate(t time.Time) string {
+	return t.Format("2006-01-02T15:04:05Z07:00")
+}
+
+// xmpSchema is a namespace block within the XMP rdf:Description.
+type xmpSchema struct {
+	prefix, uri string
+	properties  []string
+}
+
+// xmpSchemas returns the Dublin Core, XMP basic and Adobe PDF schemas
+// mirroring the information dictionary.
+func (md *docMetadata) xmpSchemas() []xmpSchema {
+	dc := xmpSchema{prefix: "dc", uri: "http://purl.org/dc/elements/1.1/"}
+	dc.properties = append(dc.properties, "<dc:format>application/pdf</dc:format>")
+	if md.title != "" {
+		dc.properties = append(dc.properties, xmpAlt("dc:title", md.title))
+	}
+	if md.author != "" {
+		dc.properties = append(dc.properties, xmpSeq("dc:creator", md.author))
+	}
+	if md.subject != "" {
+		dc.properties = append(dc.properties, xmpAlt("dc:description", md.subject))
+	}
+
+	basic := xmpSchema{prefix: "xmp", uri: "http://ns.adobe.com/xap/1.0/"}
+	if md.creator != "" {
+		basic.properties = append(basic.properties, xmpSimple("xmp:CreatorTool", md.creator))
+	}
+	if !md.creationDate.IsZero() {
+		basic.properties = append(basic.properties, xmpSimple("xmp:CreateDate", xmpDate(md.creationDate)))
+	}
+	if !md.modDate.IsZero() {
+		basic.properties = append(basic.properties,
+			xmpSimple("xmp:ModifyDate", xmpDate(md.modDate)),
+			xmpSimple("xmp:MetadataDate", xmpDate(md.modDate)))
+	}
+
+	pdf := xmpSchema{prefix: "pdf", uri: "http://ns.adobe.com/pdf/1.3/"}
+	if md.keywords != "" {
+		pdf.properties = append(pdf.properties, xmpSimple("pdf:Keywords", md.keywords))
+	}
+	if md.producer != "" {
+		pdf.properties = append(pdf.properties, xmpSimple("pdf:Producer", md.producer))
+	}
+	return []xmpSchema{dc, basic, pdf}
+}
+
+// xmpPacket serializes schemas as a complete XMP packet.
+func xmpPacket(schemas []xmpSchema) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("<?xpacket begin=\"\xEF\xBB\xBF\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	buf.WriteString("<x:xmpmeta xmlns:x=\"adobe:ns:meta/\">\n")
+	buf.WriteString("<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n")
+	for _, schema := range schemas {
+		if len(schema.properties) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "<rdf:Description rdf:about=\"\" xmlns:%s=\"%s\">\n", schema.prefix, schema.uri)
+		for _, p := range schema.properties {
+			buf.WriteString(p)
+			buf.WriteString("\n")
+		}
+		buf.WriteString("</rdf:Description>\n")
+	}
+	buf.WriteString("</rdf:RDF>\n")
+	buf.WriteString("</x:xmpmeta>\n")
+	// Padding lets editors update the packet in place.
+	for i := 0; i < 20; i++ {
+		buf.WriteString(strings.Repeat(" ", 99))
+		buf.WriteString("\n")
+	}
+	buf.WriteString("<?xpacket end=\"w\"?>")
+	return buf.Bytes()
+}
+
+func xmpEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+func xmpSimple(tag, value string) string {
+	return fmt.Sprintf("<%s>%s</%s>", tag, xmpEscape(value), tag)
+}
+
+func xmpAlt(tag, value string) string {
+	return fmt.Sprintf("<%s><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></%s>", tag, xmpEscape(value), tag)
+}
+
+func xmpSeq(tag, value string) string {
+	return fmt.Sprintf("<%s><rdf:Seq><rdf:li>%s</rdf:li></rdf:Seq></%s>", tag, xmpEscape(value), tag)
+}
+
+// newMetadataStream returns an uncompressed XMP metadata stream for the
+// catalog's /Metadata entry (PDF spec §14.3.2).
+func newMetadataStream(seq, gen int, data []byte) *stream {
+	s := newStream(seq, gen, data)
+	s.dict["Type"] = name("Metadata")
+	s.dict["Subtype"] = name("XML")
+	return s
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPdfDate(t *testing.T) {
+	utc := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	expectS(t, "D:20260304050607Z", pdfDate(utc))
+
+	east := time.Date(2026, 3, 4, 5, 6, 7, 0, time.FixedZone("", 5*3600+30*60))
+	expectS(t, "D:20260304050607+05'30'", pdfDate(east))
+
+	west := time.Date(2026, 3, 4, 5, 6, 7, 0, time.FixedZone("", -8*3600))
+	expectS(t, "D:20260304050607-08'00'", pdfDate(west))
+}
+
+func TestXmpDate(t *testing.T) {
+	expectS(t, "2026-03-04T05:06:07Z", xmpDate(time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)))
+	expectS(t, "2026-03-04T05:06:07-08:00", xmpDate(time.Date(2026, 3, 4, 5, 6, 7, 0, time.FixedZone("", -8*3600))))
+}
+
+func TestDocMetadata_InfoDict(t *testing.T) {
+	var md docMetadata
+	check(t, md.isEmpty(), "zero metadata should be empty")
+	md.title = "Квартальный отчёт"
+	md.author = "Jane (Q) Doe"
+	md.creationDate = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	check(t, !md.isEmpty(), "metadata with a title should not be empty")
+
+	d := md.infoDict()
+	expectS(t, "(Jane \\(Q\\) Doe) ", stringFromWriter(d["Author"]))
+	expectS(t, "(D:20260102030405Z) ", stringFromWriter(d["CreationDate"]))
+	check(t, strings.HasPrefix(stringFromWriter(d["Title"]), "<FEFF041A"), "non-Latin title should be UTF-16BE")
+	_, hasSubject := d["Subject"]
+	check(t, !hasSubject, "unset fields should be omitted")
+}
+
+func TestXmpPacket(t *testing.T) {
+	md := docMetadata{
+		title:    "Fish & Chips",
+		author:   "A <B>",
+		keywords: "food",
+		producer: "leadtype",
+		modDate:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	xmp := string(xmpPacket(md.xmpSchemas()))
+	for _, fragment := range []string{
+		"<?xpacket begin=\"\xEF\xBB\xBF\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>",
+		"<dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">Fish &amp; Chips</rdf:li></rdf:Alt></dc:title>",
+		"<dc:creator><rdf:Seq><rdf:li>A &lt;B&gt;</rdf:li></rdf:Seq></dc:creator>",
+		"<pdf:Keywords>food</pdf:Keywords>",
+		"<pdf:Producer>leadtype</pdf:Producer>",
+		"<xmp:ModifyDate>2026-01-02T03:04:05Z</xmp:ModifyDate>",
+		"<?xpacket end=\"w\"?>",
+	} {
+		if !strings.Contains(xmp, fragment) {
+			t.Fatalf("expected XMP to contain %q, got:\n%s", fragment, xmp)
+		}
+	}
+	check(t, !strings.Contains(xmp, "xmp:CreateDate"), "unset dates should be omitted")
+}
+
+func TestDocWriter_Metadata(t *testing.T) {
+	dw := NewDocWriter()
+	dw.SetTitle("Statement").
+		SetAuthor("Accounts").
+		SetSubject("Monthly statement").
+		SetKeywords("statement, invoice").
+		SetCreator("billing").
+		SetProducer("leadtype").
+		SetCreationDate(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)).
+		SetModDate(time.Date(2026, 1, 3, 3, 4, 5, 0, time.UTC))
+
+	var buf bytes.Buffer
+	if _, err := dw.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.String()
+	for _, fragment := range []string{
+		"%PDF-1.4\n",
+		"/Title (Statement) \n",
+		"/Author (Accounts) \n",
+		"/Subject (Monthly statement) \n",
+		"/Keywords (statement, invoice) \n",
+		"/Creator (billing) \n",
+		"/Producer (leadtype) \n",
+		"/CreationDate (D:20260102030405Z) \n",
+		"/ModDate (D:20260103030405Z) \n",
+		"/Subtype /XML \n/Type /Metadata \n",
+		"<xmp:CreatorTool>billing</xmp:CreatorTool>",
+	} {
+		if !strings.Contains(pdf, fragment) {
+			t.Fatalf("expected generated PDF to contain %q, got:\n%s", fragment, pdf)
+		}
+	}
+	infoRef := stringFromWriter(dw.file.trailer.dict["Info"])
+	check(t, strings.Contains(pdf, "trailer\n<<\n/Info "+infoRef), "trailer should reference the info dictionary")
+	check(t, dw.catalog.dict["Metadata"] != nil, "catalog should reference the XMP metadata stream")
+}
+
+func TestDocWriter_NoMetadata(t *testing.T) {
+	dw := NewDocWriter()
+	var buf bytes.Buffer
+	if _, err := dw.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	check(t, strings.HasPrefix(buf.String(), "%PDF-1.3\n"), "version should remain 1.3 without metadata")
+	check(t, dw.file.trailer.dict["Info"] == nil, "trailer should not reference an info dictionary")
+	check(t, dw.catalog.dict["Metadata"] == nil, "catalog should not reference metadata")
+}
@@ -40,7 +40,10 @@ func (mw *miscWriter) setColorStroke(colors []float64) {
 	fmt.Fprintf(mw.wr, "%s SC\n", float64Slice(colors).join(" "))
 }
 
-// TODO: scn, SCN: patterns and separations
+func (mw *miscWriter) setPatternFill(name string) {
+	fmt.Fprintf(mw.wr, "/%s scn\n", name)
+}
+
 func (mw *miscWriter) setColorRenderingIntent(intent string) {
 	fmt.Fprintf(mw.wr, "/%s ri\n", intent)
 }
@@ -61,6 +64,25 @@ func (mw *miscWriter) setRgbColorStroke(red, green, blue float64) {
 	fmt.Fprintf(mw.wr, "%s %s %s RG\n", g(red), g(green), g(blue))
 }
 
+func (mw *miscWriter) shade(name string) {
+	fmt.Fprintf(mw.wr, "/%s sh\n", name)
+}
+
 func (mw *miscWriter) xObject(name string) {
 	fmt.Fprintf(mw.wr, "/%s Do\n", name)
 }
+
+func (mw *miscWriter) beginMarkedContent(tag string) {
+	name(tag).write(mw.wr)
+	fmt.Fprintf(mw.wr, "BMC\n")
+}
+
+func (mw *miscWriter) beginMarkedContentWithProperties(tag string, properties dictionary) {
+	name(tag).write(mw.wr)
+	properties.write(mw.wr)
+	fmt.Fprintf(mw.wr, "BDC\n")
+}
+
+func (mw *miscWriter) endMarkedContent() {
+	fmt.Fprintf(mw.wr, "EMC\n")
+}
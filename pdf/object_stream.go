@@ -0,0 +1,81 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// maxObjectStreamMembers limits the size of each object stream so that
+// readers need not decompress one huge stream to reach any object.
+const maxObjectStreamMembers = 100
+
+// compressible is implemented by stream objects, which cannot themselves be
+// stored in an object stream.
+type compressible interface {
+	compress() error
+}
+
+// objectStream is a compressed /ObjStm stream holding the bodies of
+// non-stream objects (PDF spec §7.5.7).
+type objectStream struct {
+	stream
+	members genWriterArray
+}
+
+func newObjectStream(seq, gen int, members genWriterArray) *objectStream {
+	var offsets, bodies bytes.Buffer
+	for _, m := range members {
+		fmt.Fprintf(&offsets, "%d %d ", m.(seqGen).Seq(), bodies.Len())
+		bodies.Write(objectBody(m))
+	}
+	objStm := &objectStream{members: members}
+	objStm.stream.init(seq, gen, append(offsets.Bytes(), bodies.Bytes()...))
+	objStm.dict["Type"] = name("ObjStm")
+	objStm.dict["N"] = integer(len(members))
+	objStm.dict["First"] = integer(offsets.Len())
+	if err := objStm.compress(); err != nil {
+		panic(err)
+	}
+	return objStm
+}
+
+// objectBody returns obj as written in the file body, less its "obj" header
+// and "endobj" footer.
+func objectBody(obj genWriter) []byte {
+	var buf bytes.Buffer
+	obj.write(&buf)
+	header := fmt.Sprintf("%d %d obj\n", obj.(seqGen).Seq(), obj.Gen())
+	return bytes.TrimSuffix(bytes.TrimPrefix(buf.Bytes(), []byte(header)), []byte("endobj\n"))
+}
+
+// writeObjectStreams moves every object that may be compressed into object
+// streams and switches the file to a cross-reference stream, which is
+// required to locate objects within object streams.
+func (dw *DocWriter) writeObjectStreams() {
+	if !dw.compressObjects {
+		return
+	}
+	var members, rest genWriterArray
+	for _, e := range dw.file.body.list {
+		_, isStream := e.(compressible)
+		isEncrypt := dw.file.body.security != nil && e == genWriter(dw.file.body.security.dict)
+		if isStream || isEncrypt || e.Gen() != 0 {
+			rest = append(rest, e)
+		} else {
+			members = append(members, e)
+		}
+	}
+	for len(members) > 0 {
+		n := min(len(members), maxObjectStreamMembers)
+		objStm := newObjectStream(dw.nextSeq(), 0, members[:n])
+		rest = append(rest, objStm)
+		dw.file.body.objectStreams = append(dw.file.body.objectStreams, objStm)
+		members = members[n:]
+	}
+	dw.file.body.list = rest
+	dw.file.xrefStreamSeq = dw.nextSeq()
+	dw.requireVersion(1.5)
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rowland/leadtype/afm_fonts"
+)
+
+func inflate(t *testing.T, data []byte) []byte {
+	t.Helper()
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestObjectBody(t *testing.T) {
+	d := newDictionaryObject(7, 0)
+	d.dict["Type"] = name("Test")
+	expectS(t, "<<\n/Type /Test \n>>\n", string(objectBody(d)))
+}
+
+func TestObjectStream(t *testing.T) {
+	a := newDictionaryObject(3, 0)
+	a.dict["A"] = integer(1)
+	b := newDictionaryObject(5, 0)
+	b.dict["B"] = str("two")
+	objStm := newObjectStream(9, 0, genWriterArray{a, b})
+
+	expectS(t, "/ObjStm ", stringFromWriter(objStm.dict["Type"]))
+	expectS(t, "2 ", stringFromWriter(objStm.dict["N"]))
+	expectS(t, "/FlateDecode ", stringFromWriter(objStm.dict["Filter"]))
+	expectS(t, "3 0 5 12 <<\n/A 1 \n>>\n<<\n/B (two) \n>>\n", string(inflate(t, objStm.data)))
+	expectS(t, "9 ", stringFromWriter(objStm.dict["First"]))
+}
+
+func TestXRefStream(t *testing.T) {
+	ss := newXRefSubSection()
+	ss.set(1, &inUseXRefEntry{0x1234, 0})
+	ss.set(2, &compressedXRefEntry{3, 1})
+	ss.set(3, &inUseXRefEntry{0x10000, 0})
+	xs := newXRefStream(4, 0, ss, dictionary{"Root": integer(99)})
+
+	expectS(t, "/XRef ", stringFromWriter(xs.dict["Type"]))
+	expectS(t, "[1 3 2 ] ", stringFromWriter(xs.dict["W"]))
+	expectS(t, "4 ", stringFromWriter(xs.dict["Size"]))
+	expectS(t, "99 ", stringFromWriter(xs.dict["Root"]))
+	expected := []byte{
+		0, 0, 0, 0, 0xFF, 0xFF,
+		1, 0, 0x12, 0x34, 0, 0,
+		2, 0, 0, 3, 0, 1,
+		1, 1, 0, 0, 0, 0,
+	}
+	check(t, bytes.Equal(expected, inflate(t, xs.data)), fmt.Sprintf("unexpected xref data % X", inflate(t, xs.data)))
+}
+
+func TestDocWriter_CompressObjects(t *testing.T) {
+	dw := NewDocWriter()
+	fonts, err := afm_fonts.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.AddFontSource(fonts)
+	dw.CompressObjects(true).SetTitle("Invoice")
+	dw.NewPage()
+	if _, err := dw.SetFont("Helvetica", 12, nil); err != nil {
+		t.Fatal(err)
+	}
+	dw.Print("Amount due")
+
+	var buf bytes.Buffer
+	if _, err := dw.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.Bytes()
+	check(t, bytes.HasPrefix(pdf, []byte("%PDF-1.5\n")), "Object streams require PDF 1.5")
+	check(t, !bytes.Contains(pdf, []byte("\nxref\n")), "Should not write an xref table")
+	check(t, !bytes.Contains(pdf, []byte("\ntrailer\n")), "Should not write a trailer dictionary")
+	check(t, !bytes.Contains(pdf, []byte("/Type /Catalog")), "Catalog should be compressed")
+
+	m := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(pdf)
+	if m == nil {
+		t.Fatal("missing startxref")
+	}
+	start, _ := strconv.Atoi(string(m[1]))
+	xs := dw.file.xrefStreamSeq
+	check(t, bytes.HasPrefix(pdf[start:], []byte(fmt.Sprintf("%d 0 obj\n", xs))), "startxref should point at the xref stream")
+
+	// Every in-use entry of the xref stream must point at its object.
+	xrefObj := string(pdf[start:])
+	streamStart := strings.Index(xrefObj, "stream\n") + len("stream\n")
+	streamEnd := strings.LastIndex(xrefObj, "endstream")
+	entries := inflate(t, []byte(xrefObj[streamStart:streamEnd]))
+	wArray := regexp.MustCompile(`/W \[1 (\d) 2 \]`).FindStringSubmatch(xrefObj)
+	if wArray == nil {
+		t.Fatal("missing /W")
+	}
+	n, _ := strconv.Atoi(wArray[1])
+	size := len(entries) / (n + 3)
+	compressed := 0
+	for seq := 0; seq < size; seq++ {
+		e := entries[seq*(n+3):]
+		f2 := 0
+		for i := 0; i < n; i++ {
+			f2 = f2<<8 | int(e[1+i])
+		}
+		switch e[0] {
+		case 1:
+			check(t, bytes.HasPrefix(pdf[f2:], []byte(fmt.Sprintf("%d 0 obj\n", seq))), fmt.Sprintf("entry %d should point at its object", seq))
+		case 2:
+			compressed++
+		}
+	}
+	check(t, compressed > 0, "Some objects should be compressed")
+}
+
+func TestDocWriter_CompressObjects_Encrypted(t *testing.T) {
+	dw := NewDocWriter()
+	dw.CompressObjects(true).SetEncryption(AES128, "", "owner", PermitPrint).SetTitle("Invoice")
+
+	var buf bytes.Buffer
+	if _, err := dw.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.String()
+	check(t, strings.HasPrefix(pdf, "%PDF-1.6\n"), "Encryption should keep the higher version")
+	check(t, strings.Contains(pdf, "/Filter /Standard \n"), "Encryption dictionary should not be compressed")
+	check(t, strings.Contains(pdf, "/ID [<"), "XRef stream should carry the file identifier")
+	check(t, strings.Contains(pdf, "/Encrypt "), "XRef stream should reference the encryption dictionary")
+}
@@ -8,9 +8,45 @@ import (
 	"compress/zlib"
 	"fmt"
 	"io"
+	"slices"
 	"sort"
+	"strings"
+	"unicode/utf16"
 )
 
+type annotation struct {
+	dictionaryObject
+}
+
+func (a *annotation) init(seq, gen int, subType string, rect rectangle) *annotation {
+	a.dictionaryObject.init(seq, gen)
+	a.dict["Type"] = name("Annot")
+	a.dict["Subtype"] = name(subType)
+	a.dict["Rect"] = &rect
+	return a
+}
+
+func newAnnotation(seq, gen int, subType string, rect rectangle) *annotation {
+	return new(annotation).init(seq, gen, subType, rect)
+}
+
+// newLinkAnnotation returns a borderless /Link annotation covering rect.
+func newLinkAnnotation(seq, gen int, rect rectangle) *annotation {
+	a := newAnnotation(seq, gen, "Link", rect)
+	a.dict["Border"] = arrayFromInts([]int{0, 0, 0})
+	return a
+}
+
+// setDest makes the annotation jump to dest, which is either an explicit
+// destination array or the name of an entry in the catalog's /Dests.
+func (a *annotation) setDest(dest writer) {
+	a.dict["Dest"] = dest
+}
+
+func (a *annotation) setURI(uri string) {
+	a.dict["A"] = dictionary{"S": name("URI"), "URI": str(uri)}
+}
+
 type array []writer
 
 func (a array) write(w io.Writer) {
@@ -30,7 +66,10 @@ func arrayFromInts(ints []int) array {
 }
 
 type body struct {
-	list genWriterArray
+	list          genWriterArray
+	objectStreams []*objectStream
+	security      *securityHandler
+	streamed      map[genWriter]bool
 }
 
 func (b *body) add(w ...genWriter) {
@@ -39,12 +78,52 @@ func (b *body) add(w ...genWriter) {
 
 func (b *body) write(w lenWriter, ss *xRefSubSection) {
 	for _, e := range b.list {
-		xe := &inUseXRefEntry{w.Len(), e.Gen()}
-		ss.set(e.(seqGen).Seq(), xe)
+		b.writeObject(w, ss, e)
+	}
+	for _, objStm := range b.objectStreams {
+		for i, m := range objStm.members {
+			ss.set(m.(seqGen).Seq(), &compressedXRefEntry{objStm.seq, i})
+		}
+	}
+}
+
+func (b *body) writeObject(w lenWriter, ss *xRefSubSection, e genWriter) {
+	xe := &inUseXRefEntry{w.Len(), e.Gen()}
+	ss.set(e.(seqGen).Seq(), xe)
+	if b.security != nil && e != genWriter(b.security.dict) {
+		e.write(b.security.writerFor(w, e.(seqGen).Seq(), e.Gen()))
+	} else {
 		e.write(w)
 	}
 }
 
+// remove drops objs from the list of objects to be written.
+func (b *body) remove(objs ...genWriter) {
+	list := b.list[:0]
+	for _, e := range b.list {
+		if !slices.Contains(objs, e) {
+			list = append(list, e)
+		}
+	}
+	b.list = list
+}
+
+// removeStreamed drops objects already written by file.writeObjects from
+// the list of objects still to be written.
+func (b *body) removeStreamed() {
+	list := b.list[:0]
+	for _, e := range b.list {
+		if !b.streamed[e] {
+			list = append(list, e)
+		}
+	}
+	for i := len(list); i < len(b.list); i++ {
+		b.list[i] = nil
+	}
+	b.list = list
+	b.streamed = nil
+}
+
 type boolean bool
 
 func (b boolean) write(w io.Writer) {
@@ -74,6 +153,11 @@ func newCatalog(seq, gen int, pageMode string, pages *pages, outlines *outlines)
 	return new(catalog).init(seq, gen, pageMode, pages, outlines)
 }
 
+func (c *catalog) setPageMode(pageMode string) {
+	c.pageMode = pageMode
+	c.dict["PageMode"] = name(pageMode)
+}
+
 type dictionary map[string]writer
 
 func (d dictionary) keys() []string {
@@ -87,6 +171,15 @@ func (d dictionary) keys() []string {
 	return sa
 }
 
+// clone returns a shallow copy of d.
+func (d dictionary) clone() dictionary {
+	c := make(dictionary, len(d))
+	for k, v := range d {
+		c[k] = v
+	}
+	return c
+}
+
 func (d dictionary) write(w io.Writer) {
 	fmt.Fprintf(w, "<<\n")
 	for _, k := range d.keys() {
@@ -122,26 +215,73 @@ type file struct {
 	header  header
 	body    body
 	trailer trailer
+	// xrefStreamSeq is the object number of the cross-reference stream
+	// written in place of the xref table and trailer, or 0 for a table.
+	xrefStreamSeq int
+	// out and ss hold the output and the cross-reference entries written so
+	// far while streaming; out is nil when the file is written all at once.
+	out *countingWriter
+	ss  *xRefSubSection
 }
 
 func newFile() *file {
-	return &file{header{}, body{}, trailer{dictionary{}, 0}}
+	return &file{trailer: trailer{dictionary{}, 0}}
 }
 
 func (f *file) write(w io.Writer) {
-	var buf bytes.Buffer // TODO: replace with io.Writer wrapper that implements lenWriter interface
-	var table xRefTable
+	var buf bytes.Buffer
 	ss := newXRefSubSection()
-	table.add(ss)
 	f.header.write(&buf)
-	f.body.write(&buf, ss)
-	f.trailer.xrefTableStart = buf.Len()
-	f.trailer.setXrefTableSize(ss.len())
-	table.write(&buf)
-	f.trailer.write(&buf)
+	f.writeRest(&buf, ss)
 	buf.WriteTo(w)
 }
 
+// writeRest writes the remaining body objects followed by the
+// cross-reference section and trailer.
+func (f *file) writeRest(w lenWriter, ss *xRefSubSection) {
+	f.body.write(w, ss)
+	f.trailer.xrefTableStart = w.Len()
+	if f.xrefStreamSeq != 0 {
+		ss.set(f.xrefStreamSeq, &inUseXRefEntry{w.Len(), 0})
+		newXRefStream(f.xrefStreamSeq, 0, ss, f.trailer.dict).write(w)
+		f.trailer.writeStartXRef(w)
+	} else {
+		var table xRefTable
+		table.add(ss)
+		f.trailer.setXrefTableSize(ss.len())
+		table.write(w)
+		f.trailer.write(w)
+	}
+}
+
+// begin starts streaming the file to w by writing its header. Objects are
+// then written as they are finished with writeObjects, and the rest of the
+// file with finish.
+func (f *file) begin(w io.Writer) {
+	f.out = &countingWriter{w: w}
+	f.ss = newXRefSubSection()
+	f.header.write(f.out)
+}
+
+// writeObjects writes objs to the streaming output ahead of the rest of the
+// body. They are removed from the body by removeStreamed before it is
+// finished.
+func (f *file) writeObjects(objs ...genWriter) error {
+	if f.body.streamed == nil {
+		f.body.streamed = make(map[genWriter]bool)
+	}
+	for _, o := range objs {
+		f.body.writeObject(f.out, f.ss, o)
+		f.body.streamed[o] = true
+	}
+	return f.out.err
+}
+
+func (f *file) finish() error {
+	f.writeRest(f.out, f.ss)
+	return f.out.err
+}
+
 type fontDescriptor struct {
 	dictionaryObject
 }
@@ -197,6 +337,12 @@ func (fd *fontDescriptor) setFontFile2(ref *indirectObjectRef) {
 	fd.dict["FontFile2"] = ref
 }
 
+// setFontFile3 attaches a /FontFile3 stream reference to the descriptor.
+// Used for embedding OpenType font data with CFF outlines per PDF spec §9.9.
+func (fd *fontDescriptor) setFontFile3(ref *indirectObjectRef) {
+	fd.dict["FontFile3"] = ref
+}
+
 type fontEncoding struct {
 	dictionaryObject
 }
@@ -222,6 +368,10 @@ func (e *freeXRefEntry) write(w io.Writer) {
 	fmt.Fprintf(w, "%.10d %.5d f\n", e.seq, e.gen)
 }
 
+func (e *freeXRefEntry) fields() (int, int, int) {
+	return 0, e.seq, e.gen
+}
+
 type genWriter interface {
 	writer
 	Gen() int
@@ -231,6 +381,7 @@ type genWriterArray []genWriter
 
 type header struct {
 	Version float32
+	binary  bool // follow the version with a comment of bytes above 127
 }
 
 func (h *header) write(w io.Writer) {
@@ -239,6 +390,10 @@ func (h *header) write(w io.Writer) {
 		v = 1.3
 	}
 	fmt.Fprintf(w, "%%PDF-%1.1f\n", v)
+	if h.binary {
+		// Marks the file as binary for transfer tools; PDF/A requires it.
+		fmt.Fprint(w, "%\xE2\xE3\xCF\xD3\n")
+	}
 }
 
 type indirectObject struct {
@@ -299,15 +454,66 @@ func (e *inUseXRefEntry) write(w io.Writer) {
 	fmt.Fprintf(w, "%.10d %.5d n\n", e.byteOffset, e.gen)
 }
 
+func (e *inUseXRefEntry) fields() (int, int, int) {
+	return 1, e.byteOffset, e.gen
+}
+
+type hexString []byte
+
+func (s hexString) write(w io.Writer) {
+	if ew, ok := w.(*encryptingWriter); ok {
+		fmt.Fprintf(ew.Writer, "<%X> ", ew.encrypt(s))
+		return
+	}
+	fmt.Fprintf(w, "<%X> ", []byte(s))
+}
+
 type lenWriter interface {
 	io.Writer
 	Len() int
 }
 
+// countingWriter is a lenWriter that passes writes through to w. After the
+// first error, further writes are discarded and the error is kept in err.
+type countingWriter struct {
+	w   io.Writer
+	n   int
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += n
+	cw.err = err
+	return n, err
+}
+
+func (cw *countingWriter) Len() int {
+	return cw.n
+}
+
 type name string
 
 func (n name) write(w io.Writer) {
-	fmt.Fprintf(w, "/%s ", n)
+	fmt.Fprintf(w, "/%s ", n.escape())
+}
+
+// escape encodes bytes that may not appear literally in a name object as
+// #xx hex sequences (PDF spec §7.3.5).
+func (n name) escape() string {
+	var buf bytes.Buffer
+	for i := 0; i < len(n); i++ {
+		c := n[i]
+		if c < 0x21 || c > 0x7E || strings.IndexByte("()<>[]{}/%#", c) >= 0 {
+			fmt.Fprintf(&buf, "#%02X", c)
+		} else {
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String()
 }
 
 func nameArray(names ...string) array {
@@ -318,6 +524,12 @@ func nameArray(names ...string) array {
 	return na
 }
 
+type null struct{}
+
+func (n null) write(w io.Writer) {
+	fmt.Fprintf(w, "null ")
+}
+
 type number struct {
 	value any
 }
@@ -326,8 +538,65 @@ func (n number) write(w io.Writer) {
 	fmt.Fprintf(w, "%v ", n.value)
 }
 
+// outlineFlags are the /F style bits for an outline item (PDF spec §12.3.3).
+const (
+	outlineItalic = 1 << 0
+	outlineBold   = 1 << 1
+)
+
+type outlineItem struct {
+	dictionaryObject
+	parent   seqGen
+	children []*outlineItem
+	open     bool
+}
+
+func (oi *outlineItem) init(seq, gen int, parent seqGen, title string, dest writer) *outlineItem {
+	oi.dictionaryObject.init(seq, gen)
+	oi.parent = parent
+	oi.dict["Title"] = textString(title)
+	oi.dict["Parent"] = &indirectObjectRef{parent}
+	if dest != nil {
+		oi.dict["Dest"] = dest
+	}
+	return oi
+}
+
+func newOutlineItem(seq, gen int, parent seqGen, title string, dest writer) *outlineItem {
+	return new(outlineItem).init(seq, gen, parent, title, dest)
+}
+
+func (oi *outlineItem) add(child *outlineItem) {
+	oi.children = append(oi.children, child)
+}
+
+func (oi *outlineItem) setColor(r, g, b float64) {
+	oi.dict["C"] = array{real(r), real(g), real(b)}
+}
+
+func (oi *outlineItem) setFlags(flags int) {
+	if flags == 0 {
+		delete(oi.dict, "F")
+		return
+	}
+	oi.dict["F"] = integer(flags)
+}
+
+func (oi *outlineItem) write(w io.Writer) {
+	setOutlineChildLinks(oi.dict, oi.children)
+	if len(oi.children) > 0 {
+		count := visibleOutlineItems(oi.children)
+		if !oi.open {
+			count = -count
+		}
+		oi.dict["Count"] = integer(count)
+	}
+	oi.dictionaryObject.write(w)
+}
+
 type outlines struct {
 	dictionaryObject
+	children []*outlineItem
 }
 
 func (o *outlines) init(seq, gen int) *outlines {
@@ -340,14 +609,53 @@ func newOutlines(seq, gen int) *outlines {
 	return new(outlines).init(seq, gen)
 }
 
+func (o *outlines) add(child *outlineItem) {
+	o.children = append(o.children, child)
+}
+
 func (o *outlines) write(w io.Writer) {
-	o.dict["Count"] = integer(0)
+	setOutlineChildLinks(o.dict, o.children)
+	o.dict["Count"] = integer(visibleOutlineItems(o.children))
 	o.dictionaryObject.write(w)
 }
 
+// setOutlineChildLinks fills in /First and /Last on the parent dictionary and
+// the /Prev and /Next sibling links on each child.
+func setOutlineChildLinks(dict dictionary, children []*outlineItem) {
+	if len(children) == 0 {
+		return
+	}
+	dict["First"] = &indirectObjectRef{children[0]}
+	dict["Last"] = &indirectObjectRef{children[len(children)-1]}
+	for i, child := range children {
+		if i > 0 {
+			child.dict["Prev"] = &indirectObjectRef{children[i-1]}
+		}
+		if i < len(children)-1 {
+			child.dict["Next"] = &indirectObjectRef{children[i+1]}
+		}
+	}
+}
+
+// visibleOutlineItems counts the items that are displayed when the given
+// level is shown: every child plus the visible descendants of open children.
+func visibleOutlineItems(children []*outlineItem) (count int) {
+	for _, child := range children {
+		count++
+		if child.open {
+			count += visibleOutlineItems(child.children)
+		}
+	}
+	return
+}
+
 type page struct {
 	pageBase
 	contents []*stream
+	annots   []*annotation
+	// baseAnnots holds the annotations of a page read from an existing
+	// file, which precede those added.
+	baseAnnots array
 }
 
 func (p *page) init(seq, gen int, parent seqGen) *page {
@@ -379,7 +687,10 @@ func (p *page) contentRefs() (result array) {
 	return
 }
 
-// TODO: setAnnots
+func (p *page) addAnnot(a *annotation) {
+	p.annots = append(p.annots, a)
+}
+
 // TODO: setBeads
 
 func (p *page) setThumb(thumb seqGen) {
@@ -398,7 +709,16 @@ func (p *page) writeBody(w io.Writer) {
 	} else if len(p.contents) == 1 {
 		p.dict["Contents"] = &indirectObjectRef{p.contents[0]}
 	}
-	p.dict["Length"] = integer(p.contentLength())
+	if len(p.annots) > 0 {
+		annots := append(array{}, p.baseAnnots...)
+		for _, a := range p.annots {
+			annots = append(annots, &indirectObjectRef{a})
+		}
+		p.dict["Annots"] = annots
+	}
+	if len(p.contents) > 0 {
+		p.dict["Length"] = integer(p.contentLength())
+	}
 	p.dict.write(w)
 }
 
@@ -418,6 +738,14 @@ func newPageBase(seq, gen int, parent *indirectObject) *pageBase {
 	return new(pageBase).init(seq, gen, parent)
 }
 
+func (pb *pageBase) setArtBox(r rectangle) {
+	pb.dict["ArtBox"] = &r
+}
+
+func (pb *pageBase) setBleedBox(r rectangle) {
+	pb.dict["BleedBox"] = &r
+}
+
 func (pb *pageBase) setCropBox(r rectangle) {
 	pb.dict["CropBox"] = &r
 }
@@ -442,9 +770,13 @@ func (pb *pageBase) setRotate(rotate int) {
 	pb.dict["Rotate"] = integer(rotate)
 }
 
+func (pb *pageBase) setTrimBox(r rectangle) {
+	pb.dict["TrimBox"] = &r
+}
+
 type pages struct {
 	pageBase
-	kids []*page
+	kids []seqGen
 }
 
 func (ps *pages) init(seq, gen int) *pages {
@@ -461,6 +793,14 @@ func (ps *pages) add(p *page) {
 	ps.kids = append(ps.kids, p)
 }
 
+// release replaces the kids from index first on with plain references, so
+// that pages already written can be freed.
+func (ps *pages) release(first int) {
+	for i := first; i < len(ps.kids); i++ {
+		ps.kids[i] = objectRef{ps.kids[i].Seq(), ps.kids[i].Gen()}
+	}
+}
+
 func (ps *pages) write(w io.Writer) {
 	ps.dict["Count"] = integer(len(ps.kids))
 	kidsRefs := make(array, len(ps.kids))
@@ -481,6 +821,12 @@ type rectangle struct {
 	x1, y1, x2, y2 float64
 }
 
+// inset returns the rectangle moved in by margin on every side, or out if
+// margin is negative.
+func (r rectangle) inset(margin float64) rectangle {
+	return rectangle{r.x1 + margin, r.y1 + margin, r.x2 - margin, r.y2 - margin}
+}
+
 func (r *rectangle) write(w io.Writer) {
 	fmt.Fprintf(w, "[")
 	number{r.x1}.write(w)
@@ -492,8 +838,12 @@ func (r *rectangle) write(w io.Writer) {
 
 type resources struct {
 	dictionaryObject
-	fonts    dictionary
-	xObjects dictionary
+	fonts       dictionary
+	xObjects    dictionary
+	colorSpaces dictionary
+	extGStates  dictionary
+	patterns    dictionary
+	shadings    dictionary
 }
 
 func (r *resources) init(seq, gen int) *resources {
@@ -507,6 +857,22 @@ func newResources(seq, gen int) *resources {
 	return new(resources).init(seq, gen)
 }
 
+func (r *resources) setColorSpace(name string, ref *indirectObjectRef) {
+	if r.colorSpaces == nil {
+		r.colorSpaces = dictionary{}
+		r.dict["ColorSpace"] = r.colorSpaces
+	}
+	r.colorSpaces[name] = ref
+}
+
+func (r *resources) setExtGState(name string, ref *indirectObjectRef) {
+	if r.extGStates == nil {
+		r.extGStates = dictionary{}
+		r.dict["ExtGState"] = r.extGStates
+	}
+	r.extGStates[name] = ref
+}
+
 func (r *resources) setFont(name string, ref *indirectObjectRef) {
 	if r.fonts == nil {
 		r.fonts = dictionary{}
@@ -515,10 +881,26 @@ func (r *resources) setFont(name string, ref *indirectObjectRef) {
 	r.fonts[name] = ref
 }
 
+func (r *resources) setPattern(name string, ref *indirectObjectRef) {
+	if r.patterns == nil {
+		r.patterns = dictionary{}
+		r.dict["Pattern"] = r.patterns
+	}
+	r.patterns[name] = ref
+}
+
 func (r *resources) setProcSet(w writer) {
 	r.dict["ProcSet"] = w
 }
 
+func (r *resources) setShading(name string, ref *indirectObjectRef) {
+	if r.shadings == nil {
+		r.shadings = dictionary{}
+		r.dict["Shading"] = r.shadings
+	}
+	r.shadings[name] = ref
+}
+
 func (r *resources) setXObject(name string, ref *indirectObjectRef) {
 	if r.xObjects == nil {
 		r.xObjects = dictionary{}
@@ -607,9 +989,37 @@ func (s str) escape() []byte {
 }
 
 func (s str) write(w io.Writer) {
+	if ew, ok := w.(*encryptingWriter); ok {
+		// Ciphertext is binary, so write it in hex to keep line ends intact.
+		fmt.Fprintf(ew.Writer, "<%X> ", ew.encrypt(s))
+		return
+	}
 	fmt.Fprintf(w, "(%s) ", s.escape())
 }
 
+// textString returns s as a PDF text string (PDF spec §7.9.2.2). ASCII text is
+// written as a literal string; anything else is written as UTF-16BE with a
+// leading byte order mark so viewers display non-Latin text correctly.
+func textString(s string) writer {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return str(s)
+	}
+	units := utf16.Encode([]rune(s))
+	data := make([]byte, 2, 2+len(units)*2)
+	data[0], data[1] = 0xFE, 0xFF
+	for _, u := range units {
+		data = append(data, byte(u>>8), byte(u))
+	}
+	return hexString(data)
+}
+
 type stream struct {
 	dictionaryObject
 	data []byte
@@ -627,6 +1037,17 @@ func newStream(seq, gen int, data []byte) *stream {
 	return new(stream).init(seq, gen, data)
 }
 
+// newFormXObject returns a form XObject (PDF spec §8.10) whose content, data,
+// is drawn within bbox using the given resource dictionary.
+func newFormXObject(seq, gen int, bbox rectangle, data []byte, resources writer) *stream {
+	s := newStream(seq, gen, data)
+	s.dict["Type"] = name("XObject")
+	s.dict["Subtype"] = name("Form")
+	s.dict["BBox"] = &bbox
+	s.dict["Resources"] = resources
+	return s
+}
+
 func (s *stream) len() int {
 	return len(s.data)
 }
@@ -664,11 +1085,15 @@ func (s *stream) write(w io.Writer) {
 }
 
 func (s *stream) writeBody(w io.Writer) {
-	s.dict["Length"] = integer(len(s.data))
+	data := s.data
+	if ew, ok := w.(*encryptingWriter); ok {
+		data = ew.encrypt(data)
+	}
+	s.dict["Length"] = integer(len(data))
 	s.dict.write(w)
 	fmt.Fprintf(w, "stream\n")
-	if s.data != nil {
-		w.Write(s.data)
+	if data != nil {
+		w.Write(data)
 	}
 	fmt.Fprintf(w, "endstream\n")
 }
@@ -682,6 +1107,20 @@ func newTrailer() *trailer {
 	return &trailer{dictionary{}, 0}
 }
 
+func (tr *trailer) setEncrypt(encrypt seqGen) {
+	tr.dict["Encrypt"] = &indirectObjectRef{encrypt}
+}
+
+// setID sets the file identifier. Both halves are the same since the file is
+// always written in full.
+func (tr *trailer) setID(id []byte) {
+	tr.dict["ID"] = array{hexString(id), hexString(id)}
+}
+
+func (tr *trailer) setInfo(info seqGen) {
+	tr.dict["Info"] = &indirectObjectRef{info}
+}
+
 func (tr *trailer) setRoot(root seqGen) {
 	tr.dict["Root"] = &indirectObjectRef{root}
 }
@@ -700,6 +1139,10 @@ func (tr *trailer) xrefTableSize() int {
 func (tr *trailer) write(w io.Writer) {
 	fmt.Fprintf(w, "trailer\n")
 	tr.dict.write(w)
+	tr.writeStartXRef(w)
+}
+
+func (tr *trailer) writeStartXRef(w io.Writer) {
 	fmt.Fprintf(w, "startxref\n")
 	fmt.Fprintf(w, "%d\n", tr.xrefTableStart)
 	fmt.Fprintf(w, "%%%%EOF\n")
@@ -710,11 +1153,13 @@ type writer interface {
 }
 
 type xRefSubSection struct {
-	list array
+	// start is the object number of the first entry in list.
+	start int
+	list  array
 }
 
 func newXRefSubSection() *xRefSubSection {
-	return &xRefSubSection{array{&freeXRefEntry{0, 65535, nil}}}
+	return &xRefSubSection{0, array{&freeXRefEntry{0, 65535, nil}}}
 }
 
 func (ss *xRefSubSection) add(w writer) {
@@ -733,12 +1178,87 @@ func (ss *xRefSubSection) len() int {
 }
 
 func (ss *xRefSubSection) write(w io.Writer) {
-	fmt.Fprintf(w, "%d %d\n", 0, len(ss.list))
+	fmt.Fprintf(w, "%d %d\n", ss.start, len(ss.list))
 	for _, e := range ss.list {
 		e.write(w)
 	}
 }
 
+// xRefStreamEntry is implemented by cross-reference entries so they can be
+// encoded in a cross-reference stream as a type and two fields.
+type xRefStreamEntry interface {
+	fields() (int, int, int)
+}
+
+// compressedXRefEntry locates an object stored at index within the object
+// stream numbered streamSeq. It can only appear in a cross-reference stream.
+type compressedXRefEntry struct {
+	streamSeq, index int
+}
+
+func (e *compressedXRefEntry) write(w io.Writer) {
+	panic("compressed objects require a cross-reference stream")
+}
+
+func (e *compressedXRefEntry) fields() (int, int, int) {
+	return 2, e.streamSeq, e.index
+}
+
+// newXRefStream returns a cross-reference stream (PDF spec §7.5.8) holding the
+// entries of ss along with the entries of the trailer dictionary.
+func newXRefStream(seq, gen int, ss *xRefSubSection, trailer dictionary) *stream {
+	return newXRefStreamSections(seq, gen, []*xRefSubSection{ss}, trailer)
+}
+
+// newXRefStreamSections returns a cross-reference stream holding the entries
+// of several subsections, listed in its /Index, as an incremental update's
+// does. /Size is taken from the trailer when it is larger than the last
+// subsection implies.
+func newXRefStreamSections(seq, gen int, sections []*xRefSubSection, trailer dictionary) *stream {
+	width := 1
+	for _, ss := range sections {
+		for _, e := range ss.list {
+			_, f2, _ := e.(xRefStreamEntry).fields()
+			for f2>>(8*width) > 0 {
+				width++
+			}
+		}
+	}
+	var data bytes.Buffer
+	var index array
+	size := 0
+	for _, ss := range sections {
+		for _, e := range ss.list {
+			typ, f2, f3 := e.(xRefStreamEntry).fields()
+			data.WriteByte(byte(typ))
+			for i := width - 1; i >= 0; i-- {
+				data.WriteByte(byte(f2 >> (8 * i)))
+			}
+			data.WriteByte(byte(f3 >> 8))
+			data.WriteByte(byte(f3))
+		}
+		index = append(index, integer(ss.start), integer(ss.len()))
+		size = max(size, ss.start+ss.len())
+	}
+	xs := newStream(seq, gen, data.Bytes())
+	for k, v := range trailer {
+		xs.dict[k] = v
+	}
+	xs.dict["Type"] = name("XRef")
+	if n, ok := trailer["Size"].(integer); ok && int(n) > size {
+		size = int(n)
+	}
+	xs.dict["Size"] = integer(size)
+	if len(sections) != 1 || sections[0].start != 0 {
+		xs.dict["Index"] = index
+	}
+	xs.dict["W"] = arrayFromInts([]int{1, width, 2})
+	if err := xs.compress(); err != nil {
+		panic(err)
+	}
+	return xs
+}
+
 type xRefTable struct {
 	// TODO: Can list be just a writer?
 	list []*xRefSubSection
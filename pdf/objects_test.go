@@ -16,6 +16,14 @@ func stringFromWriter(w writer) string {
 	return buf.String()
 }
 
+func TestAnnotation(t *testing.T) {
+	a := newLinkAnnotation(1, 0, rectangle{1, 2, 3, 4})
+	a.setDest(name("intro"))
+	var buf bytes.Buffer
+	a.write(&buf)
+	expectS(t, "1 0 obj\n<<\n/Border [0 0 0 ] \n/Dest /intro \n/Rect [1 2 3 4 ] \n/Subtype /Link \n/Type /Annot \n>>\nendobj\n", buf.String())
+}
+
 func TestArray(t *testing.T) {
 	var buf bytes.Buffer
 	ary := array{name("name"), integer(7)}
@@ -187,6 +195,10 @@ func TestName(t *testing.T) {
 	name("name").write(&buf)
 
 	expectS(t, "/name ", buf.String())
+
+	buf.Reset()
+	name("Chapter 1 (a)#").write(&buf)
+	expectS(t, "/Chapter#201#20#28a#29#23 ", buf.String())
 }
 
 func nameShouldEqual(t *testing.T, expected string, actual writer) {
@@ -230,6 +242,31 @@ func TestOutlines(t *testing.T) {
 	expectS(t, "1 0 obj\n<<\n/Count 0 \n/Type /Outlines \n>>\nendobj\n", buf.String())
 }
 
+func TestOutlineItem(t *testing.T) {
+	o := newOutlines(1, 0)
+	first := newOutlineItem(2, 0, o, "First", nil)
+	second := newOutlineItem(3, 0, o, "Second", nil)
+	child := newOutlineItem(4, 0, second, "Child", nil)
+	o.add(first)
+	o.add(second)
+	second.add(child)
+	second.setColor(1, 0, 0)
+	second.setFlags(outlineBold)
+
+	var buf bytes.Buffer
+	o.write(&buf)
+	expectS(t, "1 0 obj\n<<\n/Count 2 \n/First 2 0 R \n/Last 3 0 R \n/Type /Outlines \n>>\nendobj\n", buf.String())
+
+	buf.Reset()
+	second.write(&buf)
+	expectS(t, "3 0 obj\n<<\n/C [1 0 0 ] \n/Count -1 \n/F 2 \n/First 4 0 R \n/Last 4 0 R \n/Parent 1 0 R \n/Prev 2 0 R \n/Title (Second) \n>>\nendobj\n", buf.String())
+
+	second.open = true
+	buf.Reset()
+	o.write(&buf)
+	expectS(t, "1 0 obj\n<<\n/Count 3 \n/First 2 0 R \n/Last 3 0 R \n/Type /Outlines \n>>\nendobj\n", buf.String())
+}
+
 func TestPage(t *testing.T) {
 	ps := newPages(1, 0)
 	p := newPage(2, 0, ps)
@@ -257,8 +294,14 @@ func TestPage(t *testing.T) {
 	// setThumb
 	p.setThumb(s)
 	expectS(t, stringFromWriter(&indirectObjectRef{s}), stringFromWriter(p.dict["Thumb"]))
-	// setAnnots
-	// TODO
+	// addAnnot
+	a := newLinkAnnotation(4, 0, rectangle{1, 2, 3, 4})
+	p.addAnnot(a)
+	buf.Reset()
+	p.writeBody(&buf)
+	buf.Reset()
+	p.dict["Annots"].write(&buf)
+	expectS(t, "[4 0 R ] ", buf.String())
 	// setBeads
 	// TODO
 }
@@ -439,6 +482,12 @@ func TestStr(t *testing.T) {
 	expectS(t, "(a\\\\b\\(cd\\)) ", buf.String())
 }
 
+func TestTextString(t *testing.T) {
+	expectS(t, "(Plain \\(ASCII\\)) ", stringFromWriter(textString("Plain (ASCII)")))
+	expectS(t, "<FEFF00C9007400E9> ", stringFromWriter(textString("Ét\u00e9")))
+	expectS(t, "<FEFF65E5672C> ", stringFromWriter(textString("日本")))
+}
+
 func TestStream(t *testing.T) {
 	s := newStream(1, 0, []byte("test"))
 	s.setFilter("bogus")
@@ -546,3 +595,19 @@ func TestBodyWrite_XRefFillsSequenceGaps(t *testing.T) {
 		xref.String(),
 	)
 }
+
+func TestResources_ExtGState(t *testing.T) {
+	var buf bytes.Buffer
+	r := newResources(1, 0)
+	obj := &indirectObject{2, 0, nil}
+	ref := &indirectObjectRef{obj}
+	r.setExtGState("GS0", ref)
+	r.write(&buf)
+
+	expectS(t, "1 0 obj\n<<\n/ExtGState <<\n/GS0 2 0 R \n>>\n\n/Font <<\n>>\n\n>>\nendobj\n", buf.String())
+}
+
+func TestExtGState(t *testing.T) {
+	gs := newExtGState(1, 0, 0.5, 1, "Multiply")
+	expectS(t, "1 0 obj\n<<\n/BM /Multiply \n/CA 1 \n/Type /ExtGState \n/ca 0.5 \n>>\nendobj\n", stringFromWriter(gs))
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"github.com/rowland/leadtype/options"
+)
+
+// Outline is an entry in the document outline, shown by PDF viewers as the
+// bookmarks panel. Entries are created with DocWriter.AddOutline and nested
+// with Outline.AddOutline.
+type Outline struct {
+	dw    *DocWriter
+	item  *outlineItem
+	title string
+}
+
+// AddOutline appends a child entry beneath o. See DocWriter.AddOutline for
+// the meaning of the arguments.
+func (o *Outline) AddOutline(title string, pw *PageWriter, y float64, options options.Options) *Outline {
+	child := o.dw.newOutline(o.item, title, pw, y, options)
+	o.item.add(child.item)
+	return child
+}
+
+// Title returns the text shown for the entry.
+func (o *Outline) Title() string {
+	return o.title
+}
+
+func (dw *DocWriter) newOutline(parent seqGen, title string, pw *PageWriter, y float64, options options.Options) *Outline {
+	var dest writer
+	if destName := options.StringDefault("dest", ""); destName != "" {
+		dest = name(destName)
+	} else {
+		if pw == nil {
+			pw = dw.CurPage()
+		}
+		dest = pw.destination(y)
+	}
+	item := newOutlineItem(dw.nextSeq(), 0, parent, title, dest)
+	item.open = options.BoolDefault("open", false)
+	if _, ok := options["color"]; ok {
+		item.setColor(options.ColorDefault("color", 0).RGB64())
+	}
+	flags := 0
+	if options.BoolDefault("bold", false) {
+		flags |= outlineBold
+	}
+	if options.BoolDefault("italic", false) {
+		flags |= outlineItalic
+	}
+	item.setFlags(flags)
+	dw.file.body.add(item)
+	return &Outline{dw: dw, item: item, title: title}
+}
+
+// destination returns an explicit /XYZ destination for this page that scrolls
+// to the vertical position y, expressed in the page's units from the top edge.
+func (pw *PageWriter) destination(y float64) array {
+	top := pw.translate(pw.units.toPts(y))
+	return array{&indirectObjectRef{pw.page}, name("XYZ"), null{}, real(top), null{}}
+}
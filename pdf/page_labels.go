@@ -0,0 +1,170 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PageLabelStyle is the numbering style of a range of page labels.
+type PageLabelStyle string
+
+const (
+	PageLabelNone       PageLabelStyle = ""  // prefix only
+	PageLabelDecimal    PageLabelStyle = "D" // 1, 2, 3
+	PageLabelUpperRoman PageLabelStyle = "R" // I, II, III
+	PageLabelLowerRoman PageLabelStyle = "r" // i, ii, iii
+	PageLabelUpperAlpha PageLabelStyle = "A" // A to Z, then AA to ZZ
+	PageLabelLowerAlpha PageLabelStyle = "a" // a to z, then aa to zz
+)
+
+// pageLabel begins a range of page labels.
+type pageLabel struct {
+	style  PageLabelStyle
+	prefix string
+	start  int
+}
+
+// SetPageLabels labels the pages from the one at pageIndex, counting from 0,
+// up to the next labeled range (PDF spec §12.4.2). Viewers show the label,
+// such as "iv" or "A-3", in place of the page's position in the file. Each
+// label is prefix followed by the page's number in style, counting from
+// start. Pages before the first range are numbered 1, 2, 3. The range
+// belongs to the page, so it moves with it when pages are inserted before
+// it. Pages that do not exist yet, or have already been streamed, cannot be
+// labeled.
+func (dw *DocWriter) SetPageLabels(pageIndex int, style PageLabelStyle, prefix string, start int) *DocWriter {
+	if i := pageIndex - dw.streamedPages; i >= 0 && i < len(dw.pages) {
+		dw.pages[i].setPageLabels(style, prefix, start)
+	}
+	return dw
+}
+
+// BeginPageLabels labels the pages from the current one, as SetPageLabels.
+func (dw *DocWriter) BeginPageLabels(style PageLabelStyle, prefix string, start int) *DocWriter {
+	dw.CurPage().setPageLabels(style, prefix, start)
+	return dw
+}
+
+func (pw *PageWriter) setPageLabels(style PageLabelStyle, prefix string, start int) {
+	if start < 1 {
+		start = 1
+	}
+	pw.label = &pageLabel{style, prefix, start}
+}
+
+// streamPageLabels keeps the label ranges of pages about to be streamed,
+// which are dropped once written.
+func (dw *DocWriter) streamPageLabels() {
+	for i, pw := range dw.pages {
+		if pw.label == nil {
+			continue
+		}
+		if dw.pageLabels == nil {
+			dw.pageLabels = make(map[int]pageLabel)
+		}
+		dw.pageLabels[dw.streamedPages+i] = *pw.label
+	}
+}
+
+// writePageLabels adds the catalog's /PageLabels number tree. The pages of
+// an incremental update follow the existing ones.
+func (dw *DocWriter) writePageLabels() {
+	offset := 0
+	if dw.update != nil {
+		offset = len(dw.update.pages)
+	}
+	labels := make(map[int]pageLabel, len(dw.pageLabels))
+	for i, label := range dw.pageLabels {
+		labels[offset+i] = label
+	}
+	for i, pw := range dw.pages {
+		if pw.label != nil {
+			labels[offset+dw.streamedPages+i] = *pw.label
+		}
+	}
+	if len(labels) == 0 {
+		return
+	}
+	indexes := make([]int, 0, len(labels)+1)
+	for i := range labels {
+		indexes = append(indexes, i)
+	}
+	if _, ok := labels[0]; !ok {
+		// The tree must label the first page.
+		indexes = append(indexes, 0)
+	}
+	sort.Ints(indexes)
+	nums := make(array, 0, 2*len(indexes))
+	for _, i := range indexes {
+		label, ok := labels[i]
+		if !ok {
+			label = pageLabel{PageLabelDecimal, "", 1}
+		}
+		dict := dictionary{}
+		if label.style != PageLabelNone {
+			dict["S"] = name(label.style)
+		}
+		if label.prefix != "" {
+			dict["P"] = textString(label.prefix)
+		}
+		if label.start != 1 {
+			dict["St"] = integer(label.start)
+		}
+		nums = append(nums, integer(i), dict)
+	}
+	dw.catalog.dict["PageLabels"] = dictionary{"Nums": nums}
+	dw.requireVersion(1.3)
+}
+
+// FormatPageLabel returns n as it is numbered in style, as viewers show it
+// in page labels.
+func FormatPageLabel(style PageLabelStyle, n int) string {
+	switch style {
+	case PageLabelNone:
+		return ""
+	case PageLabelUpperRoman:
+		return strings.ToUpper(romanNumeral(n))
+	case PageLabelLowerRoman:
+		return romanNumeral(n)
+	case PageLabelUpperAlpha:
+		return strings.ToUpper(alphaNumeral(n))
+	case PageLabelLowerAlpha:
+		return alphaNumeral(n)
+	}
+	return strconv.Itoa(n)
+}
+
+var romanNumerals = []struct {
+	value   int
+	numeral string
+}{
+	{1000, "m"}, {900, "cm"}, {500, "d"}, {400, "cd"},
+	{100, "c"}, {90, "xc"}, {50, "l"}, {40, "xl"},
+	{10, "x"}, {9, "ix"}, {5, "v"}, {4, "iv"}, {1, "i"},
+}
+
+func romanNumeral(n int) string {
+	if n < 1 {
+		return strconv.Itoa(n)
+	}
+	var sb strings.Builder
+	for _, r := range romanNumerals {
+		for ; n >= r.value; n -= r.value {
+			sb.WriteString(r.numeral)
+		}
+	}
+	return sb.String()
+}
+
+// alphaNumeral numbers pages a to z, then aa to zz, aaa to zzz and so on.
+func alphaNumeral(n int) string {
+	if n < 1 {
+		return strconv.Itoa(n)
+	}
+	letter := byte('a' + (n-1)%26)
+	return strings.Repeat(string(letter), (n-1)/26+1)
+}
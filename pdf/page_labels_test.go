@@ -0,0 +1,71 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFormatPageLabel(t *testing.T) {
+	expectS(t, "14", FormatPageLabel(PageLabelDecimal, 14))
+	expectS(t, "xiv", FormatPageLabel(PageLabelLowerRoman, 14))
+	expectS(t, "MCMXCIV", FormatPageLabel(PageLabelUpperRoman, 1994))
+	expectS(t, "c", FormatPageLabel(PageLabelLowerAlpha, 3))
+	expectS(t, "BB", FormatPageLabel(PageLabelUpperAlpha, 28))
+	expectS(t, "", FormatPageLabel(PageLabelNone, 3))
+}
+
+func TestDocWriter_SetPageLabels(t *testing.T) {
+	dw := NewDocWriter()
+	for i := 0; i < 6; i++ {
+		dw.NewPage()
+	}
+	dw.SetPageLabels(0, PageLabelLowerRoman, "", 1)
+	dw.SetPageLabels(3, PageLabelDecimal, "", 1)
+	dw.SetPageLabels(5, PageLabelDecimal, "A-", 8)
+	var buf bytes.Buffer
+	if _, err := dw.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	check(t, bytes.Contains(buf.Bytes(), []byte("/PageLabels <<\n/Nums [0 <<\n/S /r \n>>\n3 <<\n/S /D \n>>\n5 <<\n/P (A-) \n/S /D \n/St 8 \n>>\n] \n>>\n")),
+		"Catalog should have a page label range for each call")
+}
+
+func TestDocWriter_SetPageLabels_NewPageAfter(t *testing.T) {
+	dw := NewDocWriter()
+	first := dw.NewPage()
+	dw.NewPage()
+	dw.BeginPageLabels(PageLabelDecimal, "A-", 1)
+	dw.NewPageAfter(first)
+	dw.writePageLabels()
+	var buf bytes.Buffer
+	dw.catalog.dict["PageLabels"].write(&buf)
+	expectS(t, "<<\n/Nums [0 <<\n/S /D \n>>\n2 <<\n/P (A-) \n/S /D \n>>\n] \n>>\n", buf.String())
+}
+
+func TestDocWriter_SetPageLabels_Streaming(t *testing.T) {
+	var buf bytes.Buffer
+	dw := NewDocWriter().StreamTo(&buf)
+	dw.NewPage()
+	dw.NewPage()
+	dw.BeginPageLabels(PageLabelLowerRoman, "", 1)
+	dw.NewPage()
+	if err := dw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	check(t, bytes.Contains(buf.Bytes(), []byte("/PageLabels <<\n/Nums [0 <<\n/S /D \n>>\n1 <<\n/S /r \n>>\n] \n>>\n")),
+		"Streamed pages should keep their labels")
+}
+
+func TestDocWriter_SetPageLabels_FirstPage(t *testing.T) {
+	dw := NewDocWriter()
+	dw.NewPage()
+	dw.NewPage()
+	dw.BeginPageLabels(PageLabelNone, "Cover", 1)
+	dw.writePageLabels()
+	var buf bytes.Buffer
+	dw.catalog.dict["PageLabels"].write(&buf)
+	expectS(t, "<<\n/Nums [0 <<\n/S /D \n>>\n1 <<\n/P (Cover) \n>>\n] \n>>\n", buf.String())
+}
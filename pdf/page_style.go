@@ -3,37 +3,203 @@
 
 package pdf
 
-import "github.com/rowland/leadtype/options"
+import (
+	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/rowland/leadtype/options"
+)
+
+// PageSizes lists the named page sizes, portrait, in points: the ISO A, B and
+// C series, the North American and ANSI sizes, common envelopes and photo
+// print sizes.
 var PageSizes = SizeMap{
-	"letter": Size{612, 792},
-	"legal":  Size{612, 1008},
-	"A4":     Size{595, 842},
-	"B5":     Size{499, 708},
-	"C5":     Size{459, 649},
+	"A0":  Size{2384, 3370},
+	"A1":  Size{1684, 2384},
+	"A2":  Size{1191, 1684},
+	"A3":  Size{842, 1191},
+	"A4":  Size{595, 842},
+	"A5":  Size{420, 595},
+	"A6":  Size{298, 420},
+	"A7":  Size{210, 298},
+	"A8":  Size{147, 210},
+	"A9":  Size{105, 147},
+	"A10": Size{74, 105},
+	"B0":  Size{2835, 4008},
+	"B1":  Size{2004, 2835},
+	"B2":  Size{1417, 2004},
+	"B3":  Size{1001, 1417},
+	"B4":  Size{709, 1001},
+	"B5":  Size{499, 709},
+	"B6":  Size{354, 499},
+	"B7":  Size{249, 354},
+	"B8":  Size{176, 249},
+	"B9":  Size{125, 176},
+	"B10": Size{88, 125},
+	"C0":  Size{2599, 3677},
+	"C1":  Size{1837, 2599},
+	"C2":  Size{1298, 1837},
+	"C3":  Size{918, 1298},
+	"C4":  Size{649, 918},
+	"C5":  Size{459, 649},
+	"C6":  Size{323, 459},
+	"C7":  Size{230, 323},
+	"C8":  Size{162, 230},
+	"C9":  Size{113, 162},
+	"C10": Size{79, 113},
+
+	"letter":       Size{612, 792},
+	"legal":        Size{612, 1008},
+	"tabloid":      Size{792, 1224},
+	"ledger":       Size{792, 1224},
+	"executive":    Size{522, 756},
+	"statement":    Size{396, 612},
+	"junior-legal": Size{360, 576},
+	"ANSI A":       Size{612, 792},
+	"ANSI B":       Size{792, 1224},
+	"ANSI C":       Size{1224, 1584},
+	"ANSI D":       Size{1584, 2448},
+	"ANSI E":       Size{2448, 3168},
+
+	"envelope-DL":      Size{312, 624},
+	"envelope-6.75":    Size{261, 468},
+	"envelope-monarch": Size{279, 540},
+	"envelope-9":       Size{279, 639},
+	"envelope-10":      Size{297, 684},
+
+	"photo-3.5x5": Size{252, 360},
+	"photo-4x6":   Size{288, 432},
+	"photo-5x7":   Size{360, 504},
+	"photo-8x10":  Size{576, 720},
+}
+
+// LookupPageSize returns the named page size, ignoring case.
+func LookupPageSize(name string) (Size, bool) {
+	if sz, ok := PageSizes[name]; ok {
+		return sz, true
+	}
+	for n, sz := range PageSizes {
+		if strings.EqualFold(n, name) {
+			return sz, true
+		}
+	}
+	return Size{}, false
 }
 
+// parsePageSize returns the page size in points for the name of a page size
+// or for dimensions such as "210x297mm" or "8.5x11in". Dimensions without
+// units are in defaultUnits.
+func parsePageSize(size string, defaultUnits *units) (Size, error) {
+	if sz, ok := LookupPageSize(size); ok {
+		return sz, nil
+	}
+	if w, h, ok := strings.Cut(strings.ToLower(size), "x"); ok {
+		suffix := strings.TrimLeft(h, "0123456789.")
+		if width, ok := parseMeasurement(w+suffix, defaultUnits); ok {
+			if height, ok := parseMeasurement(h, defaultUnits); ok && width > 0 && height > 0 {
+				return Size{width, height}, nil
+			}
+		}
+	}
+	return Size{}, fmt.Errorf("unknown page size %q", size)
+}
+
+// parseMeasurement returns a measurement in points. Numbers, and strings
+// without a suffix naming units such as "3mm", are in defaultUnits.
+func parseMeasurement(value any, defaultUnits *units) (float64, bool) {
+	switch value := value.(type) {
+	case float64:
+		return defaultUnits.toPts(value), true
+	case int:
+		return defaultUnits.toPts(float64(value)), true
+	case string:
+		value = strings.TrimSpace(value)
+		u := defaultUnits
+		for name, conversion := range UnitConversions {
+			if strings.HasSuffix(value, name) {
+				value, u = strings.TrimSpace(strings.TrimSuffix(value, name)), conversion
+				break
+			}
+		}
+		f, err := strconv.ParseFloat(value, 64)
+		return u.toPts(f), err == nil
+	}
+	return 0, false
+}
+
+// pageStyle holds the boxes of a page (PDF spec §14.11.2), in points. The
+// trim box is the finished page, with its lower left corner at the origin;
+// the bleed box extends it by the bleed, and the media box extends that by
+// the slug, where printer's marks go.
 type pageStyle struct {
 	orientation string
 	landscape   bool
-	pageSize    rectangle
+	pageSize    rectangle // media box
 	cropSize    rectangle
+	bleedBox    rectangle
+	trimBox     rectangle
+	artBox      *rectangle
 	rotate      int
+	err         error
 }
 
+// newPageStyle returns the style of a page with the options:
+//
+//	page_size    name of a size in PageSizes, or dimensions such as "210x297mm"
+//	page_width   width of the trimmed page, overriding page_size
+//	page_height  height of the trimmed page, overriding page_size
+//	orientation  "portrait" or "landscape", which swaps the page_size dimensions
+//	bleed        margin printed beyond the trimmed page, such as "3mm"
+//	slug         margin beyond the bleed, for printer's marks
+//	art_margin   inset of the art box from the trimmed page, if any
+//	crop_size    name of a size for the crop box, instead of the media box
+//	rotate       "portrait" or "landscape", the rotation for viewing
+//
+// Measurements without units are in the units option. An unknown page size
+// falls back to letter, and is reported by err.
 func newPageStyle(options options.Options) *pageStyle {
 	ps := new(pageStyle)
+	u := UnitConversions[options.StringDefault("units", "pt")]
+	if u == nil {
+		u = UnitConversions["pt"]
+	}
 	ps.orientation = options.StringDefault("orientation", "portrait")
+	ps.landscape = ps.orientation == "landscape"
 	pageSizeName := options.StringDefault("page_size", "letter")
-	ps.pageSize = makeSizeRectangle(pageSizeName, ps.orientation)
-	cropSizeName := options.StringDefault("crop_size", pageSizeName)
-	ps.cropSize = makeSizeRectangle(cropSizeName, ps.orientation)
+	sz, err := parsePageSize(pageSizeName, u)
+	if err != nil {
+		ps.err = err
+		sz = PageSizes["letter"]
+	}
+	if ps.landscape {
+		sz.Width, sz.Height = sz.Height, sz.Width
+	}
+	if width, ok := parseMeasurement(options["page_width"], u); ok && width > 0 {
+		sz.Width = width
+	}
+	if height, ok := parseMeasurement(options["page_height"], u); ok && height > 0 {
+		sz.Height = height
+	}
+	ps.trimBox = rectangle{0, 0, sz.Width, sz.Height}
+	bleed, _ := parseMeasurement(options["bleed"], u)
+	ps.bleedBox = ps.trimBox.inset(-bleed)
+	slug, _ := parseMeasurement(options["slug"], u)
+	ps.pageSize = ps.bleedBox.inset(-slug)
+	ps.cropSize = ps.pageSize
+	if cropSizeName, ok := options["crop_size"].(string); ok {
+		ps.cropSize = makeSizeRectangle(cropSizeName, ps.orientation)
+	}
+	if margin, ok := parseMeasurement(options["art_margin"], u); ok {
+		art := ps.trimBox.inset(margin)
+		ps.artBox = &art
+	}
 	ps.rotate = lookupRotation(options.StringDefault("rotate", "portrait"))
 	return ps
 }
 
 func makeSizeRectangle(size, orientation string) (r rectangle) {
-	sz := PageSizes[size]
+	sz, _ := LookupPageSize(size)
 	if orientation == "landscape" {
 		r.x2, r.y2 = sz.Height, sz.Width
 	} else {
@@ -4,6 +4,8 @@
 package pdf
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/rowland/leadtype/options"
@@ -72,3 +74,51 @@ func TestNewPageStyle(t *testing.T) {
 	ps6 := newPageStyle(opt6)
 	expectNI(t, "rotate", 270, ps6.rotate)
 }
+
+func TestNewPageStyle_Dimensions(t *testing.T) {
+	ps1 := newPageStyle(options.Options{"page_size": "210x297mm"})
+	check(t, ps1.err == nil, "Dimensions should be parsed")
+	expectF(t, 595.35, ps1.trimBox.x2)
+	expectF(t, 841.995, ps1.trimBox.y2)
+
+	ps2 := newPageStyle(options.Options{"page_size": "a5", "page_width": 5.5, "units": "in"})
+	expectF(t, 396, ps2.trimBox.x2)
+	expectF(t, 595, ps2.trimBox.y2)
+
+	ps3 := newPageStyle(options.Options{"page_width": "4in", "page_height": "6in"})
+	expectF(t, 288, ps3.trimBox.x2)
+	expectF(t, 432, ps3.trimBox.y2)
+
+	ps4 := newPageStyle(options.Options{"page_size": "quarto"})
+	check(t, ps4.err != nil, "Unknown size should be reported")
+	expectF(t, 612, ps4.trimBox.x2)
+
+	ps5 := newPageStyle(options.Options{"page_size": "ledger", "orientation": "landscape"})
+	expectF(t, 1224, ps5.trimBox.x2)
+	expectF(t, 792, ps5.trimBox.y2)
+}
+
+func TestNewPageStyle_Bleed(t *testing.T) {
+	ps := newPageStyle(options.Options{"page_size": "photo-4x6", "bleed": "9pt", "slug": 18, "art_margin": "0.5in"})
+	check(t, ps.trimBox == rectangle{0, 0, 288, 432}, "Trim box should be the page size")
+	check(t, ps.bleedBox == rectangle{-9, -9, 297, 441}, "Bleed box should extend the trim box by the bleed")
+	check(t, ps.pageSize == rectangle{-27, -27, 315, 459}, "Media box should extend the bleed box by the slug")
+	check(t, ps.cropSize == ps.pageSize, "Crop box should default to the media box")
+	check(t, ps.artBox != nil && *ps.artBox == rectangle{36, 36, 252, 396}, "Art box should be inset from the trim box")
+}
+
+func TestDocWriter_NewPageWithOptions_Boxes(t *testing.T) {
+	dw := NewDocWriter()
+	pw := dw.NewPageWithOptions(options.Options{"page_size": "A4", "bleed": "0.125in"})
+	expectF(t, 595, pw.PageWidth())
+	expectF(t, 842, pw.PageHeight())
+	s := stringFromWriter(pw.page)
+	check(t, strings.Contains(s, "/MediaBox [-9 -9 604 851 ] "), "Media box should include the bleed")
+	check(t, strings.Contains(s, "/BleedBox [-9 -9 604 851 ] "), "Bleed box should be written")
+	check(t, strings.Contains(s, "/TrimBox [0 0 595 842 ] "), "Trim box should be written")
+
+	dw.NewPageWithOptions(options.Options{"page_size": "quarto"})
+	var buf bytes.Buffer
+	_, err := dw.WriteTo(&buf)
+	check(t, err != nil && strings.Contains(err.Error(), "quarto"), "Unknown page size should be reported")
+}
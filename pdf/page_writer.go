@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/rowland/leadtype/bidi"
 	"github.com/rowland/leadtype/codepage"
 	"github.com/rowland/leadtype/colors"
 	"github.com/rowland/leadtype/font"
@@ -41,7 +42,9 @@ const (
 type PageWriter struct {
 	drawState
 	autoPath      bool
+	ctm           transformMatrix
 	dw            *DocWriter
+	existing      *existingPage // set when editing a page of an existing file
 	fonts         []*font.Font
 	gw            *graphWriter
 	inGraph       bool
@@ -49,9 +52,12 @@ type PageWriter struct {
 	inText        bool
 	isClosed      bool
 	keepOrigin    bool
+	label         *pageLabel // set when a range of page labels begins here
 	last          drawState
 	line          *rich_text.RichText
 	lineHeight    float64
+	marked        *openTag
+	marks         *markedPage
 	mw            *miscWriter
 	options       options.Options
 	origin        Location
@@ -60,6 +66,7 @@ type PageWriter struct {
 	pageWidth     float64
 	pathStates    []pathState
 	stream        bytes.Buffer
+	template      *pageTemplate // set when drawing a template
 	tw            *textWriter
 	units         *units
 	vTextAlignPts float64
@@ -67,14 +74,18 @@ type PageWriter struct {
 }
 
 type pathState struct {
-	autoPath  bool
-	fillColor colors.Color
-	lineColor colors.Color
-	lineWidth float64
-	miter     float64
-	lineCap   LineCapStyle
-	lineJoin  LineJoinStyle
-	lineDash  string
+	autoPath      bool
+	fillColor     colors.Color
+	fillGradient  *Gradient
+	lineColor     colors.Color
+	lineWidth     float64
+	miter         float64
+	lineCap       LineCapStyle
+	lineJoin      LineJoinStyle
+	lineDash      string
+	fillOpacity   float64
+	strokeOpacity float64
+	blendMode     string
 }
 
 func newPageWriter(dw *DocWriter, options options.Options) *PageWriter {
@@ -90,35 +101,55 @@ func clonePageWriter(opw *PageWriter) *PageWriter {
 }
 
 func (pw *PageWriter) init(dw *DocWriter, options options.Options) *PageWriter {
-	pw.dw = dw
-	pw.options = options
-	pw.lineSpacing = options.FloatDefault("line_spacing", 1.0)
-	pw.units = UnitConversions[options.StringDefault("units", "pt")]
-	pw.vTextAlign = parseVerticalTextAlign(options.StringDefault("v_text_align", "base"))
+	pw.initState(dw, options)
 	ps := newPageStyle(options)
-	pw.pageHeight = ps.pageSize.y2
-	pw.pageWidth = ps.pageSize.x2
+	if ps.err != nil && dw.pageErr == nil {
+		dw.pageErr = ps.err
+	}
+	pw.pageHeight = ps.trimBox.y2
+	pw.pageWidth = ps.trimBox.x2
 	pw.page = newPage(pw.dw.nextSeq(), 0, pw.dw.catalog.pages)
 	pw.page.setMediaBox(ps.pageSize)
 	pw.page.setCropBox(ps.cropSize)
+	if ps.bleedBox != ps.trimBox {
+		pw.page.setBleedBox(ps.bleedBox)
+	}
+	pw.page.setTrimBox(ps.trimBox)
+	if ps.artBox != nil {
+		pw.page.setArtBox(*ps.artBox)
+	}
 	pw.page.setRotate(ps.rotate)
 	pw.page.setResources(pw.dw.resources)
 	pw.dw.file.body.add(pw.page)
+	return pw
+}
+
+// initState sets the drawing state of a new page and its content writers.
+func (pw *PageWriter) initState(dw *DocWriter, options options.Options) {
+	pw.dw = dw
+	pw.options = options
+	pw.lineSpacing = options.FloatDefault("line_spacing", 1.0)
+	pw.units = UnitConversions[options.StringDefault("units", "pt")]
+	pw.vTextAlign = parseVerticalTextAlign(options.StringDefault("v_text_align", "base"))
 	pw.autoPath = true
 	pw.lineJoinStyle = MiterJoin
 	pw.last.lineJoinStyle = MiterJoin
 	pw.miterLimit = 10
 	pw.last.miterLimit = 10
+	pw.fillOpacity, pw.last.fillOpacity = 1, 1
+	pw.strokeOpacity, pw.last.strokeOpacity = 1, 1
+	pw.blendMode, pw.last.blendMode = "Normal", "Normal"
+	pw.ctm = identityMatrix
 	pw.mw = newMiscWriter(&pw.stream)
 	pw.tw = newTextWriter(&pw.stream)
 	pw.gw = newGraphWriter(&pw.stream)
-
-	return pw
 }
 
 func (pw *PageWriter) AddFont(family string, options options.Options) ([]*font.Font, error) {
 	if font, err := font.New(family, options, pw.dw.fontSources); err != nil {
 		return nil, err
+	} else if pw.dw.conformance != NoConformance && font.SubType() == "Type1" {
+		return nil, errPDFAFont
 	} else {
 		return pw.addFont(font), nil
 	}
@@ -129,6 +160,41 @@ func (pw *PageWriter) addFont(font *font.Font) []*font.Font {
 	return pw.fonts
 }
 
+// AddDestination registers name as a named destination that scrolls to the
+// vertical position y on this page. Links and outline entries on any page may
+// refer to it.
+func (pw *PageWriter) AddDestination(name string, y float64) {
+	pw.dw.addNamedDest(name, pw.destination(y))
+}
+
+func (pw *PageWriter) addAnnot(a *annotation) {
+	if pw.template != nil {
+		// Annotations belong to pages; a form XObject cannot carry them.
+		return
+	}
+	if _, ok := a.dict["F"]; !ok && pw.dw.conformance != NoConformance {
+		// PDF/A requires annotations to be printed with the page.
+		a.dict["F"] = integer(annotPrint)
+	}
+	if r, ok := a.dict["Rect"].(*rectangle); ok && pw.existing != nil {
+		*r = pw.existing.stampRect(*r)
+	}
+	pw.dw.file.body.add(a)
+	pw.page.addAnnot(a)
+}
+
+// addTextLink places a link for a run of text. A link beginning with "#"
+// refers to a named destination; anything else is treated as a URI.
+func (pw *PageWriter) addTextLink(rect rectangle, link string) {
+	a := newLinkAnnotation(pw.dw.nextSeq(), 0, rect)
+	if dest, ok := strings.CutPrefix(link, "#"); ok {
+		a.setDest(name(dest))
+	} else {
+		a.setURI(link)
+	}
+	pw.addAnnot(a)
+}
+
 func (pw *PageWriter) autoStrokeAndFill(stroke bool, fill bool) {
 	if !pw.autoPath {
 		return
@@ -149,16 +215,55 @@ func (pw *PageWriter) carriageReturn() {
 	pw.moveTo(pw.origin.X, pw.origin.Y)
 }
 
+func (pw *PageWriter) checkSetExtGState() {
+	if pw.fillOpacity == pw.last.fillOpacity &&
+		pw.strokeOpacity == pw.last.strokeOpacity &&
+		pw.blendMode == pw.last.blendMode {
+		return
+	}
+	if pw.inPath && pw.autoPath {
+		pw.gw.stroke()
+		pw.inPath = false
+	}
+	pw.gw.setExtGState(pw.dw.extGState(pw.fillOpacity, pw.strokeOpacity, pw.blendMode))
+	pw.last.fillOpacity = pw.fillOpacity
+	pw.last.strokeOpacity = pw.strokeOpacity
+	pw.last.blendMode = pw.blendMode
+}
+
 func (pw *PageWriter) checkSetFillColor() {
-	if pw.fillColor == pw.last.fillColor {
+	pw.checkSetExtGState()
+	if pw.fillGradient != nil {
+		pw.checkSetFillPattern()
+		return
+	}
+	if pw.fillColor == pw.last.fillColor && pw.last.fillPattern == "" {
 		return
 	}
 	if pw.inPath && pw.autoPath {
 		pw.gw.stroke()
 		pw.inPath = false
 	}
-	pw.mw.setRgbColorFill(pw.fillColor.RGB64())
+	pw.setColorFill(pw.fillColor)
 	pw.last.fillColor = pw.fillColor
+	pw.last.fillPattern = ""
+}
+
+func (pw *PageWriter) checkSetFillPattern() {
+	sh := pw.dw.shading(pw.fillGradient.shading(pw))
+	pattern := pw.dw.shadingPattern(sh, pw.ctm)
+	if pattern == pw.last.fillPattern {
+		return
+	}
+	if pw.inPath && pw.autoPath {
+		pw.gw.stroke()
+		pw.inPath = false
+	}
+	if pw.last.fillPattern == "" {
+		pw.mw.setColorSpaceFill("Pattern")
+	}
+	pw.mw.setPatternFill(pattern)
+	pw.last.fillPattern = pattern
 }
 
 func (pw *PageWriter) checkSetFont() {
@@ -206,18 +311,21 @@ func (pw *PageWriter) checkSetVTextAlign(force bool) {
 }
 
 func (pw *PageWriter) checkSetFontColor() {
-	if pw.fontColor == pw.last.fillColor {
+	pw.checkSetExtGState()
+	if pw.fontColor == pw.last.fillColor && pw.last.fillPattern == "" {
 		return
 	}
 	if pw.inPath && pw.autoPath {
 		pw.gw.stroke()
 		pw.inPath = false
 	}
-	pw.mw.setRgbColorFill(pw.fontColor.RGB64())
+	pw.setColorFill(pw.fontColor)
 	pw.last.fillColor = pw.fontColor
+	pw.last.fillPattern = ""
 }
 
 func (pw *PageWriter) checkSetLineColor() {
+	pw.checkSetExtGState()
 	if pw.lineColor == pw.last.lineColor {
 		return
 	}
@@ -225,7 +333,7 @@ func (pw *PageWriter) checkSetLineColor() {
 		pw.gw.stroke()
 		pw.inPath = false
 	}
-	pw.mw.setRgbColorStroke(pw.lineColor.RGB64())
+	pw.setColorStroke(pw.lineColor)
 	pw.last.lineColor = pw.lineColor
 }
 
@@ -335,8 +443,14 @@ func (pw *PageWriter) close() {
 	}
 	// end margins
 	// end sub page
-	pw.endText()
-	pw.endGraph()
+	drawn := pw.stream.Len() > 0 || pw.line != nil
+	pw.endContent()
+	if pw.existing != nil {
+		pw.closeExisting(drawn)
+		pw.stream = bytes.Buffer{}
+		pw.isClosed = true
+		return
+	}
 	// compress stream
 	pdfStream := newStream(pw.dw.nextSeq(), 0, pw.stream.Bytes())
 	if pw.dw.compressPages {
@@ -348,10 +462,21 @@ func (pw *PageWriter) close() {
 	// set annots
 	pw.page.add(pdfStream)
 	pw.dw.catalog.pages.add(pw.page) // unless reusing page
-	pw.stream.Reset()
+	// Release the buffer, which an uncompressed content stream shares.
+	pw.stream = bytes.Buffer{}
 	pw.isClosed = true
 }
 
+// endContent ends any text, graphics or marked content left open.
+func (pw *PageWriter) endContent() {
+	pw.endText()
+	pw.endGraph()
+	if pw.marked != nil {
+		pw.mw.endMarkedContent()
+		pw.marked = nil
+	}
+}
+
 var errTooFewPoints = errors.New("Need at least 4 points for curve")
 var errNoActivePath = errors.New("No active manual path.")
 var errPathAlreadyActive = errors.New("Manual path already active.")
@@ -365,14 +490,18 @@ func (pw *PageWriter) beginManualPath() error {
 	}
 	pw.flushText()
 	pw.pathStates = append(pw.pathStates, pathState{
-		autoPath:  pw.autoPath,
-		fillColor: pw.fillColor,
-		lineColor: pw.lineColor,
-		lineWidth: pw.lineWidth,
-		miter:     pw.miterLimit,
-		lineCap:   pw.lineCapStyle,
-		lineJoin:  pw.lineJoinStyle,
-		lineDash:  pw.lineDashPattern,
+		autoPath:      pw.autoPath,
+		fillColor:     pw.fillColor,
+		fillGradient:  pw.fillGradient,
+		lineColor:     pw.lineColor,
+		lineWidth:     pw.lineWidth,
+		miter:         pw.miterLimit,
+		lineCap:       pw.lineCapStyle,
+		lineJoin:      pw.lineJoinStyle,
+		lineDash:      pw.lineDashPattern,
+		fillOpacity:   pw.fillOpacity,
+		strokeOpacity: pw.strokeOpacity,
+		blendMode:     pw.blendMode,
 	})
 	pw.autoPath = false
 	return nil
@@ -394,12 +523,16 @@ func (pw *PageWriter) restorePathState() {
 	last := pw.pathStates[len(pw.pathStates)-1]
 	pw.autoPath = last.autoPath
 	pw.fillColor = last.fillColor
+	pw.fillGradient = last.fillGradient
 	pw.lineColor = last.lineColor
 	pw.lineWidth = last.lineWidth
 	pw.miterLimit = last.miter
 	pw.lineCapStyle = last.lineCap
 	pw.lineJoinStyle = last.lineJoin
 	pw.lineDashPattern = last.lineDash
+	pw.fillOpacity = last.fillOpacity
+	pw.strokeOpacity = last.strokeOpacity
+	pw.blendMode = last.blendMode
 	pw.pathStates = pw.pathStates[:len(pw.pathStates)-1]
 }
 
@@ -482,6 +615,7 @@ func (pw *PageWriter) Clip(fn func()) error {
 		return err
 	}
 	pw.startGraph()
+	savedLast := pw.last
 	pw.gw.saveGraphicsState()
 	pw.gw.clip()
 	pw.gw.newPath()
@@ -497,6 +631,7 @@ func (pw *PageWriter) Clip(fn func()) error {
 		pw.endGraph()
 	}
 	pw.gw.restoreGraphicsState()
+	pw.last = savedLast
 	return nil
 }
 
@@ -504,7 +639,7 @@ func (pw *PageWriter) scopedTransform(a, b, c, d, x, y float64, fn func()) error
 	if len(pw.pathStates) > 0 {
 		return errTransformInsideManualPath
 	}
-	savedLast := pw.last
+	savedLast, savedCTM := pw.last, pw.ctm
 	if pw.inText {
 		pw.endText()
 	} else if pw.line != nil {
@@ -518,6 +653,7 @@ func (pw *PageWriter) scopedTransform(a, b, c, d, x, y float64, fn func()) error
 	}
 	pw.gw.saveGraphicsState()
 	pw.gw.concatMatrix(a, b, c, d, x, y)
+	pw.ctm = transformMatrix{a, b, c, d, x, y}.concat(pw.ctm)
 	if fn != nil {
 		fn()
 	}
@@ -533,7 +669,7 @@ func (pw *PageWriter) scopedTransform(a, b, c, d, x, y float64, fn func()) error
 		pw.endGraph()
 	}
 	pw.gw.restoreGraphicsState()
-	pw.last = savedLast
+	pw.last, pw.ctm = savedLast, savedCTM
 	return nil
 }
 
@@ -856,7 +992,7 @@ func (pw *PageWriter) flushText() {
 	textLoc := loc1
 	usedPositionedText := false
 	var buf bytes.Buffer
-	merged := pw.line.Merge()
+	merged := pw.line.Reorder(bidi.Auto).Merge()
 	// Iterate leaf pieces directly. TrueType leaves are encoded as big-endian
 	// uint16 glyph ID pairs. AFM/Type1 leaves use codepage-based encoding.
 	merged.VisitAll(func(p *rich_text.RichText) {
@@ -873,12 +1009,12 @@ func (pw *PageWriter) flushText() {
 
 			var shaped []shaping.GlyphPosition
 			var runes []rune // allocated only when shaping is attempted
-			var glyphSequences map[int][]rune
+			var glyphSequences [][]rune
 			usePositionedGlyphs := false
-			if p.Font.Shaper != nil && shaping.ContainsArabic(p.Text) {
+			if p.IsShaped() {
 				runes = []rune(p.Text)
-				shaped, _ = p.Font.Shaper.Shape(runes, p.Font, float32(p.FontSize))
-				glyphSequences = shapedGlyphSequences(shaped, runes)
+				shaped, _ = shaping.ShapeRuns(p.Font.Shaper, runes, p.Font, float32(p.FontSize), p.OpenTypeFeatures())
+				glyphSequences = shapedGlyphSequences(shaped, runes, p.Font.GlyphIndex)
 			}
 
 			if shaped != nil {
@@ -905,14 +1041,15 @@ func (pw *PageWriter) flushText() {
 			if shaped != nil {
 				usedPositionedText = true
 				penX := 0.0
+				spacing := shapedSpacing(shaped, runes, p.CharSpacing, p.WordSpacing)
 				// The shaper returns glyphs in visual order, so emit them in the
 				// same order while advancing the pen explicitly.
-				for _, gp := range shaped {
+				for i, gp := range shaped {
 					if gr != nil {
-						if seq := glyphSequences[gp.ClusterIndex]; len(seq) > 0 {
-							gr.recordRunes(gp.GlyphID, seq)
-						} else {
+						if glyphSequences == nil {
 							gr.record(gp.GlyphID, runes[gp.ClusterIndex])
+						} else if seq := glyphSequences[i]; len(seq) > 0 {
+							gr.recordRunes(gp.GlyphID, seq)
 						}
 					}
 					buf.WriteByte(byte(gp.GlyphID >> 8))
@@ -925,16 +1062,21 @@ func (pw *PageWriter) flushText() {
 					pw.tw.showHex(buf.Bytes())
 					buf.Reset()
 					penX += float64(gp.XAdvance) / 64.0
+					if spacing != nil {
+						penX += spacing[i]
+					}
 				}
 			} else if usePositionedGlyphs {
 				usedPositionedText = true
 				penX := 0.0
 				fsize := p.FontSize / float64(p.Font.UnitsPerEm())
+				kern := newKerner(p)
 				for _, r := range p.Text {
 					gid := p.Font.GlyphIndex(r)
 					if gr != nil {
 						gr.record(gid, r)
 					}
+					penX += fsize * float64(kern.next(r))
 					advanceWidth, _ := p.Font.AdvanceWidth(r)
 					buf.WriteByte(byte(gid >> 8))
 					buf.WriteByte(byte(gid & 0xFF))
@@ -946,6 +1088,22 @@ func (pw *PageWriter) flushText() {
 						penX += p.WordSpacing
 					}
 				}
+			} else if p.Kerned() {
+				var elements array
+				kern := newKerner(p)
+				for _, r := range p.Text {
+					if k := kern.next(r); k != 0 {
+						elements = append(elements, hexString(bytes.Clone(buf.Bytes())), kern.displacement(k))
+						buf.Reset()
+					}
+					gid := p.Font.GlyphIndex(r)
+					if gr != nil {
+						gr.record(gid, r)
+					}
+					buf.WriteByte(byte(gid >> 8))
+					buf.WriteByte(byte(gid & 0xFF))
+				}
+				pw.tw.showWithDispacements(append(elements, hexString(buf.Bytes())))
 			} else {
 				for _, r := range p.Text {
 					gid := p.Font.GlyphIndex(r)
@@ -962,11 +1120,17 @@ func (pw *PageWriter) flushText() {
 			}
 		} else {
 			// AFM/Type1: still needs codepage-based encoding.
+			kern := newKerner(p)
 			p.EachCodepage(func(cpi codepage.CodepageIndex, text string, piece *rich_text.RichText) {
 				buf.Reset()
+				var elements array
 				if cpi >= 0 {
 					cp := cpi.Codepage()
 					for _, r := range text {
+						if k := kern.next(r); k != 0 {
+							elements = append(elements, str(bytes.Clone(buf.Bytes())), kern.displacement(k))
+							buf.Reset()
+						}
 						ch, _ := cp.CharForCodepoint(r)
 						buf.WriteByte(byte(ch))
 					}
@@ -980,13 +1144,18 @@ func (pw *PageWriter) flushText() {
 				pw.charSpacing = piece.CharSpacing
 				pw.wordSpacing = piece.WordSpacing
 				pw.checkSetSpacing()
-				pw.tw.show(buf.Bytes())
+				if len(elements) > 0 {
+					pw.tw.showWithDispacements(append(elements, str(buf.Bytes())))
+				} else {
+					pw.tw.show(buf.Bytes())
+				}
 			})
 		}
 	})
 	if usedPositionedText {
 		pw.tw.setMatrix(1, 0, 0, 1, pw.loc.X, pw.loc.Y)
 	}
+	var links textLinks
 	pw.line.VisitAll(func(p *rich_text.RichText) {
 		if !p.IsLeaf() {
 			return
@@ -998,8 +1167,14 @@ func (pw *PageWriter) flushText() {
 		if p.Strikeout {
 			pw.drawUnderline(loc1, loc2, p.StrikeoutPosition, p.StrikeoutThickness)
 		}
+		links.add(p.Link, rectangle{
+			loc1.X, loc1.Y + pw.vTextAlignPts + p.Descent(),
+			loc2.X, loc1.Y + pw.vTextAlignPts + p.Ascent()})
 		loc1 = loc2
 	})
+	for _, link := range links {
+		pw.addTextLink(link.rect, link.target)
+	}
 	pw.last.loc = pw.loc
 	pw.lineHeight = math.Max(pw.lineHeight, pw.line.Leading()*pw.lineSpacing)
 	pw.loc.X += pw.line.Width()
@@ -1016,40 +1191,188 @@ func (pw *PageWriter) flushText() {
 	pw.flushing = false
 }
 
-func shapedGlyphSequences(glyphs []shaping.GlyphPosition, runes []rune) map[int][]rune {
+// kerner finds the kerning between successive runes of a piece of text.
+type kerner struct {
+	font *font.Font
+	on   bool
+	prev rune
+}
+
+func newKerner(p *rich_text.RichText) *kerner {
+	return &kerner{font: p.Font, on: p.Kerned(), prev: -1}
+}
+
+// next returns the kerning between the previous rune and r, in glyph-space
+// units. Soft hyphens, which are not measured, are skipped.
+func (k *kerner) next(r rune) int {
+	if r == wordbreaking.SoftHyphen {
+		return 0
+	}
+	prev := k.prev
+	k.prev = r
+	if !k.on || prev < 0 {
+		return 0
+	}
+	return k.font.PairKerning(prev, r)
+}
+
+// displacement returns kerning as a TJ displacement, in thousandths of a
+// unit of text space, where positive values move the next glyph left.
+func (k *kerner) displacement(kerning int) real {
+	return real(-float64(kerning) * 1000 / float64(k.font.UnitsPerEm()))
+}
+
+type textLink struct {
+	target string
+	rect   rectangle
+}
+
+// textLinks accumulates link rectangles for the pieces of a line, merging
+// adjacent pieces that share a target into a single rectangle.
+type textLinks []textLink
+
+func (links *textLinks) add(target string, rect rectangle) {
+	if target == "" {
+		return
+	}
+	if n := len(*links); n > 0 {
+		last := &(*links)[n-1]
+		if last.target == target && last.rect.x2 == rect.x1 {
+			last.rect.x2 = rect.x2
+			last.rect.y1 = math.Min(last.rect.y1, rect.y1)
+			last.rect.y2 = math.Max(last.rect.y2, rect.y2)
+			return
+		}
+	}
+	*links = append(*links, textLink{target, rect})
+}
+
+// shapedGlyphSequences returns the text each shaped glyph stands for, for the
+// ToUnicode map, or nil for glyphs that stand for none of their own.
+//
+// A cluster shaped into a single glyph, such as a ligature, maps to the whole
+// cluster. Where a cluster is shaped into several glyphs, as when an Indic
+// pre-base matra is moved before its consonant, each glyph that is the font's
+// own glyph for one of the cluster's characters maps to that character, and
+// the first glyph that is not maps to the rest, so that no text is repeated.
+func shapedGlyphSequences(glyphs []shaping.GlyphPosition, runes []rune, glyphIndex func(rune) uint16) [][]rune {
 	if len(glyphs) == 0 || len(runes) == 0 {
 		return nil
 	}
 
-	clusterStarts := make(map[int]struct{}, len(glyphs))
-	for _, gp := range glyphs {
+	clusters := make(map[int][]int, len(glyphs))
+	for i, gp := range glyphs {
 		if gp.ClusterIndex >= 0 && gp.ClusterIndex < len(runes) {
-			clusterStarts[gp.ClusterIndex] = struct{}{}
+			clusters[gp.ClusterIndex] = append(clusters[gp.ClusterIndex], i)
 		}
 	}
-	if len(clusterStarts) == 0 {
+	if len(clusters) == 0 {
 		return nil
 	}
 
-	sortedStarts := make([]int, 0, len(clusterStarts))
-	for start := range clusterStarts {
+	sortedStarts := make([]int, 0, len(clusters))
+	for start := range clusters {
 		sortedStarts = append(sortedStarts, start)
 	}
 	sort.Ints(sortedStarts)
 
-	sequences := make(map[int][]rune, len(sortedStarts))
+	sequences := make([][]rune, len(glyphs))
 	for i, start := range sortedStarts {
 		end := len(runes)
 		if i+1 < len(sortedStarts) {
 			end = sortedStarts[i+1]
 		}
-		if start < end {
-			sequences[start] = append([]rune(nil), runes[start:end]...)
+		text := runes[start:end]
+		members := clusters[start]
+		if len(members) == 1 {
+			sequences[members[0]] = append([]rune(nil), text...)
+			continue
+		}
+		used := make([]bool, len(text))
+		var unmatched []int
+		for _, g := range members {
+			matched := false
+			for k, r := range text {
+				if !used[k] && glyphIndex(r) == glyphs[g].GlyphID {
+					used[k] = true
+					sequences[g] = []rune{r}
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				unmatched = append(unmatched, g)
+			}
+		}
+		var rest []rune
+		for k, r := range text {
+			if !used[k] {
+				rest = append(rest, r)
+			}
+		}
+		if len(rest) == 0 {
+			continue
+		}
+		if len(unmatched) > 0 {
+			sequences[unmatched[0]] = rest
+		} else {
+			sequences[members[0]] = append(sequences[members[0]], rest...)
 		}
 	}
 	return sequences
 }
 
+// shapedSpacing returns the character and word spacing to add after each
+// shaped glyph, or nil if there is none. Each cluster's spacing, for all of
+// its characters, follows the first of its glyphs, so that shaped text is
+// spaced as RichText measures it.
+func shapedSpacing(glyphs []shaping.GlyphPosition, runes []rune, charSpacing, wordSpacing float64) []float64 {
+	if charSpacing == 0 && wordSpacing == 0 {
+		return nil
+	}
+	starts := make([]int, 0, len(glyphs))
+	for _, gp := range glyphs {
+		starts = append(starts, gp.ClusterIndex)
+	}
+	sort.Ints(starts)
+	spacing := make([]float64, len(glyphs))
+	seen := make(map[int]bool, len(glyphs))
+	for i, gp := range glyphs {
+		if seen[gp.ClusterIndex] || gp.ClusterIndex < 0 || gp.ClusterIndex >= len(runes) {
+			continue
+		}
+		seen[gp.ClusterIndex] = true
+		end := len(runes)
+		if k := sort.SearchInts(starts, gp.ClusterIndex+1); k < len(starts) {
+			end = starts[k]
+		}
+		for _, r := range runes[gp.ClusterIndex:end] {
+			if r == wordbreaking.SoftHyphen {
+				continue
+			}
+			spacing[i] += charSpacing
+			if r == ' ' {
+				spacing[i] += wordSpacing
+			}
+		}
+	}
+	return spacing
+}
+
+func (pw *PageWriter) BlendMode() string {
+	return pw.blendMode
+}
+
+// FillGradient returns the gradient used in place of the fill color, or nil
+// if fills are solid.
+func (pw *PageWriter) FillGradient() *Gradient {
+	return pw.fillGradient
+}
+
+func (pw *PageWriter) FillOpacity() float64 {
+	return pw.fillOpacity
+}
+
 func (pw *PageWriter) FontColor() colors.Color {
 	return pw.fontColor
 }
@@ -1093,6 +1416,36 @@ func (pw *PageWriter) MiterLimit() float64 {
 	return pw.miterLimit
 }
 
+// LinkToDestination places a link over the given rectangle that jumps to the
+// named destination dest, registered with AddDestination.
+func (pw *PageWriter) LinkToDestination(x, y, width, height float64, dest string) {
+	a := newLinkAnnotation(pw.dw.nextSeq(), 0, pw.annotRect(x, y, width, height))
+	a.setDest(name(dest))
+	pw.addAnnot(a)
+}
+
+// LinkToPage places a link over the given rectangle that jumps to the vertical
+// position targetY on target.
+func (pw *PageWriter) LinkToPage(x, y, width, height float64, target *PageWriter, targetY float64) {
+	a := newLinkAnnotation(pw.dw.nextSeq(), 0, pw.annotRect(x, y, width, height))
+	a.setDest(target.destination(targetY))
+	pw.addAnnot(a)
+}
+
+// LinkToURI places a link over the given rectangle that opens uri.
+func (pw *PageWriter) LinkToURI(x, y, width, height float64, uri string) {
+	a := newLinkAnnotation(pw.dw.nextSeq(), 0, pw.annotRect(x, y, width, height))
+	a.setURI(uri)
+	pw.addAnnot(a)
+}
+
+// annotRect converts a rectangle in page units, measured from the top-left
+// corner, to an annotation rectangle in default user space.
+func (pw *PageWriter) annotRect(x, y, width, height float64) rectangle {
+	x1, y1 := pw.units.toPts(x), pw.translate(pw.units.toPts(y+height))
+	return rectangle{x1, y1, x1 + pw.units.toPts(width), y1 + pw.units.toPts(height)}
+}
+
 func (pw *PageWriter) LineTo(x, y float64) {
 	xpts, ypts := pw.units.toPts(x), pw.translate(pw.units.toPts(y))
 	pw.lineTo(xpts, ypts)
@@ -1156,6 +1509,21 @@ func (pw *PageWriter) PageWidth() float64 {
 	return pw.units.fromPts(pw.pageWidth)
 }
 
+// PaintGradient fills the current clipping region with gradient. Combined
+// with Clip, it shades an arbitrary path; outside of a clipping region it
+// covers as much of the page as the gradient's extent and Extend settings
+// allow.
+func (pw *PageWriter) PaintGradient(gradient *Gradient) {
+	pw.flushText()
+	pw.startGraph()
+	if pw.inPath && pw.autoPath {
+		pw.gw.stroke()
+		pw.inPath = false
+	}
+	pw.checkSetExtGState()
+	pw.mw.shade(pw.dw.shading(gradient.shading(pw)).name)
+}
+
 func (pw *PageWriter) Print(text string) (err error) {
 	i := strings.IndexAny(text, "\t\r\n")
 	for i >= 0 {
@@ -1180,6 +1548,9 @@ func (pw *PageWriter) PrintImage(data []byte, x, y float64, width, height *float
 	if svg.LooksLikeSVG(data) {
 		return pw.PrintSVG(data, x, y, width, height)
 	}
+	if isPDF(data) {
+		return pw.PrintPDFPage(data, 1, x, y, width, height)
+	}
 	key := imageKey(data)
 	image, name, err := pw.dw.loadImage(data, key)
 	if err != nil {
@@ -1193,6 +1564,13 @@ func (pw *PageWriter) PrintImage(data []byte, x, y float64, width, height *float
 		bitsPerComponent: image.bitsPerComponent,
 	}
 	wpts, hpts := imageSizeInPoints(info, pw.units, width, height)
+	pw.placeXObject(name, xpts, ypts, wpts, hpts, 1, 1)
+	return pw.units.fromPts(wpts), pw.units.fromPts(hpts), nil
+}
+
+// placeXObject draws the XObject name, scaled from its natural size to
+// width and height, with its top left corner at x, y, all in points.
+func (pw *PageWriter) placeXObject(name string, x, y, width, height, naturalWidth, naturalHeight float64) {
 	if pw.inPath {
 		pw.endPath()
 	}
@@ -1202,12 +1580,13 @@ func (pw *PageWriter) PrintImage(data []byte, x, y float64, width, height *float
 	if pw.inGraph {
 		pw.endGraph()
 	}
-	writeImageXObject(pw.mw, pw.gw, name, xpts, ypts, wpts, hpts, pw.pageHeight)
-	return pw.units.fromPts(wpts), pw.units.fromPts(hpts), nil
+	pw.markContent()
+	pw.checkSetExtGState()
+	writeXObject(pw.mw, pw.gw, name, x, y, width, height, naturalWidth, naturalHeight, pw.pageHeight)
 }
 
 func (pw *PageWriter) PrintImageFile(filename string, x, y float64, width, height *float64) (actualWidth, actualHeight float64, err error) {
-	data, err := pw.dw.readImageFile(filename)
+	data, err := pw.dw.readAssetFile(filename)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -1226,8 +1605,10 @@ func (pw *PageWriter) PrintSVG(data []byte, x, y float64, width, height *float64
 	ypts := pw.units.toPts(y)
 	prevUnits := pw.units
 	pw.units = UnitConversions["pt"]
+	prevGradient := pw.SetFillGradient(nil)
 	defer func() {
 		pw.units = prevUnits
+		pw.fillGradient = prevGradient
 	}()
 	renderer := newSVGRenderer(doc, pw, xpts, ypts, wpts, hpts)
 	if err := renderer.render(); err != nil {
@@ -1237,7 +1618,7 @@ func (pw *PageWriter) PrintSVG(data []byte, x, y float64, width, height *float64
 }
 
 func (pw *PageWriter) PrintSVGFile(filename string, x, y float64, width, height *float64) (actualWidth, actualHeight float64, err error) {
-	data, err := pw.dw.readImageFile(filename)
+	data, err := pw.dw.readAssetFile(filename)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -1254,12 +1635,29 @@ func (pw *PageWriter) print(text string) (err error) {
 	return
 }
 
+// PrintParagraph prints lines of text, such as those from RichText.WrapToWidth, one below another.
+//
+// Options:
+//
+//	width:      Width of the lines, expressed in points. Defaults to the rest of the page.
+//	text-align: "left", "center", "right" or "justify".
+//	            Defaults to "right" for right-to-left paragraphs and "left" otherwise.
+//	dir:        Direction of the paragraph: "ltr", "rtl" or "auto".
+//	            Defaults to "auto", taking the direction of the first strong character.
+//	            Each line is reordered for display by the Unicode Bidirectional Algorithm.
 func (pw *PageWriter) PrintParagraph(para []*rich_text.RichText, options options.Options) {
 	pw.flushText()
 	width := options.FloatDefault("width", pw.PageWidth()-pw.loc.X)
+	dir := paragraphDirection(para, bidi.ParseDirection(options.StringDefault("dir", "auto")))
+	textAlign := "left"
+	if dir == bidi.RightToLeft {
+		textAlign = "right"
+	}
+	textAlign = options.StringDefault("text-align", textAlign)
 	for _, p := range para {
+		p = p.Reorder(dir)
 		pw.origin = pw.loc
-		switch options.StringDefault("text-align", "left") {
+		switch textAlign {
 		case "center":
 			pw.keepOrigin = true
 			pw.loc = Location{pw.loc.X + (width-p.Width())/2, pw.loc.Y}
@@ -1313,12 +1711,30 @@ func (pw *PageWriter) PrintRichText(text *rich_text.RichText) {
 	}
 }
 
+// paragraphDirection resolves dir for the lines of a paragraph, taking the
+// direction of its first strong character for bidi.Auto.
+func paragraphDirection(para []*rich_text.RichText, dir bidi.Direction) bidi.Direction {
+	if dir != bidi.Auto {
+		return dir
+	}
+	var sb strings.Builder
+	for _, p := range para {
+		sb.WriteString(p.String())
+	}
+	return bidi.BaseDirection(sb.String())
+}
+
 func (pw *PageWriter) PrintWithOptions(text string, options options.Options) (err error) {
 	var para []*rich_text.RichText
 	rt, err := pw.richTextForString(text)
 	if err != nil {
 		return
 	}
+	if link := options.StringDefault("link", ""); link != "" {
+		rt.VisitAll(func(p *rich_text.RichText) {
+			p.Link = link
+		})
+	}
 	if width := options.FloatDefault("width", 0); width > 0 {
 		flags := make([]wordbreaking.Flags, rt.Len())
 		wordbreaking.MarkRuneAttributes(rt.String(), flags)
@@ -1447,6 +1863,21 @@ func (pw *PageWriter) SetFont(name string, size float64, options options.Options
 	return pw.AddFont(name, options)
 }
 
+// SetBlendMode sets the blend mode used to composite subsequent drawing with
+// the page. Names are those in BlendModes, matched case-insensitively; CSS
+// spellings such as "color-dodge" are also accepted. Unknown names are ignored.
+func (pw *PageWriter) SetBlendMode(mode string) (prev string) {
+	prev = pw.blendMode
+	if bm, ok := lookupBlendMode(mode); ok {
+		pw.blendMode = bm
+	}
+	return
+}
+
+// SetFillColor sets the color used to fill shapes. value is a colors.Color,
+// an RGB value such as 0xFF0000, or a string parsed by colors.NamedColor,
+// such as "red", "cmyk(0, 1, 1, 0)" or "spot(PANTONE 185 C, cmyk(0, 0.91, 0.76, 0))".
+// CMYK and spot colors are written in their own color spaces, not as RGB.
 func (pw *PageWriter) SetFillColor(value any) (prev colors.Color) {
 	prev = pw.fillColor
 
@@ -1466,6 +1897,23 @@ func (pw *PageWriter) SetFillColor(value any) (prev colors.Color) {
 	return
 }
 
+// SetFillGradient makes shapes, paths and rectangles fill with gradient
+// rather than the fill color. Pass nil to return to solid fills. Text
+// continues to use the font color.
+func (pw *PageWriter) SetFillGradient(gradient *Gradient) (prev *Gradient) {
+	prev = pw.fillGradient
+	pw.fillGradient = gradient
+	return
+}
+
+// SetFillOpacity sets the constant alpha, from 0 (transparent) to 1 (opaque),
+// used when filling shapes and painting text and images.
+func (pw *PageWriter) SetFillOpacity(opacity float64) (prev float64) {
+	prev = pw.fillOpacity
+	pw.fillOpacity = math.Max(0, math.Min(1, opacity))
+	return
+}
+
 func (pw *PageWriter) SetFontColor(value any) (prev colors.Color) {
 	prev = pw.fontColor
 
@@ -1502,6 +1950,9 @@ func (pw *PageWriter) SetFontStyle(style string) (prev string, err error) {
 			"weight":       font.Weight,
 			"style":        style,
 			"relativeSize": font.RelativeSize,
+			"instance":     font.Instance,
+			"variations":   font.Variations,
+			"features":     font.Features,
 		}
 		if font.RuneSet != nil {
 			options["ranges"] = font.RuneSet
@@ -1568,6 +2019,14 @@ func (pw *PageWriter) setLineWidth(width float64) (prev float64) {
 	return
 }
 
+// SetStrokeOpacity sets the constant alpha, from 0 (transparent) to 1
+// (opaque), used when stroking lines and shape borders.
+func (pw *PageWriter) SetStrokeOpacity(opacity float64) (prev float64) {
+	prev = pw.strokeOpacity
+	pw.strokeOpacity = math.Max(0, math.Min(1, opacity))
+	return
+}
+
 func (pw *PageWriter) SetUnderline(underline bool) (prev bool) {
 	prev = pw.underline
 	pw.underline = underline
@@ -1595,6 +2054,7 @@ func (pw *PageWriter) startGraph() {
 	if pw.inText {
 		pw.endText()
 	}
+	pw.markContent()
 	pw.last.loc = Location{0, 0}
 	pw.inGraph = true
 }
@@ -1606,6 +2066,7 @@ func (pw *PageWriter) startText() {
 	if pw.inGraph {
 		pw.endGraph()
 	}
+	pw.markContent()
 	pw.last.loc = Location{0, 0}
 	pw.resetTextStateCache()
 	pw.tw.open()
@@ -1633,6 +2094,10 @@ func (pw *PageWriter) translate(y float64) float64 {
 	return pw.pageHeight - y
 }
 
+func (pw *PageWriter) StrokeOpacity() float64 {
+	return pw.strokeOpacity
+}
+
 func (pw *PageWriter) Underline() bool {
 	return pw.underline
 }
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"fmt"
 	"image/jpeg"
+	"math"
 	"strings"
 	"testing"
 
@@ -446,6 +447,35 @@ func TestPageWriter_flushText(t *testing.T) {
 	expectS(t, "BT\n/F0 12 Tf\n(Hello, World!) Tj\n", pw.stream.String())
 }
 
+func TestPageWriter_flushText_Kerning(t *testing.T) {
+	dw := NewDocWriter()
+	afmfc, err := afm_fonts.Default()
+	if err != nil {
+		t.Fatalf("Default AFM fonts returned error: %v", err)
+	}
+	dw.AddFontSource(afmfc)
+	pw := dw.NewPage()
+
+	if _, err := pw.SetFont("Helvetica", 12, options.Options{"kerning": true}); err != nil {
+		t.Fatalf("SetFont returned error: %v", err)
+	}
+	pw.Print("AVA To")
+	pw.flushText()
+	if got := pw.stream.String(); !strings.Contains(got, "[(A) 70 (V) 80 (A ) 50 (T) 120 (o) ] TJ\n") {
+		t.Errorf("expected kerned text, got:\n%s", got)
+	}
+
+	pw = dw.NewPage()
+	if _, err := pw.SetFont("Helvetica", 12, options.Options{}); err != nil {
+		t.Fatalf("SetFont returned error: %v", err)
+	}
+	pw.Print("AVA")
+	pw.flushText()
+	if got := pw.stream.String(); !strings.Contains(got, "(AVA) Tj\n") {
+		t.Errorf("expected unkerned text, got:\n%s", got)
+	}
+}
+
 func TestPageWriter_SetVTextAlign(t *testing.T) {
 	dw := NewDocWriter()
 	pw := newPageWriter(dw, options.Options{})
@@ -679,6 +709,117 @@ func TestPageWriter_PrintParagraph_JustifyLeavesLastLineAtLeftEdge(t *testing.T)
 	}
 }
 
+func TestPageWriter_LinkToURI(t *testing.T) {
+	dw := NewDocWriter()
+	pw := dw.NewPage()
+	pw.SetUnits("in")
+	pw.LinkToURI(1, 1, 2, 0.5, "https://example.com/(x)")
+	check(t, len(pw.page.annots) == 1, "LinkToURI should add an annotation to the page")
+	expectS(t,
+		"<<\n/A <<\n/S /URI \n/URI (https://example.com/\\(x\\)) \n>>\n\n/Border [0 0 0 ] \n/Rect [72 684 216 720 ] \n/Subtype /Link \n/Type /Annot \n>>\n",
+		stringFromWriter(pw.page.annots[0].dict))
+}
+
+func TestPageWriter_LinkToDestination(t *testing.T) {
+	dw := NewDocWriter()
+	p1 := dw.NewPage()
+	p2 := dw.NewPage()
+	p2.AddDestination("summary", 100)
+	p1.LinkToDestination(72, 72, 100, 20, "summary")
+	p1.LinkToPage(72, 144, 100, 20, p2, 0)
+
+	expectS(t, "/summary ", stringFromWriter(p1.page.annots[0].dict["Dest"]))
+	expectS(t, fmt.Sprintf("[%d 0 R /XYZ null 792 null ] ", p2.page.Seq()), stringFromWriter(p1.page.annots[1].dict["Dest"]))
+	expectS(t, fmt.Sprintf("[%d 0 R /XYZ null 692 null ] ", p2.page.Seq()), stringFromWriter(dw.namedDests.dict["summary"]))
+	expectS(t, stringFromWriter(&indirectObjectRef{dw.namedDests}), stringFromWriter(dw.catalog.dict["Dests"]))
+}
+
+func TestPageWriter_PrintParagraph_LinksFollowWrappedLines(t *testing.T) {
+	fonts, err := afm_fonts.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw := NewDocWriter()
+	dw.AddFontSource(fonts)
+	pw := dw.NewPage()
+	if _, err := pw.SetFont("Helvetica", 12, options.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	rt, err := rich_text.New("Visit ", pw.Fonts(), 12, options.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt, err = rt.Add("our web site", pw.Fonts(), 12, options.Options{"link": "https://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt, err = rt.Add(" or the ", pw.Fonts(), 12, options.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt, err = rt.Add("summary", pw.Fonts(), 12, options.Options{"link": "#summary"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	flags := make([]wordbreaking.Flags, rt.Len())
+	wordbreaking.MarkRuneAttributes(rt.String(), flags)
+	para := rt.WrapToWidth(60, flags, false)
+	if len(para) < 3 {
+		t.Fatalf("expected wrapped paragraph to produce at least 3 lines, got %d", len(para))
+	}
+
+	pw.MoveTo(72, 72)
+	pw.PrintParagraph(para, options.Options{"width": 60})
+	pw.flushText()
+
+	var uris, dests int
+	var lastY float64
+	for i, a := range pw.page.annots {
+		rect := a.dict["Rect"].(*rectangle)
+		check(t, rect.x1 >= 72 && rect.x2 <= 132, "link rectangle should lie within the paragraph width")
+		check(t, rect.y2 > rect.y1, "link rectangle should have height")
+		if i > 0 {
+			check(t, rect.y1 < lastY, "each link fragment should sit on a later line")
+		}
+		lastY = rect.y1
+		if _, ok := a.dict["A"]; ok {
+			uris++
+		}
+		if _, ok := a.dict["Dest"]; ok {
+			dests++
+		}
+	}
+	check(t, uris == 2, fmt.Sprintf("expected URI link split across 2 lines, got %d", uris))
+	check(t, dests == 1, fmt.Sprintf("expected 1 destination link, got %d", dests))
+}
+
+func TestPageWriter_PrintParagraph_RightToLeft(t *testing.T) {
+	fonts, err := afm_fonts.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw := NewDocWriter()
+	dw.AddFontSource(fonts)
+	pw := dw.NewPage()
+	if _, err := pw.SetFont("Helvetica", 12, options.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	rt, err := rich_text.New("Hello, world.", pw.Fonts(), 12, options.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pw.MoveTo(72, 720)
+	pw.PrintParagraph([]*rich_text.RichText{rt}, options.Options{"dir": "rtl", "width": 200})
+	pw.flushText()
+
+	got := pw.stream.String()
+	check(t, strings.Contains(got, "(.Hello, world) Tj\n"), "right-to-left paragraph should be reordered for display:\n"+got)
+	var x, y float64
+	_, err = fmt.Sscanf(got[strings.Index(got, "BT\n")+3:], "%g %g Td", &x, &y)
+	check(t, err == nil && math.Abs(x-(72+200-rt.Width())) < 0.001, "right-to-left paragraph should align right by default:\n"+got)
+}
+
 func TestPageWriter_FlushText_PositionedTrueTypeLeafSetsFontBeforeGlyphs(t *testing.T) {
 	skipIfNoTTFFonts(t)
 	fc, err := ttf_fonts.NewFromSystemFonts()
@@ -1097,6 +1238,45 @@ func TestPageWriter_Print(t *testing.T) {
 	expectS(t, "Hello, World!", pw.line.String())
 }
 
+func TestPageWriter_SetFillOpacity(t *testing.T) {
+	dw := NewDocWriter()
+	pw := newPageWriter(dw, options.Options{})
+
+	check(t, pw.FillOpacity() == 1, "Should default to opaque")
+	check(t, pw.SetFillOpacity(1.5) == 1, "Should return previous value")
+	check(t, pw.FillOpacity() == 1, "Should clamp to 1")
+	pw.SetFillOpacity(0.5)
+	pw.SetBlendMode("multiply")
+	check(t, pw.BlendMode() == "Multiply", "Should canonicalize blend mode")
+	pw.SetBlendMode("bogus")
+	check(t, pw.BlendMode() == "Multiply", "Should ignore unknown blend mode")
+
+	pw.SetUnits("in")
+	pw.Rectangle(1, 1, 1, 1, false, true)
+	pw.Rectangle(2, 2, 1, 1, false, true)
+	expectS(t, "/GS0 gs\n72 648 72 72 re\nf\n144 576 72 72 re\nf\n", pw.stream.String())
+
+	var buf bytes.Buffer
+	dw.resources.write(&buf)
+	check(t, strings.Contains(buf.String(), "/ExtGState <<\n/GS0 "), "Resources should include GS0")
+}
+
+func TestPageWriter_SetStrokeOpacity(t *testing.T) {
+	dw := NewDocWriter()
+	pw := newPageWriter(dw, options.Options{})
+
+	check(t, pw.StrokeOpacity() == 1, "Should default to opaque")
+	pw.SetStrokeOpacity(0.25)
+	pw.MoveTo(0, 0)
+	pw.LineTo(72, 0)
+	pw.SetStrokeOpacity(1)
+	pw.MoveTo(0, 72)
+	pw.LineTo(72, 72)
+	check(t, len(dw.extGStates) == 2, "Each distinct state should be written once")
+	check(t, strings.HasPrefix(pw.stream.String(), "/GS0 gs\n"), "Should select translucent state")
+	check(t, strings.Contains(pw.stream.String(), "/GS1 gs\n"), "Should restore opaque state")
+}
+
 func TestPageWriter_SetFont(t *testing.T) {
 	skipIfNoTTFFonts(t)
 	dw := NewDocWriter()
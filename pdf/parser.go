@@ -0,0 +1,355 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+var errUnexpectedEOF = errors.New("unexpected end of PDF data")
+
+// objectRef is a reference to an object of an existing file. It is written
+// unchanged when the objects referring to it are written again.
+type objectRef struct {
+	seq, gen int
+}
+
+func (ref objectRef) Gen() int {
+	return ref.gen
+}
+
+func (ref objectRef) Seq() int {
+	return ref.seq
+}
+
+func (ref objectRef) write(w io.Writer) {
+	fmt.Fprintf(w, "%d %d R ", ref.seq, ref.gen)
+}
+
+// rawStream is a stream object read from an existing file. Its data is
+// still encoded with the filters named in its dictionary.
+type rawStream struct {
+	dict dictionary
+	data []byte
+}
+
+// parser reads PDF objects (PDF spec §7.3) from data, producing the same
+// values the package writes, so that objects read from a file can be
+// modified and written again.
+type parser struct {
+	data []byte
+	pos  int
+}
+
+func isWhitespace(c byte) bool {
+	return c == 0 || c == '\t' || c == '\n' || c == '\f' || c == '\r' || c == ' '
+}
+
+func isDelimiter(c byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+func isRegular(c byte) bool {
+	return !isWhitespace(c) && !isDelimiter(c)
+}
+
+// skipSpace skips whitespace and comments.
+func (p *parser) skipSpace() {
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if c == '%' {
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' && p.data[p.pos] != '\r' {
+				p.pos++
+			}
+		} else if isWhitespace(c) {
+			p.pos++
+		} else {
+			return
+		}
+	}
+}
+
+// keyword returns the run of regular characters at the current position.
+func (p *parser) keyword() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.data) && isRegular(p.data[p.pos]) {
+		p.pos++
+	}
+	return string(p.data[start:p.pos])
+}
+
+// expect consumes keyword kw or returns an error.
+func (p *parser) expect(kw string) error {
+	pos := p.pos
+	if got := p.keyword(); got != kw {
+		return fmt.Errorf("expected %q at offset %d, found %q", kw, pos, got)
+	}
+	return nil
+}
+
+// parseObject reads the next object. A number followed by a generation
+// number and "R" is read as an objectRef.
+func (p *parser) parseObject() (writer, error) {
+	p.skipSpace()
+	if p.pos >= len(p.data) {
+		return nil, errUnexpectedEOF
+	}
+	switch c := p.data[p.pos]; {
+	case c == '/':
+		return p.parseName(), nil
+	case c == '(':
+		return p.parseLiteralString()
+	case c == '<' && p.pos+1 < len(p.data) && p.data[p.pos+1] == '<':
+		return p.parseDictionary()
+	case c == '<':
+		return p.parseHexString()
+	case c == '[':
+		return p.parseArray()
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return p.parseNumberOrRef()
+	}
+	pos := p.pos
+	switch kw := p.keyword(); kw {
+	case "true":
+		return boolean(true), nil
+	case "false":
+		return boolean(false), nil
+	case "null":
+		return null{}, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q at offset %d", kw, pos)
+	}
+}
+
+func (p *parser) parseName() name {
+	p.pos++ // '/'
+	var buf bytes.Buffer
+	for p.pos < len(p.data) && isRegular(p.data[p.pos]) {
+		c := p.data[p.pos]
+		if c == '#' && p.pos+2 < len(p.data) {
+			if v, err := strconv.ParseUint(string(p.data[p.pos+1:p.pos+3]), 16, 8); err == nil {
+				buf.WriteByte(byte(v))
+				p.pos += 3
+				continue
+			}
+		}
+		buf.WriteByte(c)
+		p.pos++
+	}
+	return name(buf.String())
+}
+
+// stringValue returns s as a literal string if it is written back safely
+// that way, and otherwise as a hex string.
+func stringValue(s []byte) writer {
+	for _, c := range s {
+		if c < 0x20 && c != '\n' && c != '\t' || c >= 0x7F {
+			return hexString(s)
+		}
+	}
+	return str(s)
+}
+
+func (p *parser) parseLiteralString() (writer, error) {
+	p.pos++ // '('
+	var buf bytes.Buffer
+	depth := 1
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		p.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return stringValue(buf.Bytes()), nil
+			}
+		case '\r':
+			// An unescaped end-of-line is read as a line feed.
+			if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+				p.pos++
+			}
+			c = '\n'
+		case '\\':
+			if p.pos >= len(p.data) {
+				return nil, errUnexpectedEOF
+			}
+			c = p.data[p.pos]
+			p.pos++
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+					p.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if c >= '0' && c <= '7' {
+					v := int(c - '0')
+					for i := 0; i < 2 && p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '7'; i++ {
+						v = v*8 + int(p.data[p.pos]-'0')
+						p.pos++
+					}
+					c = byte(v)
+				}
+			}
+		}
+		buf.WriteByte(c)
+	}
+	return nil, errUnexpectedEOF
+}
+
+func (p *parser) parseHexString() (writer, error) {
+	p.pos++ // '<'
+	var digits []byte
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		p.pos++
+		if c == '>' {
+			if len(digits)%2 == 1 {
+				digits = append(digits, '0')
+			}
+			s := make([]byte, len(digits)/2)
+			for i := range s {
+				v, err := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+				if err != nil {
+					return nil, fmt.Errorf("invalid hex string at offset %d", p.pos)
+				}
+				s[i] = byte(v)
+			}
+			return hexString(s), nil
+		}
+		if !isWhitespace(c) {
+			digits = append(digits, c)
+		}
+	}
+	return nil, errUnexpectedEOF
+}
+
+func (p *parser) parseArray() (writer, error) {
+	p.pos++ // '['
+	a := array{}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return nil, errUnexpectedEOF
+		}
+		if p.data[p.pos] == ']' {
+			p.pos++
+			return a, nil
+		}
+		obj, err := p.parseObject()
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, obj)
+	}
+}
+
+func (p *parser) parseDictionary() (dictionary, error) {
+	p.pos += 2 // "<<"
+	d := dictionary{}
+	for {
+		p.skipSpace()
+		if p.pos+1 >= len(p.data) {
+			return nil, errUnexpectedEOF
+		}
+		if p.data[p.pos] == '>' && p.data[p.pos+1] == '>' {
+			p.pos += 2
+			return d, nil
+		}
+		if p.data[p.pos] != '/' {
+			return nil, fmt.Errorf("expected name at offset %d", p.pos)
+		}
+		key := p.parseName()
+		value, err := p.parseObject()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := value.(null); !ok {
+			d[string(key)] = value
+		}
+	}
+}
+
+func (p *parser) parseNumberOrRef() (writer, error) {
+	pos := p.pos
+	kw := p.keyword()
+	if n, err := strconv.Atoi(kw); err == nil {
+		// Look ahead for "gen R".
+		save := p.pos
+		if gen, err := strconv.Atoi(p.keyword()); err == nil && gen >= 0 && p.keyword() == "R" {
+			return objectRef{n, gen}, nil
+		}
+		p.pos = save
+		return integer(n), nil
+	}
+	if f, err := strconv.ParseFloat(kw, 64); err == nil {
+		return real(f), nil
+	}
+	return nil, fmt.Errorf("invalid number %q at offset %d", kw, pos)
+}
+
+// parseIndirectObject reads the object "seq gen obj ... endobj" at the
+// current position. Stream objects are returned as a *rawStream holding
+// the stream dictionary and data; length resolves an indirect /Length.
+func (p *parser) parseIndirectObject(seq int, length func(writer) (int, error)) (writer, *rawStream, error) {
+	pos := p.pos
+	if n, err := strconv.Atoi(p.keyword()); err != nil || n != seq {
+		return nil, nil, fmt.Errorf("object %d not found at offset %d", seq, pos)
+	}
+	if _, err := strconv.Atoi(p.keyword()); err != nil {
+		return nil, nil, fmt.Errorf("object %d not found at offset %d", seq, pos)
+	}
+	if err := p.expect("obj"); err != nil {
+		return nil, nil, err
+	}
+	obj, err := p.parseObject()
+	if err != nil {
+		return nil, nil, err
+	}
+	d, ok := obj.(dictionary)
+	if !ok {
+		return obj, nil, nil
+	}
+	save := p.pos
+	if p.keyword() != "stream" {
+		p.pos = save
+		return obj, nil, nil
+	}
+	// The keyword is followed by CRLF or LF before the data.
+	if p.pos < len(p.data) && p.data[p.pos] == '\r' {
+		p.pos++
+	}
+	if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+		p.pos++
+	}
+	n, err := length(d["Length"])
+	if err != nil {
+		return nil, nil, err
+	}
+	if n < 0 || p.pos+n > len(p.data) {
+		return nil, nil, fmt.Errorf("invalid length for stream %d", seq)
+	}
+	s := &rawStream{dict: d, data: p.data[p.pos : p.pos+n]}
+	p.pos += n
+	return d, s, nil
+}
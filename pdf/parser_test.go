@@ -0,0 +1,44 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import "testing"
+
+func TestParser_ParseObject(t *testing.T) {
+	for _, tc := range []struct {
+		src, want string
+	}{
+		{"/Name#20One", "/Name#20One "},
+		{"(a \\(b\\)\\n\\101)", "(a \\(b\\)\nA) "},
+		{"(\\376\\377)", "<FEFF> "},
+		{"(plain (nested) text)", "(plain \\(nested\\) text) "},
+		{"<48656C6C6F>", "<48656C6C6F> "},
+		{"<4>", "<40> "},
+		{"[1 -2.5 3 0 R true null]", "[1 -2.5 3 0 R true null ] "},
+		{"<< /A 1 /B null /C << /D /E >> >>", "<<\n/A 1 \n/C <<\n/D /E \n>>\n\n>>\n"},
+		{"% comment\n42", "42 "},
+	} {
+		p := &parser{data: []byte(tc.src)}
+		obj, err := p.parseObject()
+		if err != nil {
+			t.Errorf("%q: %v", tc.src, err)
+			continue
+		}
+		expectS(t, tc.want, stringFromWriter(obj))
+	}
+}
+
+func TestParser_ParseIndirectObject(t *testing.T) {
+	p := &parser{data: []byte("7 0 obj\n<< /Length 5 >>\nstream\r\nhello\nendstream\nendobj\n")}
+	obj, s, err := p.parseIndirectObject(7, func(v writer) (int, error) { return int(v.(integer)), nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFatal(t, s != nil, "Should read a stream")
+	expectS(t, "hello", string(s.data))
+	expectS(t, "<<\n/Length 5 \n>>\n", stringFromWriter(obj))
+
+	_, _, err = p.parseIndirectObject(8, nil)
+	check(t, err != nil, "Should report a missing object")
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/rowland/leadtype/rich_text"
+)
+
+// Conformance is a PDF/A conformance level (ISO 19005) a document can be
+// written to, for long-term archiving.
+type Conformance int
+
+const (
+	// NoConformance writes an ordinary PDF file.
+	NoConformance Conformance = iota
+	// PDFA2B is PDF/A-2b, which preserves the visual appearance of the document.
+	PDFA2B
+	// PDFA3B is PDF/A-3b, which also allows attaching files of any type, such
+	// as the data an invoice was produced from.
+	PDFA3B
+)
+
+var errPDFAEncryption = errors.New("PDF/A does not allow encryption")
+var errPDFAFont = errors.New("PDF/A requires embedded fonts; standard Type1 fonts cannot be embedded, use a TrueType or OpenType font")
+var errPDFACMYK = errors.New("PDF/A with an sRGB output intent does not allow CMYK or spot colors")
+var errPDFAAttachment = errors.New("PDF/A-2b does not allow attaching files; use PDF/A-3b")
+
+func (c Conformance) String() string {
+	switch c {
+	case PDFA2B:
+		return "PDF/A-2b"
+	case PDFA3B:
+		return "PDF/A-3b"
+	}
+	return "none"
+}
+
+// part is the part of ISO 19005 defining the conformance level.
+func (c Conformance) part() int {
+	switch c {
+	case PDFA2B:
+		return 2
+	case PDFA3B:
+		return 3
+	}
+	return 0
+}
+
+// xmpSchema returns the PDF/A identification schema (ISO 19005-2 §6.6.4)
+// declaring the conformance level in the document's metadata.
+func (c Conformance) xmpSchema() xmpSchema {
+	return xmpSchema{prefix: "pdfaid", uri: "http://www.aiim.org/pdfa/ns/id/", properties: []string{
+		xmpSimple("pdfaid:part", strconv.Itoa(c.part())),
+		xmpSimple("pdfaid:conformance", "B"),
+	}}
+}
+
+// SetConformance makes the document conform to a PDF/A level. The standard
+// Type1 fonts, which are referenced rather than embedded, are rejected when
+// added once it is set. The document gets an sRGB output
+// intent, XMP metadata identifying the level and a file identifier, and
+// annotations are made printable. Encryption, standard Type1 fonts and, with
+// the sRGB intent, CMYK and spot colors are not allowed; WriteTo and Close
+// return an error if they have been used.
+func (dw *DocWriter) SetConformance(conformance Conformance) *DocWriter {
+	dw.conformance = conformance
+	dw.file.header.binary = conformance != NoConformance
+	return dw
+}
+
+func (dw *DocWriter) Conformance() Conformance {
+	return dw.conformance
+}
+
+// checkConformance returns an error for any feature the document uses that
+// its conformance level forbids.
+func (dw *DocWriter) checkConformance() error {
+	if dw.conformance != NoConformance && dw.encryption != nil {
+		return errPDFAEncryption
+	}
+	if dw.conformance != NoConformance && dw.usesCMYK {
+		return errPDFACMYK
+	}
+	if dw.conformance != NoConformance && dw.usesType1Font() {
+		return errPDFAFont
+	}
+	return nil
+}
+
+// usesType1Font reports whether a standard Type1 font has been used, either
+// in text already written or in text still pending on a page.
+func (dw *DocWriter) usesType1Font() bool {
+	if dw.usesType1 {
+		return true
+	}
+	for _, pw := range dw.pages {
+		if pw.line == nil {
+			continue
+		}
+		found := false
+		pw.line.VisitAll(func(p *rich_text.RichText) {
+			if p.Font != nil && p.Font.SubType() == "Type1" {
+				found = true
+			}
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// writeOutputIntent adds the sRGB output intent PDF/A requires of documents
+// using device colors, along with the file identifier.
+func (dw *DocWriter) writeOutputIntent() {
+	if dw.conformance == NoConformance {
+		return
+	}
+	profile := newStream(dw.nextSeq(), 0, sRGBProfile())
+	profile.dict["N"] = integer(3)
+	if err := profile.compress(); err != nil {
+		panic(err)
+	}
+	dw.file.body.add(profile)
+	dw.catalog.dict["OutputIntents"] = array{dictionary{
+		"Type":                      name("OutputIntent"),
+		"S":                         name("GTS_PDFA1"),
+		"OutputConditionIdentifier": str("sRGB IEC61966-2.1"),
+		"RegistryName":              str("http://www.color.org"),
+		"Info":                      str("sRGB IEC61966-2.1"),
+		"DestOutputProfile":         &indirectObjectRef{profile},
+	}}
+	if dw.file.trailer.dict["ID"] == nil {
+		dw.file.trailer.setID(randomBytes(16))
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/rowland/leadtype/afm_fonts"
+)
+
+func TestSRGBProfile(t *testing.T) {
+	profile := sRGBProfile()
+	expectI(t, len(profile), int(binary.BigEndian.Uint32(profile)))
+	expectS(t, "mntrRGB XYZ ", string(profile[12:24]))
+	expectS(t, "acsp", string(profile[36:40]))
+	expectI(t, 9, int(binary.BigEndian.Uint32(profile[128:])))
+	tagOffset := func(i int) uint32 { return binary.BigEndian.Uint32(profile[132+12*i+4:]) }
+	expectS(t, "rTRC", string(profile[132+12*6:132+12*6+4]))
+	check(t, tagOffset(6) == tagOffset(7) && tagOffset(7) == tagOffset(8), "Channels should share the transfer curve")
+	for i := 0; i < 9; i++ {
+		check(t, tagOffset(i)%4 == 0, "Tag data should be aligned")
+	}
+}
+
+func TestDocWriter_SetConformance(t *testing.T) {
+	dw := NewDocWriter().SetConformance(PDFA2B)
+	expectS(t, "PDF/A-2b", dw.Conformance().String())
+	pw := dw.NewPage()
+	pw.LinkToURI(1, 1, 2, 1, "https://example.com")
+	var buf bytes.Buffer
+	if _, err := dw.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	check(t, strings.HasPrefix(s, "%PDF-1.4\n%\xE2\xE3\xCF\xD3\n"), "Header should be followed by a binary comment")
+	check(t, strings.Contains(s, "<pdfaid:part>2</pdfaid:part>\n<pdfaid:conformance>B</pdfaid:conformance>"), "XMP should identify the conformance level")
+	check(t, strings.Contains(s, "/OutputIntents [<<\n/DestOutputProfile "), "Catalog should have an output intent")
+	check(t, strings.Contains(s, "/S /GTS_PDFA1 "), "Output intent should be for PDF/A")
+	check(t, strings.Contains(s, "/N 3 "), "Output profile should be RGB")
+	check(t, strings.Contains(s, "/ID [<"), "Trailer should have a file identifier")
+	check(t, strings.Contains(stringFromWriter(pw.page.annots[0]), "/F 4 "), "Annotations should be printable")
+	check(t, dw.file.trailer.dict["Info"] == nil, "Empty information dictionary should be omitted")
+}
+
+func TestDocWriter_SetConformance_Type1Font(t *testing.T) {
+	dw := NewDocWriter().SetConformance(PDFA3B)
+	fonts, err := afm_fonts.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.AddFontSource(fonts)
+	dw.NewPage()
+	_, err = dw.SetFont("Helvetica", 12, nil)
+	check(t, err == errPDFAFont, "Standard Type1 fonts should be rejected")
+}
+
+func TestDocWriter_SetConformance_Type1FontAddedEarlier(t *testing.T) {
+	dw := NewDocWriter()
+	fonts, err := afm_fonts.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.AddFontSource(fonts)
+	dw.NewPage()
+	if _, err := dw.SetFont("Helvetica", 12, nil); err != nil {
+		t.Fatal(err)
+	}
+	dw.SetConformance(PDFA2B)
+	dw.Print("Hello")
+	var buf bytes.Buffer
+	_, err = dw.WriteTo(&buf)
+	check(t, err == errPDFAFont, "Standard Type1 fonts added before SetConformance should be rejected")
+	check(t, buf.Len() == 0, "Nothing should be written")
+}
+
+func TestDocWriter_SetConformance_Encryption(t *testing.T) {
+	dw := NewDocWriter().SetConformance(PDFA2B)
+	dw.SetEncryption(AES128, "user", "owner", PermitAll)
+	var buf bytes.Buffer
+	_, err := dw.WriteTo(&buf)
+	check(t, err == errPDFAEncryption, "Encryption should be rejected")
+	check(t, buf.Len() == 0, "Nothing should be written")
+}
@@ -0,0 +1,542 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+var errNoStartXRef = errors.New("startxref not found; not a PDF file")
+var errXRefLoop = errors.New("cross-reference sections refer to each other in a loop")
+
+// xrefEntry locates an object of an existing file: for type 1, at a byte
+// offset; for type 2, at an index within an object stream. Type 0 entries
+// mark free objects.
+type xrefEntry struct {
+	typ, offset, gen int
+}
+
+// pdfReader reads the objects of an existing PDF file through its
+// cross-reference sections, which may be tables or streams.
+type pdfReader struct {
+	data    []byte
+	version string
+	xref    map[int]xrefEntry
+	trailer dictionary
+	// startXRef is the offset of the newest cross-reference section, the
+	// /Prev of any update.
+	startXRef int
+	// xrefStreams reports whether the newest section is a stream.
+	xrefStreams   bool
+	objectStreams map[int]*objectStreamReader
+}
+
+// objectStreamReader holds the decoded contents of an object stream.
+type objectStreamReader struct {
+	data    []byte
+	offsets []int
+}
+
+func newPDFReader(data []byte) (*pdfReader, error) {
+	r := &pdfReader{data: data, xref: make(map[int]xrefEntry), objectStreams: make(map[int]*objectStreamReader)}
+	if bytes.HasPrefix(data, []byte("%PDF-")) {
+		end := bytes.IndexAny(data, "\r\n")
+		if end < 0 {
+			end = len(data)
+		}
+		r.version = string(bytes.TrimSpace(data[5:end]))
+	}
+	i := bytes.LastIndex(data, []byte("startxref"))
+	if i < 0 {
+		return nil, errNoStartXRef
+	}
+	p := &parser{data: data, pos: i + len("startxref")}
+	offset, err := strconv.Atoi(p.keyword())
+	if err != nil {
+		return nil, errNoStartXRef
+	}
+	r.startXRef = offset
+	if err := r.readXRef(offset, make(map[int]bool)); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// readXRef reads the cross-reference section at offset and those preceding
+// it. Entries already read, from newer sections, take precedence.
+func (r *pdfReader) readXRef(offset int, seen map[int]bool) error {
+	if seen[offset] {
+		return errXRefLoop
+	}
+	seen[offset] = true
+	if offset < 0 || offset >= len(r.data) {
+		return fmt.Errorf("cross-reference offset %d out of range", offset)
+	}
+	p := &parser{data: r.data, pos: offset}
+	var trailer dictionary
+	var err error
+	if save := p.pos; p.keyword() == "xref" {
+		trailer, err = r.readXRefTable(p)
+	} else {
+		p.pos = save
+		trailer, err = r.readXRefStream(p)
+		if r.trailer == nil {
+			r.xrefStreams = true
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if r.trailer == nil {
+		r.trailer = trailer
+	}
+	// A hybrid file's table refers to a stream with the compressed objects.
+	if stm, ok := trailer["XRefStm"].(integer); ok {
+		if err := r.readXRef(int(stm), seen); err != nil {
+			return err
+		}
+	}
+	if prev, ok := trailer["Prev"].(integer); ok {
+		return r.readXRef(int(prev), seen)
+	}
+	return nil
+}
+
+func (r *pdfReader) setEntry(seq int, e xrefEntry) {
+	if _, ok := r.xref[seq]; !ok {
+		r.xref[seq] = e
+	}
+}
+
+func (r *pdfReader) readXRefTable(p *parser) (dictionary, error) {
+	for {
+		save := p.pos
+		kw := p.keyword()
+		if kw == "trailer" {
+			p.skipSpace()
+			return p.parseDictionary()
+		}
+		start, err1 := strconv.Atoi(kw)
+		count, err2 := strconv.Atoi(p.keyword())
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("invalid cross-reference table at offset %d", save)
+		}
+		for i := 0; i < count; i++ {
+			offset, err1 := strconv.Atoi(p.keyword())
+			gen, err2 := strconv.Atoi(p.keyword())
+			kind := p.keyword()
+			if err1 != nil || err2 != nil || (kind != "n" && kind != "f") {
+				return nil, fmt.Errorf("invalid cross-reference entry for object %d", start+i)
+			}
+			if kind == "n" {
+				r.setEntry(start+i, xrefEntry{1, offset, gen})
+			} else {
+				r.setEntry(start+i, xrefEntry{0, 0, gen})
+			}
+		}
+	}
+}
+
+func (r *pdfReader) readXRefStream(p *parser) (dictionary, error) {
+	save := p.pos
+	seq, err := strconv.Atoi(p.keyword())
+	if err != nil {
+		return nil, fmt.Errorf("invalid cross-reference section at offset %d", save)
+	}
+	p.pos = save
+	_, s, err := p.parseIndirectObject(seq, r.length)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil || s.dict["Type"] != name("XRef") {
+		return nil, fmt.Errorf("object %d is not a cross-reference stream", seq)
+	}
+	data, err := decodeStream(s)
+	if err != nil {
+		return nil, err
+	}
+	w, _ := s.dict["W"].(array)
+	if len(w) != 3 {
+		return nil, fmt.Errorf("invalid /W in cross-reference stream %d", seq)
+	}
+	widths := make([]int, 3)
+	for i := range widths {
+		n, _ := w[i].(integer)
+		widths[i] = int(n)
+	}
+	size, _ := s.dict["Size"].(integer)
+	index, ok := s.dict["Index"].(array)
+	if !ok {
+		index = array{integer(0), size}
+	}
+	field := func(b []byte, def int) int {
+		if len(b) == 0 {
+			return def
+		}
+		v := 0
+		for _, c := range b {
+			v = v<<8 | int(c)
+		}
+		return v
+	}
+	entrySize := widths[0] + widths[1] + widths[2]
+	pos := 0
+	for i := 0; i+1 < len(index); i += 2 {
+		start, _ := index[i].(integer)
+		count, _ := index[i+1].(integer)
+		for j := 0; j < int(count); j++ {
+			if pos+entrySize > len(data) {
+				return nil, fmt.Errorf("cross-reference stream %d is truncated", seq)
+			}
+			e := data[pos : pos+entrySize]
+			pos += entrySize
+			typ := field(e[:widths[0]], 1)
+			f2 := field(e[widths[0]:widths[0]+widths[1]], 0)
+			f3 := field(e[widths[0]+widths[1]:], 0)
+			r.setEntry(int(start)+j, xrefEntry{typ, f2, f3})
+		}
+	}
+	return s.dict, nil
+}
+
+// length returns the value of a stream's /Length entry, which may be an
+// indirect reference.
+func (r *pdfReader) length(v writer) (int, error) {
+	v, err := r.resolve(v)
+	if err != nil {
+		return 0, err
+	}
+	if n, ok := v.(integer); ok {
+		return int(n), nil
+	}
+	return 0, errors.New("stream has no valid /Length")
+}
+
+// object returns object seq as a value, or its dictionary if it is a stream.
+func (r *pdfReader) object(seq int) (writer, error) {
+	obj, _, err := r.readObject(seq)
+	return obj, err
+}
+
+// stream returns object seq, which must be a stream.
+func (r *pdfReader) stream(seq int) (*rawStream, error) {
+	_, s, err := r.readObject(seq)
+	if err == nil && s == nil {
+		err = fmt.Errorf("object %d is not a stream", seq)
+	}
+	return s, err
+}
+
+func (r *pdfReader) readObject(seq int) (writer, *rawStream, error) {
+	e, ok := r.xref[seq]
+	switch {
+	case !ok || e.typ == 0:
+		return null{}, nil, nil
+	case e.typ == 2:
+		obj, err := r.compressedObject(e.offset, e.gen)
+		return obj, nil, err
+	}
+	if e.offset < 0 || e.offset >= len(r.data) {
+		return nil, nil, fmt.Errorf("object %d offset out of range", seq)
+	}
+	p := &parser{data: r.data, pos: e.offset}
+	return p.parseIndirectObject(seq, r.length)
+}
+
+// compressedObject returns the object at index within object stream seq.
+func (r *pdfReader) compressedObject(seq, index int) (writer, error) {
+	os, ok := r.objectStreams[seq]
+	if !ok {
+		s, err := r.stream(seq)
+		if err != nil {
+			return nil, err
+		}
+		data, err := decodeStream(s)
+		if err != nil {
+			return nil, err
+		}
+		n, _ := s.dict["N"].(integer)
+		first, _ := s.dict["First"].(integer)
+		os = &objectStreamReader{data: data}
+		p := &parser{data: data}
+		for i := 0; i < int(n); i++ {
+			p.keyword() // object number
+			offset, err := strconv.Atoi(p.keyword())
+			if err != nil {
+				return nil, fmt.Errorf("invalid object stream %d", seq)
+			}
+			os.offsets = append(os.offsets, int(first)+offset)
+		}
+		r.objectStreams[seq] = os
+	}
+	if index < 0 || index >= len(os.offsets) || os.offsets[index] >= len(os.data) {
+		return nil, fmt.Errorf("object %d of object stream %d not found", index, seq)
+	}
+	p := &parser{data: os.data, pos: os.offsets[index]}
+	return p.parseObject()
+}
+
+// resolve returns the object v refers to, or v itself if it is not a
+// reference.
+func (r *pdfReader) resolve(v writer) (writer, error) {
+	if ref, ok := v.(objectRef); ok {
+		return r.object(ref.seq)
+	}
+	return v, nil
+}
+
+// resolveDict returns the dictionary v is or refers to, or nil.
+func (r *pdfReader) resolveDict(v writer) (dictionary, error) {
+	v, err := r.resolve(v)
+	if err != nil {
+		return nil, err
+	}
+	d, _ := v.(dictionary)
+	return d, nil
+}
+
+// existingPage is a page of an existing file, with the attributes it
+// inherits from the page tree resolved.
+type existingPage struct {
+	ref       objectRef
+	dict      dictionary
+	resources dictionary
+	mediaBox  rectangle
+	cropBox   rectangle
+	rotate    int
+	// contents and xobjects are resolved when the page is edited.
+	contents array
+	xobjects dictionary
+}
+
+// readPages returns the pages of the page tree rooted at node, which is
+// object ref, in order, along with the root's resolved /Kids.
+func (r *pdfReader) readPages(ref objectRef, node dictionary) (array, []*existingPage, error) {
+	var pages []*existingPage
+	kids, err := r.readPageNode(node, dictionary{}, map[int]bool{ref.seq: true}, &pages)
+	return kids, pages, err
+}
+
+// readPageNode adds the pages below node to pages and returns the node's
+// resolved /Kids. inherited holds the inheritable attributes of the node's
+// ancestors.
+func (r *pdfReader) readPageNode(node, inherited dictionary, seen map[int]bool, pages *[]*existingPage) (array, error) {
+	attrs := inherited.clone()
+	for _, key := range []string{"Resources", "MediaBox", "CropBox", "Rotate"} {
+		if v, ok := node[key]; ok {
+			attrs[key] = v
+		}
+	}
+	v, err := r.resolve(node["Kids"])
+	if err != nil {
+		return nil, err
+	}
+	kids, _ := v.(array)
+	for _, kid := range kids {
+		ref, ok := kid.(objectRef)
+		if !ok {
+			continue
+		}
+		if seen[ref.seq] {
+			return nil, errors.New("page tree refers to itself")
+		}
+		seen[ref.seq] = true
+		d, err := r.resolveDict(ref)
+		if err != nil {
+			return nil, err
+		}
+		if d == nil {
+			continue
+		}
+		if _, isNode := d["Kids"]; isNode || d["Type"] == name("Pages") {
+			if _, err := r.readPageNode(d, attrs, seen, pages); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		page, err := r.readPage(ref, d, attrs)
+		if err != nil {
+			return nil, err
+		}
+		*pages = append(*pages, page)
+	}
+	return kids, nil
+}
+
+func (r *pdfReader) readPage(ref objectRef, d, inherited dictionary) (*existingPage, error) {
+	attrs := inherited.clone()
+	for k, v := range d {
+		attrs[k] = v
+	}
+	ep := &existingPage{ref: ref, dict: d, mediaBox: rectangle{0, 0, 612, 792}}
+	var err error
+	if ep.resources, err = r.resolveDict(attrs["Resources"]); err != nil {
+		return nil, err
+	}
+	if ep.resources == nil {
+		ep.resources = dictionary{}
+	}
+	v, err := r.resolve(attrs["MediaBox"])
+	if err != nil {
+		return nil, err
+	}
+	if box, ok := rectangleValue(v); ok {
+		ep.mediaBox = box
+	}
+	ep.cropBox = ep.mediaBox
+	if v, err = r.resolve(attrs["CropBox"]); err != nil {
+		return nil, err
+	}
+	if box, ok := rectangleValue(v); ok {
+		// The crop box is clipped to the media box.
+		ep.cropBox = rectangle{
+			max(box.x1, ep.mediaBox.x1), max(box.y1, ep.mediaBox.y1),
+			min(box.x2, ep.mediaBox.x2), min(box.y2, ep.mediaBox.y2),
+		}
+	}
+	if v, err = r.resolve(attrs["Rotate"]); err != nil {
+		return nil, err
+	}
+	if rotate, ok := v.(integer); ok {
+		ep.rotate = (int(rotate)%360 + 360) % 360
+	}
+	return ep, nil
+}
+
+// contents returns the references to the content streams of ep.
+func (r *pdfReader) contents(ep *existingPage) (array, error) {
+	switch v := ep.dict["Contents"].(type) {
+	case array:
+		return v, nil
+	case objectRef:
+		obj, err := r.object(v.seq)
+		if err != nil {
+			return nil, err
+		}
+		if a, ok := obj.(array); ok {
+			return a, nil
+		}
+		return array{v}, nil
+	}
+	return nil, nil
+}
+
+// rectangleValue returns the rectangle an array of four numbers describes.
+func rectangleValue(v writer) (rectangle, bool) {
+	a, ok := v.(array)
+	if !ok || len(a) != 4 {
+		return rectangle{}, false
+	}
+	var f [4]float64
+	for i, n := range a {
+		switch n := n.(type) {
+		case integer:
+			f[i] = float64(n)
+		case real:
+			f[i] = float64(n)
+		default:
+			return rectangle{}, false
+		}
+	}
+	return rectangle{min(f[0], f[2]), min(f[1], f[3]), max(f[0], f[2]), max(f[1], f[3])}, true
+}
+
+// decodeStream returns the data of s decoded with its filters. Only
+// FlateDecode, with or without PNG predictors, is supported.
+func decodeStream(s *rawStream) ([]byte, error) {
+	var filters, params array
+	switch f := s.dict["Filter"].(type) {
+	case name:
+		filters = array{f}
+		params = array{s.dict["DecodeParms"]}
+	case array:
+		filters = f
+		params, _ = s.dict["DecodeParms"].(array)
+	}
+	data := s.data
+	for i, f := range filters {
+		if f != name("FlateDecode") {
+			return nil, fmt.Errorf("unsupported stream filter %v", f)
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if data, err = io.ReadAll(zr); err != nil {
+			return nil, err
+		}
+		var dp dictionary
+		if i < len(params) {
+			dp, _ = params[i].(dictionary)
+		}
+		if predictor, _ := dp["Predictor"].(integer); predictor >= 10 {
+			columns, ok := dp["Columns"].(integer)
+			if !ok {
+				columns = 1
+			}
+			if data, err = unpredictPNG(data, int(columns)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return data, nil
+}
+
+// unpredictPNG reverses PNG prediction of rows of the given width, each
+// preceded by its filter type byte.
+func unpredictPNG(data []byte, columns int) ([]byte, error) {
+	rowSize := columns + 1
+	if len(data)%rowSize != 0 {
+		return nil, errors.New("predicted data is not a whole number of rows")
+	}
+	out := make([]byte, 0, len(data)/rowSize*columns)
+	prev := make([]byte, columns)
+	for row := 0; row < len(data); row += rowSize {
+		filter, cur := data[row], append([]byte(nil), data[row+1:row+rowSize]...)
+		for i := range cur {
+			var left, upLeft byte
+			if i > 0 {
+				left, upLeft = cur[i-1], prev[i-1]
+			}
+			up := prev[i]
+			switch filter {
+			case 1:
+				cur[i] += left
+			case 2:
+				cur[i] += up
+			case 3:
+				cur[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				cur[i] += paeth(left, up, upLeft)
+			}
+		}
+		out = append(out, cur...)
+		prev = cur
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	}
+	return c
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
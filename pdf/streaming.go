@@ -0,0 +1,112 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+var errStreaming = errors.New("document is being streamed; finish it with Close")
+var errNotStreaming = errors.New("document is not being streamed; write it with WriteTo")
+var errLateEncryption = errors.New("encryption must be set before streaming begins")
+
+// StreamTo switches the document to streaming output. The file header is
+// written to w immediately, and each page is written as soon as the next page
+// is started, so that memory use does not grow with the number of pages.
+// Fonts, the page tree and other shared objects are written when the document
+// is finished with Close, which takes the place of WriteTo.
+//
+// In streaming mode, pages can only be added at the end: NewPageAfter returns
+// nil for any page but the last, and a page must not be drawn on once a newer
+// page has been started. Encryption must be set before calling StreamTo.
+func (dw *DocWriter) StreamTo(w io.Writer) *DocWriter {
+	// Features requiring a later version may be used after the header is
+	// written; the catalog's /Version entry, honored from PDF 1.4, covers them.
+	dw.requireVersion(1.4)
+	dw.writeEncryption()
+	dw.file.begin(w)
+	dw.streamedVersion = dw.file.header.Version
+	return dw
+}
+
+// Close finishes a document started with StreamTo, writing the remaining
+// pages and the shared objects, cross-reference section and trailer.
+func (dw *DocWriter) Close() error {
+	if dw.file.out == nil {
+		return errNotStreaming
+	}
+	if dw.update != nil {
+		return errUpdateStreaming
+	}
+	if dw.encryption != nil && dw.file.body.security == nil {
+		return errLateEncryption
+	}
+	if dw.pageErr != nil {
+		return dw.pageErr
+	}
+	if err := dw.checkImposition(); err != nil {
+		return err
+	}
+	if err := dw.checkConformance(); err != nil {
+		return err
+	}
+	dw.streamPages()
+	dw.file.body.removeStreamed()
+	if err := dw.finishBody(); err != nil {
+		return err
+	}
+	if dw.file.header.Version > dw.streamedVersion {
+		dw.catalog.dict["Version"] = name(fmt.Sprintf("%1.1f", dw.file.header.Version))
+	}
+	return dw.file.finish()
+}
+
+// streamPages writes every page not yet streamed, together with its content
+// streams, annotations and any images added so far, and releases their data.
+// The page writers are dropped, leaving only references to their pages in
+// the page tree, so that memory use does not grow with the number of pages.
+func (dw *DocWriter) streamPages() {
+	if dw.file.out == nil || len(dw.pages) == 0 {
+		return
+	}
+	var objs genWriterArray
+	for _, pw := range dw.pages {
+		pw.close()
+		objs = append(objs, pw.page)
+		for _, s := range pw.page.contents {
+			objs = append(objs, s)
+		}
+		for _, a := range pw.page.annots {
+			objs = append(objs, a)
+		}
+	}
+	for _, img := range dw.unstreamedImages {
+		objs = append(objs, img)
+	}
+	dw.streamPageLabels()
+	dw.catalog.pages.release(dw.streamedPages)
+	dw.streamedPages += len(dw.pages)
+	clear(dw.pages)
+	dw.pages = dw.pages[:0]
+	dw.unstreamedImages = nil
+	dw.file.writeObjects(objs...)
+	dw.file.body.removeStreamed()
+	for _, e := range objs {
+		switch e := e.(type) {
+		case *stream:
+			e.data = nil
+		case *pdfImage:
+			e.data = nil
+		}
+	}
+}
+
+// addUnstreamedImage queues img to be streamed with the current page.
+func (dw *DocWriter) addUnstreamedImage(img *pdfImage) {
+	if dw.file.out != nil {
+		dw.unstreamedImages = append(dw.unstreamedImages, img)
+	}
+}
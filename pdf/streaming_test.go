@@ -0,0 +1,176 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rowland/leadtype/afm_fonts"
+)
+
+// checkXRefTable verifies that every in-use entry of the classic
+// cross-reference table in pdf points at the start of its object.
+func checkXRefTable(t *testing.T, pdf []byte) {
+	t.Helper()
+	m := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(pdf)
+	if m == nil {
+		t.Fatal("missing startxref")
+	}
+	start, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(pdf[start:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", start)
+	}
+	lines := strings.Split(string(pdf[start:]), "\n")
+	var first, count int
+	fmt.Sscanf(lines[1], "%d %d", &first, &count)
+	for i := 0; i < count; i++ {
+		var offset, gen int
+		var kind string
+		fmt.Sscanf(lines[2+i], "%d %d %s", &offset, &gen, &kind)
+		if kind != "n" {
+			continue
+		}
+		if !bytes.HasPrefix(pdf[offset:], []byte(fmt.Sprintf("%d %d obj\n", first+i, gen))) {
+			t.Fatalf("xref entry %d points at offset %d, which is not its object", first+i, offset)
+		}
+	}
+}
+
+func TestDocWriter_StreamTo(t *testing.T) {
+	var buf bytes.Buffer
+	dw := NewDocWriter().CompressPages(false)
+	fonts, err := afm_fonts.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.AddFontSource(fonts)
+	dw.StreamTo(&buf)
+	check(t, strings.HasPrefix(buf.String(), "%PDF-1.4\n"), "Header should be written immediately")
+
+	first := dw.NewPage()
+	dw.SetFont("Helvetica", 12, nil)
+	dw.Print("Page one")
+	check(t, !strings.Contains(buf.String(), "Page one"), "Current page should not be written yet")
+	dw.NewPage()
+	check(t, strings.Contains(buf.String(), "(Page one) Tj"), "Finished page should be written when the next begins")
+	check(t, first.page.contents[0].data == nil, "Streamed content should be released")
+	dw.Print("Page two")
+	dw.NewPage()
+	dw.Print("Page three")
+
+	if err := dw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.Bytes()
+	check(t, bytes.Contains(pdf, []byte("(Page three) Tj")), "Last page should be written by Close")
+	check(t, bytes.Contains(pdf, []byte("/Count 3 \n")), "Page tree should list every page")
+	check(t, bytes.Index(pdf, []byte("/Type /Pages")) > bytes.Index(pdf, []byte("(Page three) Tj")), "Page tree should be written last")
+	checkXRefTable(t, pdf)
+}
+
+// liveHeap returns the bytes of heap memory in use after a collection.
+func liveHeap() uint64 {
+	var ms runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&ms)
+	return ms.HeapAlloc
+}
+
+func TestDocWriter_StreamTo_Memory(t *testing.T) {
+	dw := NewDocWriter().CompressPages(false)
+	fonts, err := afm_fonts.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.AddFontSource(fonts)
+	dw.StreamTo(io.Discard)
+	addPages := func(n int) {
+		for i := 0; i < n; i++ {
+			dw.NewPage()
+			dw.SetFont("Helvetica", 12, nil)
+			for line := 0; line < 50; line++ {
+				dw.MoveTo(36, float64(36+line*14))
+				dw.Print("The quick brown fox jumps over the lazy dog.")
+			}
+		}
+	}
+	addPages(200)
+	before := liveHeap()
+	addPages(1000)
+	after := liveHeap()
+	if after > before && (after-before)/1000 > 512 {
+		t.Errorf("Live heap grew from %d to %d bytes over 1000 pages", before, after)
+	}
+	if err := dw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDocWriter_StreamTo_NewPageAfter(t *testing.T) {
+	var buf bytes.Buffer
+	dw := NewDocWriter().StreamTo(&buf)
+	first := dw.NewPage()
+	second := dw.NewPage()
+	check(t, dw.NewPageAfter(first) == nil, "Should not insert before streamed pages")
+	check(t, dw.NewPageAfter(second) != nil, "Should append after the last page")
+	if err := dw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	checkXRefTable(t, buf.Bytes())
+}
+
+func TestDocWriter_StreamTo_Errors(t *testing.T) {
+	var buf bytes.Buffer
+	check(t, NewDocWriter().Close() == errNotStreaming, "Close requires streaming")
+
+	dw := NewDocWriter().StreamTo(&buf)
+	_, err := dw.WriteTo(&buf)
+	check(t, err == errStreaming, "WriteTo should be refused while streaming")
+
+	dw.SetEncryption(AES128, "", "owner", PermitPrint)
+	check(t, dw.Close() == errLateEncryption, "Encryption must precede streaming")
+}
+
+func TestDocWriter_StreamTo_Version(t *testing.T) {
+	var buf bytes.Buffer
+	dw := NewDocWriter().StreamTo(&buf)
+	dw.NewPage()
+	dw.SetFillGradient(nil)
+	dw.CompressObjects(true)
+	if err := dw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	check(t, strings.HasPrefix(buf.String(), "%PDF-1.4\n"), "Header is written before later features are known")
+	check(t, dw.catalog.dict["Version"] != nil, "Catalog should declare the raised version")
+	expectS(t, "/1.5 ", stringFromWriter(dw.catalog.dict["Version"]))
+}
+
+func TestDocWriter_StreamTo_Encrypted(t *testing.T) {
+	var buf bytes.Buffer
+	dw := NewDocWriter().CompressPages(false).SetEncryption(AES256, "user", "owner", PermitPrint)
+	fonts, err := afm_fonts.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.AddFontSource(fonts)
+	dw.StreamTo(&buf)
+	dw.NewPage()
+	dw.SetFont("Helvetica", 12, nil)
+	dw.Print("Salary")
+	dw.NewPage()
+	if err := dw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.Bytes()
+	check(t, bytes.HasPrefix(pdf, []byte("%PDF-2.0\n")), "Encryption set before streaming should raise the header")
+	check(t, !bytes.Contains(pdf, []byte("Salary")), "Streamed pages should be encrypted")
+	checkXRefTable(t, pdf)
+}
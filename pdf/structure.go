@@ -0,0 +1,314 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"errors"
+	"io"
+
+	"github.com/rowland/leadtype/options"
+)
+
+var errEmptyRole = errors.New("structure type must not be empty")
+var errNoOpenTag = errors.New("EndTag without matching BeginTag")
+var errNoOpenArtifact = errors.New("EndArtifact without matching BeginArtifact")
+
+// structTreeRoot is the root of a tagged document's logical structure
+// (PDF spec §14.7.2). Its kids are the top-level structure elements.
+type structTreeRoot struct {
+	dictionaryObject
+	kids       array
+	parentTree *parentTree
+}
+
+func newStructTreeRoot(seq, gen int, parentTree *parentTree) *structTreeRoot {
+	root := &structTreeRoot{parentTree: parentTree}
+	root.dictionaryObject.init(seq, gen)
+	root.dict["Type"] = name("StructTreeRoot")
+	root.dict["ParentTree"] = &indirectObjectRef{parentTree}
+	return root
+}
+
+func (root *structTreeRoot) add(elem *structElem) {
+	root.kids = append(root.kids, &indirectObjectRef{elem})
+}
+
+func (root *structTreeRoot) write(w io.Writer) {
+	root.dict["K"] = root.kids
+	root.dict["ParentTreeNextKey"] = integer(len(root.parentTree.pages))
+	root.dictionaryObject.write(w)
+}
+
+// structElem is an element of the structure tree. Its kids, in reading
+// order, are child elements and the marked-content sequences it owns.
+type structElem struct {
+	dictionaryObject
+	role string
+	kids array
+	page *page
+}
+
+func newStructElem(seq, gen int, role string, parent seqGen) *structElem {
+	elem := &structElem{role: role}
+	elem.dictionaryObject.init(seq, gen)
+	elem.dict["Type"] = name("StructElem")
+	elem.dict["S"] = name(role)
+	elem.dict["P"] = &indirectObjectRef{parent}
+	return elem
+}
+
+func (elem *structElem) add(child *structElem) {
+	elem.kids = append(elem.kids, &indirectObjectRef{child})
+}
+
+// addContent records that the marked-content sequence mcid of page pg
+// belongs to the element. The element's /Pg is the page of its first
+// sequence; sequences on other pages are referenced with their page.
+func (elem *structElem) addContent(pg *page, mcid int) {
+	if elem.page == nil {
+		elem.page = pg
+		elem.dict["Pg"] = &indirectObjectRef{pg}
+	}
+	if pg == elem.page {
+		elem.kids = append(elem.kids, integer(mcid))
+		return
+	}
+	elem.kids = append(elem.kids, dictionary{
+		"Type": name("MCR"),
+		"Pg":   &indirectObjectRef{pg},
+		"MCID": integer(mcid),
+	})
+}
+
+func (elem *structElem) setOptions(options options.Options) {
+	for option, key := range map[string]string{"alt": "Alt", "actual_text": "ActualText", "lang": "Lang", "title": "T"} {
+		if s := options.StringDefault(option, ""); s != "" {
+			elem.dict[key] = textString(s)
+		}
+	}
+}
+
+func (elem *structElem) write(w io.Writer) {
+	if len(elem.kids) > 0 {
+		elem.dict["K"] = elem.kids
+	}
+	elem.dictionaryObject.write(w)
+}
+
+// markedPage lists the structure elements owning the marked-content
+// sequences of one page, indexed by MCID. Its key is the page's
+// /StructParents entry.
+type markedPage struct {
+	key   int
+	elems []*structElem
+}
+
+// parentTree is the number tree mapping each marked page's key to its
+// marked-content owners, letting readers find the element for any content.
+type parentTree struct {
+	dictionaryObject
+	pages []*markedPage
+}
+
+func newParentTree(seq, gen int) *parentTree {
+	pt := new(parentTree)
+	pt.dictionaryObject.init(seq, gen)
+	return pt
+}
+
+func (pt *parentTree) addPage() *markedPage {
+	mp := &markedPage{key: len(pt.pages)}
+	pt.pages = append(pt.pages, mp)
+	return mp
+}
+
+func (pt *parentTree) write(w io.Writer) {
+	nums := make(array, 0, len(pt.pages)*2)
+	for _, mp := range pt.pages {
+		owners := make(array, len(mp.elems))
+		for i, elem := range mp.elems {
+			owners[i] = &indirectObjectRef{elem}
+		}
+		nums = append(nums, integer(mp.key), owners)
+	}
+	pt.dict["Nums"] = nums
+	pt.dictionaryObject.write(w)
+}
+
+// openTag is an entry in the document's stack of open tags and artifacts.
+// Artifacts have no structure element; their properties, if any, are
+// written with the marked-content sequence.
+type openTag struct {
+	elem       *structElem
+	properties dictionary
+}
+
+// structTree returns the document's structure tree root, creating it and
+// marking the document as tagged on first use.
+func (dw *DocWriter) structTree() *structTreeRoot {
+	if dw.structTreeRoot == nil {
+		pt := newParentTree(dw.nextSeq(), 0)
+		dw.file.body.add(pt)
+		dw.structTreeRoot = newStructTreeRoot(dw.nextSeq(), 0, pt)
+		dw.file.body.add(dw.structTreeRoot)
+		dw.catalog.dict["StructTreeRoot"] = &indirectObjectRef{dw.structTreeRoot}
+		dw.catalog.dict["MarkInfo"] = dictionary{"Marked": boolean(true)}
+		dw.requireVersion(1.4)
+	}
+	return dw.structTreeRoot
+}
+
+// Tagged reports whether the document has a structure tree, that is,
+// whether BeginTag has been called.
+func (dw *DocWriter) Tagged() bool {
+	return dw.structTreeRoot != nil
+}
+
+// SetLanguage sets the document's natural language, such as "en-US", used
+// by screen readers and other assistive technology.
+func (dw *DocWriter) SetLanguage(lang string) *DocWriter {
+	dw.catalog.dict["Lang"] = textString(lang)
+	return dw
+}
+
+func (dw *DocWriter) topTag() *openTag {
+	if len(dw.tags) == 0 {
+		return nil
+	}
+	return dw.tags[len(dw.tags)-1]
+}
+
+// BeginTag starts a structure element of the given standard structure
+// type, such as "P", "H1", "Table", "TR", "TD" or "Figure", as a child of
+// the innermost open element. Content drawn until the matching EndTag,
+// on the current or later pages, belongs to the element. Supported options are
+// "alt", "actual_text", "lang" and "title". Tags begun inside an artifact
+// are part of the artifact.
+func (dw *DocWriter) BeginTag(role string, options options.Options) error {
+	if role == "" {
+		return errEmptyRole
+	}
+	dw.endMarkedContent()
+	top := dw.topTag()
+	if top != nil && top.elem == nil {
+		dw.tags = append(dw.tags, top)
+		return nil
+	}
+	root := dw.structTree()
+	var elem *structElem
+	if top == nil {
+		elem = newStructElem(dw.nextSeq(), 0, role, root)
+		root.add(elem)
+	} else {
+		elem = newStructElem(dw.nextSeq(), 0, role, top.elem)
+		top.elem.add(elem)
+	}
+	elem.setOptions(options)
+	dw.file.body.add(elem)
+	dw.tags = append(dw.tags, &openTag{elem: elem})
+	return nil
+}
+
+// EndTag ends the innermost element started with BeginTag.
+func (dw *DocWriter) EndTag() error {
+	if len(dw.tags) == 0 {
+		return errNoOpenTag
+	}
+	dw.endMarkedContent()
+	dw.tags = dw.tags[:len(dw.tags)-1]
+	return nil
+}
+
+// BeginArtifact starts content that is not part of the document's logical
+// structure, such as page headers and footers, backgrounds and decorative
+// rules. The "type" option may be "Pagination", "Layout", "Page" or
+// "Background", and "subtype" may be "Header", "Footer" or "Watermark".
+func (dw *DocWriter) BeginArtifact(options options.Options) error {
+	dw.endMarkedContent()
+	properties := dictionary{}
+	if typ := options.StringDefault("type", ""); typ != "" {
+		properties["Type"] = name(typ)
+	}
+	if subtype := options.StringDefault("subtype", ""); subtype != "" {
+		properties["Subtype"] = name(subtype)
+	}
+	dw.tags = append(dw.tags, &openTag{properties: properties})
+	return nil
+}
+
+// EndArtifact ends the innermost artifact started with BeginArtifact.
+func (dw *DocWriter) EndArtifact() error {
+	if top := dw.topTag(); top == nil || top.elem != nil {
+		return errNoOpenArtifact
+	}
+	dw.endMarkedContent()
+	dw.tags = dw.tags[:len(dw.tags)-1]
+	return nil
+}
+
+// endMarkedContent ends the current page's open marked-content sequence
+// before the innermost open tag changes.
+func (dw *DocWriter) endMarkedContent() {
+	if dw.curPage != nil {
+		dw.curPage.endMarkedContent()
+	}
+}
+
+// endMarkedContent ends the page's open marked-content sequence, first
+// writing any queued text, which belongs to it.
+func (pw *PageWriter) endMarkedContent() {
+	pw.flushText()
+	if pw.inText {
+		pw.endText()
+	}
+	if pw.inGraph {
+		pw.endGraph()
+	}
+	if pw.marked != nil {
+		pw.mw.endMarkedContent()
+		pw.marked = nil
+	}
+}
+
+// markContent is called before content is drawn, outside of any text or
+// path, to open a marked-content sequence for the innermost open tag.
+// Sequences are opened lazily so that tags without content on a page,
+// and artifacts that draw nothing, add nothing to it.
+func (pw *PageWriter) markContent() {
+	if pw.template != nil {
+		// Content placed from a template is marked where it is placed.
+		return
+	}
+	top := pw.dw.topTag()
+	if pw.marked == top {
+		return
+	}
+	if pw.marked != nil {
+		pw.mw.endMarkedContent()
+	}
+	pw.marked = top
+	switch {
+	case top == nil:
+	case top.elem == nil && len(top.properties) == 0:
+		pw.mw.beginMarkedContent("Artifact")
+	case top.elem == nil:
+		pw.mw.beginMarkedContentWithProperties("Artifact", top.properties)
+	default:
+		pw.mw.beginMarkedContentWithProperties(top.elem.role, dictionary{"MCID": integer(pw.markedContentID(top.elem))})
+	}
+}
+
+// markedContentID allocates the page's next marked-content identifier to
+// elem, registering the page in the parent tree on first use.
+func (pw *PageWriter) markedContentID(elem *structElem) int {
+	if pw.marks == nil {
+		pw.marks = pw.dw.structTree().parentTree.addPage()
+		pw.page.dict["StructParents"] = integer(pw.marks.key)
+		pw.page.dict["Tabs"] = name("S")
+	}
+	mcid := len(pw.marks.elems)
+	pw.marks.elems = append(pw.marks.elems, elem)
+	elem.addContent(pw.page, mcid)
+	return mcid
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rowland/leadtype/afm_fonts"
+	"github.com/rowland/leadtype/options"
+)
+
+func newTaggedTestDoc(t *testing.T) *DocWriter {
+	t.Helper()
+	dw := NewDocWriter().CompressPages(false)
+	fonts, err := afm_fonts.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.AddFontSource(fonts)
+	dw.NewPage()
+	if _, err := dw.SetFont("Helvetica", 12, nil); err != nil {
+		t.Fatal(err)
+	}
+	return dw
+}
+
+func TestDocWriter_BeginTag(t *testing.T) {
+	dw := newTaggedTestDoc(t)
+	pw := dw.CurPage()
+	check(t, !dw.Tagged(), "Document should not be tagged before BeginTag")
+	check(t, dw.BeginTag("Document", nil) == nil, "BeginTag should succeed")
+	dw.BeginTag("H1", nil)
+	dw.Print("Title")
+	dw.EndTag()
+	dw.BeginTag("P", options.Options{"lang": "fr"})
+	dw.Print("Bonjour")
+	dw.EndTag()
+	dw.BeginTag("Figure", options.Options{"alt": "A red square"})
+	dw.Rectangle(10, 10, 20, 20, false, true)
+	dw.EndTag()
+	check(t, dw.EndTag() == nil, "EndTag should close the document element")
+	check(t, dw.EndTag() == errNoOpenTag, "EndTag should fail without an open tag")
+	check(t, dw.BeginTag("", nil) == errEmptyRole, "BeginTag should require a structure type")
+	check(t, dw.Tagged(), "Document should be tagged after BeginTag")
+
+	content := pw.stream.String()
+	for i, role := range []string{"H1", "P", "Figure"} {
+		seq := "/" + role + " <<\n/MCID " + string(rune('0'+i)) + " \n>>\nBDC\n"
+		check(t, strings.Contains(content, seq), "Content should mark "+role)
+	}
+	check(t, strings.Index(content, "(Title) Tj") < strings.Index(content, "/P <<"), "Queued text should be written in its own tag")
+	expectI(t, 3, strings.Count(content, "EMC\n"))
+	check(t, !strings.Contains(content, "/Document "), "Elements without content should not be marked")
+
+	root := dw.structTreeRoot
+	expectS(t, stringFromWriter(&indirectObjectRef{root}), stringFromWriter(dw.catalog.dict["StructTreeRoot"]))
+	expectS(t, "<<\n/Marked true \n>>\n", stringFromWriter(dw.catalog.dict["MarkInfo"]))
+	expectS(t, "0 ", stringFromWriter(pw.page.dict["StructParents"]))
+	check(t, len(root.kids) == 1, "Root should have the document element")
+	doc := root.kids[0].(*indirectObjectRef).obj.(*structElem)
+	expectI(t, 3, len(doc.kids))
+	heading := doc.kids[0].(*indirectObjectRef).obj.(*structElem)
+	expectS(t, "/H1 ", stringFromWriter(heading.dict["S"]))
+	expectS(t, stringFromWriter(&indirectObjectRef{doc}), stringFromWriter(heading.dict["P"]))
+	expectS(t, stringFromWriter(&indirectObjectRef{pw.page}), stringFromWriter(heading.dict["Pg"]))
+	check(t, strings.Contains(stringFromWriter(heading), "/K [0 ] "), "Heading should own MCID 0")
+	para := doc.kids[1].(*indirectObjectRef).obj.(*structElem)
+	expectS(t, "(fr) ", stringFromWriter(para.dict["Lang"]))
+	figure := doc.kids[2].(*indirectObjectRef).obj.(*structElem)
+	expectS(t, "(A red square) ", stringFromWriter(figure.dict["Alt"]))
+
+	pt := stringFromWriter(root.parentTree)
+	refs := stringFromWriter(&indirectObjectRef{heading}) + stringFromWriter(&indirectObjectRef{para}) + stringFromWriter(&indirectObjectRef{figure})
+	check(t, strings.Contains(pt, "/Nums [0 ["+refs+"] ] "), "Parent tree should map each MCID to its element")
+	check(t, strings.Contains(stringFromWriter(root), "/ParentTreeNextKey 1 "), "Next key should follow the marked page")
+}
+
+func TestDocWriter_BeginTag_AcrossPages(t *testing.T) {
+	dw := newTaggedTestDoc(t)
+	page1 := dw.CurPage()
+	dw.BeginTag("P", nil)
+	dw.Print("First")
+	dw.NewPage()
+	page2 := dw.CurPage()
+	dw.Print("Second")
+	dw.EndTag()
+	page1.close()
+	page2.close()
+
+	expectS(t, "1 ", stringFromWriter(page2.page.dict["StructParents"]))
+	check(t, strings.HasSuffix(string(page1.page.contents[0].data), "EMC\n"), "Sequence should end with its page")
+	para := dw.structTreeRoot.kids[0].(*indirectObjectRef).obj.(*structElem)
+	expectS(t, stringFromWriter(&indirectObjectRef{page1.page}), stringFromWriter(para.dict["Pg"]))
+	check(t, strings.Contains(stringFromWriter(para), "/MCID 0 \n/Pg "+stringFromWriter(&indirectObjectRef{page2.page})),
+		"Content on a later page should be referenced with its page")
+}
+
+func TestDocWriter_BeginArtifact(t *testing.T) {
+	dw := newTaggedTestDoc(t)
+	pw := dw.CurPage()
+	dw.BeginTag("Document", nil)
+	dw.BeginArtifact(options.Options{"type": "Pagination", "subtype": "Footer"})
+	dw.BeginTag("P", nil)
+	dw.Print("Page 1")
+	dw.EndTag()
+	check(t, dw.EndArtifact() == nil, "EndArtifact should succeed")
+	dw.BeginArtifact(nil)
+	dw.EndArtifact()
+	check(t, dw.EndArtifact() == errNoOpenArtifact, "EndArtifact should fail inside a tag")
+	dw.EndTag()
+
+	content := pw.stream.String()
+	check(t, strings.Contains(content, "/Artifact <<\n/Subtype /Footer \n/Type /Pagination \n>>\nBDC\n"), "Artifact should be marked with its properties")
+	check(t, !strings.Contains(content, "/Artifact BMC"), "Empty artifacts should not be marked")
+	check(t, !strings.Contains(content, "MCID"), "Artifacts should not have MCIDs")
+	doc := dw.structTreeRoot.kids[0].(*indirectObjectRef).obj.(*structElem)
+	check(t, len(doc.kids) == 0, "Tags inside artifacts should not be added to the structure tree")
+}
+
+func TestDocWriter_SetLanguage(t *testing.T) {
+	dw := NewDocWriter()
+	dw.SetLanguage("en-US")
+	dw.NewPage()
+	var buf bytes.Buffer
+	dw.WriteTo(&buf)
+	check(t, strings.Contains(buf.String(), "/Lang (en-US) "), "Catalog should have the language")
+}
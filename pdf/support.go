@@ -157,3 +157,26 @@ func reverseCurvePoints(points []Location) []Location {
 	}
 	return reversed
 }
+
+// transformMatrix holds the six significant entries [a b c d e f] of a PDF
+// transformation matrix.
+type transformMatrix [6]float64
+
+var identityMatrix = transformMatrix{1, 0, 0, 1, 0, 0}
+
+// concat returns the matrix that applies m followed by n, matching the
+// effect of the cm operator with operand m on a current matrix n.
+func (m transformMatrix) concat(n transformMatrix) transformMatrix {
+	return transformMatrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+func (m transformMatrix) array() array {
+	return array{real(m[0]), real(m[1]), real(m[2]), real(m[3]), real(m[4]), real(m[5])}
+}
@@ -276,9 +276,6 @@ func (r *svgRenderer) drawSegments(segments []svg.Segment, style svg.Style, tran
 	if !fill && !stroke {
 		return nil
 	}
-	if style.Opacity < 1 || style.FillOpacity < 1 || style.StrokeOpacity < 1 {
-		logSVGWarnings([]svg.Warning{{Element: "style", Message: "opacity is not yet implemented; rendering as opaque"}})
-	}
 	var err error
 	err = r.pw.Path(func() {
 		r.traceSegments(segments, transform)
@@ -339,6 +336,8 @@ func (r *svgRenderer) finishPath(style svg.Style, fill, stroke bool) error {
 	prevJoin := r.pw.SetLineJoinStyle(mapSVGLineJoin(style.LineJoin))
 	prevMiter := r.pw.SetMiterLimit(style.MiterLimit)
 	prevDash := r.pw.SetLineDashPattern(dashPatternForStyle(style, r.scaleX))
+	prevFillOpacity := r.pw.SetFillOpacity(style.FillOpacity * style.Opacity)
+	prevStrokeOpacity := r.pw.SetStrokeOpacity(style.StrokeOpacity * style.Opacity)
 	defer func() {
 		r.pw.SetFillColor(prevFill)
 		r.pw.SetLineColor(prevStroke)
@@ -347,6 +346,8 @@ func (r *svgRenderer) finishPath(style svg.Style, fill, stroke bool) error {
 		r.pw.SetLineJoinStyle(prevJoin)
 		r.pw.SetMiterLimit(prevMiter)
 		r.pw.SetLineDashPattern(prevDash)
+		r.pw.SetFillOpacity(prevFillOpacity)
+		r.pw.SetStrokeOpacity(prevStrokeOpacity)
 	}()
 	switch {
 	case fill && stroke:
@@ -452,11 +453,15 @@ func (r *svgRenderer) drawText(text *svg.Text, style svg.Style, transform svg.Tr
 	savedFontSize := r.pw.fontSize
 	savedFontColor := r.pw.fontColor
 	prevUnits := r.pw.units
+	prevFillOpacity := r.pw.SetFillOpacity(style.FillOpacity * style.Opacity)
 	defer func() {
+		// Queued text takes its opacity from the state at flush time.
+		r.pw.flushText()
 		r.pw.fonts = savedFonts
 		r.pw.fontSize = savedFontSize
 		r.pw.fontColor = savedFontColor
 		r.pw.units = prevUnits
+		r.pw.SetFillOpacity(prevFillOpacity)
 	}()
 	r.pw.SetFontColor(style.Fill.Color)
 	r.pw.units = UnitConversions["pt"]
@@ -0,0 +1,108 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errTemplateOpen = errors.New("a template is already being drawn")
+var errNoTemplate = errors.New("no template is being drawn")
+var errUnknownTemplate = errors.New("unknown template")
+var errTemplateField = errors.New("form fields cannot be placed in a template")
+
+// pageTemplate is drawing captured once as a form XObject (PDF spec §8.10)
+// to be placed any number of times, on any page.
+type pageTemplate struct {
+	name          string
+	width, height float64 // in points
+	// page is the page that was current when the template was begun.
+	page *PageWriter
+}
+
+// BeginTemplate starts capturing drawing into a template width by height,
+// in the document's units, returning the writer to draw it with. Until
+// EndTemplate is called, it is the current page, with its origin at the top
+// left corner of the template. Annotations, such as links, are not captured.
+func (dw *DocWriter) BeginTemplate(width, height float64) (*PageWriter, error) {
+	if dw.curPage != nil && dw.curPage.template != nil {
+		return nil, errTemplateOpen
+	}
+	pw := new(PageWriter)
+	pw.initState(dw, dw.options)
+	pw.pageWidth, pw.pageHeight = pw.units.toPts(width), pw.units.toPts(height)
+	// The page is never added to the document; it stands in for the page
+	// the template is placed on.
+	pw.page = newPage(0, 0, dw.catalog.pages)
+	pw.template = &pageTemplate{width: pw.pageWidth, height: pw.pageHeight, page: dw.curPage}
+	dw.curPage = pw
+	return pw, nil
+}
+
+// EndTemplate finishes the template being drawn, adding it to the document,
+// and makes the page that was current when it was begun current again. It
+// returns the name by which the template is placed.
+func (dw *DocWriter) EndTemplate() (string, error) {
+	pw := dw.curPage
+	if pw == nil || pw.template == nil {
+		return "", errNoTemplate
+	}
+	pw.endText()
+	pw.endGraph()
+	t := pw.template
+	bbox := rectangle{0, 0, t.width, t.height}
+	form := newFormXObject(dw.nextSeq(), 0, bbox, pw.stream.Bytes(), &indirectObjectRef{dw.resources})
+	if dw.compressPages {
+		if err := form.compress(); err != nil {
+			return "", err
+		}
+	}
+	dw.file.body.add(form)
+	if dw.templates == nil {
+		dw.templates = make(map[string]*pageTemplate)
+	}
+	t.name = fmt.Sprintf("Tpl%d", len(dw.templates)+1)
+	dw.templates[t.name] = t
+	dw.resources.setXObject(t.name, &indirectObjectRef{form})
+	pw.stream.Reset()
+	pw.isClosed = true
+	dw.curPage = t.page
+	t.page = nil
+	return t.name, nil
+}
+
+// TemplateDimensions returns the size of the named template in the units of
+// the current page.
+func (dw *DocWriter) TemplateDimensions(name string) (width, height float64, err error) {
+	return dw.CurPage().TemplateDimensions(name)
+}
+
+// PrintTemplate places the named template on the current page, as
+// PageWriter.PrintTemplate.
+func (dw *DocWriter) PrintTemplate(name string, x, y, scale float64) error {
+	return dw.CurPage().PrintTemplate(name, x, y, scale)
+}
+
+// TemplateDimensions returns the size of the named template in the units of
+// the page, before any scaling.
+func (pw *PageWriter) TemplateDimensions(name string) (width, height float64, err error) {
+	t, ok := pw.dw.templates[name]
+	if !ok {
+		return 0, 0, errUnknownTemplate
+	}
+	return pw.units.fromPts(t.width), pw.units.fromPts(t.height), nil
+}
+
+// PrintTemplate places the named template with its top left corner at x, y,
+// scaled by scale. A template may be placed in another template drawn after
+// it.
+func (pw *PageWriter) PrintTemplate(name string, x, y, scale float64) error {
+	t, ok := pw.dw.templates[name]
+	if !ok {
+		return errUnknownTemplate
+	}
+	pw.placeXObject(name, pw.units.toPts(x), pw.units.toPts(y), t.width*scale, t.height*scale, t.width, t.height)
+	return nil
+}
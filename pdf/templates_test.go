@@ -0,0 +1,67 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed by the Apache License, Version 2.0, as described in the LICENSE file.
+
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rowland/leadtype/afm_fonts"
+)
+
+func TestDocWriter_Template(t *testing.T) {
+	dw := NewDocWriter().CompressPages(false)
+	fonts, err := afm_fonts.Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dw.AddFontSource(fonts)
+	first := dw.NewPage()
+
+	tpl, err := dw.BeginTemplate(100, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(t, dw.CurPage() == tpl, "Template should become the current page")
+	_, err = dw.BeginTemplate(10, 10)
+	check(t, err == errTemplateOpen, "Templates should not nest")
+	tpl.Rectangle(0, 0, 100, 50, true, false)
+	tpl.SetFont("Helvetica", 12, nil)
+	tpl.MoveTo(10, 20)
+	tpl.Print("Logo")
+	tpl.LinkToURI(0, 0, 100, 50, "https://example.com")
+	check(t, tpl.TextField(0, 0, 10, 10, "name", nil) == errTemplateField, "Fields should not be placed in templates")
+	name, err := dw.EndTemplate()
+	check(t, err == nil, "EndTemplate should succeed")
+	expectS(t, "Tpl1", name)
+	check(t, dw.CurPage() == first, "Page should be current again")
+	_, err = dw.EndTemplate()
+	check(t, err == errNoTemplate, "EndTemplate should require a template")
+
+	check(t, dw.PrintTemplate(name, 10, 20, 2) == nil, "PrintTemplate should succeed")
+	expectS(t, "q\n2 0 0 2 10 672 cm\n/Tpl1 Do\nQ\n", first.stream.String())
+	second := dw.NewPage()
+	second.PrintTemplate(name, 0, 0, 1)
+	expectS(t, "q\n1 0 0 1 0 742 cm\n/Tpl1 Do\nQ\n", second.stream.String())
+	check(t, dw.PrintTemplate("Tpl2", 0, 0, 1) == errUnknownTemplate, "Unknown templates should be reported")
+	width, height, err := dw.TemplateDimensions(name)
+	check(t, err == nil, "TemplateDimensions should succeed")
+	expectF(t, 100, width)
+	expectF(t, 50, height)
+
+	form := dw.resources.xObjects[name].(*indirectObjectRef).obj.(*stream)
+	expectS(t, "/Form ", stringFromWriter(form.dict["Subtype"]))
+	expectS(t, "[0 0 100 50 ] ", stringFromWriter(form.dict["BBox"]))
+	check(t, strings.Contains(string(form.data), "(Logo) Tj"), "Template should capture text")
+	check(t, strings.Contains(string(form.data), " re\n"), "Template should capture paths")
+	check(t, len(first.page.annots) == 0 && len(tpl.page.annots) == 0, "Links should not be captured")
+
+	var buf bytes.Buffer
+	if _, err := dw.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expectI(t, 1, bytes.Count(buf.Bytes(), []byte("(Logo) Tj")))
+	expectI(t, 2, bytes.Count(buf.Bytes(), []byte("/Type /Page ")))
+}
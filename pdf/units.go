@@ -28,6 +28,7 @@ var UnitConversions = UnitConversionMap{
 	"pt": &units{"pt", 1},
 	"in": &units{"in", 72},
 	"cm": &units{"cm", 28.35},
+	"mm": &units{"mm", 2.835},
 }
 
 func unitsFromPts(units string, measurement float64) float64 {
@@ -7,11 +7,13 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"unicode"
 	"unicode/utf8"
 
+	"github.com/rowland/leadtype/bidi"
 	"github.com/rowland/leadtype/codepage"
 	"github.com/rowland/leadtype/colors"
 	"github.com/rowland/leadtype/font"
@@ -41,7 +43,50 @@ type RichText struct {
 	CharSpacing        float64
 	WordSpacing        float64
 	NoBreak            bool
+	Kerning            bool
+	Features           []shaping.Feature
+	Link               string
 	pieces             []*RichText
+	level              bidi.Level // embedding level, once reordered
+	reordered          bool
+	paragraph          *bidiParagraph // the paragraph a line was wrapped from
+	lineStart          int            // rune offset of the line in its paragraph
+}
+
+// bidiParagraph is the text of a paragraph wrapped into lines. Its embedding
+// levels are resolved once, when the first of its lines is reordered, since
+// the Unicode Bidirectional Algorithm resolves them over the whole paragraph.
+type bidiParagraph struct {
+	text     []rune
+	rtl      bool
+	dir      bidi.Direction
+	resolved *bidi.Paragraph
+}
+
+func newBidiParagraph(text string) *bidiParagraph {
+	return &bidiParagraph{text: []rune(text), rtl: bidi.HasRightToLeft(text)}
+}
+
+// lineLevels returns the levels of the n characters of the line at start,
+// resolving the paragraph in direction dir. A hyphen added where a word was
+// broken takes the level of the character before it.
+func (bp *bidiParagraph) lineLevels(dir bidi.Direction, start, n int) []bidi.Level {
+	if bp.resolved == nil || bp.dir != dir {
+		bp.resolved = bidi.NewParagraph(bp.text, dir)
+		bp.dir = dir
+	}
+	end := min(start+n, len(bp.text))
+	levels := bp.resolved.LineLevels(start, end)
+	for len(levels) < n {
+		level := bidi.Level(0)
+		if len(levels) > 0 {
+			level = levels[len(levels)-1]
+		} else if bp.resolved.Direction() == bidi.RightToLeft {
+			level = 1
+		}
+		levels = append(levels, level)
+	}
+	return levels
 }
 
 var errNoFontSet = errors.New("No font set")
@@ -67,6 +112,12 @@ var errNoFontSet = errors.New("No font set")
 //	word_spacing: Add extra space between words, expressed in points.
 //	nobreak:      Prevent WordsToWidth or WrapToWidth from breaking within this stretch of text.
 //	              A bool, a string that evalutes to bool via strconv.ParseBool, a non-zero int or float64.
+//	kerning:      Kern pairs of characters using the font's kerning data, as when the font has Kerning set.
+//	              A bool, a string that evalutes to bool via strconv.ParseBool, a non-zero int or float64.
+//	features:     OpenType features to shape text with, after those of its font, such as "tnum,smcp".
+//	              A []shaping.Feature or a string to parse with shaping.ParseFeatures.
+//	link:         Make the text a hyperlink when printed.
+//	              A URI, or "#name" to jump to a named destination within the document.
 func New(s string, fonts []*font.Font, fontSize float64, options options.Options) (*RichText, error) {
 	piece := &RichText{
 		Text:        s,
@@ -77,6 +128,17 @@ func New(s string, fonts []*font.Font, fontSize float64, options options.Options
 		CharSpacing: options.FloatDefault("char_spacing", 0),
 		WordSpacing: options.FloatDefault("word_spacing", 0),
 		NoBreak:     options.BoolDefault("nobreak", false),
+		Kerning:     options.BoolDefault("kerning", false),
+		Link:        options.StringDefault("link", ""),
+	}
+	switch features := options["features"].(type) {
+	case []shaping.Feature:
+		piece.Features = features
+	case string:
+		var err error
+		if piece.Features, err = shaping.ParseFeatures(features); err != nil {
+			return nil, err
+		}
 	}
 	var defaultFont *font.Font
 	if len(fonts) == 0 {
@@ -304,6 +366,25 @@ func (piece *RichText) IsLeaf() bool {
 	return len(piece.pieces) == 0
 }
 
+// IsShaped returns true if this piece is set by its font's shaper, as text in a complex script
+// such as Arabic, Hebrew or Devanagari is, and as any text with OpenType features requested is.
+func (piece *RichText) IsShaped() bool {
+	return piece.Font != nil && piece.Font.Shaper != nil &&
+		(shaping.NeedsShaping(piece.Text) || len(piece.OpenTypeFeatures()) > 0)
+}
+
+// OpenTypeFeatures returns the OpenType features to shape the piece with:
+// those of its font, followed by its own, which take precedence.
+func (piece *RichText) OpenTypeFeatures() []shaping.Feature {
+	if piece.Font == nil || len(piece.Font.Features) == 0 {
+		return piece.Features
+	}
+	if len(piece.Features) == 0 {
+		return piece.Font.Features
+	}
+	return append(slices.Clip(piece.Font.Features), piece.Features...)
+}
+
 // IsNewLine returns true if this piece contains a single new line character.
 func (piece *RichText) IsNewLine() bool {
 	return piece.Text == "\n"
@@ -381,7 +462,19 @@ func (piece *RichText) MatchesAttributes(other *RichText) bool {
 		piece.Underline == other.Underline &&
 		piece.Strikeout == other.Strikeout &&
 		piece.CharSpacing == other.CharSpacing &&
-		piece.WordSpacing == other.WordSpacing
+		piece.WordSpacing == other.WordSpacing &&
+		piece.Kerning == other.Kerning &&
+		slices.Equal(piece.Features, other.Features) &&
+		piece.Link == other.Link &&
+		piece.reordered == other.reordered &&
+		// Shaped text is kept in logical order, so can't join text reordered around it.
+		(!piece.reordered || piece.Font.Shaper == nil || (piece.level == other.level && !piece.level.IsRightToLeft()))
+}
+
+// Kerned reports whether pairs of characters in the piece are kerned, because
+// either the piece or its font has Kerning set.
+func (piece *RichText) Kerned() bool {
+	return piece.Kerning || (piece.Font != nil && piece.Font.Kerning)
 }
 
 func (piece *RichText) measure() *RichText {
@@ -402,9 +495,9 @@ func (piece *RichText) measure() *RichText {
 	piece.StrikeoutThickness = float64(metrics.StrikeoutThickness()) * fsize
 	piece.UnderlinePosition = float64(metrics.UnderlinePosition()) * fsize
 	piece.UnderlineThickness = float64(metrics.UnderlineThickness()) * fsize
-	if piece.Font.Shaper != nil && shaping.ContainsArabic(piece.Text) {
+	if piece.IsShaped() {
 		runes := []rune(piece.Text)
-		if shaped, err := piece.Font.Shaper.Shape(runes, piece.Font, float32(piece.FontSize)); err == nil && shaped != nil {
+		if shaped, err := shaping.ShapeRuns(piece.Font.Shaper, runes, piece.Font, float32(piece.FontSize), piece.OpenTypeFeatures()); err == nil && shaped != nil {
 			for _, r := range runes {
 				if r == wordbreaking.SoftHyphen {
 					continue
@@ -421,11 +514,17 @@ func (piece *RichText) measure() *RichText {
 			return piece
 		}
 	}
+	kerned := piece.Kerned()
+	prev := rune(-1)
 	for _, rune := range piece.Text {
 		if rune == wordbreaking.SoftHyphen {
 			continue
 		}
 		piece.chars += 1
+		if kerned && prev >= 0 {
+			piece.width += fsize * float64(metrics.PairKerning(prev, rune))
+		}
+		prev = rune
 		runeWidth, _ := metrics.AdvanceWidth(rune)
 		piece.width += (fsize * float64(runeWidth)) + piece.CharSpacing
 		if rune == ' ' {
@@ -469,6 +568,80 @@ func (piece *RichText) Merge() *RichText {
 	return mergedText
 }
 
+// Reorder returns a line of text in display order, by the Unicode Bidirectional Algorithm,
+// with dir as the direction of its paragraph. Call it on each line from WrapToWidth:
+// the levels of a wrapped line are those resolved over the whole paragraph it came from.
+// Text that was not wrapped is taken to be a paragraph of its own.
+//
+// Pieces are split where their embedding levels change and arranged from left to right.
+// The text of right-to-left pieces is reversed, with brackets and other paired characters mirrored,
+// except where it is shaped, as shapers return their glyphs in display order.
+// Text with nothing to reorder is returned as it is, as is text already reordered.
+func (piece *RichText) Reorder(dir bidi.Direction) *RichText {
+	var leaves []*RichText
+	reordered := false
+	piece.VisitAll(func(p *RichText) {
+		if p.IsLeaf() {
+			leaves = append(leaves, p)
+			reordered = reordered || p.reordered
+		}
+	})
+	text := piece.String()
+	hasRightToLeft := piece.paragraph != nil && piece.paragraph.rtl || bidi.HasRightToLeft(text)
+	if reordered || (dir != bidi.RightToLeft && !hasRightToLeft) {
+		return piece
+	}
+	runes := []rune(text)
+	var levels []bidi.Level
+	if piece.paragraph != nil {
+		levels = piece.paragraph.lineLevels(dir, piece.lineStart, len(runes))
+	} else {
+		levels = bidi.NewParagraph(runes, dir).LineLevels(0, len(runes))
+	}
+
+	var segments []*RichText
+	var segmentLevels []bidi.Level
+	offset := 0
+	for _, leaf := range leaves {
+		leafRunes := []rune(leaf.Text)
+		for start := 0; start < len(leafRunes); {
+			level := levels[offset+start]
+			end := start + 1
+			for end < len(leafRunes) && levels[offset+end] == level {
+				end++
+			}
+			segment := leaf.Clone()
+			segment.Text = string(leafRunes[start:end])
+			segment.level = level
+			segment.reordered = true
+			segments = append(segments, segment)
+			segmentLevels = append(segmentLevels, level)
+			start = end
+		}
+		offset += len(leafRunes)
+	}
+
+	result := piece.Clone()
+	result.Text = ""
+	result.pieces = make([]*RichText, 0, len(segments))
+	for _, i := range bidi.VisualOrder(segmentLevels) {
+		segment := segments[i]
+		if segment.level.IsRightToLeft() && !segment.IsShaped() {
+			segmentRunes := []rune(segment.Text)
+			reversed := make([]rune, len(segmentRunes))
+			for j, r := range segmentRunes {
+				reversed[len(segmentRunes)-1-j] = bidi.Mirror(r)
+			}
+			segment.Text = string(reversed)
+		}
+		result.pieces = append(result.pieces, segment)
+	}
+	if len(result.pieces) == 1 {
+		return result.pieces[0]
+	}
+	return result
+}
+
 // Split returns the two structures resulting from splitting this text at the specified offset.
 func (piece *RichText) Split(offset int) (left, right *RichText) {
 	if offset < 0 {
@@ -698,6 +871,8 @@ func (piece *RichText) scaleClone(scale float64) *RichText {
 		CharSpacing: piece.CharSpacing,
 		WordSpacing: piece.WordSpacing,
 		NoBreak:     piece.NoBreak,
+		Kerning:     piece.Kerning,
+		Features:    piece.Features,
 	}
 	if piece.IsLeaf() {
 		clone.FontSize *= scale
@@ -731,8 +906,10 @@ func (piece *RichText) WordsToWidth(
 	var metrics font.FontMetrics
 	var fsize float64
 	var lastRune rune
+	// prevRune is the last rune of the current leaf piece, for kerning, or -1.
+	var prevRune rune
 	// shapedAdv[i] holds the shaped advance (in points) attributable to rune i
-	// within the current Arabic leaf piece. nil for non-Arabic or unshaped leaves.
+	// within the current shaped leaf piece. nil for unshaped leaves.
 	var shapedAdv []float64
 	var leafRuneIdx int
 
@@ -766,11 +943,12 @@ func (piece *RichText) WordsToWidth(
 			fsize = p.FontSize / float64(p.Font.UnitsPerEm())
 			lastPiece = p
 			leafRuneIdx = 0
-			// Pre-shape Arabic leaves so word widths reflect contextual forms
-			// and ligatures rather than individual unshaped glyph metrics.
-			if p.Font.Shaper != nil && shaping.ContainsArabic(p.Text) {
+			prevRune = -1
+			// Pre-shape complex-script leaves so word widths reflect contextual
+			// forms and ligatures rather than individual unshaped glyph metrics.
+			if p.IsShaped() {
 				leafRunes := []rune(p.Text)
-				if glyphs, err := p.Font.Shaper.Shape(leafRunes, p.Font, float32(p.FontSize)); err == nil && glyphs != nil {
+				if glyphs, err := shaping.ShapeRuns(p.Font.Shaper, leafRunes, p.Font, float32(p.FontSize), p.OpenTypeFeatures()); err == nil && glyphs != nil {
 					shapedAdv = make([]float64, len(leafRunes))
 					for _, g := range glyphs {
 						shapedAdv[g.ClusterIndex] += float64(g.XAdvance) / 64.0
@@ -786,9 +964,13 @@ func (piece *RichText) WordsToWidth(
 			if shapedAdv != nil && leafRuneIdx < len(shapedAdv) {
 				wordWidth += shapedAdv[leafRuneIdx] + p.CharSpacing
 			} else {
+				if prevRune >= 0 && p.Kerned() {
+					wordWidth += fsize * float64(p.Font.PairKerning(prevRune, r))
+				}
 				runeWidth, _ := metrics.AdvanceWidth(r)
 				wordWidth += (fsize * float64(runeWidth)) + p.CharSpacing
 			}
+			prevRune = r
 			if unicode.IsSpace(r) {
 				wordWidth += p.WordSpacing
 			}
@@ -814,12 +996,29 @@ func (piece *RichText) WordsToWidth(
 }
 
 // WrapToWidth returns one or more lines of text resulting from repeatedly invoking WordsToWidth.
+// Each line remembers the paragraph it came from, for Reorder.
 func (piece *RichText) WrapToWidth(width float64, wordFlags []wordbreaking.Flags, hardBreak bool) (lines []*RichText) {
+	text := piece.String()
+	paragraph := newBidiParagraph(text)
+	total := utf8.RuneCountInString(text)
+	rest := piece
 	line, remainder, remainderFlags := piece.WordsToWidth(width, wordFlags, hardBreak)
 	for remainder != nil {
-		lines = append(lines, line.TrimSpace())
+		lines = append(lines, paragraph.line(line, total-utf8.RuneCountInString(rest.String())))
+		rest = remainder
 		line, remainder, remainderFlags = remainder.WordsToWidth(width, remainderFlags, hardBreak)
 	}
-	lines = append(lines, line.TrimSpace())
+	lines = append(lines, paragraph.line(line, total-utf8.RuneCountInString(rest.String())))
 	return
 }
+
+// line trims the space around a line starting at rune offset start in the
+// paragraph and records where the trimmed line starts.
+func (bp *bidiParagraph) line(line *RichText, start int) *RichText {
+	s := line.String()
+	start += utf8.RuneCountInString(s) - utf8.RuneCountInString(strings.TrimLeftFunc(s, unicode.IsSpace))
+	trimmed := line.TrimSpace().Clone()
+	trimmed.paragraph = bp
+	trimmed.lineStart = start
+	return trimmed
+}
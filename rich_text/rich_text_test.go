@@ -5,14 +5,17 @@ package rich_text
 
 import (
 	"math"
+	"reflect"
 	"testing"
 	"unicode"
 
 	"github.com/rowland/leadtype/afm_fonts"
+	"github.com/rowland/leadtype/bidi"
 	"github.com/rowland/leadtype/codepage"
 	"github.com/rowland/leadtype/colors"
 	"github.com/rowland/leadtype/font"
 	"github.com/rowland/leadtype/options"
+	"github.com/rowland/leadtype/shaping"
 	"github.com/rowland/leadtype/ttf"
 	"github.com/rowland/leadtype/ttf_fonts"
 	"github.com/rowland/leadtype/wordbreaking"
@@ -520,6 +523,14 @@ func TestRichText_MatchesAttributes(t *testing.T) {
 	p2 = p1
 	p2.WordSpacing = 1
 	st.False(p1.MatchesAttributes(&p2), "Attributes should not match.")
+
+	p2 = p1
+	p2.Link = "https://example.com"
+	st.False(p1.MatchesAttributes(&p2), "Attributes should not match.")
+
+	p2 = p1
+	p2.Features = []shaping.Feature{{Tag: "tnum", Value: 1}}
+	st.False(p1.MatchesAttributes(&p2), "Attributes should not match.")
 }
 
 func TestRichText_measure(t *testing.T) {
@@ -654,6 +665,148 @@ func mixedText() *RichText {
 	return rt
 }
 
+func amiriFonts(t *testing.T) []*font.Font {
+	fc, err := ttf_fonts.New("../shaping/testdata/Amiri-Regular.ttf")
+	if err != nil || len(fc.FontInfos) == 0 {
+		t.Skipf("Arabic fixture font not found: %v", err)
+	}
+	f, err := font.New(fc.FontInfos[0].Family(), options.Options{}, font.FontSources{fc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []*font.Font{f}
+}
+
+func TestRichText_Reorder(t *testing.T) {
+	fonts := amiriFonts(t)
+	tests := []struct {
+		text string
+		dir  bidi.Direction
+		want string
+	}{
+		{"plain text", bidi.Auto, "plain text"},
+		{"Total: (مرحبا) 12.", bidi.Auto, "Total: (ابحرم) 12."},
+		{"مرحبا (abc)", bidi.Auto, "(abc) ابحرم"},
+		{"مرحبا 12", bidi.RightToLeft, "12 ابحرم"},
+		{"abc.", bidi.RightToLeft, ".abc"},
+	}
+	for _, tt := range tests {
+		rt, err := New(tt.text, fonts, 10, options.Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := rt.Reorder(tt.dir).String(); got != tt.want {
+			t.Errorf("Reorder(%q, %v) = %q, want %q", tt.text, tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestRichText_Reorder_pieces(t *testing.T) {
+	fonts := amiriFonts(t)
+	rt, err := New("one ", fonts, 10, options.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt, err = rt.Add("مرحبا", fonts, 10, options.Options{"underline": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt, err = rt.Add(" two", fonts, 10, options.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := rt.Reorder(bidi.Auto)
+	if got, want := line.String(), "one ابحرم two"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if !line.pieces[1].Underline {
+		t.Error("reordered Arabic should keep its attributes")
+	}
+	if again := line.Reorder(bidi.RightToLeft); again != line {
+		t.Error("reordered text should not be reordered again")
+	}
+}
+
+func TestRichText_Reorder_wrapped(t *testing.T) {
+	fonts := amiriFonts(t)
+	tests := []struct {
+		text string
+		want []string
+	}{
+		// Resolved alone, the second line would start with a number at the
+		// paragraph's level: "123 وهد".
+		{"ابجدهوزحط 123 دهو", []string{"طحزوهدجبا", "وهد 123"}},
+		{"ابجدهوزحط (123) دهو", []string{"طحزوهدجبا", "وهد (123)"}},
+	}
+	for _, tt := range tests {
+		rt, err := New(tt.text, fonts, 10, options.Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		first, err := New("ابجدهوزحط ", fonts, 10, options.Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		flags := make([]wordbreaking.Flags, rt.Len())
+		wordbreaking.MarkRuneAttributes(rt.String(), flags)
+		lines := rt.WrapToWidth(first.Width(), flags, false)
+		if len(lines) != len(tt.want) {
+			t.Fatalf("WrapToWidth(%q) = %d lines, want %d", tt.text, len(lines), len(tt.want))
+		}
+		for i, line := range lines {
+			if got := line.Reorder(bidi.LeftToRight).String(); got != tt.want[i] {
+				t.Errorf("line %d of %q = %q, want %q", i, tt.text, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestRichText_Reorder_shaped(t *testing.T) {
+	fonts := amiriFonts(t)
+	fonts[0].Shaper = shaping.NewShaper()
+	rt, err := New("مرحبا ١٢", fonts, 10, options.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := rt.Reorder(bidi.Auto)
+	if got, want := line.String(), "١٢مرحبا "; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if line.pieces[0].IsShaped() {
+		t.Error("Arabic digits, displayed left to right, should not be shaped")
+	}
+	if !line.pieces[1].IsShaped() {
+		t.Error("shaped Arabic should keep its logical order for the shaper")
+	}
+}
+
+func TestRichText_Features(t *testing.T) {
+	fonts := amiriFonts(t)
+	fonts[0].Shaper = shaping.NewShaper()
+	plain, err := New("1,234.50", fonts, 10, options.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.IsShaped() {
+		t.Error("Latin text without features should not be shaped")
+	}
+	rt, err := New("1,234.50", fonts, 10, options.Options{"features": "tnum, -liga"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rt.IsShaped() {
+		t.Error("Latin text with features should be shaped")
+	}
+	fonts[0].Features = []shaping.Feature{{Tag: "smcp", Value: 1}}
+	want := []shaping.Feature{{Tag: "smcp", Value: 1}, {Tag: "tnum", Value: 1}, {Tag: "liga", Value: 0}}
+	if got := rt.OpenTypeFeatures(); !reflect.DeepEqual(got, want) {
+		t.Errorf("OpenTypeFeatures() = %v, want %v", got, want)
+	}
+	if _, err := New("1,234.50", fonts, 10, options.Options{"features": "tabular"}); err == nil {
+		t.Error("expected an error for an invalid feature tag")
+	}
+}
+
 func TestRichText_Split(t *testing.T) {
 	skipIfNoTTFFonts(t)
 	st := SuperTest{t}
@@ -753,6 +906,37 @@ func TestRichText_Width(t *testing.T) {
 	st.AlmostEqual(60.024414, p.Width(), 0.001)
 }
 
+func TestRichText_Width_kerning(t *testing.T) {
+	st := SuperTest{t}
+	plain := helveticaText("AVA")
+	st.AlmostEqual(20.01, plain.Width(), 0.001)
+	kerned, err := New("AVA", afm_fonts.Families("Helvetica"), 10, options.Options{"kerning": true})
+	st.Must(err == nil, "Should create kerned text.")
+	st.Must(kerned.Kerned(), "Text should be kerned.")
+	// A V -70, V A -80
+	st.AlmostEqual(18.51, kerned.Width(), 0.001)
+	st.False(plain.MatchesAttributes(kerned), "Kerned and unkerned text should not merge.")
+}
+
+func TestRichText_WordsToWidth_kerning(t *testing.T) {
+	st := SuperTest{t}
+	for _, kerning := range []bool{false, true} {
+		p, err := New("AV AV AV", afm_fonts.Families("Helvetica"), 10, options.Options{"kerning": kerning})
+		st.Must(err == nil, "Should create text.")
+		flags := make([]wordbreaking.Flags, p.Len())
+		wordbreaking.MarkRuneAttributes(p.String(), flags)
+		// "AV AV" is 28.06 wide kerned and 29.46 unkerned.
+		line, _, _ := p.WordsToWidth(28.5, flags, false)
+		line = line.TrimRightSpace()
+		if kerning {
+			st.Equal("AV AV", line.String())
+			st.AlmostEqual(28.06, line.Width(), 0.001)
+		} else {
+			st.Equal("AV", line.String())
+		}
+	}
+}
+
 func TestRichText_WordsToWidth_empty(t *testing.T) {
 	st := SuperTest{t}
 	p := new(RichText)
@@ -0,0 +1,47 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package main
+
+import (
+	"github.com/rowland/leadtype/colors"
+	"github.com/rowland/leadtype/pdf"
+)
+
+func init() {
+	registerSample("test_017_gradients", "fill shapes with linear and radial gradients", runTest017Gradients)
+}
+
+func runTest017Gradients() (string, error) {
+	return writeDoc("test_017_gradients.pdf", func(doc *pdf.DocWriter) error {
+		doc.NewPage()
+		doc.SetUnits("in")
+
+		doc.SetFillGradient(pdf.NewLinearGradient(1, 1, 7.5, 1,
+			pdf.GradientStop{Offset: 0, Color: colors.Navy},
+			pdf.GradientStop{Offset: 0.5, Color: colors.DodgerBlue},
+			pdf.GradientStop{Offset: 1, Color: colors.White}))
+		doc.Rectangle(1, 1, 6.5, 2, false, true)
+
+		doc.SetFillGradient(pdf.NewRadialGradient(2.5, 5, 1.5, 2, 4.5, 0,
+			pdf.GradientStop{Offset: 0, Color: colors.White},
+			pdf.GradientStop{Offset: 1, Color: colors.DarkRed}))
+		if err := doc.Circle(2.5, 5, 1.5, false, true, false); err != nil {
+			return err
+		}
+		doc.SetFillGradient(nil)
+
+		sunset := pdf.NewLinearGradient(5, 3.5, 5, 6.5,
+			pdf.GradientStop{Offset: 0, Color: colors.Gold},
+			pdf.GradientStop{Offset: 1, Color: colors.OrangeRed})
+		return doc.Path(func() {
+			doc.MoveTo(4.5, 6.5)
+			doc.LineTo(6, 3.5)
+			doc.LineTo(7.5, 6.5)
+			doc.LineTo(4.5, 6.5)
+			doc.Clip(func() {
+				doc.PaintGradient(sunset)
+			})
+		})
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package main
+
+import (
+	"github.com/rowland/leadtype/afm_fonts"
+	"github.com/rowland/leadtype/options"
+	"github.com/rowland/leadtype/pdf"
+)
+
+func init() {
+	registerSample("test_018_forms", "place fillable text, button, choice and signature fields", runTest018Forms)
+}
+
+func runTest018Forms() (string, error) {
+	return writeDoc("test_018_forms.pdf", func(doc *pdf.DocWriter) error {
+		afmfc, err := afm_fonts.Default()
+		if err != nil {
+			return err
+		}
+		doc.AddFontSource(afmfc)
+
+		doc.NewPage()
+		doc.SetUnits("in")
+		if _, err := doc.SetFont("Helvetica", 12, options.Options{}); err != nil {
+			return err
+		}
+
+		labels := []string{"Name", "Country", "Topics", "Plan", "Newsletter", "Signature"}
+		for i, label := range labels {
+			doc.MoveTo(1, 1.2+float64(i)*0.75)
+			doc.Print(label)
+		}
+
+		if err := doc.TextField(2.5, 1, 4, 0.3, "name", options.Options{"value": "Jane Doe", "required": true}); err != nil {
+			return err
+		}
+		if err := doc.ComboBox(2.5, 1.75, 2, 0.3, "country", []string{"Canada", "Mexico", "United States"},
+			options.Options{"value": "Canada", "background_color": "AliceBlue"}); err != nil {
+			return err
+		}
+		if err := doc.ListBox(2.5, 2.5, 2, 0.65, "topics", []string{"Billing", "Reporting", "Support"},
+			options.Options{"value": []string{"Billing", "Support"}, "multiselect": true}); err != nil {
+			return err
+		}
+		for i, plan := range []string{"Basic", "Pro"} {
+			x := 2.5 + float64(i)*1.25
+			checked := i == 0
+			if err := doc.RadioButton(x, 3.3, 0.2, 0.2, "plan", plan, options.Options{"checked": checked}); err != nil {
+				return err
+			}
+			doc.MoveTo(x+0.3, 3.45)
+			doc.Print(plan)
+		}
+		if err := doc.CheckBox(2.5, 4.05, 0.2, 0.2, "newsletter", options.Options{"checked": true}); err != nil {
+			return err
+		}
+		return doc.SignatureField(2.5, 4.75, 3, 0.6, "signature", options.Options{"tooltip": "Sign here"})
+	})
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package shaping
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Feature is an OpenType feature to apply when shaping, such as "tnum" for
+// tabular figures or "smcp" for small capitals.
+type Feature struct {
+	Tag   string // four-character OpenType feature tag
+	Value uint32 // 1 to turn the feature on, 0 to turn it off, or the alternate to choose
+}
+
+func (f Feature) String() string {
+	switch f.Value {
+	case 0:
+		return "-" + f.Tag
+	case 1:
+		return f.Tag
+	}
+	return fmt.Sprintf("%s=%d", f.Tag, f.Value)
+}
+
+// ParseFeatures parses a list of OpenType features separated by commas or
+// spaces, such as "tnum, smcp, -liga, salt=2". A tag alone turns a feature
+// on, a tag after "-" turns it off, and "tag=n" chooses alternate n.
+func ParseFeatures(s string) ([]Feature, error) {
+	var features []Feature
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		feature := Feature{Tag: field, Value: 1}
+		switch {
+		case strings.HasPrefix(field, "-"):
+			feature = Feature{Tag: field[1:], Value: 0}
+		case strings.HasPrefix(field, "+"):
+			feature.Tag = field[1:]
+		default:
+			if tag, value, ok := strings.Cut(field, "="); ok {
+				n, err := strconv.ParseUint(value, 10, 32)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value for feature %q: %s", tag, value)
+				}
+				feature = Feature{Tag: tag, Value: uint32(n)}
+			}
+		}
+		if !validFeatureTag(feature.Tag) {
+			return nil, fmt.Errorf("invalid feature tag: %q", feature.Tag)
+		}
+		features = append(features, feature)
+	}
+	return features, nil
+}
+
+// validFeatureTag reports whether tag is four printable ASCII characters.
+func validFeatureTag(tag string) bool {
+	if len(tag) != 4 {
+		return false
+	}
+	for i := 0; i < len(tag); i++ {
+		if tag[i] < 0x20 || tag[i] > 0x7E {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package shaping_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rowland/leadtype/shaping"
+)
+
+func TestParseFeatures(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []shaping.Feature
+	}{
+		{"", nil},
+		{"tnum", []shaping.Feature{{Tag: "tnum", Value: 1}}},
+		{"tnum,smcp", []shaping.Feature{{Tag: "tnum", Value: 1}, {Tag: "smcp", Value: 1}}},
+		{" +c2sc, -liga  salt=2 ", []shaping.Feature{{Tag: "c2sc", Value: 1}, {Tag: "liga", Value: 0}, {Tag: "salt", Value: 2}}},
+		{"ss01,ss20", []shaping.Feature{{Tag: "ss01", Value: 1}, {Tag: "ss20", Value: 1}}},
+	}
+	for _, tt := range tests {
+		got, err := shaping.ParseFeatures(tt.s)
+		if err != nil {
+			t.Errorf("ParseFeatures(%q): %v", tt.s, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFeatures(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestParseFeatures_invalid(t *testing.T) {
+	for _, s := range []string{"tabular", "tnum=x", "-", "sm\x01p"} {
+		if _, err := shaping.ParseFeatures(s); err == nil {
+			t.Errorf("ParseFeatures(%q) should fail", s)
+		}
+	}
+}
+
+func TestFeature_String(t *testing.T) {
+	features := []shaping.Feature{{Tag: "tnum", Value: 1}, {Tag: "liga", Value: 0}, {Tag: "salt", Value: 3}}
+	want := []string{"tnum", "-liga", "salt=3"}
+	for i, f := range features {
+		if got := f.String(); got != want[i] {
+			t.Errorf("String() = %q, want %q", got, want[i])
+		}
+	}
+}
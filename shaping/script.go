@@ -0,0 +1,202 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package shaping
+
+import "unicode"
+
+// Script identifies a writing system by its ISO 15924 code, such as "Deva"
+// for Devanagari. Shapers use it to choose the script's shaping rules.
+type Script string
+
+const (
+	Common     Script = "Zyyy" // punctuation, spaces and digits shared by scripts
+	Unknown    Script = "Zzzz"
+	Latin      Script = "Latn"
+	Greek      Script = "Grek"
+	Cyrillic   Script = "Cyrl"
+	Arabic     Script = "Arab"
+	Hebrew     Script = "Hebr"
+	Syriac     Script = "Syrc"
+	Thaana     Script = "Thaa"
+	Devanagari Script = "Deva"
+	Bengali    Script = "Beng"
+	Gurmukhi   Script = "Guru"
+	Gujarati   Script = "Gujr"
+	Oriya      Script = "Orya"
+	Tamil      Script = "Taml"
+	Telugu     Script = "Telu"
+	Kannada    Script = "Knda"
+	Malayalam  Script = "Mlym"
+	Sinhala    Script = "Sinh"
+	Thai       Script = "Thai"
+	Lao        Script = "Laoo"
+	Tibetan    Script = "Tibt"
+	Myanmar    Script = "Mymr"
+	Khmer      Script = "Khmr"
+)
+
+type scriptInfo struct {
+	script   Script
+	table    *unicode.RangeTable
+	language string // BCP 47 tag of the language most often written in the script
+	rtl      bool
+	complex  bool // needs a shaper to join, reorder or position its glyphs
+}
+
+// scripts lists the complex scripts first, as NeedsShaping only looks at those.
+var scripts = []scriptInfo{
+	{Arabic, unicode.Arabic, "ar", true, true},
+	{Hebrew, unicode.Hebrew, "he", true, true},
+	{Syriac, unicode.Syriac, "syr", true, true},
+	{Thaana, unicode.Thaana, "dv", true, true},
+	{Devanagari, unicode.Devanagari, "hi", false, true},
+	{Bengali, unicode.Bengali, "bn", false, true},
+	{Gurmukhi, unicode.Gurmukhi, "pa", false, true},
+	{Gujarati, unicode.Gujarati, "gu", false, true},
+	{Oriya, unicode.Oriya, "or", false, true},
+	{Tamil, unicode.Tamil, "ta", false, true},
+	{Telugu, unicode.Telugu, "te", false, true},
+	{Kannada, unicode.Kannada, "kn", false, true},
+	{Malayalam, unicode.Malayalam, "ml", false, true},
+	{Sinhala, unicode.Sinhala, "si", false, true},
+	{Thai, unicode.Thai, "th", false, true},
+	{Lao, unicode.Lao, "lo", false, true},
+	{Tibetan, unicode.Tibetan, "bo", false, true},
+	{Myanmar, unicode.Myanmar, "my", false, true},
+	{Khmer, unicode.Khmer, "km", false, true},
+	{Latin, unicode.Latin, "en", false, false},
+	{Greek, unicode.Greek, "el", false, false},
+	{Cyrillic, unicode.Cyrillic, "ru", false, false},
+}
+
+// firstComplexRune is the first rune of the first complex script, Hebrew.
+const firstComplexRune = 0x0590
+
+func (s Script) info() *scriptInfo {
+	for i := range scripts {
+		if scripts[i].script == s {
+			return &scripts[i]
+		}
+	}
+	return nil
+}
+
+// IsComplex reports whether text in the script needs a shaper to join,
+// reorder or position its glyphs.
+func (s Script) IsComplex() bool {
+	info := s.info()
+	return info != nil && info.complex
+}
+
+// IsRightToLeft reports whether the script is written right to left.
+func (s Script) IsRightToLeft() bool {
+	info := s.info()
+	return info != nil && info.rtl
+}
+
+// Language returns the BCP 47 tag of the language most often written in the
+// script, such as "hi" for Devanagari, or "" if there is none.
+func (s Script) Language() string {
+	if info := s.info(); info != nil {
+		return info.language
+	}
+	return ""
+}
+
+// ScriptOf returns the script of r. Digits, punctuation, spaces and combining
+// marks used by several scripts are Common, taking the script of the text
+// around them.
+func ScriptOf(r rune) Script {
+	if unicode.IsDigit(r) || unicode.In(r, unicode.Common, unicode.Inherited) {
+		return Common
+	}
+	for i := range scripts {
+		if unicode.Is(scripts[i].table, r) {
+			return scripts[i].script
+		}
+	}
+	return Unknown
+}
+
+// NeedsShaping reports whether s contains any letter or mark of a complex
+// script. It exits on the first match, so it is cheap for typical Latin-only text.
+func NeedsShaping(s string) bool {
+	for _, r := range s {
+		if r < firstComplexRune {
+			continue
+		}
+		if ScriptOf(r).IsComplex() {
+			return true
+		}
+	}
+	return false
+}
+
+// Run is a run of text in a single script, from Start up to End.
+type Run struct {
+	Start, End int
+	Script     Script
+}
+
+// Itemize splits text into runs of a single script each. Common characters
+// join the run before them, or the first run if they lead the text.
+func Itemize(text []rune) []Run {
+	var runs []Run
+	for i, r := range text {
+		script := ScriptOf(r)
+		switch {
+		case len(runs) == 0:
+			runs = append(runs, Run{i, i + 1, script})
+		case script == Common || script == runs[len(runs)-1].Script:
+			runs[len(runs)-1].End = i + 1
+		case runs[len(runs)-1].Script == Common:
+			runs[len(runs)-1].End = i + 1
+			runs[len(runs)-1].Script = script
+		default:
+			runs = append(runs, Run{i, i + 1, script})
+		}
+	}
+	return runs
+}
+
+// scriptOfRun returns the script of a run of text, from its first character
+// that is not Common.
+func scriptOfRun(text []rune) Script {
+	for _, r := range text {
+		if script := ScriptOf(r); script != Common {
+			return script
+		}
+	}
+	return Common
+}
+
+// ShapeRuns shapes text with shaper one script run at a time, so that each
+// run is shaped by the rules of its own script, applying features to all of
+// them. ClusterIndex values refer to text as a whole. Runs are returned in
+// logical order, unless all are right to left, as text reordered for display
+// is, when they are in visual order. As with Shape, a nil slice with a nil
+// error means that shaping is unavailable.
+func ShapeRuns(shaper Shaper, text []rune, font FontReader, ppem float32, features []Feature) ([]GlyphPosition, error) {
+	runs := Itemize(text)
+	rtl := len(runs) > 0
+	for _, run := range runs {
+		rtl = rtl && run.Script.IsRightToLeft()
+	}
+	var glyphs []GlyphPosition
+	for k := range runs {
+		run := runs[k]
+		if rtl {
+			run = runs[len(runs)-1-k]
+		}
+		shaped, err := shaper.Shape(text[run.Start:run.End], font, ppem, features)
+		if err != nil || shaped == nil {
+			return nil, err
+		}
+		for _, g := range shaped {
+			g.ClusterIndex += run.Start
+			glyphs = append(glyphs, g)
+		}
+	}
+	return glyphs, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package shaping_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rowland/leadtype/shaping"
+)
+
+func TestScriptOf(t *testing.T) {
+	tests := []struct {
+		r    rune
+		want shaping.Script
+	}{
+		{'a', shaping.Latin},
+		{' ', shaping.Common},
+		{'7', shaping.Common},
+		{'٣', shaping.Common}, // Arabic-Indic digit
+		{'म', shaping.Devanagari},
+		{'ि', shaping.Devanagari}, // vowel sign I, a pre-base matra
+		{'த', shaping.Tamil},
+		{'א', shaping.Hebrew},
+		{'ب', shaping.Arabic},
+		{'ก', shaping.Thai},
+		{'ក', shaping.Khmer},
+		{'က', shaping.Myanmar},
+		{'表', shaping.Unknown},
+	}
+	for _, tt := range tests {
+		if got := shaping.ScriptOf(tt.r); got != tt.want {
+			t.Errorf("ScriptOf(%q) = %s, want %s", tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestScript_properties(t *testing.T) {
+	if !shaping.Hebrew.IsRightToLeft() || shaping.Devanagari.IsRightToLeft() {
+		t.Error("Hebrew should be right to left and Devanagari not")
+	}
+	if !shaping.Tamil.IsComplex() || shaping.Latin.IsComplex() || shaping.Common.IsComplex() {
+		t.Error("Tamil should be complex and Latin and Common not")
+	}
+	if got := shaping.Devanagari.Language(); got != "hi" {
+		t.Errorf("Devanagari.Language() = %q, want %q", got, "hi")
+	}
+	if got := shaping.Unknown.Language(); got != "" {
+		t.Errorf("Unknown.Language() = %q, want empty", got)
+	}
+}
+
+func TestNeedsShaping(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"", false},
+		{"plain Latin text, 123", false},
+		{"Привет", false},
+		{"١٢٣", false},
+		{"नमस्ते", true},
+		{"Hello, வணக்கம்", true},
+		{"שלום", true},
+		{"مرحبا", true},
+		{"สวัสดี", true},
+	}
+	for _, tt := range tests {
+		if got := shaping.NeedsShaping(tt.s); got != tt.want {
+			t.Errorf("NeedsShaping(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestItemize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []shaping.Run
+	}{
+		{"", nil},
+		{"abc", []shaping.Run{{0, 3, shaping.Latin}}},
+		{"12 abc", []shaping.Run{{0, 6, shaping.Latin}}},
+		{"Hindi: नमस्ते!", []shaping.Run{{0, 7, shaping.Latin}, {7, 14, shaping.Devanagari}}},
+		{"தமிழ் 2026 हिन्दी", []shaping.Run{{0, 11, shaping.Tamil}, {11, 17, shaping.Devanagari}}},
+		{"שלום, مرحبا", []shaping.Run{{0, 6, shaping.Hebrew}, {6, 11, shaping.Arabic}}},
+		{"...", []shaping.Run{{0, 3, shaping.Common}}},
+	}
+	for _, tt := range tests {
+		if got := shaping.Itemize([]rune(tt.text)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Itemize(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+// recordingShaper returns one glyph per rune, in logical order, and records
+// the runs it is asked to shape.
+type recordingShaper struct {
+	runs []string
+}
+
+func (s *recordingShaper) Shape(text []rune, _ shaping.FontReader, _ float32, _ []shaping.Feature) ([]shaping.GlyphPosition, error) {
+	s.runs = append(s.runs, string(text))
+	glyphs := make([]shaping.GlyphPosition, len(text))
+	for i, r := range text {
+		glyphs[i] = shaping.GlyphPosition{GlyphID: uint16(r), ClusterIndex: i}
+	}
+	return glyphs, nil
+}
+
+func TestShapeRuns(t *testing.T) {
+	tests := []struct {
+		text     string
+		runs     []string
+		clusters []int
+	}{
+		{"ab कि", []string{"ab ", "कि"}, []int{0, 1, 2, 3, 4}},
+		{"אב مر", []string{"مر", "אב "}, []int{3, 4, 0, 1, 2}},
+	}
+	for _, tt := range tests {
+		s := &recordingShaper{}
+		glyphs, err := shaping.ShapeRuns(s, []rune(tt.text), emptyFontReader{}, 12, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(s.runs, tt.runs) {
+			t.Errorf("ShapeRuns(%q) shaped runs %q, want %q", tt.text, s.runs, tt.runs)
+		}
+		var clusters []int
+		for _, g := range glyphs {
+			clusters = append(clusters, g.ClusterIndex)
+		}
+		if !reflect.DeepEqual(clusters, tt.clusters) {
+			t.Errorf("ShapeRuns(%q) clusters = %v, want %v", tt.text, clusters, tt.clusters)
+		}
+	}
+}
+
+func TestShapeRuns_unavailable(t *testing.T) {
+	glyphs, err := shaping.ShapeRuns(stubShaper{}, []rune("नमस्ते"), emptyFontReader{}, 12, nil)
+	if glyphs != nil || err != nil {
+		t.Errorf("ShapeRuns with no shaping = %v, %v, want nil, nil", glyphs, err)
+	}
+}
+
+type stubShaper struct{}
+
+func (stubShaper) Shape(_ []rune, _ shaping.FontReader, _ float32, _ []shaping.Feature) ([]shaping.GlyphPosition, error) {
+	return nil, nil
+}
@@ -1,7 +1,11 @@
 // Copyright 2024 Brent Rowland.
 // Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
 
-// Package shaping provides Arabic (and complex-script) text shaping.
+// Package shaping provides complex-script text shaping, for Arabic, Hebrew,
+// the Indic scripts, Thai, Khmer, Myanmar and others. Itemize splits text
+// into runs of a single script, and ShapeRuns shapes each with its own rules.
+// Text in any script may also be shaped to apply OpenType features, such as
+// small capitals or tabular figures, parsed with ParseFeatures.
 //
 // Three implementations are available, selected at build time:
 //
@@ -22,6 +26,24 @@ type FontReader interface {
 	Bytes() []byte
 }
 
+// VariableFontReader is an optional interface implemented by fonts that may
+// be instances of variable fonts. Variation returns the user-space
+// coordinates of each axis of the instance, such as {"wght": 650}, or nil
+// for a font that is not an instance. Bytes still returns the variable font,
+// so FontKey must distinguish its instances.
+type VariableFontReader interface {
+	FontReader
+	Variation() map[string]float64
+}
+
+// variationOf returns the axis coordinates of font, or nil.
+func variationOf(font FontReader) map[string]float64 {
+	if vf, ok := font.(VariableFontReader); ok {
+		return vf.Variation()
+	}
+	return nil
+}
+
 // GlyphPosition holds one shaped glyph's identifier and its positioning data.
 //
 // XAdvance, YAdvance, XOffset, and YOffset are expressed in 26.6 fixed-point
@@ -42,17 +64,20 @@ type GlyphPosition struct {
 
 // Shaper shapes a run of Unicode text into positioned glyphs for a given font.
 //
-//   - text is the Arabic (or other complex-script) run to shape.
+//   - text is the run to shape, in a single script, as from Itemize. Shapers
+//     take the script, direction and language from the text.
 //   - font provides the font identity and raw bytes; Bytes() is only called
 //     on a cache miss to avoid unnecessary I/O.
 //   - ppem is the size of the font in the caller's coordinate system (e.g.
 //     points for PDF output). It is used to scale the returned metrics.
+//   - features are OpenType features to turn on or off, in addition to those
+//     the script applies by default, such as ligatures and kerning.
 //
 // The returned slice is in visual order (already reordered for RTL text).
 // A nil slice with a nil error indicates the no-op stub build; callers should
 // fall back to standard font metrics in that case.
 type Shaper interface {
-	Shape(text []rune, font FontReader, ppem float32) ([]GlyphPosition, error)
+	Shape(text []rune, font FontReader, ppem float32, features []Feature) ([]GlyphPosition, error)
 }
 
 // ContainsArabic reports whether s contains any rune in the Arabic Unicode
@@ -12,6 +12,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/rowland/leadtype/shaping"
+	"github.com/rowland/leadtype/ttf"
 )
 
 //go:embed testdata/Amiri-Regular.ttf
@@ -35,7 +36,7 @@ func TestShape_arabic(t *testing.T) {
 	s := shaping.NewShaper()
 	runes := []rune(arabicText)
 
-	glyphs, err := s.Shape(runes, amiri, 12)
+	glyphs, err := s.Shape(runes, amiri, 12, nil)
 	if err != nil {
 		t.Fatalf("Shape: %v", err)
 	}
@@ -59,10 +60,72 @@ func TestShape_arabic(t *testing.T) {
 	}
 }
 
+func TestShape_directionFromScript(t *testing.T) {
+	s := shaping.NewShaper()
+	arabic, err := s.Shape([]rune(arabicText), amiri, 12, nil)
+	if err != nil {
+		t.Fatalf("Shape: %v", err)
+	}
+	if first, last := arabic[0].ClusterIndex, arabic[len(arabic)-1].ClusterIndex; first <= last {
+		t.Errorf("Arabic glyphs should run right to left, got clusters %d..%d", first, last)
+	}
+	latin, err := s.Shape([]rune("Amiri"), amiri, 12, nil)
+	if err != nil {
+		t.Fatalf("Shape: %v", err)
+	}
+	if first, last := latin[0].ClusterIndex, latin[len(latin)-1].ClusterIndex; first >= last {
+		t.Errorf("Latin glyphs should run left to right, got clusters %d..%d", first, last)
+	}
+}
+
+func TestShape_features(t *testing.T) {
+	s := shaping.NewShaper()
+	digits := []rune("1111")
+	plain, err := s.Shape(digits, amiri, 12, nil)
+	if err != nil {
+		t.Fatalf("Shape: %v", err)
+	}
+	proportional, err := s.Shape(digits, amiri, 12, []shaping.Feature{{Tag: "pnum", Value: 1}})
+	if err != nil {
+		t.Fatalf("Shape: %v", err)
+	}
+	if len(plain) != len(proportional) {
+		t.Fatalf("got %d glyphs with pnum, want %d", len(proportional), len(plain))
+	}
+	if plain[0].GlyphID == proportional[0].GlyphID && plain[0].XAdvance == proportional[0].XAdvance {
+		t.Errorf("pnum should change the digits, got glyph %d advance %d both ways", plain[0].GlyphID, plain[0].XAdvance)
+	}
+}
+
+func TestShape_variableFontInstance(t *testing.T) {
+	base, err := ttf.LoadFont("../ttf/testdata/minimal-var.ttf")
+	if err != nil {
+		t.Fatalf("LoadFont: %v", err)
+	}
+	bold, err := base.Instance(map[string]float64{"wght": 650})
+	if err != nil {
+		t.Fatalf("Instance: %v", err)
+	}
+	s := shaping.NewShaper()
+	for _, tt := range []struct {
+		font *ttf.Font
+		want int
+	}{{base, 512}, {bold, 632}, {base, 512}} {
+		glyphs, err := s.Shape([]rune("A"), tt.font, 1000, nil)
+		if err != nil {
+			t.Fatalf("Shape: %v", err)
+		}
+		advance, _ := tt.font.AdvanceWidth('A')
+		if len(glyphs) != 1 || int(glyphs[0].XAdvance/64) != tt.want || advance != tt.want {
+			t.Errorf("%s: shaped %+v, want advance %d matching the font's %d", tt.font.FontKey(), glyphs, tt.want, advance)
+		}
+	}
+}
+
 func TestShape_corruptFont(t *testing.T) {
 	s := shaping.NewShaper()
 	corrupt := staticFontReader{"corrupt", []byte("not a font")}
-	_, err := s.Shape([]rune(arabicText), corrupt, 12)
+	_, err := s.Shape([]rune(arabicText), corrupt, 12, nil)
 	if err == nil {
 		t.Fatal("expected error for corrupt font data, got nil")
 	}
@@ -72,11 +135,11 @@ func TestShape_fontCacheHit(t *testing.T) {
 	s := shaping.NewShaper()
 	runes := []rune(arabicText)
 
-	first, err := s.Shape(runes, amiri, 12)
+	first, err := s.Shape(runes, amiri, 12, nil)
 	if err != nil {
 		t.Fatalf("first Shape: %v", err)
 	}
-	second, err := s.Shape(runes, amiri, 12)
+	second, err := s.Shape(runes, amiri, 12, nil)
 	if err != nil {
 		t.Fatalf("second Shape: %v", err)
 	}
@@ -103,7 +166,7 @@ func TestShape_envFont(t *testing.T) {
 	}
 	s := shaping.NewShaper()
 	fr := staticFontReader{path, data}
-	glyphs, err := s.Shape([]rune(arabicText), fr, 12)
+	glyphs, err := s.Shape([]rune(arabicText), fr, 12, nil)
 	if err != nil {
 		t.Fatalf("Shape: %v", err)
 	}
@@ -12,13 +12,14 @@ import (
 
 	"github.com/go-text/typesetting/di"
 	"github.com/go-text/typesetting/font"
+	ot "github.com/go-text/typesetting/font/opentype"
 	"github.com/go-text/typesetting/language"
 	gotextshaping "github.com/go-text/typesetting/shaping"
 	"golang.org/x/image/math/fixed"
 )
 
-// NewShaper returns a pure-Go Arabic shaper backed by go-text/typesetting
-// with a default font cache size of 1.
+// NewShaper returns a pure-Go complex-script shaper backed by
+// go-text/typesetting with a default font cache size of 1.
 func NewShaper() Shaper { return &goTextShaper{maxSize: 1} }
 
 type fontCacheEntry struct {
@@ -106,9 +107,11 @@ func (s *goTextShaper) parsedFont(fr FontReader) (*font.Font, error) {
 	return loaded.Font, nil
 }
 
-// Shape shapes a run of Arabic text using go-text/typesetting's pure-Go
-// HarfBuzz port. The returned glyphs are in visual (display) order.
-func (s *goTextShaper) Shape(text []rune, fr FontReader, ppem float32) ([]GlyphPosition, error) {
+// Shape shapes a run of text in a single script using go-text/typesetting's
+// pure-Go HarfBuzz port, with the script, direction and language of the run
+// and the given OpenType features, at the font's variation coordinates, if any.
+// The returned glyphs are in visual (display) order.
+func (s *goTextShaper) Shape(text []rune, fr FontReader, ppem float32, features []Feature) ([]GlyphPosition, error) {
 	parsed, err := s.parsedFont(fr)
 	if err != nil {
 		return nil, err
@@ -116,16 +119,41 @@ func (s *goTextShaper) Shape(text []rune, fr FontReader, ppem float32) ([]GlyphP
 
 	// font.Face wraps Font with per-call glyph-extent caching; not thread-safe.
 	face := font.NewFace(parsed)
+	if variation := variationOf(fr); len(variation) > 0 {
+		variations := make([]font.Variation, 0, len(variation))
+		for tag, value := range variation {
+			if validFeatureTag(tag) {
+				variations = append(variations, font.Variation{Tag: ot.MustNewTag(tag), Value: float32(value)})
+			}
+		}
+		face.SetVariations(variations)
+	}
 
+	script := scriptOfRun(text)
+	direction := di.DirectionLTR
+	if script.IsRightToLeft() {
+		direction = di.DirectionRTL
+	}
+	goTextScript, err := language.ParseScript(string(script))
+	if err != nil {
+		goTextScript = language.Common
+	}
+	fontFeatures := make([]gotextshaping.FontFeature, 0, len(features))
+	for _, f := range features {
+		if validFeatureTag(f.Tag) {
+			fontFeatures = append(fontFeatures, gotextshaping.FontFeature{Tag: ot.MustNewTag(f.Tag), Value: f.Value})
+		}
+	}
 	input := gotextshaping.Input{
-		Text:      text,
-		RunStart:  0,
-		RunEnd:    len(text),
-		Direction: di.DirectionRTL,
-		Face:      face,
-		Size:      fixed.Int26_6(ppem * 64),
-		Script:    language.Arabic,
-		Language:  language.NewLanguage("ar"),
+		Text:         text,
+		RunStart:     0,
+		RunEnd:       len(text),
+		Direction:    direction,
+		Face:         face,
+		Size:         fixed.Int26_6(ppem * 64),
+		Script:       goTextScript,
+		Language:     language.NewLanguage(script.Language()),
+		FontFeatures: fontFeatures,
 	}
 
 	s.mu.Lock()
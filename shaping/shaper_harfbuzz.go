@@ -14,18 +14,20 @@ import (
 	"unsafe"
 )
 
-// NewShaper returns a CGO Arabic shaper backed by the system libharfbuzz.
+// NewShaper returns a CGO complex-script shaper backed by the system libharfbuzz.
 // Build with: go build -tags harfbuzz ./...
 // Requires: libharfbuzz-dev (or equivalent) installed on the build host.
 func NewShaper() Shaper { return &hbShaper{} }
 
 type hbShaper struct{}
 
-// Shape shapes a run of Arabic text using the system libharfbuzz via CGO.
+// Shape shapes a run of text in a single script using the system libharfbuzz
+// via CGO, with the script, direction and language of the run and the given
+// OpenType features, at the font's variation coordinates, if any.
 // The returned glyphs are in visual (display) order.
 // TODO: add a size-1 cache here (keyed by fr.FontKey()) to avoid re-creating
 // the hb_face_t on every call, as in the pure-Go backend.
-func (h *hbShaper) Shape(text []rune, fr FontReader, ppem float32) ([]GlyphPosition, error) {
+func (h *hbShaper) Shape(text []rune, fr FontReader, ppem float32, features []Feature) ([]GlyphPosition, error) {
 	fontBytes := fr.Bytes()
 	if len(fontBytes) == 0 {
 		return nil, fmt.Errorf("shaping: no font data for %q", fr.FontKey())
@@ -52,15 +54,39 @@ func (h *hbShaper) Shape(text []rune, fr FontReader, ppem float32) ([]GlyphPosit
 	scale := C.int(ppem * 64)
 	C.hb_font_set_scale(hbFont, scale, scale)
 
+	if variation := variationOf(fr); len(variation) > 0 {
+		// hb_variation_t holds no pointers, so the slice may be passed to C directly.
+		hbVariations := make([]C.hb_variation_t, 0, len(variation))
+		for tag, value := range variation {
+			cTag := C.CString(tag)
+			hbVariations = append(hbVariations, C.hb_variation_t{
+				tag:   C.hb_tag_from_string(cTag, -1),
+				value: C.float(value),
+			})
+			C.free(unsafe.Pointer(cTag))
+		}
+		C.hb_font_set_variations(hbFont, &hbVariations[0], C.uint(len(hbVariations)))
+	}
+
 	// Build the shaping buffer.
 	buf := C.hb_buffer_create()
 	defer C.hb_buffer_destroy(buf)
 
-	C.hb_buffer_set_direction(buf, C.HB_DIRECTION_RTL)
-	C.hb_buffer_set_script(buf, C.HB_SCRIPT_ARABIC)
-
-	lang := C.hb_language_from_string(C.CString("ar"), -1)
-	C.hb_buffer_set_language(buf, lang)
+	script := scriptOfRun(text)
+	if script.IsRightToLeft() {
+		C.hb_buffer_set_direction(buf, C.HB_DIRECTION_RTL)
+	} else {
+		C.hb_buffer_set_direction(buf, C.HB_DIRECTION_LTR)
+	}
+	scriptTag := C.CString(string(script))
+	defer C.free(unsafe.Pointer(scriptTag))
+	C.hb_buffer_set_script(buf, C.hb_script_from_string(scriptTag, -1))
+
+	if language := script.Language(); language != "" {
+		languageTag := C.CString(language)
+		defer C.free(unsafe.Pointer(languageTag))
+		C.hb_buffer_set_language(buf, C.hb_language_from_string(languageTag, -1))
+	}
 
 	// Encode the rune slice as UTF-8 and add it to the buffer.
 	utf8 := []byte(string(text))
@@ -69,7 +95,21 @@ func (h *hbShaper) Shape(text []rune, fr FontReader, ppem float32) ([]GlyphPosit
 	C.hb_buffer_add_utf8(buf, cs, C.int(len(utf8)), 0, C.int(len(utf8)))
 
 	// Shape.
-	C.hb_shape(hbFont, buf, nil, 0)
+	// hb_feature_t holds no pointers, so the slice may be passed to C directly.
+	hbFeatures := make([]C.hb_feature_t, len(features))
+	for i, f := range features {
+		tag := C.CString(f.Tag)
+		hbFeatures[i].tag = C.hb_tag_from_string(tag, -1)
+		C.free(unsafe.Pointer(tag))
+		hbFeatures[i].value = C.uint32_t(f.Value)
+		hbFeatures[i].start = 0
+		hbFeatures[i].end = C.uint(^uint32(0)) // HB_FEATURE_GLOBAL_END
+	}
+	var featuresPtr *C.hb_feature_t
+	if len(hbFeatures) > 0 {
+		featuresPtr = &hbFeatures[0]
+	}
+	C.hb_shape(hbFont, buf, featuresPtr, C.uint(len(hbFeatures)))
 
 	// Retrieve results.
 	var count C.uint
@@ -25,7 +25,7 @@ func (emptyFontReader) Bytes() []byte   { return nil }
 // The stub returns nil, nil; active shapers should return an error.
 func TestShape_emptyFont(t *testing.T) {
 	s := shaping.NewShaper()
-	glyphs, err := s.Shape([]rune("مرحبا"), emptyFontReader{}, 12)
+	glyphs, err := s.Shape([]rune("مرحبا"), emptyFontReader{}, 12, nil)
 	if err == nil && glyphs != nil {
 		t.Errorf("expected nil glyphs for empty font data, got %d glyphs", len(glyphs))
 	}
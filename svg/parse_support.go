@@ -9,6 +9,15 @@ import (
 )
 
 func parseColor(text string) (Paint, error) {
+	// The cmyk(), device-cmyk() and spot() notations are parsed before the
+	// text is lowercased, to keep the case of spot color names.
+	if trimmed := strings.TrimSpace(text); strings.HasSuffix(trimmed, ")") && !strings.HasPrefix(strings.ToLower(trimmed), "rgb(") {
+		named, err := colors.NamedColor(trimmed)
+		if err != nil {
+			return Paint{}, err
+		}
+		return Paint{Set: true, Color: named}, nil
+	}
 	text = strings.TrimSpace(strings.ToLower(text))
 	if text == "" {
 		return Paint{}, fmt.Errorf("empty color")
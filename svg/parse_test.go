@@ -1,6 +1,10 @@
 package svg
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/rowland/leadtype/colors"
+)
 
 func TestParseRootUsesViewBoxWhenSizeMissing(t *testing.T) {
 	doc, warnings, err := Parse([]byte(`<svg viewBox="0 0 200 100"><rect width="200" height="100"/></svg>`))
@@ -62,6 +66,23 @@ func TestParseColorFormats(t *testing.T) {
 	}
 }
 
+func TestParseColorCMYK(t *testing.T) {
+	paint, err := parseColor("device-cmyk(0 1 1 0)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paint.Color != colors.CMYK(0, 1, 1, 0) {
+		t.Fatalf("device-cmyk = %v, want cmyk(0, 1, 1, 0)", paint.Color)
+	}
+	paint, err = parseColor("spot(Brand Red, cmyk(0, 0.9, 0.8, 0), 0.5)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := paint.Color.SpotName(); name != "Brand Red" {
+		t.Fatalf("spot name = %q, want %q", name, "Brand Red")
+	}
+}
+
 func TestParseLengthUnits(t *testing.T) {
 	if got, err := parseLength("1in", 100); err != nil || got != 72 {
 		t.Fatalf("1in = %v, %v", got, err)
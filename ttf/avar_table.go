@@ -0,0 +1,75 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ttf
+
+import (
+	"io"
+	"os"
+)
+
+// avarTable holds the segment maps of an avar table, which adjust the
+// normalized coordinates of each axis of a variable font.
+type avarTable struct {
+	segmentMaps [][][2]float64 // per axis, ascending from and to coordinates
+}
+
+func (table *avarTable) init(rs io.ReadSeeker, entry *tableDirEntry) (err error) {
+	if _, err = rs.Seek(int64(entry.offset), os.SEEK_SET); err != nil {
+		return
+	}
+	data := make([]byte, entry.length)
+	if _, err = io.ReadFull(rs, data); err != nil {
+		return
+	}
+	table.parse(data)
+	return nil
+}
+
+func (table *avarTable) parse(data []byte) {
+	r := tableReader(data)
+	if r.u16(0) != 1 {
+		return
+	}
+	axisCount := int(r.u16(6))
+	pos := 8
+	for i := 0; i < axisCount; i++ {
+		count := int(r.u16(pos))
+		if pos+2+4*count > len(data) {
+			table.segmentMaps = nil
+			return
+		}
+		segments := make([][2]float64, count)
+		for j := range segments {
+			segments[j] = [2]float64{r.f2dot14(pos + 2 + 4*j), r.f2dot14(pos + 4 + 4*j)}
+		}
+		table.segmentMaps = append(table.segmentMaps, segments)
+		pos += 2 + 4*count
+	}
+}
+
+// mapCoord maps the normalized coordinate v of an axis through its segment
+// map, interpolating between the segments' ends.
+func (table *avarTable) mapCoord(axis int, v float64) float64 {
+	if axis >= len(table.segmentMaps) || len(table.segmentMaps[axis]) == 0 {
+		return v
+	}
+	segments := table.segmentMaps[axis]
+	first, last := segments[0], segments[len(segments)-1]
+	if v <= first[0] {
+		return v + first[1] - first[0]
+	}
+	if v >= last[0] {
+		return v + last[1] - last[0]
+	}
+	for i := 1; i < len(segments); i++ {
+		from, to := segments[i-1], segments[i]
+		if v <= to[0] {
+			if to[0] == from[0] {
+				return to[1]
+			}
+			return from[1] + (v-from[0])*(to[1]-from[1])/(to[0]-from[0])
+		}
+	}
+	return v
+}
@@ -0,0 +1,719 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ttf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+)
+
+// cffTable holds the parts of the CFF table of an OpenType font with CFF
+// outlines (Adobe Technical Note #5176) needed to subset it.
+type cffTable struct {
+	names       [][]byte
+	topDict     cffDict
+	strings     [][]byte
+	globalSubrs [][]byte
+	charStrings [][]byte
+	charset     []uint16 // SID, or CID if cidKeyed, of each glyph after .notdef; nil if predefined
+	cidKeyed    bool
+	fdSelect    []uint8   // Font DICT of each glyph, if cidKeyed
+	fonts       []cffFont // the font's Private DICT, or one for each Font DICT if cidKeyed
+}
+
+// cffFont is a Private DICT and its local subroutines, with the Font DICT
+// that refers to it in a CID-keyed font.
+type cffFont struct {
+	dict    cffDict
+	private cffDict
+	subrs   [][]byte
+}
+
+// DICT operators; two-byte operators are 12 followed by the second byte.
+const (
+	cffOpEncoding    = 16
+	cffOpCharset     = 15
+	cffOpCharStrings = 17
+	cffOpPrivate     = 18
+	cffOpSubrs       = 19
+	cffOpROS         = 12<<8 | 30
+	cffOpCIDCount    = 12<<8 | 34
+	cffOpFDArray     = 12<<8 | 36
+	cffOpFDSelect    = 12<<8 | 37
+)
+
+var errCFFDamaged = errors.New("damaged CFF table")
+var errCFFVersion = errors.New("unsupported CFF version")
+
+// parseCFF reads a CFF table. Only version 1 tables are supported; CFF2
+// tables, as in variable fonts, are not.
+func parseCFF(data []byte) (cff *cffTable, err error) {
+	if len(data) < 4 {
+		return nil, errCFFDamaged
+	}
+	if data[0] != 1 {
+		return nil, errCFFVersion
+	}
+	cff = new(cffTable)
+	pos := int(data[2])
+	if cff.names, pos, err = readCFFIndex(data, pos); err != nil {
+		return
+	}
+	if len(cff.names) == 0 {
+		return nil, errCFFDamaged
+	}
+	var topDicts [][]byte
+	if topDicts, pos, err = readCFFIndex(data, pos); err != nil {
+		return
+	}
+	if len(topDicts) == 0 {
+		return nil, errCFFDamaged
+	}
+	if cff.topDict, err = parseCFFDict(topDicts[0]); err != nil {
+		return
+	}
+	if cff.strings, pos, err = readCFFIndex(data, pos); err != nil {
+		return
+	}
+	if cff.globalSubrs, _, err = readCFFIndex(data, pos); err != nil {
+		return
+	}
+	offset, ok := cff.topDict.int(cffOpCharStrings)
+	if !ok {
+		return nil, errCFFDamaged
+	}
+	if cff.charStrings, _, err = readCFFIndex(data, offset); err != nil {
+		return
+	}
+	numGlyphs := len(cff.charStrings)
+	if numGlyphs == 0 {
+		return nil, errCFFDamaged
+	}
+	if offset, _ := cff.topDict.int(cffOpCharset); offset > 2 {
+		if cff.charset, err = readCFFCharset(data, offset, numGlyphs); err != nil {
+			return
+		}
+	}
+	if _, cff.cidKeyed = cff.topDict.entry(cffOpROS); !cff.cidKeyed {
+		font, err := readCFFPrivate(data, cff.topDict)
+		if err != nil {
+			return nil, err
+		}
+		cff.fonts = []cffFont{font}
+		return cff, nil
+	}
+	if cff.charset == nil {
+		// CID-keyed fonts have no predefined charsets.
+		return nil, errCFFDamaged
+	}
+	offset, ok = cff.topDict.int(cffOpFDArray)
+	if !ok {
+		return nil, errCFFDamaged
+	}
+	fontDicts, _, err := readCFFIndex(data, offset)
+	if err != nil {
+		return
+	}
+	for _, b := range fontDicts {
+		dict, err := parseCFFDict(b)
+		if err != nil {
+			return nil, err
+		}
+		font, err := readCFFPrivate(data, dict)
+		if err != nil {
+			return nil, err
+		}
+		font.dict = dict
+		cff.fonts = append(cff.fonts, font)
+	}
+	offset, ok = cff.topDict.int(cffOpFDSelect)
+	if !ok {
+		return nil, errCFFDamaged
+	}
+	cff.fdSelect, err = readCFFFDSelect(data, offset, numGlyphs, len(cff.fonts))
+	return
+}
+
+// checkCFF parses the CFF table at entry, so that a damaged table is
+// reported when the font is loaded rather than when it is subset.
+func checkCFF(rs io.ReadSeeker, entry *tableDirEntry) (err error) {
+	if _, err = rs.Seek(int64(entry.offset), os.SEEK_SET); err != nil {
+		return
+	}
+	data := make([]byte, entry.length)
+	if _, err = io.ReadFull(rs, data); err != nil {
+		return
+	}
+	_, err = parseCFF(data)
+	return
+}
+
+// subset returns the CFF table of a font of the first numGlyphs glyphs, in
+// which glyphs not in closure are left empty. Glyph IDs are unchanged and, in
+// a CID-keyed font, become the CIDs, as PDF's Identity-H encoding of the
+// glyphs requires. Subroutines are kept whole.
+func (cff *cffTable) subset(closure map[uint16]bool, numGlyphs int) []byte {
+	numGlyphs = min(numGlyphs, len(cff.charStrings))
+	charStrings := make([][]byte, numGlyphs)
+	for i := range charStrings {
+		if closure[uint16(i)] {
+			charStrings[i] = cff.charStrings[i]
+		} else {
+			charStrings[i] = []byte{cffEndchar}
+		}
+	}
+
+	topDict := cff.topDict.without(cffOpEncoding)
+	var charset, fdSelect bytes.Buffer
+	if cff.cidKeyed {
+		charset.WriteByte(2) // format 2, with CIDs equal to glyph IDs
+		if numGlyphs > 1 {
+			writeUint16(&charset, 1)
+			writeUint16(&charset, uint16(numGlyphs-2))
+		}
+		fdSelect.WriteByte(0) // format 0: a Font DICT for each glyph
+		fdSelect.Write(cff.fdSelect[:numGlyphs])
+		topDict = topDict.with(cffOpCIDCount, numGlyphs)
+	} else if cff.charset != nil {
+		charset.WriteByte(0) // format 0
+		for _, sid := range cff.charset[:numGlyphs-1] {
+			writeUint16(&charset, sid)
+		}
+	}
+
+	fonts := make([]cffFont, len(cff.fonts))
+	for i, font := range cff.fonts {
+		fonts[i] = font
+		fonts[i].private = font.private.without(cffOpSubrs)
+		if len(font.subrs) > 0 {
+			// Local subroutines follow their Private DICT, whose length
+			// includes the offset to them.
+			fonts[i].private = fonts[i].private.with(cffOpSubrs, 0)
+			fonts[i].private = fonts[i].private.with(cffOpSubrs, len(fonts[i].private.bytes()))
+		}
+	}
+	// layout sets the offsets in the Top DICT of the data following the
+	// Global Subr INDEX at base, and returns where the Private DICTs begin.
+	// Offsets are written in the 5-byte form, so the Top DICT's length does
+	// not depend on them.
+	layout := func(base int) (cffDict, int) {
+		d := topDict
+		if charset.Len() > 0 {
+			d = d.with(cffOpCharset, base)
+			base += charset.Len()
+		}
+		if cff.cidKeyed {
+			d = d.with(cffOpFDSelect, base)
+			base += fdSelect.Len()
+		}
+		d = d.with(cffOpCharStrings, base)
+		base += cffIndexLength(charStrings)
+		if !cff.cidKeyed {
+			return d.with(cffOpPrivate, len(fonts[0].private.bytes()), base), base
+		}
+		privates := base
+		for _, font := range fonts {
+			base += len(font.private.bytes()) + cffIndexLength(font.subrs)
+		}
+		return d.with(cffOpFDArray, base), privates
+	}
+	topDict, _ = layout(0)
+	topDict, privates := layout(4 + cffIndexLength(cff.names[:1]) + cffIndexLength([][]byte{topDict.bytes()}) +
+		cffIndexLength(cff.strings) + cffIndexLength(cff.globalSubrs))
+
+	var b bytes.Buffer
+	b.Write([]byte{1, 0, 4, 4}) // version 1.0, header size, offset size
+	writeCFFIndex(&b, cff.names[:1])
+	writeCFFIndex(&b, [][]byte{topDict.bytes()})
+	writeCFFIndex(&b, cff.strings)
+	writeCFFIndex(&b, cff.globalSubrs)
+	b.Write(charset.Bytes())
+	b.Write(fdSelect.Bytes())
+	writeCFFIndex(&b, charStrings)
+	for _, font := range fonts {
+		b.Write(font.private.bytes())
+		writeCFFIndex(&b, font.subrs)
+	}
+	if cff.cidKeyed {
+		fontDicts := make([][]byte, len(fonts))
+		for i, font := range fonts {
+			size := len(font.private.bytes())
+			fontDicts[i] = font.dict.with(cffOpPrivate, size, privates).bytes()
+			privates += size + cffIndexLength(font.subrs)
+		}
+		writeCFFIndex(&b, fontDicts)
+	}
+	return b.Bytes()
+}
+
+const cffEndchar = 14
+
+// seacComponents returns the base and accent glyphs of glyph gid if its
+// charstring ends with the deprecated seac-like form of endchar, which
+// builds an accented character from two others given by their codes in
+// Adobe's StandardEncoding. Only fonts that are not CID-keyed may use it.
+func (cff *cffTable) seacComponents(gid uint16) (base, accent uint16, ok bool) {
+	if cff.cidKeyed || int(gid) >= len(cff.charStrings) {
+		return
+	}
+	bchar, achar, ok := cff.seacCodes(cff.charStrings[gid])
+	if !ok {
+		return
+	}
+	base, ok = cff.glyphForSID(cffStandardSID(bchar))
+	if !ok {
+		return
+	}
+	accent, ok = cff.glyphForSID(cffStandardSID(achar))
+	return
+}
+
+// seacCodes runs a Type 2 charstring as far as its endchar, following
+// subroutine calls, and returns the character codes of the endchar's seac
+// operands, if it has them. Only the operators that affect the stack or how
+// the charstring is read are interpreted.
+func (cff *cffTable) seacCodes(charString []byte) (bchar, achar int, ok bool) {
+	var stack []int
+	stems := 0
+	// run returns true once the charstring has ended.
+	var run func(cs []byte, depth int) bool
+	run = func(cs []byte, depth int) bool {
+		if depth > 10 {
+			return true
+		}
+		for i := 0; i < len(cs); {
+			b0 := int(cs[i])
+			switch {
+			case b0 >= 32 && b0 <= 246:
+				stack = append(stack, b0-139)
+				i++
+				continue
+			case b0 >= 247 && b0 <= 254:
+				if i+2 > len(cs) {
+					return true
+				}
+				if b0 <= 250 {
+					stack = append(stack, (b0-247)*256+int(cs[i+1])+108)
+				} else {
+					stack = append(stack, -(b0-251)*256-int(cs[i+1])-108)
+				}
+				i += 2
+				continue
+			case b0 == 28:
+				if i+3 > len(cs) {
+					return true
+				}
+				stack = append(stack, int(int16(binary.BigEndian.Uint16(cs[i+1:]))))
+				i += 3
+				continue
+			case b0 == 255:
+				if i+5 > len(cs) {
+					return true
+				}
+				// A 16.16 fixed-point number; only its integer part is kept.
+				stack = append(stack, int(int32(binary.BigEndian.Uint32(cs[i+1:]))>>16))
+				i += 5
+				continue
+			}
+			i++
+			switch b0 {
+			case 1, 3, 18, 23: // hstem, vstem, hstemhm, vstemhm
+				stems += len(stack) / 2
+			case 19, 20: // hintmask, cntrmask, with implied vstem operands
+				stems += len(stack) / 2
+				i += (stems + 7) / 8
+			case 10, 29: // callsubr, callgsubr
+				if len(stack) == 0 {
+					return true
+				}
+				subrs := cff.globalSubrs
+				if b0 == 10 {
+					subrs = cff.fonts[0].subrs
+				}
+				n := stack[len(stack)-1] + cffSubrBias(len(subrs))
+				stack = stack[:len(stack)-1]
+				if n < 0 || n >= len(subrs) || run(subrs[n], depth+1) {
+					return true
+				}
+				continue
+			case 11: // return
+				return false
+			case cffEndchar:
+				if len(stack) >= 4 {
+					bchar, achar, ok = stack[len(stack)-2], stack[len(stack)-1], true
+				}
+				return true
+			case 12: // escape to a two-byte operator
+				i++
+			}
+			stack = stack[:0]
+		}
+		return false
+	}
+	run(charString, 0)
+	return
+}
+
+// cffSubrBias returns the bias added to the operands of callsubr and
+// callgsubr for a subroutine INDEX of count subroutines.
+func cffSubrBias(count int) int {
+	switch {
+	case count < 1240:
+		return 107
+	case count < 33900:
+		return 1131
+	}
+	return 32768
+}
+
+// glyphForSID returns the glyph named by the string sid.
+func (cff *cffTable) glyphForSID(sid int) (uint16, bool) {
+	if sid == 0 {
+		return 0, false
+	}
+	if cff.charset == nil {
+		// In the predefined ISOAdobe charset, glyph IDs are SIDs.
+		return uint16(sid), sid < len(cff.charStrings)
+	}
+	for i, s := range cff.charset {
+		if int(s) == sid {
+			return uint16(i + 1), true
+		}
+	}
+	return 0, false
+}
+
+// cffStandardSID returns the SID of the glyph name code has in Adobe's
+// StandardEncoding (CFF spec Appendix B), or 0 if it has none.
+func cffStandardSID(code int) int {
+	switch {
+	case code >= 32 && code <= 126:
+		return code - 31
+	case code >= 161 && code <= 251:
+		return int(cffStandardHighSIDs[code-161])
+	}
+	return 0
+}
+
+// cffStandardHighSIDs holds the SIDs of StandardEncoding codes 161 to 251.
+var cffStandardHighSIDs = [...]uint8{
+	96, 97, 98, 99, 100, 101, 102, 103, 104, 105, 106, 107, 108, 109, 110, 0,
+	111, 112, 113, 114, 0, 115, 116, 117, 118, 119, 120, 121, 122, 0, 123, 0,
+	124, 125, 126, 127, 128, 129, 130, 131, 0, 132, 133, 0, 134, 135, 136, 137,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	138, 0, 139, 0, 0, 0, 0, 140, 141, 142, 143, 0, 0, 0, 0, 0,
+	144, 0, 0, 0, 145, 0, 0, 146, 147, 148, 149,
+}
+
+// readCFFIndex returns the items of the INDEX at pos and the position
+// following it.
+func readCFFIndex(data []byte, pos int) (items [][]byte, end int, err error) {
+	r := tableReader(data)
+	if pos < 0 || pos+2 > len(data) {
+		return nil, 0, errCFFDamaged
+	}
+	count := int(r.u16(pos))
+	if count == 0 {
+		return nil, pos + 2, nil
+	}
+	if pos+3 > len(data) {
+		return nil, 0, errCFFDamaged
+	}
+	offSize := int(data[pos+2])
+	if offSize < 1 || offSize > 4 || pos+3+(count+1)*offSize > len(data) {
+		return nil, 0, errCFFDamaged
+	}
+	offset := func(i int) int {
+		var v int
+		for _, b := range data[pos+3+i*offSize : pos+3+(i+1)*offSize] {
+			v = v<<8 | int(b)
+		}
+		return v
+	}
+	// Offsets count from 1, at the byte before the data.
+	base := pos + 3 + (count+1)*offSize - 1
+	items = make([][]byte, count)
+	for i := range items {
+		start, end := offset(i), offset(i+1)
+		if start < 1 || end < start || base+end > len(data) {
+			return nil, 0, errCFFDamaged
+		}
+		items[i] = data[base+start : base+end]
+	}
+	return items, base + offset(count), nil
+}
+
+// writeCFFIndex writes an INDEX with 4-byte offsets.
+func writeCFFIndex(b *bytes.Buffer, items [][]byte) {
+	writeUint16(b, uint16(len(items)))
+	if len(items) == 0 {
+		return
+	}
+	b.WriteByte(4)
+	offset := uint32(1)
+	writeUint32(b, offset)
+	for _, item := range items {
+		offset += uint32(len(item))
+		writeUint32(b, offset)
+	}
+	for _, item := range items {
+		b.Write(item)
+	}
+}
+
+// cffIndexLength returns the length of items written by writeCFFIndex.
+func cffIndexLength(items [][]byte) int {
+	if len(items) == 0 {
+		return 2
+	}
+	n := 3 + 4*(len(items)+1)
+	for _, item := range items {
+		n += len(item)
+	}
+	return n
+}
+
+// readCFFCharset returns the SIDs or CIDs of the glyphs after .notdef.
+func readCFFCharset(data []byte, pos, numGlyphs int) ([]uint16, error) {
+	r := tableReader(data)
+	if pos < 0 || pos >= len(data) {
+		return nil, errCFFDamaged
+	}
+	charset := make([]uint16, 0, numGlyphs-1)
+	format := data[pos]
+	pos++
+	for len(charset) < numGlyphs-1 {
+		switch format {
+		case 0:
+			if pos+2 > len(data) {
+				return nil, errCFFDamaged
+			}
+			charset = append(charset, r.u16(pos))
+			pos += 2
+		case 1, 2:
+			size := 3 + int(format-1)
+			if pos+size > len(data) {
+				return nil, errCFFDamaged
+			}
+			first, left := int(r.u16(pos)), int(data[pos+2])
+			if format == 2 {
+				left = int(r.u16(pos + 2))
+			}
+			for i := 0; i <= left && len(charset) < numGlyphs-1; i++ {
+				charset = append(charset, uint16(first+i))
+			}
+			pos += size
+		default:
+			return nil, errCFFDamaged
+		}
+	}
+	return charset, nil
+}
+
+// readCFFFDSelect returns the Font DICT of each glyph.
+func readCFFFDSelect(data []byte, pos, numGlyphs, numFonts int) ([]uint8, error) {
+	r := tableReader(data)
+	if pos < 0 || pos >= len(data) {
+		return nil, errCFFDamaged
+	}
+	fdSelect := make([]uint8, numGlyphs)
+	switch data[pos] {
+	case 0:
+		if pos+1+numGlyphs > len(data) {
+			return nil, errCFFDamaged
+		}
+		copy(fdSelect, data[pos+1:])
+	case 3:
+		nRanges := int(r.u16(pos + 1))
+		if pos+3+3*nRanges+2 > len(data) {
+			return nil, errCFFDamaged
+		}
+		for i := 0; i < nRanges; i++ {
+			rec := pos + 3 + 3*i
+			first, fd, next := int(r.u16(rec)), data[rec+2], int(r.u16(rec+3))
+			for gid := first; gid < next && gid < numGlyphs; gid++ {
+				fdSelect[gid] = fd
+			}
+		}
+	default:
+		return nil, errCFFDamaged
+	}
+	for _, fd := range fdSelect {
+		if int(fd) >= numFonts {
+			return nil, errCFFDamaged
+		}
+	}
+	return fdSelect, nil
+}
+
+// readCFFPrivate reads the Private DICT, and the local subroutines, to which
+// dict refers.
+func readCFFPrivate(data []byte, dict cffDict) (font cffFont, err error) {
+	values, ok := dict.ints(cffOpPrivate)
+	if !ok || len(values) != 2 {
+		return font, errCFFDamaged
+	}
+	size, offset := values[0], values[1]
+	if size < 0 || offset < 0 || offset+size > len(data) {
+		return font, errCFFDamaged
+	}
+	if font.private, err = parseCFFDict(data[offset : offset+size]); err != nil {
+		return
+	}
+	if subrs, ok := font.private.int(cffOpSubrs); ok {
+		// The offset is from the start of the Private DICT.
+		font.subrs, _, err = readCFFIndex(data, offset+subrs)
+	}
+	return
+}
+
+// cffDict is the operators of a DICT, in order, with their operands as
+// encoded.
+type cffDict []cffDictEntry
+
+type cffDictEntry struct {
+	op       int
+	operands [][]byte
+}
+
+func parseCFFDict(data []byte) (dict cffDict, err error) {
+	var operands [][]byte
+	for i := 0; i < len(data); {
+		b0 := data[i]
+		if b0 <= 21 {
+			op := int(b0)
+			i++
+			if b0 == 12 {
+				if i >= len(data) {
+					return nil, errCFFDamaged
+				}
+				op = 12<<8 | int(data[i])
+				i++
+			}
+			dict = append(dict, cffDictEntry{op, operands})
+			operands = nil
+			continue
+		}
+		var n int
+		switch {
+		case b0 == 28:
+			n = 3
+		case b0 == 29:
+			n = 5
+		case b0 == 30:
+			// A real number, in nibbles ending with 0xf.
+			for n = 1; i+n < len(data); n++ {
+				if data[i+n]>>4 == 0xf || data[i+n]&0xf == 0xf {
+					break
+				}
+			}
+			n++
+		case b0 >= 32 && b0 <= 246:
+			n = 1
+		case b0 >= 247 && b0 <= 254:
+			n = 2
+		default:
+			return nil, errCFFDamaged
+		}
+		if i+n > len(data) {
+			return nil, errCFFDamaged
+		}
+		operands = append(operands, data[i:i+n])
+		i += n
+	}
+	return dict, nil
+}
+
+func (dict cffDict) entry(op int) (cffDictEntry, bool) {
+	for _, e := range dict {
+		if e.op == op {
+			return e, true
+		}
+	}
+	return cffDictEntry{}, false
+}
+
+// ints returns the integer operands of op; real operands are 0.
+func (dict cffDict) ints(op int) ([]int, bool) {
+	e, ok := dict.entry(op)
+	if !ok {
+		return nil, false
+	}
+	values := make([]int, len(e.operands))
+	for i, operand := range e.operands {
+		values[i] = cffOperandInt(operand)
+	}
+	return values, true
+}
+
+// int returns the last operand of op.
+func (dict cffDict) int(op int) (int, bool) {
+	values, ok := dict.ints(op)
+	if !ok || len(values) == 0 {
+		return 0, false
+	}
+	return values[len(values)-1], true
+}
+
+// with returns a copy of dict with op set to values, in the 5-byte integer
+// form, in place of any operands it had.
+func (dict cffDict) with(op int, values ...int) cffDict {
+	operands := make([][]byte, len(values))
+	for i, v := range values {
+		operands[i] = binary.BigEndian.AppendUint32([]byte{29}, uint32(int32(v)))
+	}
+	clone := make(cffDict, len(dict), len(dict)+1)
+	copy(clone, dict)
+	for i, e := range clone {
+		if e.op == op {
+			clone[i].operands = operands
+			return clone
+		}
+	}
+	return append(clone, cffDictEntry{op, operands})
+}
+
+// without returns a copy of dict without op.
+func (dict cffDict) without(op int) cffDict {
+	clone := make(cffDict, 0, len(dict))
+	for _, e := range dict {
+		if e.op != op {
+			clone = append(clone, e)
+		}
+	}
+	return clone
+}
+
+func (dict cffDict) bytes() []byte {
+	var b bytes.Buffer
+	for _, e := range dict {
+		for _, operand := range e.operands {
+			b.Write(operand)
+		}
+		if e.op > 0xFF {
+			b.WriteByte(byte(e.op >> 8))
+		}
+		b.WriteByte(byte(e.op))
+	}
+	return b.Bytes()
+}
+
+func cffOperandInt(operand []byte) int {
+	switch b0 := int(operand[0]); {
+	case b0 == 28:
+		return int(int16(binary.BigEndian.Uint16(operand[1:])))
+	case b0 == 29:
+		return int(int32(binary.BigEndian.Uint32(operand[1:])))
+	case b0 >= 32 && b0 <= 246:
+		return b0 - 139
+	case b0 >= 247 && b0 <= 250:
+		return (b0-247)*256 + int(operand[1]) + 108
+	case b0 >= 251 && b0 <= 254:
+		return -(b0-251)*256 - int(operand[1]) - 108
+	}
+	return 0
+}
@@ -0,0 +1,211 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ttf
+
+import (
+	"bytes"
+	"testing"
+)
+
+const (
+	minimalOTF    = "testdata/minimal.otf"
+	minimalCIDOTF = "testdata/minimal-cid.otf"
+)
+
+func loadCFF(t *testing.T, data []byte) *cffTable {
+	t.Helper()
+	entry := mustFindTable(t, data, "CFF ")
+	cff, err := parseCFF(data[entry.offset : entry.offset+entry.length])
+	if err != nil {
+		t.Fatalf("parseCFF: %v", err)
+	}
+	return cff
+}
+
+func TestMinimalOTF_Metrics(t *testing.T) {
+	font, err := LoadFont(minimalOTF)
+	if err != nil {
+		t.Fatalf("LoadFont: %v", err)
+	}
+	expectS(t, "Family", "MinimalCFF", font.Family())
+	expectS(t, "PostScriptName", "MinimalCFF", font.PostScriptName())
+	expect(t, "HasCFFOutlines", font.HasCFFOutlines())
+	expectI(t, "UnitsPerEm", 1000, font.UnitsPerEm())
+	expectI(t, "NumGlyphs", 188, font.NumGlyphs())
+	width, notFound := font.AdvanceWidth('A')
+	expect(t, "A found", !notFound)
+	expectI(t, "AdvanceWidth", 512, width)
+
+	ttf, err := LoadFont(minimalTTF)
+	if err != nil {
+		t.Fatalf("LoadFont: %v", err)
+	}
+	expect(t, "TrueType HasCFFOutlines", !ttf.HasCFFOutlines())
+}
+
+func TestParseCFF(t *testing.T) {
+	cff := loadCFF(t, loadRawFont(t, minimalOTF))
+	expect(t, "cidKeyed", !cff.cidKeyed)
+	expectI(t, "charStrings", 188, len(cff.charStrings))
+	expectI(t, "charset", 187, len(cff.charset))
+	expectI(t, "globalSubrs", 1, len(cff.globalSubrs))
+	expectI(t, "fonts", 1, len(cff.fonts))
+	expectI(t, "subrs", 1, len(cff.fonts[0].subrs))
+	defaultWidth, _ := cff.fonts[0].private.int(20)
+	expectI(t, "defaultWidthX", 512, defaultWidth)
+	expectS(t, "name", "MinimalCFF", string(cff.names[0]))
+
+	cid := loadCFF(t, loadRawFont(t, minimalCIDOTF))
+	expect(t, "cidKeyed", cid.cidKeyed)
+	expectI(t, "charStrings", 188, len(cid.charStrings))
+	expectI(t, "first CID", 101, int(cid.charset[0]))
+	expectI(t, "last CID", 287, int(cid.charset[186]))
+	expectI(t, "fdSelect", 188, len(cid.fdSelect))
+	expectI(t, "fonts", 1, len(cid.fonts))
+	expectI(t, "subrs", 1, len(cid.fonts[0].subrs))
+}
+
+func TestParseCFF_Damaged(t *testing.T) {
+	data := loadRawFont(t, minimalOTF)
+	entry := mustFindTable(t, data, "CFF ")
+	table := data[entry.offset : entry.offset+entry.length]
+	for _, n := range []int{0, 3, 20, 60, len(table) / 2} {
+		if _, err := parseCFF(table[:n]); err == nil {
+			t.Errorf("expected error parsing %d of %d bytes", n, len(table))
+		}
+	}
+	cff2 := bytes.Clone(table)
+	cff2[0] = 2
+	if _, err := parseCFF(cff2); err != errCFFVersion {
+		t.Errorf("expected %v, got %v", errCFFVersion, err)
+	}
+
+	// An empty Name INDEX: a count of zero in place of the header's INDEX.
+	noNames := append(append(bytes.Clone(table[:table[2]]), 0, 0), table[table[2]:]...)
+	if _, err := parseCFF(noNames); err != errCFFDamaged {
+		t.Errorf("expected %v for an empty Name INDEX, got %v", errCFFDamaged, err)
+	}
+	if _, err := readCFFFDSelect(table, -5, 1, 1); err != errCFFDamaged {
+		t.Errorf("expected %v for a negative FDSelect offset, got %v", errCFFDamaged, err)
+	}
+	if _, err := readCFFCharset(table, -5, 2); err != errCFFDamaged {
+		t.Errorf("expected %v for a negative charset offset, got %v", errCFFDamaged, err)
+	}
+
+	// Damaged fonts fail to load, rather than to subset.
+	font := bytes.Clone(data)
+	font[entry.offset] = 2
+	if _, err := LoadFontFromBytes(font); err != errCFFVersion {
+		t.Errorf("expected LoadFontFromBytes to fail with %v, got %v", errCFFVersion, err)
+	}
+}
+
+func TestCFFSeacComponents(t *testing.T) {
+	font, err := LoadFont(minimalOTF)
+	if err != nil {
+		t.Fatalf("LoadFont: %v", err)
+	}
+	cff := loadCFF(t, loadRawFont(t, minimalOTF))
+	a, c, z := font.GlyphIndex('A'), font.GlyphIndex('C'), font.GlyphIndex('Z')
+	cff.charset[a-1] = 34  // A
+	cff.charset[c-1] = 125 // acute
+	// hstem with two stems, a one-byte hintmask, then 0 0 65 194 and a call
+	// of a global subroutine holding endchar, which takes them as seac's.
+	cff.charStrings[z] = []byte{139, 139, 139, 139, 1, 19, 0xC0, 139, 139, 204, 247, 86, 32, 29}
+	cff.globalSubrs = [][]byte{{cffEndchar}}
+	base, accent, ok := cff.seacComponents(z)
+	expect(t, "seac", ok)
+	expectI(t, "base", int(a), int(base))
+	expectI(t, "accent", int(c), int(accent))
+
+	_, _, ok = cff.seacComponents(a)
+	expect(t, "no seac", !ok)
+	cff.charset[c-1] = 0
+	_, _, ok = cff.seacComponents(z)
+	expect(t, "accent missing", !ok)
+}
+
+func TestCFFDict(t *testing.T) {
+	// 100 (1 byte), 1000 (2 bytes), -1000 (2 bytes), 10000 (3 bytes), -2.25 (real), op 5; op 12 7.
+	data := []byte{239, 250, 124, 254, 124, 28, 0x27, 0x10, 30, 0xe2, 0xa2, 0x5f, 5, 139, 12, 7}
+	dict, err := parseCFFDict(data)
+	if err != nil {
+		t.Fatalf("parseCFFDict: %v", err)
+	}
+	values, ok := dict.ints(5)
+	expect(t, "op 5", ok)
+	expectI(t, "operands", 5, len(values))
+	expectI(t, "1 byte", 100, values[0])
+	expectI(t, "2 bytes", 1000, values[1])
+	expectI(t, "2 bytes negative", -1000, values[2])
+	expectI(t, "3 bytes", 10000, values[3])
+	value, ok := dict.int(12<<8 | 7)
+	expect(t, "op 12 7", ok)
+	expectI(t, "op 12 7 operand", 0, value)
+	expect(t, "round trip", bytes.Equal(data, dict.bytes()))
+
+	dict = dict.with(5, 70000).without(12<<8 | 7)
+	value, _ = dict.int(5)
+	expectI(t, "with", 70000, value)
+	expectI(t, "length", 6, len(dict.bytes()))
+
+	if _, err := parseCFFDict([]byte{28, 1}); err == nil {
+		t.Error("expected error for truncated operand")
+	}
+}
+
+func TestSubset_CFF(t *testing.T) {
+	for _, filename := range []string{minimalOTF, minimalCIDOTF} {
+		t.Run(filename, func(t *testing.T) {
+			orig, err := LoadFont(filename)
+			if err != nil {
+				t.Fatalf("LoadFont: %v", err)
+			}
+			a, c := orig.GlyphIndex('A'), orig.GlyphIndex('C')
+			data, err := orig.Subset([]uint16{a, c})
+			if err != nil {
+				t.Fatalf("Subset: %v", err)
+			}
+			if len(data) >= len(loadRawFont(t, filename)) {
+				t.Errorf("expected subset smaller than the original")
+			}
+			sub, err := LoadFontFromBytes(data)
+			if err != nil {
+				t.Fatalf("LoadFontFromBytes: %v", err)
+			}
+			expectS(t, "sfnt version", "OTTO", string(data[:4]))
+			expect(t, "HasCFFOutlines", sub.HasCFFOutlines())
+			expectI(t, "NumGlyphs", int(c)+1, sub.NumGlyphs())
+			expectI(t, "glyph A", int(a), int(sub.GlyphIndex('A')))
+			expectI(t, "glyph B", 0, int(sub.GlyphIndex('B')))
+			expectI(t, "checksum", 0xB1B0AFBA, int(ttfTableChecksum(data)))
+
+			origCFF := loadCFF(t, loadRawFont(t, filename))
+			cff := loadCFF(t, data)
+			expectI(t, "charStrings", int(c)+1, len(cff.charStrings))
+			expect(t, "notdef kept", bytes.Equal(origCFF.charStrings[0], cff.charStrings[0]))
+			expect(t, "A kept", bytes.Equal(origCFF.charStrings[a], cff.charStrings[a]))
+			expect(t, "B emptied", bytes.Equal([]byte{cffEndchar}, cff.charStrings[a+1]))
+			expectI(t, "globalSubrs", len(origCFF.globalSubrs), len(cff.globalSubrs))
+			expectI(t, "subrs", len(origCFF.fonts[0].subrs), len(cff.fonts[0].subrs))
+			expectI(t, "charset", int(c), len(cff.charset))
+			if cff.cidKeyed {
+				for gid, cid := range cff.charset {
+					if int(cid) != gid+1 {
+						t.Fatalf("CID of glyph %d: expected %d, got %d", gid+1, gid+1, cid)
+					}
+				}
+				count, _ := cff.topDict.int(cffOpCIDCount)
+				expectI(t, "CIDCount", int(c)+1, count)
+				expectI(t, "fdSelect", int(c)+1, len(cff.fdSelect))
+			} else {
+				for i, sid := range cff.charset {
+					if sid != origCFF.charset[i] {
+						t.Fatalf("SID of glyph %d: expected %d, got %d", i+1, origCFF.charset[i], sid)
+					}
+				}
+			}
+		})
+	}
+}
@@ -13,20 +13,36 @@ import (
 
 type Font struct {
 	FontInfo
+	avarTable avarTable
 	cmapTable cmapTable
+	gposTable gposTable
 	headTable headTable
 	hheaTable hheaTable
 	hmtxTable hmtxTable
+	hvarTable hvarTable
+	kernTable kernTable
 	maxpTable maxpTable
+	mvarTable mvarTable
 	postTable postTable
 	vheaTable vheaTable
 	vmtxTable vmtxTable
 	rawBytes  []byte // non-nil only when loaded via LoadFontFromBytes
+
+	// Instances of a variable font have the base font, their user-space
+	// and normalized coordinates, and metrics of their own.
+	base      *Font
+	instances map[string]*Font // of the base font, keyed by coordinates; guarded by instancesMu
+	variation map[string]float64
+	coords    []float64
 }
 
 // FontKey returns a stable string identifying this font, used as a cache key
-// by the shaper. No I/O is performed.
+// by the shaper. Instances of a variable font, which share its file, are
+// told apart by their coordinates. No I/O is performed.
 func (font *Font) FontKey() string {
+	if font.variation != nil {
+		return fmt.Sprintf("%s@%d#%s", font.filename, font.ttcOffset, variationKey(font.fvarTable.axes, font.variation))
+	}
 	return fmt.Sprintf("%s@%d", font.filename, font.ttcOffset)
 }
 
@@ -118,6 +134,39 @@ func (font *Font) init(file io.ReadSeeker, offset int64) (err error) {
 			return
 		}
 	}
+	if entry := font.tableDir.table("CFF "); entry != nil {
+		if err = checkCFF(file, entry); err != nil {
+			return
+		}
+	}
+	if entry := font.tableDir.table("GPOS"); entry != nil {
+		if err = font.gposTable.init(file, entry); err != nil {
+			return
+		}
+	}
+	if entry := font.tableDir.table("kern"); entry != nil && !font.gposTable.hasKerning() {
+		if err = font.kernTable.init(file, entry); err != nil {
+			return
+		}
+	}
+	if !font.IsVariable() {
+		return
+	}
+	if entry := font.tableDir.table("avar"); entry != nil {
+		if err = font.avarTable.init(file, entry); err != nil {
+			return
+		}
+	}
+	if entry := font.tableDir.table("HVAR"); entry != nil {
+		if err = font.hvarTable.init(file, entry); err != nil {
+			return
+		}
+	}
+	if entry := font.tableDir.table("MVAR"); entry != nil {
+		if err = font.mvarTable.init(file, entry); err != nil {
+			return
+		}
+	}
 	return
 }
 
@@ -146,6 +195,21 @@ func (font *Font) AdvanceWidthForGlyph(glyphID uint16) int {
 	return int(font.hmtxTable.lookupAdvanceWidth(int(glyphID)))
 }
 
+// PairKerning returns the adjustment to the advance width of left when it is
+// followed by right, in glyph-space units; negative values tighten the pair.
+// Pairs come from the kern feature of the GPOS table or, without one, from
+// the kern table.
+func (font *Font) PairKerning(left, right rune) int {
+	l, r := font.GlyphIndex(left), font.GlyphIndex(right)
+	if l == 0 || r == 0 {
+		return 0
+	}
+	if font.gposTable.hasKerning() {
+		return font.gposTable.kerning(l, r)
+	}
+	return font.kernTable.kerning(l, r)
+}
+
 func (font *Font) Ascent() int {
 	return int(font.hheaTable.ascent)
 }
@@ -22,6 +22,7 @@ type FontInfo struct {
 	tableDir      tableDir
 	nameTable     nameTable
 	os2Table      os2Table
+	fvarTable     fvarTable
 }
 
 // 1,077,216 ns
@@ -74,9 +75,19 @@ func (fi *FontInfo) init(file io.ReadSeeker, offset int64) (err error) {
 			return
 		}
 	}
+	if entry := fi.tableDir.table("fvar"); entry != nil {
+		if err = fi.fvarTable.init(file, entry, &fi.nameTable); err != nil {
+			return
+		}
+	}
 	return
 }
 
+// Axes returns the design axes of a variable font, or nil.
+func (fi *FontInfo) Axes() []VariationAxis {
+	return fi.fvarTable.axes
+}
+
 func (fi *FontInfo) AvgWidth() int {
 	return int(fi.os2Table.xAvgCharWidth)
 }
@@ -116,6 +127,18 @@ func (fi *FontInfo) FullName() string {
 	return fi.nameTable.fullName
 }
 
+// HasCFFOutlines reports whether the font is an OpenType font with CFF
+// outlines, usually an .otf file, rather than TrueType outlines.
+func (fi *FontInfo) HasCFFOutlines() bool {
+	return fi.tableDir.table("CFF ") != nil
+}
+
+// IsVariable reports whether the font is a variable font, with design axes
+// along which instances may be chosen.
+func (fi *FontInfo) IsVariable() bool {
+	return len(fi.fvarTable.axes) > 0
+}
+
 func (fi *FontInfo) License() string {
 	return fi.nameTable.licenseDescription
 }
@@ -124,6 +147,11 @@ func (fi *FontInfo) Manufacturer() string {
 	return fi.nameTable.manufacturerName
 }
 
+// NamedInstances returns the named instances of a variable font, or nil.
+func (fi *FontInfo) NamedInstances() []NamedInstance {
+	return fi.fvarTable.instances
+}
+
 func (fi *FontInfo) PostScriptName() string {
 	return fi.nameTable.postScriptName
 }
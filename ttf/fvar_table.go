@@ -0,0 +1,107 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ttf
+
+import (
+	"io"
+	"os"
+)
+
+// VariationAxis is a design axis of a variable font, such as weight (wght)
+// or width (wdth), with its range in user-space units.
+type VariationAxis struct {
+	Tag     string
+	Name    string
+	Min     float64
+	Default float64
+	Max     float64
+	Hidden  bool // not meant to be offered to users
+}
+
+// NamedInstance is a position in the design space of a variable font that
+// the font names, such as "Bold" or "Condensed Light".
+type NamedInstance struct {
+	Name           string
+	PostScriptName string             // empty unless the font gives one
+	Coords         map[string]float64 // user-space coordinates keyed by axis tag
+}
+
+// fvarTable holds the axes and named instances of a variable font.
+type fvarTable struct {
+	axes      []VariationAxis
+	instances []NamedInstance
+}
+
+const fvarAxisHidden = 0x0001
+
+func (table *fvarTable) init(rs io.ReadSeeker, entry *tableDirEntry, names *nameTable) (err error) {
+	if _, err = rs.Seek(int64(entry.offset), os.SEEK_SET); err != nil {
+		return
+	}
+	data := make([]byte, entry.length)
+	if _, err = io.ReadFull(rs, data); err != nil {
+		return
+	}
+	table.parse(data, names)
+	return nil
+}
+
+// parse reads the axes and instances of data, taking their names from
+// names. A table cut short yields no axes.
+func (table *fvarTable) parse(data []byte, names *nameTable) {
+	r := tableReader(data)
+	if r.u16(0) != 1 {
+		return
+	}
+	axesOffset, axisCount, axisSize := int(r.u16(4)), int(r.u16(8)), int(r.u16(10))
+	instanceCount, instanceSize := int(r.u16(12)), int(r.u16(14))
+	instancesOffset := axesOffset + axisCount*axisSize
+	if axisSize < 20 || instancesOffset > len(data) {
+		return
+	}
+	table.axes = make([]VariationAxis, axisCount)
+	for i := range table.axes {
+		pos := axesOffset + i*axisSize
+		table.axes[i] = VariationAxis{
+			Tag:     string(r.bytes(pos, 4)),
+			Name:    names.getField(r.u16(pos + 18)),
+			Min:     r.fixed(pos + 4),
+			Default: r.fixed(pos + 8),
+			Max:     r.fixed(pos + 12),
+			Hidden:  r.u16(pos+16)&fvarAxisHidden != 0,
+		}
+	}
+	if instanceSize < 4+4*axisCount {
+		return
+	}
+	for j := 0; j < instanceCount; j++ {
+		pos := instancesOffset + j*instanceSize
+		if pos+instanceSize > len(data) {
+			break
+		}
+		instance := NamedInstance{
+			Name:   names.getField(r.u16(pos)),
+			Coords: make(map[string]float64, axisCount),
+		}
+		for i, axis := range table.axes {
+			instance.Coords[axis.Tag] = r.fixed(pos + 4 + 4*i)
+		}
+		if instanceSize >= 6+4*axisCount {
+			if id := r.u16(pos + 4 + 4*axisCount); id != 0xFFFF {
+				instance.PostScriptName = names.getField(id)
+			}
+		}
+		table.instances = append(table.instances, instance)
+	}
+}
+
+// axis returns the index of the axis with tag, or -1.
+func (table *fvarTable) axis(tag string) int {
+	for i, axis := range table.axes {
+		if axis.Tag == tag {
+			return i
+		}
+	}
+	return -1
+}
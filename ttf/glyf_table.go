@@ -0,0 +1,307 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ttf
+
+import (
+	"bytes"
+	"errors"
+	"math"
+)
+
+// Simple glyph flag bits (OpenType spec, glyf table).
+const (
+	glyphOnCurve       = 0x01
+	glyphXShort        = 0x02
+	glyphYShort        = 0x04
+	glyphRepeat        = 0x08
+	glyphXSame         = 0x10 // or, with glyphXShort, a positive x
+	glyphYSame         = 0x20 // or, with glyphYShort, a positive y
+	glyphOverlapSimple = 0x40
+	glyphCubic         = 0x80
+
+	glyphKeptFlags = glyphOnCurve | glyphOverlapSimple | glyphCubic
+)
+
+var errGlyphDamaged = errors.New("glyph data is damaged")
+
+// glyphPoint is a point of a glyph outline, with the flags that do not
+// concern its encoding.
+type glyphPoint struct {
+	x, y  int
+	flags byte
+}
+
+// simpleGlyph is a glyph drawn from contours of its own points.
+type simpleGlyph struct {
+	endPts       []int // last point of each contour
+	instructions []byte
+	points       []glyphPoint
+}
+
+func parseSimpleGlyph(data []byte) (*simpleGlyph, error) {
+	r := tableReader(data)
+	numContours := int(int16(r.u16(0)))
+	if len(data) < 10 || numContours < 0 {
+		return nil, errGlyphDamaged
+	}
+	g := &simpleGlyph{endPts: make([]int, numContours)}
+	pos := 10
+	numPoints := 0
+	for i := range g.endPts {
+		g.endPts[i] = int(r.u16(pos))
+		if g.endPts[i] < numPoints-1 {
+			return nil, errGlyphDamaged
+		}
+		numPoints = g.endPts[i] + 1
+		pos += 2
+	}
+	instructionLength := int(r.u16(pos))
+	pos += 2
+	if g.instructions = r.bytes(pos, instructionLength); g.instructions == nil {
+		return nil, errGlyphDamaged
+	}
+	pos += instructionLength
+	flags := make([]byte, 0, numPoints)
+	for len(flags) < numPoints {
+		if pos >= len(data) {
+			return nil, errGlyphDamaged
+		}
+		flag := data[pos]
+		pos++
+		repeat := 1
+		if flag&glyphRepeat != 0 {
+			repeat += int(r.u8(pos))
+			pos++
+		}
+		for ; repeat > 0 && len(flags) < numPoints; repeat-- {
+			flags = append(flags, flag)
+		}
+	}
+	g.points = make([]glyphPoint, numPoints)
+	x, y := 0, 0
+	for i, flag := range flags {
+		switch {
+		case flag&glyphXShort != 0 && flag&glyphXSame != 0:
+			x += int(r.u8(pos))
+			pos++
+		case flag&glyphXShort != 0:
+			x -= int(r.u8(pos))
+			pos++
+		case flag&glyphXSame == 0:
+			x += int(int16(r.u16(pos)))
+			pos += 2
+		}
+		g.points[i] = glyphPoint{x: x, flags: flag & glyphKeptFlags}
+	}
+	for i, flag := range flags {
+		switch {
+		case flag&glyphYShort != 0 && flag&glyphYSame != 0:
+			y += int(r.u8(pos))
+			pos++
+		case flag&glyphYShort != 0:
+			y -= int(r.u8(pos))
+			pos++
+		case flag&glyphYSame == 0:
+			y += int(int16(r.u16(pos)))
+			pos += 2
+		}
+		g.points[i].y = y
+	}
+	if pos > len(data) {
+		return nil, errGlyphDamaged
+	}
+	return g, nil
+}
+
+// bytes encodes the glyph, with its bounding box recomputed.
+func (g *simpleGlyph) bytes() []byte {
+	var buf bytes.Buffer
+	bounds := pointBounds(g.points)
+	writeInt16(&buf, int16(len(g.endPts)))
+	for _, v := range bounds {
+		writeInt16(&buf, int16(v))
+	}
+	for _, end := range g.endPts {
+		writeUint16(&buf, uint16(end))
+	}
+	writeUint16(&buf, uint16(len(g.instructions)))
+	buf.Write(g.instructions)
+
+	flags := make([]byte, len(g.points))
+	var xs, ys bytes.Buffer
+	prev := glyphPoint{}
+	for i, p := range g.points {
+		flags[i] = p.flags
+		if i > 0 {
+			flags[i] &^= glyphOverlapSimple
+		}
+		flags[i] |= encodeGlyphCoord(&xs, p.x-prev.x, glyphXShort, glyphXSame)
+		flags[i] |= encodeGlyphCoord(&ys, p.y-prev.y, glyphYShort, glyphYSame)
+		prev = p
+	}
+	for i := 0; i < len(flags); {
+		run := 1
+		for i+run < len(flags) && flags[i+run] == flags[i] && run < 256 {
+			run++
+		}
+		if run > 1 {
+			buf.WriteByte(flags[i] | glyphRepeat)
+			buf.WriteByte(byte(run - 1))
+		} else {
+			buf.WriteByte(flags[i])
+		}
+		i += run
+	}
+	buf.Write(xs.Bytes())
+	buf.Write(ys.Bytes())
+	return buf.Bytes()
+}
+
+// encodeGlyphCoord writes the change d in a coordinate in its shortest form
+// and returns the flags that give that form.
+func encodeGlyphCoord(buf *bytes.Buffer, d int, short, same byte) byte {
+	switch {
+	case d == 0:
+		return same
+	case d > 0 && d < 256:
+		buf.WriteByte(byte(d))
+		return short | same
+	case d < 0 && d > -256:
+		buf.WriteByte(byte(-d))
+		return short
+	}
+	writeInt16(buf, int16(d))
+	return 0
+}
+
+// pointBounds returns the xMin, yMin, xMax and yMax of points.
+func pointBounds(points []glyphPoint) [4]int {
+	if len(points) == 0 {
+		return [4]int{}
+	}
+	b := [4]int{points[0].x, points[0].y, points[0].x, points[0].y}
+	for _, p := range points[1:] {
+		b[0], b[1] = min(b[0], p.x), min(b[1], p.y)
+		b[2], b[3] = max(b[2], p.x), max(b[3], p.y)
+	}
+	return b
+}
+
+// glyphComponent is a glyph drawn as part of a composite glyph.
+type glyphComponent struct {
+	flags      uint16
+	glyph      uint16
+	arg1, arg2 int    // x and y offsets, or the points to align
+	scale      []byte // scale or transformation matrix, as stored
+}
+
+// compositeGlyph is a glyph drawn from other glyphs.
+type compositeGlyph struct {
+	components   []glyphComponent
+	instructions []byte
+}
+
+func parseCompositeGlyph(data []byte) (*compositeGlyph, error) {
+	r := tableReader(data)
+	if len(data) < 10 || int16(r.u16(0)) >= 0 {
+		return nil, errGlyphDamaged
+	}
+	g := &compositeGlyph{}
+	pos := 10
+	for {
+		if pos+4 > len(data) {
+			return nil, errGlyphDamaged
+		}
+		c := glyphComponent{flags: r.u16(pos), glyph: r.u16(pos + 2)}
+		pos += 4
+		switch xy := c.flags&compositeArgsAreXYValues != 0; {
+		case c.flags&compositeArgOneAndTwoAreWords != 0 && xy:
+			c.arg1, c.arg2 = int(int16(r.u16(pos))), int(int16(r.u16(pos+2)))
+			pos += 4
+		case c.flags&compositeArgOneAndTwoAreWords != 0:
+			c.arg1, c.arg2 = int(r.u16(pos)), int(r.u16(pos+2))
+			pos += 4
+		case xy:
+			c.arg1, c.arg2 = int(int8(r.u8(pos))), int(int8(r.u8(pos+1)))
+			pos += 2
+		default:
+			c.arg1, c.arg2 = int(r.u8(pos)), int(r.u8(pos+1))
+			pos += 2
+		}
+		n := 0
+		switch {
+		case c.flags&compositeWeHaveATwoByTwo != 0:
+			n = 8
+		case c.flags&compositeWeHaveAnXAndYScale != 0:
+			n = 4
+		case c.flags&compositeWeHaveAScale != 0:
+			n = 2
+		}
+		if c.scale = r.bytes(pos, n); c.scale == nil {
+			return nil, errGlyphDamaged
+		}
+		pos += n
+		g.components = append(g.components, c)
+		if c.flags&compositeMoreComponents == 0 {
+			break
+		}
+	}
+	if g.components[len(g.components)-1].flags&compositeWeHaveInstructions != 0 {
+		n := int(r.u16(pos))
+		if g.instructions = r.bytes(pos+2, n); g.instructions == nil {
+			return nil, errGlyphDamaged
+		}
+	}
+	return g, nil
+}
+
+// bytes encodes the glyph with the bounding box bounds. Arguments are
+// written as words, so that offsets moved by variations still fit.
+func (g *compositeGlyph) bytes(bounds [4]int) []byte {
+	var buf bytes.Buffer
+	writeInt16(&buf, -1)
+	for _, v := range bounds {
+		writeInt16(&buf, int16(v))
+	}
+	for i, c := range g.components {
+		flags := c.flags | compositeArgOneAndTwoAreWords
+		flags &^= compositeMoreComponents | compositeWeHaveInstructions
+		if i < len(g.components)-1 {
+			flags |= compositeMoreComponents
+		} else if len(g.instructions) > 0 {
+			flags |= compositeWeHaveInstructions
+		}
+		writeUint16(&buf, flags)
+		writeUint16(&buf, c.glyph)
+		writeUint16(&buf, uint16(c.arg1))
+		writeUint16(&buf, uint16(c.arg2))
+		buf.Write(c.scale)
+	}
+	if len(g.instructions) > 0 {
+		writeUint16(&buf, uint16(len(g.instructions)))
+		buf.Write(g.instructions)
+	}
+	return buf.Bytes()
+}
+
+// transform returns the matrix a, b, c, d with which a component's points
+// are scaled: x' = a*x + c*y, y' = b*x + d*y.
+func (c *glyphComponent) transform() [4]float64 {
+	r := tableReader(c.scale)
+	switch len(c.scale) {
+	case 2:
+		return [4]float64{r.f2dot14(0), 0, 0, r.f2dot14(0)}
+	case 4:
+		return [4]float64{r.f2dot14(0), 0, 0, r.f2dot14(2)}
+	case 8:
+		return [4]float64{r.f2dot14(0), r.f2dot14(2), r.f2dot14(4), r.f2dot14(6)}
+	}
+	return [4]float64{1, 0, 0, 1}
+}
+
+// otRound rounds v to the nearest integer, rounding halves up, as font
+// tools do.
+func otRound(v float64) int {
+	return int(math.Floor(v + 0.5))
+}
@@ -0,0 +1,276 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ttf
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"sort"
+)
+
+// gposTable gives access to the pair adjustment lookups of the kern feature
+// of a GPOS table. The table is kept whole and its subtables are read as
+// pairs are looked up.
+type gposTable struct {
+	data    []byte
+	lookups [][]int // offsets of the pair adjustment subtables of each kern lookup
+}
+
+const (
+	gposLookupPair      = 2
+	gposLookupExtension = 9
+
+	valueFormatXAdvance = 0x0004
+)
+
+func (table *gposTable) init(rs io.ReadSeeker, entry *tableDirEntry) (err error) {
+	if _, err = rs.Seek(int64(entry.offset), os.SEEK_SET); err != nil {
+		return
+	}
+	data := make([]byte, entry.length)
+	if _, err = io.ReadFull(rs, data); err != nil {
+		return
+	}
+	table.parse(data)
+	return nil
+}
+
+// parse finds the pair adjustment subtables of the lookups of the kern
+// features, for any script and language, in the order they are applied.
+func (table *gposTable) parse(data []byte) {
+	r := tableReader(data)
+	if r.u16(0) != 1 {
+		return
+	}
+	featureList, lookupList := int(r.u16(6)), int(r.u16(8))
+	var indexes []int
+	seen := make(map[int]bool)
+	for i := 0; i < int(r.u16(featureList)); i++ {
+		rec := featureList + 2 + 6*i
+		if string(r.bytes(rec, 4)) != "kern" {
+			continue
+		}
+		feature := featureList + int(r.u16(rec+4))
+		for j := 0; j < int(r.u16(feature+2)); j++ {
+			index := int(r.u16(feature + 4 + 2*j))
+			if !seen[index] {
+				seen[index] = true
+				indexes = append(indexes, index)
+			}
+		}
+	}
+	sort.Ints(indexes)
+	for _, index := range indexes {
+		if index >= int(r.u16(lookupList)) {
+			continue
+		}
+		lookup := lookupList + int(r.u16(lookupList+2+2*index))
+		lookupType := r.u16(lookup)
+		var subtables []int
+		for k := 0; k < int(r.u16(lookup+4)); k++ {
+			subtable := lookup + int(r.u16(lookup+6+2*k))
+			subtableType := lookupType
+			if lookupType == gposLookupExtension {
+				subtableType = r.u16(subtable + 2)
+				subtable += int(r.u32(subtable + 4))
+			}
+			if subtableType == gposLookupPair {
+				subtables = append(subtables, subtable)
+			}
+		}
+		if len(subtables) > 0 {
+			table.lookups = append(table.lookups, subtables)
+		}
+	}
+	if len(table.lookups) > 0 {
+		table.data = data
+	}
+}
+
+func (table *gposTable) hasKerning() bool {
+	return len(table.lookups) > 0
+}
+
+// kerning returns the sum of the x advance adjustments to left made by each
+// kern lookup, where the first of its subtables to cover the pair applies.
+func (table *gposTable) kerning(left, right uint16) int {
+	r := tableReader(table.data)
+	total := 0
+	for _, subtables := range table.lookups {
+		for _, subtable := range subtables {
+			if value, ok := r.pairAdjustment(subtable, left, right); ok {
+				total += value
+				break
+			}
+		}
+	}
+	return total
+}
+
+// tableReader reads big-endian values from a table, giving zero for any
+// read beyond its end, so that a damaged table yields no adjustments.
+type tableReader []byte
+
+func (r tableReader) bytes(pos, n int) []byte {
+	if pos < 0 || pos+n > len(r) {
+		return nil
+	}
+	return r[pos : pos+n]
+}
+
+func (r tableReader) u8(pos int) uint8 {
+	if b := r.bytes(pos, 1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r tableReader) u16(pos int) uint16 {
+	if b := r.bytes(pos, 2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r tableReader) u32(pos int) uint32 {
+	if b := r.bytes(pos, 4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+// fixed reads a 16.16 fixed-point number.
+func (r tableReader) fixed(pos int) float64 {
+	return float64(int32(r.u32(pos))) / 65536
+}
+
+// f2dot14 reads a 2.14 fixed-point number.
+func (r tableReader) f2dot14(pos int) float64 {
+	return float64(int16(r.u16(pos))) / 16384
+}
+
+// pairAdjustment returns the x advance adjustment to the first glyph of a
+// pair from a PairPos subtable, and whether the subtable covers the pair.
+func (r tableReader) pairAdjustment(subtable int, left, right uint16) (int, bool) {
+	coverageIndex, ok := r.coverageIndex(subtable+int(r.u16(subtable+2)), left)
+	if !ok {
+		return 0, false
+	}
+	valueFormat1, valueFormat2 := r.u16(subtable+4), r.u16(subtable+6)
+	size1, size2 := valueRecordSize(valueFormat1), valueRecordSize(valueFormat2)
+	switch r.u16(subtable) {
+	case 1:
+		if coverageIndex >= int(r.u16(subtable+8)) {
+			return 0, false
+		}
+		pairSet := subtable + int(r.u16(subtable+10+2*coverageIndex))
+		recordSize := 2 + size1 + size2
+		// Pair value records are sorted by second glyph.
+		low, high := 0, int(r.u16(pairSet))-1
+		for low <= high {
+			i := (low + high) / 2
+			rec := pairSet + 2 + i*recordSize
+			switch second := r.u16(rec); {
+			case second < right:
+				low = i + 1
+			case second > right:
+				high = i - 1
+			default:
+				return r.xAdvance(rec+2, valueFormat1), true
+			}
+		}
+		return 0, false
+	case 2:
+		class1 := r.glyphClass(subtable+int(r.u16(subtable+8)), left)
+		class2 := r.glyphClass(subtable+int(r.u16(subtable+10)), right)
+		class1Count, class2Count := int(r.u16(subtable+12)), int(r.u16(subtable+14))
+		if class1 >= class1Count || class2 >= class2Count {
+			return 0, true
+		}
+		rec := subtable + 16 + (class1*class2Count+class2)*(size1+size2)
+		return r.xAdvance(rec, valueFormat1), true
+	}
+	return 0, false
+}
+
+// valueRecordSize returns the size of a value record of the given format,
+// which has a 16-bit field for each bit set.
+func valueRecordSize(format uint16) int {
+	size := 0
+	for f := format & 0xFF; f != 0; f >>= 1 {
+		size += 2 * int(f&1)
+	}
+	return size
+}
+
+// xAdvance returns the XAdvance field of the value record at pos.
+func (r tableReader) xAdvance(pos int, format uint16) int {
+	if format&valueFormatXAdvance == 0 {
+		return 0
+	}
+	pos += valueRecordSize(format & (valueFormatXAdvance - 1))
+	return int(int16(r.u16(pos)))
+}
+
+// coverageIndex returns the index of glyph in a coverage table.
+func (r tableReader) coverageIndex(coverage int, glyph uint16) (int, bool) {
+	switch r.u16(coverage) {
+	case 1:
+		low, high := 0, int(r.u16(coverage+2))-1
+		for low <= high {
+			i := (low + high) / 2
+			switch g := r.u16(coverage + 4 + 2*i); {
+			case g < glyph:
+				low = i + 1
+			case g > glyph:
+				high = i - 1
+			default:
+				return i, true
+			}
+		}
+	case 2:
+		low, high := 0, int(r.u16(coverage+2))-1
+		for low <= high {
+			i := (low + high) / 2
+			rec := coverage + 4 + 6*i
+			switch {
+			case r.u16(rec+2) < glyph:
+				low = i + 1
+			case r.u16(rec) > glyph:
+				high = i - 1
+			default:
+				return int(r.u16(rec+4)) + int(glyph-r.u16(rec)), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// glyphClass returns the class of glyph in a class definition table; glyphs
+// it does not list are in class 0.
+func (r tableReader) glyphClass(classDef int, glyph uint16) int {
+	switch r.u16(classDef) {
+	case 1:
+		start := r.u16(classDef + 2)
+		if glyph >= start && int(glyph-start) < int(r.u16(classDef+4)) {
+			return int(r.u16(classDef + 6 + 2*int(glyph-start)))
+		}
+	case 2:
+		low, high := 0, int(r.u16(classDef+2))-1
+		for low <= high {
+			i := (low + high) / 2
+			rec := classDef + 4 + 6*i
+			switch {
+			case r.u16(rec+2) < glyph:
+				low = i + 1
+			case r.u16(rec) > glyph:
+				high = i - 1
+			default:
+				return int(r.u16(rec + 4))
+			}
+		}
+	}
+	return 0
+}
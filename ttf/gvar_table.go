@@ -0,0 +1,425 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ttf
+
+import "fmt"
+
+// gvarTable holds the glyph variations of a variable font with TrueType
+// outlines: for each glyph, the deltas its points move by toward the peaks
+// of regions of the design space.
+type gvarTable struct {
+	data         []byte
+	axisCount    int
+	sharedTuples [][]float64
+	offsets      []int // of each glyph's variation data, then the end of the last
+}
+
+const (
+	gvarLongOffsets = 0x0001
+
+	tupleSharedPoints       = 0x8000
+	tupleCountMask          = 0x0FFF
+	tupleEmbeddedPeak       = 0x8000
+	tupleIntermediateRegion = 0x4000
+	tuplePrivatePoints      = 0x2000
+	tupleIndexMask          = 0x0FFF
+
+	pointsAreWords   = 0x80
+	pointRunMask     = 0x7F
+	deltasAreZero    = 0x80
+	deltasAreWords   = 0x40
+	deltasAreLongs   = 0xC0
+	deltaRunMask     = 0x3F
+	deltaSizeMask    = 0xC0
+	numPhantomPoints = 4
+)
+
+func (table *gvarTable) parse(data []byte) {
+	r := tableReader(data)
+	if r.u16(0) != 1 {
+		return
+	}
+	table.axisCount = int(r.u16(4))
+	sharedTupleCount, sharedTuples := int(r.u16(6)), int(r.u32(8))
+	glyphCount, flags, dataArray := int(r.u16(12)), r.u16(14), int(r.u32(16))
+	table.sharedTuples = make([][]float64, sharedTupleCount)
+	for i := range table.sharedTuples {
+		table.sharedTuples[i] = readTuple(r, sharedTuples+2*i*table.axisCount, table.axisCount)
+	}
+	table.offsets = make([]int, glyphCount+1)
+	for i := range table.offsets {
+		if flags&gvarLongOffsets != 0 {
+			table.offsets[i] = dataArray + int(r.u32(20+4*i))
+		} else {
+			table.offsets[i] = dataArray + 2*int(r.u16(20+2*i))
+		}
+	}
+	table.data = data
+}
+
+func readTuple(r tableReader, pos, axisCount int) []float64 {
+	tuple := make([]float64, axisCount)
+	for i := range tuple {
+		tuple[i] = r.f2dot14(pos + 2*i)
+	}
+	return tuple
+}
+
+// deltas returns the x and y deltas at coords of the points of glyph: its
+// outline points, or one for each component of a composite glyph, followed
+// by the four phantom points. For a simple glyph, endPts gives the last
+// point of each contour, so that the deltas of the points a variation
+// leaves out can be inferred from those it moves.
+func (table *gvarTable) deltas(glyph int, points []glyphPoint, endPts []int, coords []float64) (dx, dy []float64) {
+	n := len(points)
+	dx, dy = make([]float64, n), make([]float64, n)
+	if glyph+1 >= len(table.offsets) {
+		return
+	}
+	start, end := table.offsets[glyph], table.offsets[glyph+1]
+	if start >= end || end > len(table.data) {
+		return
+	}
+	r := tableReader(table.data[start:end])
+	tupleCount := int(r.u16(0) & tupleCountMask)
+	serialized := int(r.u16(2))
+	var shared []int
+	if r.u16(0)&tupleSharedPoints != 0 {
+		shared, serialized = readPackedPoints(r, serialized)
+	}
+	pos := 4
+	for i := 0; i < tupleCount; i++ {
+		size, index := int(r.u16(pos)), r.u16(pos+2)
+		pos += 4
+		var peak, startTuple, endTuple []float64
+		if index&tupleEmbeddedPeak != 0 {
+			peak = readTuple(r, pos, table.axisCount)
+			pos += 2 * table.axisCount
+		} else if int(index&tupleIndexMask) < len(table.sharedTuples) {
+			peak = table.sharedTuples[index&tupleIndexMask]
+		}
+		if index&tupleIntermediateRegion != 0 {
+			startTuple = readTuple(r, pos, table.axisCount)
+			endTuple = readTuple(r, pos+2*table.axisCount, table.axisCount)
+			pos += 4 * table.axisCount
+		}
+		tupleData := serialized
+		serialized += size
+		scalar := tupleScalar(peak, startTuple, endTuple, coords)
+		if peak == nil || scalar == 0 {
+			continue
+		}
+		pts := shared
+		if index&tuplePrivatePoints != 0 {
+			pts, tupleData = readPackedPoints(r, tupleData)
+		}
+		count := len(pts)
+		if pts == nil {
+			count = n
+		}
+		xs, tupleData := readPackedDeltas(r, tupleData, count)
+		ys, _ := readPackedDeltas(r, tupleData, count)
+		if pts == nil {
+			for j := range dx {
+				dx[j] += scalar * float64(xs[j])
+				dy[j] += scalar * float64(ys[j])
+			}
+			continue
+		}
+		tx, ty, touched := make([]float64, n), make([]float64, n), make([]bool, n)
+		for j, pt := range pts {
+			if pt < n {
+				tx[pt], ty[pt], touched[pt] = float64(xs[j]), float64(ys[j]), true
+			}
+		}
+		if endPts != nil {
+			interpolateUntouched(points, endPts, tx, ty, touched)
+		}
+		for j := range dx {
+			dx[j] += scalar * tx[j]
+			dy[j] += scalar * ty[j]
+		}
+	}
+	return
+}
+
+// tupleScalar returns the weight at coords of the deltas of a tuple whose
+// region peaks at peak. Without an intermediate region, the region runs
+// from zero to the peak on each axis.
+func tupleScalar(peak, start, end []float64, coords []float64) float64 {
+	scalar := 1.0
+	for i, p := range peak {
+		if p == 0 {
+			continue
+		}
+		v := 0.0
+		if i < len(coords) {
+			v = coords[i]
+		}
+		s, e := min(0, p), max(0, p)
+		if start != nil {
+			s, e = start[i], end[i]
+		}
+		if scalar *= axisScalar(v, s, p, e); scalar == 0 {
+			break
+		}
+	}
+	return scalar
+}
+
+// readPackedPoints reads packed point numbers at pos, returning nil where
+// they stand for all of a glyph's points.
+func readPackedPoints(r tableReader, pos int) ([]int, int) {
+	count := int(r.u8(pos))
+	pos++
+	if count == 0 {
+		return nil, pos
+	}
+	if count&pointsAreWords != 0 {
+		count = (count&pointRunMask)<<8 | int(r.u8(pos))
+		pos++
+	}
+	points := make([]int, 0, count)
+	last := 0
+	for len(points) < count && pos < len(r) {
+		control := r.u8(pos)
+		pos++
+		for run := int(control&pointRunMask) + 1; run > 0 && len(points) < count; run-- {
+			if control&pointsAreWords != 0 {
+				last += int(r.u16(pos))
+				pos += 2
+			} else {
+				last += int(r.u8(pos))
+				pos++
+			}
+			points = append(points, last)
+		}
+	}
+	return points, pos
+}
+
+// readPackedDeltas reads count packed deltas at pos.
+func readPackedDeltas(r tableReader, pos, count int) ([]int, int) {
+	deltas := make([]int, 0, count)
+	for len(deltas) < count && pos < len(r) {
+		control := r.u8(pos)
+		pos++
+		size := 1
+		switch control & deltaSizeMask {
+		case deltasAreZero:
+			size = 0
+		case deltasAreWords:
+			size = 2
+		case deltasAreLongs:
+			size = 4
+		}
+		for run := int(control&deltaRunMask) + 1; run > 0 && len(deltas) < count; run-- {
+			deltas = append(deltas, r.signed(pos, size))
+			pos += size
+		}
+	}
+	for len(deltas) < count {
+		deltas = append(deltas, 0)
+	}
+	return deltas, pos
+}
+
+// interpolateUntouched infers the deltas of the points of each contour
+// that are not touched from those of the nearest touched points before and
+// after them in the contour, as TrueType's IUP instruction does.
+func interpolateUntouched(points []glyphPoint, endPts []int, dx, dy []float64, touched []bool) {
+	start := 0
+	for _, end := range endPts {
+		if end >= len(points) {
+			return
+		}
+		var refs []int
+		for i := start; i <= end; i++ {
+			if touched[i] {
+				refs = append(refs, i)
+			}
+		}
+		for k, ref1 := range refs {
+			ref2 := refs[(k+1)%len(refs)]
+			for i := ref1 + 1; ; i++ {
+				if i > end {
+					i = start
+				}
+				if i == ref2 {
+					break
+				}
+				p, p1, p2 := points[i], points[ref1], points[ref2]
+				dx[i] = interpolateDelta(p.x, p1.x, p2.x, dx[ref1], dx[ref2])
+				dy[i] = interpolateDelta(p.y, p1.y, p2.y, dy[ref1], dy[ref2])
+			}
+		}
+		start = end + 1
+	}
+}
+
+// interpolateDelta returns the delta of a coordinate v from those of two
+// reference coordinates: interpolated between them, or that of the nearer
+// outside them.
+func interpolateDelta(v, v1, v2 int, d1, d2 float64) float64 {
+	if v1 == v2 {
+		if d1 == d2 {
+			return d1
+		}
+		return 0
+	}
+	if v1 > v2 {
+		v1, v2, d1, d2 = v2, v1, d2, d1
+	}
+	switch {
+	case v <= v1:
+		return d1
+	case v >= v2:
+		return d2
+	}
+	return d1 + (d2-d1)*float64(v-v1)/float64(v2-v1)
+}
+
+// glyphInstancer applies the glyph variations of a variable font at the
+// normalized coordinates of an instance to the glyphs of its glyf table.
+type glyphInstancer struct {
+	font     *Font // the variable font, with the default metrics
+	coords   []float64
+	glyf     []byte
+	offsets  []uint32
+	lengths  []uint32
+	gvar     gvarTable
+	outlines map[uint16][]glyphPoint // instanced outlines, with components placed
+}
+
+// newGlyphInstancer returns an instancer of the glyphs of font, whose file
+// contents are raw.
+func (font *Font) newGlyphInstancer(raw []byte, coords []float64) (*glyphInstancer, error) {
+	glyfEntry, locaEntry := font.tableDir.table("glyf"), font.tableDir.table("loca")
+	if glyfEntry == nil || locaEntry == nil {
+		return nil, fmt.Errorf("instance: font has no glyf and loca tables")
+	}
+	if int(glyfEntry.offset+glyfEntry.length) > len(raw) {
+		return nil, fmt.Errorf("instance: malformed glyf table")
+	}
+	gi := &glyphInstancer{
+		font:     font,
+		coords:   coords,
+		glyf:     raw[glyfEntry.offset : glyfEntry.offset+glyfEntry.length],
+		outlines: make(map[uint16][]glyphPoint),
+	}
+	gi.offsets, gi.lengths = parseLocaOffsets(raw, locaEntry, int(font.maxpTable.numGlyphs), font.headTable.indexToLocFormat == 1)
+	if entry := font.tableDir.table("gvar"); entry != nil && int(entry.offset+entry.length) <= len(raw) {
+		gi.gvar.parse(raw[entry.offset : entry.offset+entry.length])
+	}
+	return gi, nil
+}
+
+// glyph returns the data of the instance of glyph id, with its advance
+// width and left side bearing.
+func (gi *glyphInstancer) glyph(id uint16) (data []byte, advance, lsb int, err error) {
+	return gi.instance(id, 0)
+}
+
+const maxComponentDepth = 8
+
+func (gi *glyphInstancer) instance(id uint16, depth int) (data []byte, advance, lsb int, err error) {
+	metric := gi.font.hmtxTable.lookup(int(id))
+	advance, lsb = int(metric.advanceWidth), int(metric.leftSideBearing)
+	if int(id) >= len(gi.offsets) || gi.lengths[id] == 0 {
+		dx, _ := gi.gvar.deltas(int(id), make([]glyphPoint, numPhantomPoints), nil, gi.coords)
+		advance = otRound(float64(advance) + dx[1] - dx[0])
+		return nil, advance, lsb, nil
+	}
+	if int(gi.offsets[id]+gi.lengths[id]) > len(gi.glyf) {
+		return nil, 0, 0, errGlyphDamaged
+	}
+	orig := gi.glyf[gi.offsets[id] : gi.offsets[id]+gi.lengths[id]]
+	r := tableReader(orig)
+	left := int(int16(r.u16(2))) - lsb
+	phantoms := []glyphPoint{{x: left}, {x: left + advance}, {}, {}}
+	var points []glyphPoint
+	var leftX, rightX float64
+	if int16(r.u16(0)) >= 0 {
+		var g *simpleGlyph
+		if g, err = parseSimpleGlyph(orig); err != nil {
+			return
+		}
+		dx, dy := gi.gvar.deltas(int(id), append(append([]glyphPoint(nil), g.points...), phantoms...), g.endPts, gi.coords)
+		for i := range g.points {
+			g.points[i].x = otRound(float64(g.points[i].x) + dx[i])
+			g.points[i].y = otRound(float64(g.points[i].y) + dy[i])
+		}
+		n := len(g.points)
+		leftX, rightX = float64(phantoms[0].x)+dx[n], float64(phantoms[1].x)+dx[n+1]
+		points, data = g.points, g.bytes()
+	} else {
+		var g *compositeGlyph
+		if g, err = parseCompositeGlyph(orig); err != nil {
+			return
+		}
+		offsets := make([]glyphPoint, len(g.components), len(g.components)+numPhantomPoints)
+		for i, c := range g.components {
+			offsets[i] = glyphPoint{x: c.arg1, y: c.arg2}
+		}
+		dx, dy := gi.gvar.deltas(int(id), append(offsets, phantoms...), nil, gi.coords)
+		for i := range g.components {
+			if c := &g.components[i]; c.flags&compositeArgsAreXYValues != 0 {
+				c.arg1 = otRound(float64(c.arg1) + dx[i])
+				c.arg2 = otRound(float64(c.arg2) + dy[i])
+			}
+		}
+		n := len(g.components)
+		leftX, rightX = float64(phantoms[0].x)+dx[n], float64(phantoms[1].x)+dx[n+1]
+		if points, err = gi.placeComponents(g, depth); err != nil {
+			return
+		}
+		data = g.bytes(pointBounds(points))
+	}
+	gi.outlines[id] = points
+	advance = otRound(rightX - leftX)
+	lsb = pointBounds(points)[0] - otRound(leftX)
+	return
+}
+
+// placeComponents returns the points of the instances of the components of
+// a composite glyph, scaled and moved into place.
+func (gi *glyphInstancer) placeComponents(g *compositeGlyph, depth int) ([]glyphPoint, error) {
+	if depth >= maxComponentDepth {
+		return nil, errGlyphDamaged
+	}
+	var points []glyphPoint
+	for _, c := range g.components {
+		component, ok := gi.outlines[c.glyph]
+		if !ok {
+			if _, _, _, err := gi.instance(c.glyph, depth+1); err != nil {
+				return nil, err
+			}
+			component = gi.outlines[c.glyph]
+		}
+		m := c.transform()
+		placed := make([]glyphPoint, len(component))
+		for i, p := range component {
+			placed[i] = glyphPoint{
+				x:     otRound(m[0]*float64(p.x) + m[2]*float64(p.y)),
+				y:     otRound(m[1]*float64(p.x) + m[3]*float64(p.y)),
+				flags: p.flags,
+			}
+		}
+		ox, oy := c.arg1, c.arg2
+		if c.flags&compositeArgsAreXYValues == 0 {
+			// Align point arg2 of the component with point arg1 of those
+			// so far, where there are such points.
+			ox, oy = 0, 0
+			if c.arg1 < len(points) && c.arg2 < len(placed) {
+				ox, oy = points[c.arg1].x-placed[c.arg2].x, points[c.arg1].y-placed[c.arg2].y
+			}
+		}
+		for i := range placed {
+			placed[i].x += ox
+			placed[i].y += oy
+		}
+		points = append(points, placed...)
+	}
+	return points, nil
+}
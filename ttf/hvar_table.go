@@ -0,0 +1,93 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ttf
+
+import (
+	"io"
+	"os"
+)
+
+// hvarTable gives the advance width deltas of the glyphs of a variable font.
+type hvarTable struct {
+	store      *itemVariationStore
+	advanceMap deltaSetIndexMap
+}
+
+func (table *hvarTable) init(rs io.ReadSeeker, entry *tableDirEntry) (err error) {
+	if _, err = rs.Seek(int64(entry.offset), os.SEEK_SET); err != nil {
+		return
+	}
+	data := make([]byte, entry.length)
+	if _, err = io.ReadFull(rs, data); err != nil {
+		return
+	}
+	table.parse(data)
+	return nil
+}
+
+func (table *hvarTable) parse(data []byte) {
+	r := tableReader(data)
+	if r.u16(0) != 1 {
+		return
+	}
+	table.store = parseItemVariationStore(r, int(r.u32(4)))
+	table.advanceMap = parseDeltaSetIndexMap(r, int(r.u32(8)))
+}
+
+func (table *hvarTable) hasAdvances() bool {
+	return table.store != nil
+}
+
+// advanceDelta returns the change to the advance width of glyph at coords.
+func (table *hvarTable) advanceDelta(glyph int, coords []float64) float64 {
+	outer, inner := table.advanceMap.lookup(glyph)
+	return table.store.delta(outer, inner, coords)
+}
+
+// mvarTable gives the deltas of the font-wide metrics of a variable font,
+// keyed by tags such as hasc (ascender) and xhgt (x-height).
+type mvarTable struct {
+	store   *itemVariationStore
+	records map[string][2]int // outer and inner indexes
+}
+
+func (table *mvarTable) init(rs io.ReadSeeker, entry *tableDirEntry) (err error) {
+	if _, err = rs.Seek(int64(entry.offset), os.SEEK_SET); err != nil {
+		return
+	}
+	data := make([]byte, entry.length)
+	if _, err = io.ReadFull(rs, data); err != nil {
+		return
+	}
+	table.parse(data)
+	return nil
+}
+
+func (table *mvarTable) parse(data []byte) {
+	r := tableReader(data)
+	if r.u16(0) != 1 {
+		return
+	}
+	recordSize, recordCount := int(r.u16(6)), int(r.u16(8))
+	table.store = parseItemVariationStore(r, int(r.u16(10)))
+	if table.store == nil || recordSize < 8 {
+		return
+	}
+	table.records = make(map[string][2]int, recordCount)
+	for i := 0; i < recordCount; i++ {
+		pos := 12 + i*recordSize
+		if tag := r.bytes(pos, 4); tag != nil {
+			table.records[string(tag)] = [2]int{int(r.u16(pos + 4)), int(r.u16(pos + 6))}
+		}
+	}
+}
+
+// delta returns the change to the metric with tag at coords.
+func (table *mvarTable) delta(tag string, coords []float64) float64 {
+	rec, ok := table.records[tag]
+	if !ok {
+		return 0
+	}
+	return table.store.delta(rec[0], rec[1], coords)
+}
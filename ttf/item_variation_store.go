@@ -0,0 +1,166 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ttf
+
+// itemVariationStore gives the deltas of the HVAR and MVAR tables of a
+// variable font, each the sum of its adjustments over the regions of the
+// design space that hold the instance.
+type itemVariationStore struct {
+	r       tableReader
+	regions [][][3]float64 // per region, the start, peak and end on each axis
+	data    []int          // offsets of the item variation data subtables
+}
+
+const (
+	itemVariationLongWords = 0x8000
+	itemVariationWordCount = 0x7FFF
+	deltaSetInnerIndexBits = 0x0F
+	deltaSetEntrySizeMask  = 0x30
+	deltaSetEntrySizeShift = 4
+)
+
+// parseItemVariationStore reads the store at offset in r, or returns nil
+// if there is none.
+func parseItemVariationStore(r tableReader, offset int) *itemVariationStore {
+	if offset == 0 || r.u16(offset) != 1 {
+		return nil
+	}
+	store := &itemVariationStore{r: r}
+	regionList := offset + int(r.u32(offset+2))
+	axisCount, regionCount := int(r.u16(regionList)), int(r.u16(regionList+2))
+	store.regions = make([][][3]float64, regionCount)
+	for i := range store.regions {
+		region := make([][3]float64, axisCount)
+		for j := range region {
+			pos := regionList + 4 + 6*(i*axisCount+j)
+			region[j] = [3]float64{r.f2dot14(pos), r.f2dot14(pos + 2), r.f2dot14(pos + 4)}
+		}
+		store.regions[i] = region
+	}
+	for i := 0; i < int(r.u16(offset+6)); i++ {
+		store.data = append(store.data, offset+int(r.u32(offset+8+4*i)))
+	}
+	return store
+}
+
+// delta returns the delta of the item at outer and inner at the normalized
+// coordinates coords.
+func (store *itemVariationStore) delta(outer, inner int, coords []float64) float64 {
+	if store == nil || outer >= len(store.data) {
+		return 0
+	}
+	r, sub := store.r, store.data[outer]
+	if inner >= int(r.u16(sub)) {
+		return 0
+	}
+	wordDeltaCount, regionIndexCount := r.u16(sub+2), int(r.u16(sub+4))
+	longWords := wordDeltaCount&itemVariationLongWords != 0
+	wordCount := int(wordDeltaCount & itemVariationWordCount)
+	wordSize, shortSize := 2, 1
+	if longWords {
+		wordSize, shortSize = 4, 2
+	}
+	rowSize := wordCount*wordSize + (regionIndexCount-wordCount)*shortSize
+	pos := sub + 6 + 2*regionIndexCount + inner*rowSize
+	total := 0.0
+	for k := 0; k < regionIndexCount; k++ {
+		size := shortSize
+		if k < wordCount {
+			size = wordSize
+		}
+		value := r.signed(pos, size)
+		pos += size
+		if region := int(r.u16(sub + 6 + 2*k)); region < len(store.regions) && value != 0 {
+			total += float64(value) * regionScalar(store.regions[region], coords)
+		}
+	}
+	return total
+}
+
+// signed reads a signed big-endian integer of 1, 2 or 4 bytes.
+func (r tableReader) signed(pos, size int) int {
+	switch size {
+	case 1:
+		return int(int8(r.u8(pos)))
+	case 2:
+		return int(int16(r.u16(pos)))
+	case 4:
+		return int(int32(r.u32(pos)))
+	}
+	return 0
+}
+
+// regionScalar returns the weight, from 0 to 1, of the adjustments of a
+// region of the design space at coords.
+func regionScalar(region [][3]float64, coords []float64) float64 {
+	scalar := 1.0
+	for i, axis := range region {
+		v := 0.0
+		if i < len(coords) {
+			v = coords[i]
+		}
+		scalar *= axisScalar(v, axis[0], axis[1], axis[2])
+		if scalar == 0 {
+			break
+		}
+	}
+	return scalar
+}
+
+// axisScalar returns the weight on one axis of a region that rises from
+// start to peak and falls to end, at the coordinate v.
+func axisScalar(v, start, peak, end float64) float64 {
+	switch {
+	case peak == 0 || start > peak || peak > end || (start < 0 && end > 0):
+		return 1
+	case v == peak:
+		return 1
+	case v <= start || v >= end:
+		return 0
+	case v < peak:
+		return (v - start) / (peak - start)
+	}
+	return (end - v) / (end - peak)
+}
+
+// deltaSetIndexMap maps glyph IDs to the outer and inner indexes of their
+// items in an item variation store.
+type deltaSetIndexMap []uint32 // outer << 16 | inner
+
+func parseDeltaSetIndexMap(r tableReader, offset int) deltaSetIndexMap {
+	if offset == 0 {
+		return nil
+	}
+	format, entryFormat := r.u8(offset), r.u8(offset+1)
+	count, pos := int(r.u16(offset+2)), offset+4
+	if format == 1 {
+		count, pos = int(r.u32(offset+2)), offset+6
+	}
+	entrySize := int(entryFormat&deltaSetEntrySizeMask)>>deltaSetEntrySizeShift + 1
+	innerBits := int(entryFormat&deltaSetInnerIndexBits) + 1
+	if count == 0 || pos+count*entrySize > len(r) {
+		return nil
+	}
+	m := make(deltaSetIndexMap, count)
+	for i := range m {
+		var entry uint32
+		for _, b := range r.bytes(pos+i*entrySize, entrySize) {
+			entry = entry<<8 | uint32(b)
+		}
+		m[i] = (entry>>innerBits)<<16 | entry&(1<<innerBits-1)
+	}
+	return m
+}
+
+// lookup returns the outer and inner indexes of item i; items past the end
+// of the map use its last entry, and with no map, i is the inner index.
+func (m deltaSetIndexMap) lookup(i int) (outer, inner int) {
+	if m == nil {
+		return 0, i
+	}
+	if i >= len(m) {
+		i = len(m) - 1
+	}
+	return int(m[i] >> 16), int(m[i] & 0xFFFF)
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ttf
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+)
+
+// kernTable holds the horizontal pairs of format 0 subtables of a kern table,
+// in either the Microsoft or the Apple layout.
+type kernTable struct {
+	pairs map[uint32]int16 // keyed by left glyph << 16 | right glyph
+}
+
+const (
+	kernCoverageHorizontal  = 0x0001
+	kernCoverageMinimum     = 0x0002
+	kernCoverageCrossStream = 0x0004
+	kernCoverageOverride    = 0x0008
+
+	appleKernCoverageVertical    = 0x8000
+	appleKernCoverageCrossStream = 0x4000
+	appleKernCoverageVariation   = 0x2000
+)
+
+func (table *kernTable) init(rs io.ReadSeeker, entry *tableDirEntry) (err error) {
+	if _, err = rs.Seek(int64(entry.offset), os.SEEK_SET); err != nil {
+		return
+	}
+	data := make([]byte, entry.length)
+	if _, err = io.ReadFull(rs, data); err != nil {
+		return
+	}
+	table.parse(data)
+	return nil
+}
+
+// parse reads what it can of data, ignoring subtables it does not support
+// or that are cut short.
+func (table *kernTable) parse(data []byte) {
+	if len(data) < 4 {
+		return
+	}
+	if binary.BigEndian.Uint16(data) == 0 {
+		// Microsoft: version, nTables; subtables have version, length, coverage.
+		nTables := int(binary.BigEndian.Uint16(data[2:]))
+		pos := 4
+		for i := 0; i < nTables && pos+6 <= len(data); i++ {
+			length := int(binary.BigEndian.Uint16(data[pos+2:]))
+			coverage := binary.BigEndian.Uint16(data[pos+4:])
+			end := min(pos+length, len(data))
+			if length < 6 {
+				// Some fonts give the length of a single large subtable mod 65536.
+				end = len(data)
+			}
+			if coverage>>8 == 0 && coverage&(kernCoverageHorizontal|kernCoverageMinimum|kernCoverageCrossStream) == kernCoverageHorizontal {
+				table.readFormat0(data[pos+6:end], coverage&kernCoverageOverride != 0)
+			}
+			pos = end
+		}
+		return
+	}
+	if len(data) < 8 || binary.BigEndian.Uint32(data) != 0x00010000 {
+		return
+	}
+	// Apple: version, nTables; subtables have length, coverage, tupleIndex.
+	nTables := int(binary.BigEndian.Uint32(data[4:]))
+	pos := 8
+	for i := 0; i < nTables && pos+8 <= len(data); i++ {
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		coverage := binary.BigEndian.Uint16(data[pos+4:])
+		if length < 8 || pos+length > len(data) {
+			return
+		}
+		if coverage&0xFF == 0 && coverage&(appleKernCoverageVertical|appleKernCoverageCrossStream|appleKernCoverageVariation) == 0 {
+			table.readFormat0(data[pos+8:pos+length], false)
+		}
+		pos += length
+	}
+}
+
+// readFormat0 adds the ordered pairs of a format 0 subtable, which add to
+// those of earlier subtables unless override is set.
+func (table *kernTable) readFormat0(data []byte, override bool) {
+	if len(data) < 8 {
+		return
+	}
+	nPairs := int(binary.BigEndian.Uint16(data))
+	data = data[8:]
+	if table.pairs == nil {
+		table.pairs = make(map[uint32]int16, nPairs)
+	}
+	for i := 0; i < nPairs && 6*i+6 <= len(data); i++ {
+		rec := data[6*i:]
+		key := binary.BigEndian.Uint32(rec)
+		value := int16(binary.BigEndian.Uint16(rec[4:]))
+		if override {
+			table.pairs[key] = value
+		} else {
+			table.pairs[key] += value
+		}
+	}
+}
+
+func (table *kernTable) kerning(left, right uint16) int {
+	return int(table.pairs[uint32(left)<<16|uint32(right)])
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ttf
+
+import (
+	"bytes"
+	"testing"
+)
+
+// u16s returns values as big-endian 16-bit fields; negative values are
+// written in two's complement.
+func u16s(values ...int) []byte {
+	var buf bytes.Buffer
+	for _, v := range values {
+		writeUint16(&buf, uint16(v))
+	}
+	return buf.Bytes()
+}
+
+func TestKernTable_Microsoft(t *testing.T) {
+	var data []byte
+	data = append(data, u16s(0, 2)...)
+	// Horizontal format 0 subtable with two pairs.
+	data = append(data, u16s(0, 6+8+12, kernCoverageHorizontal, 2, 12, 1, 0)...)
+	data = append(data, u16s(3, 4, -50, 4, 3, 20)...)
+	// Cross-stream subtable, which does not change advances.
+	data = append(data, u16s(0, 6+8+6, kernCoverageHorizontal|kernCoverageCrossStream, 1, 6, 0, 0)...)
+	data = append(data, u16s(3, 4, 99)...)
+
+	var table kernTable
+	table.parse(data)
+	expectI(t, "3 4", -50, table.kerning(3, 4))
+	expectI(t, "4 3", 20, table.kerning(4, 3))
+	expectI(t, "3 3", 0, table.kerning(3, 3))
+}
+
+func TestKernTable_Apple(t *testing.T) {
+	var data []byte
+	data = append(data, u16s(1, 0, 0, 1)...)
+	data = append(data, u16s(0, 8+8+6, 0, 0, 1, 6, 0, 0)...)
+	data = append(data, u16s(7, 9, -80)...)
+
+	var table kernTable
+	table.parse(data)
+	expectI(t, "7 9", -80, table.kerning(7, 9))
+}
+
+func TestGposTable_Kerning(t *testing.T) {
+	// Lookup 0: PairPos format 1 covering glyph 3, followed by 5 or 7.
+	pairPos1 := u16s(1, 22, valueFormatXAdvance, 0, 1, 12)
+	pairPos1 = append(pairPos1, u16s(2, 5, -40, 7, -60)...)
+	pairPos1 = append(pairPos1, u16s(1, 1, 3)...)
+	lookup0 := append(u16s(gposLookupPair, 0, 1, 8), pairPos1...)
+
+	// Lookup 1: an extension to PairPos format 2, with value records of
+	// XPlacement and XAdvance. Glyphs 3 and 4 are in first classes 0 and 1;
+	// glyphs 5 and 6 are in second class 1.
+	pairPos2 := u16s(2, 32, 0x0001|valueFormatXAdvance, 0, 42, 52, 2, 2)
+	pairPos2 = append(pairPos2, u16s(0, 0, 0, -10, 0, 0, 0, -25)...)
+	pairPos2 = append(pairPos2, u16s(2, 1, 3, 4, 0)...)
+	pairPos2 = append(pairPos2, u16s(1, 3, 2, 0, 1)...)
+	pairPos2 = append(pairPos2, u16s(2, 1, 5, 6, 1)...)
+	extension := append(u16s(1, gposLookupPair, 0, 8), pairPos2...)
+	lookup1 := append(u16s(gposLookupExtension, 0, 1, 8), extension...)
+
+	var data []byte
+	data = append(data, u16s(1, 0, 10, 12, 28)...)
+	data = append(data, u16s(0)...)
+	data = append(data, u16s(1)...)
+	data = append(data, "kern"...)
+	data = append(data, u16s(8, 0, 2, 1, 0)...)
+	data = append(data, u16s(2, 6, 6+len(lookup0))...)
+	data = append(data, lookup0...)
+	data = append(data, lookup1...)
+
+	var table gposTable
+	table.parse(data)
+	expect(t, "hasKerning", table.hasKerning())
+	expectI(t, "3 5", -50, table.kerning(3, 5))
+	expectI(t, "3 7", -60, table.kerning(3, 7))
+	expectI(t, "4 6", -25, table.kerning(4, 6))
+	expectI(t, "3 9", 0, table.kerning(3, 9))
+	expectI(t, "9 5", 0, table.kerning(9, 5))
+}
+
+func TestGposTable_Damaged(t *testing.T) {
+	var table gposTable
+	table.parse(u16s(1, 0, 10, 12, 0xFFFF))
+	expect(t, "hasKerning", !table.hasKerning())
+	expectI(t, "kerning", 0, table.kerning(3, 5))
+}
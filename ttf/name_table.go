@@ -52,6 +52,8 @@ const (
 	preferredSubfamilyID    = 17
 	compatibleFullID        = 18
 	sampleTextID            = 19
+
+	variationsPostScriptNamePrefixID = 25
 )
 
 type nameTable struct {
@@ -78,6 +80,7 @@ type nameTable struct {
 	preferredSubfamily    string
 	compatibleFull        string
 	sampleText            string
+	otherNames            map[uint16]string // other name IDs, such as those of fvar axes and instances
 }
 
 func (table *nameTable) init(rs io.ReadSeeker, entry *tableDirEntry) (err error) {
@@ -154,7 +157,7 @@ func (table *nameTable) getField(nameID uint16) string {
 	case sampleTextID:
 		return table.sampleText
 	}
-	return ""
+	return table.otherNames[nameID]
 }
 
 func (table *nameTable) readField(rec *nameRecord, file io.Reader) (s string, err error) {
@@ -218,6 +221,11 @@ func (table *nameTable) setField(nameID uint16, s string, overwrite bool) {
 		table.compatibleFull = s
 	case sampleTextID:
 		table.sampleText = s
+	default:
+		if table.otherNames == nil {
+			table.otherNames = make(map[uint16]string)
+		}
+		table.otherNames[nameID] = s
 	}
 }
 
@@ -14,10 +14,12 @@ import (
 // Composite glyph component flag bits (OpenType spec §2.5.2 / Table 31).
 const (
 	compositeArgOneAndTwoAreWords uint16 = 1 << 0
+	compositeArgsAreXYValues      uint16 = 1 << 1
 	compositeWeHaveAScale         uint16 = 1 << 3
 	compositeMoreComponents       uint16 = 1 << 5
 	compositeWeHaveAnXAndYScale   uint16 = 1 << 6
 	compositeWeHaveATwoByTwo      uint16 = 1 << 7
+	compositeWeHaveInstructions   uint16 = 1 << 8
 )
 
 // Subset returns a valid TTF binary containing only the glyphs in glyphIDs
@@ -25,12 +27,18 @@ const (
 // glyph records. Glyph 0 (.notdef) is always included. The output uses long
 // (format 1) loca offsets and trims maxp, hmtx, cmap, and post alongside
 // glyf/loca so the embedded subset is materially smaller while preserving
-// glyph IDs up to the highest included glyph.
+// glyph IDs up to the highest included glyph. For an OpenType font with CFF
+// outlines, the CFF table is subset in place of glyf/loca. The subset of an
+// instance of a variable font is a static font with the instance's glyphs
+// and metrics.
 func (font *Font) Subset(glyphIDs []uint16) ([]byte, error) {
 	raw, err := os.ReadFile(font.filename)
 	if err != nil {
 		return nil, fmt.Errorf("subset: reading %s: %w", font.filename, err)
 	}
+	if entry := font.tableDir.table("CFF "); entry != nil {
+		return font.subsetCFF(raw, entry, glyphIDs)
+	}
 
 	glyfEntry := font.tableDir.table("glyf")
 	locaEntry := font.tableDir.table("loca")
@@ -92,12 +100,17 @@ func (font *Font) Subset(glyphIDs []uint16) ([]byte, error) {
 	for _, id := range glyphIDs {
 		expand(id)
 	}
+	subsetGlyphCount := subsetGlyphCount(closure)
 
-	subsetGlyphCount := 1
-	for id := range closure {
-		if int(id)+1 > subsetGlyphCount {
-			subsetGlyphCount = int(id) + 1
+	// An instance of a variable font has its glyph variations applied, which
+	// move the glyphs' left side bearings.
+	var instancer *glyphInstancer
+	var lsbs map[uint16]int
+	if font.base != nil {
+		if instancer, err = font.base.newGlyphInstancer(raw, font.coords); err != nil {
+			return nil, fmt.Errorf("subset: %w", err)
 		}
+		lsbs = make(map[uint16]int, len(closure))
 	}
 
 	// Build new glyf table and corresponding long-format loca.
@@ -107,7 +120,13 @@ func (font *Font) Subset(glyphIDs []uint16) ([]byte, error) {
 		newLoca[i] = uint32(glyfBuf.Len())
 		if closure[uint16(i)] && glyfLen[i] > 0 {
 			absOff := int(glyfEntry.offset) + int(glyfOff[i])
-			glyfBuf.Write(raw[absOff : absOff+int(glyfLen[i])])
+			glyph := raw[absOff : absOff+int(glyfLen[i])]
+			if instancer != nil {
+				if glyph, _, lsbs[uint16(i)], err = instancer.glyph(uint16(i)); err != nil {
+					return nil, fmt.Errorf("subset: glyph %d: %w", i, err)
+				}
+			}
+			glyfBuf.Write(glyph)
 			// Pad each glyph slot to a 4-byte boundary.
 			if pad := glyfBuf.Len() % 4; pad != 0 {
 				glyfBuf.Write(make([]byte, 4-pad))
@@ -122,18 +141,71 @@ func (font *Font) Subset(glyphIDs []uint16) ([]byte, error) {
 		binary.BigEndian.PutUint32(locaBuf[i*4:], off)
 	}
 
-	maxpBuf, err := subsetMaxpTable(raw, font.tableDir.table("maxp"), uint16(subsetGlyphCount))
+	tables := map[string][]byte{
+		"glyf": glyfBuf.Bytes(),
+		"loca": locaBuf,
+	}
+	if instancer != nil {
+		tables["hmtx"] = font.subsetHmtxTable(uint16(subsetGlyphCount), lsbs)
+	}
+	return font.assembleSubset(raw, closure, subsetGlyphCount, tables)
+}
+
+// subsetCFF subsets an OpenType font with CFF outlines. Glyphs are drawn
+// from their own charstrings and subroutines, so the closure is the glyphs
+// asked for and the base and accent glyphs of those built with the
+// deprecated seac-like form of endchar.
+func (font *Font) subsetCFF(raw []byte, entry *tableDirEntry, glyphIDs []uint16) ([]byte, error) {
+	if int(entry.offset)+int(entry.length) > len(raw) {
+		return nil, fmt.Errorf("subset: malformed CFF table")
+	}
+	cff, err := parseCFF(raw[entry.offset : entry.offset+entry.length])
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("subset: %w", err)
 	}
-	hheaBuf, err := subsetHheaTable(raw, font.tableDir.table("hhea"), uint16(subsetGlyphCount))
+	numGlyphs := len(cff.charStrings)
+	closure := make(map[uint16]bool, len(glyphIDs)+1)
+	closure[0] = true
+	for _, id := range glyphIDs {
+		if int(id) < numGlyphs {
+			closure[id] = true
+		}
+		if base, accent, ok := cff.seacComponents(id); ok {
+			closure[base] = true
+			closure[accent] = true
+		}
+	}
+	subsetGlyphCount := subsetGlyphCount(closure)
+	return font.assembleSubset(raw, closure, subsetGlyphCount, map[string][]byte{
+		"CFF ": cff.subset(closure, subsetGlyphCount),
+	})
+}
+
+// subsetGlyphCount returns the number of glyphs a subset keeps: those up to
+// the highest in closure.
+func subsetGlyphCount(closure map[uint16]bool) int {
+	count := 1
+	for id := range closure {
+		if int(id)+1 > count {
+			count = int(id) + 1
+		}
+	}
+	return count
+}
+
+// assembleSubset returns the font with the outline and other tables in
+// tables and its remaining tables trimmed to the first subsetGlyphCount
+// glyphs.
+func (font *Font) assembleSubset(raw []byte, closure map[uint16]bool, subsetGlyphCount int, tables map[string][]byte) ([]byte, error) {
+	maxpBuf, err := subsetMaxpTable(raw, font.tableDir.table("maxp"), uint16(subsetGlyphCount))
 	if err != nil {
 		return nil, err
 	}
-	hmtxBuf, err := font.subsetHmtxTable(uint16(subsetGlyphCount))
+	hheaBuf, err := subsetHheaTable(raw, font.tableDir.table("hhea"), uint16(subsetGlyphCount))
 	if err != nil {
 		return nil, err
 	}
+	hmtxBuf := font.subsetHmtxTable(uint16(subsetGlyphCount), nil)
 	cmapBuf, err := font.subsetCmapTable(closure)
 	if err != nil {
 		return nil, err
@@ -143,45 +215,42 @@ func (font *Font) Subset(glyphIDs []uint16) ([]byte, error) {
 		return nil, err
 	}
 
-	// Collect all tables from the original font, replacing glyf and loca.
+	// Collect all tables from the original font, replacing those in tables.
 	type namedTable struct {
 		tag  string
 		data []byte
 	}
-	tables := make([]namedTable, 0, len(font.tableDir.entries))
+	kept := make([]namedTable, 0, len(font.tableDir.entries))
 	for _, entry := range font.tableDir.entries {
 		if !subsetKeepTable(entry.tag) {
 			continue
 		}
-		var data []byte
-		switch entry.tag {
-		case "glyf":
-			data = glyfBuf.Bytes()
-		case "loca":
-			data = locaBuf
-		case "maxp":
+		data, ok := tables[entry.tag]
+		switch {
+		case ok:
+		case entry.tag == "maxp":
 			data = maxpBuf
-		case "hhea":
+		case entry.tag == "hhea":
 			data = hheaBuf
-		case "hmtx":
+		case entry.tag == "hmtx":
 			data = hmtxBuf
-		case "cmap":
+		case entry.tag == "cmap":
 			data = cmapBuf
-		case "post":
+		case entry.tag == "post":
 			data = postBuf
 		default:
 			data = make([]byte, entry.length)
 			copy(data, raw[entry.offset:uint32(entry.offset)+entry.length])
 		}
-		tables = append(tables, namedTable{entry.tag, data})
+		kept = append(kept, namedTable{entry.tag, data})
 	}
 	// Table directory entries must be in ascending tag order.
-	sort.Slice(tables, func(i, j int) bool { return tables[i].tag < tables[j].tag })
+	sort.Slice(kept, func(i, j int) bool { return kept[i].tag < kept[j].tag })
 
 	// Compute table offsets. The font starts with:
 	//   12-byte offset table + 16*nTables-byte directory
 	// followed by table data aligned to a 4-byte boundary.
-	nTables := uint16(len(tables))
+	nTables := uint16(len(kept))
 	dirEnd := 12 + 16*int(nTables)
 	dataStart := dirEnd
 	if rem := dataStart % 4; rem != 0 {
@@ -195,9 +264,9 @@ func (font *Font) Subset(glyphIDs []uint16) ([]byte, error) {
 		length   uint32
 		data     []byte
 	}
-	records := make([]record, len(tables))
+	records := make([]record, len(kept))
 	off := uint32(dataStart)
-	for i, t := range tables {
+	for i, t := range kept {
 		copy(records[i].tag[:], t.tag)
 		records[i].length = uint32(len(t.data))
 		records[i].offset = off
@@ -212,13 +281,15 @@ func (font *Font) Subset(glyphIDs []uint16) ([]byte, error) {
 
 	// Patch the head table:
 	//   • zero checkSumAdjustment (byte offset 8, 4 bytes)
-	//   • set indexToLocFormat = 1 (byte offset 50, 2 bytes)
+	//   • set indexToLocFormat = 1 (byte offset 50, 2 bytes), if there is a loca
 	for i := range records {
 		if string(records[i].tag[:]) == "head" {
 			patched := make([]byte, len(records[i].data))
 			copy(patched, records[i].data)
-			binary.BigEndian.PutUint32(patched[8:], 0)  // checkSumAdjustment = 0
-			binary.BigEndian.PutUint16(patched[50:], 1) // indexToLocFormat = 1 (long)
+			binary.BigEndian.PutUint32(patched[8:], 0) // checkSumAdjustment = 0
+			if _, ok := tables["loca"]; ok {
+				binary.BigEndian.PutUint16(patched[50:], 1) // indexToLocFormat = 1 (long)
+			}
 			records[i].data = patched
 			records[i].checkSum = ttfTableChecksum(patched)
 		}
@@ -265,6 +336,7 @@ func (font *Font) Subset(glyphIDs []uint16) ([]byte, error) {
 }
 
 var subsetRequiredTables = map[string]bool{
+	"CFF ": true,
 	"cmap": true,
 	"glyf": true,
 	"head": true,
@@ -313,14 +385,19 @@ func subsetHheaTable(raw []byte, entry *tableDirEntry, numGlyphs uint16) ([]byte
 	return data, nil
 }
 
-func (font *Font) subsetHmtxTable(numGlyphs uint16) ([]byte, error) {
+// subsetHmtxTable returns the metrics of the first numGlyphs glyphs, with
+// the left side bearings in lsbs in place of the font's.
+func (font *Font) subsetHmtxTable(numGlyphs uint16, lsbs map[uint16]int) []byte {
 	var buf bytes.Buffer
 	for gid := uint16(0); gid < numGlyphs; gid++ {
 		metric := font.hmtxTable.lookup(int(gid))
+		if lsb, ok := lsbs[gid]; ok {
+			metric.leftSideBearing = int16(lsb)
+		}
 		writeUint16(&buf, metric.advanceWidth)
 		writeInt16(&buf, metric.leftSideBearing)
 	}
-	return buf.Bytes(), nil
+	return buf.Bytes()
 }
 
 func (font *Font) subsetCmapTable(closure map[uint16]bool) ([]byte, error) {
@@ -1,7 +1,7 @@
 //go:build ignore
 
-// Command generate produces the minimal TTF and TTC test fixtures used by the
-// ttf package tests. Run with:
+// Command generate produces the minimal TTF, TTC, OTF and variable TTF test
+// fixtures used by the ttf package tests. Run with:
 //
 //	go run ttf/testdata/generate/main.go
 //
@@ -12,6 +12,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"math"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -38,6 +39,28 @@ func main() {
 		os.Exit(1)
 	}
 	fmt.Println("wrote minimal.ttc")
+
+	for _, otf := range []struct {
+		filename string
+		family   string
+		cidKeyed bool
+	}{
+		{"minimal.otf", "MinimalCFF", false},
+		{"minimal-cid.otf", "MinimalCID", true},
+	} {
+		otfData := buildOTF(otf.family, "Regular", 400, otf.cidKeyed)
+		if err := os.WriteFile(filepath.Join(outDir, otf.filename), otfData, 0644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println("wrote", otf.filename)
+	}
+
+	if err := os.WriteFile(filepath.Join(outDir, "minimal-var.ttf"), buildVariableFont(), 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Println("wrote minimal-var.ttf")
 }
 
 // ── codepoint set ────────────────────────────────────────────────────────────
@@ -98,6 +121,27 @@ func buildFont(family, subfamily string, weight uint16) []byte {
 	return assembleTTF(tables)
 }
 
+// buildOTF builds an OpenType font with CFF outlines and the same glyphs and
+// metrics as buildFont, but with a square in place of the composite glyph.
+func buildOTF(family, subfamily string, weight uint16, cidKeyed bool) []byte {
+	cp := codepoints()
+	numGlyphs := uint16(len(cp) + 1)
+
+	tables := map[string][]byte{
+		"CFF ": buildCFF(family, cp, cidKeyed),
+		"cmap": buildCmap(cp),
+		"head": buildHead(),
+		"hhea": buildHhea(numGlyphs),
+		"hmtx": buildHmtx(numGlyphs),
+		"maxp": buildMaxpCFF(numGlyphs),
+		"name": buildName(family, subfamily),
+		"OS/2": buildOS2(weight, cp),
+		"post": buildPost(),
+	}
+
+	return assembleFont(0x4F54544F, tables) // 'OTTO'
+}
+
 // ── table builders ────────────────────────────────────────────────────────────
 
 func buildCmap(cp []rune) []byte {
@@ -240,12 +284,12 @@ func compositeGlyph(comp1, comp2 uint16) []byte {
 	putI16(&b, glyphWidth*2) // xMax
 	putI16(&b, glyphHeight)  // yMax
 	// Component 1: MORE_COMPONENTS set, args are int8 offsets (0,0)
-	putU16(&b, 0x0020) // flags: MORE_COMPONENTS
+	putU16(&b, 0x0022) // flags: MORE_COMPONENTS | ARGS_ARE_XY_VALUES
 	putU16(&b, comp1)  // glyphIndex
 	b.WriteByte(0)     // arg1 (x offset, int8)
 	b.WriteByte(0)     // arg2 (y offset, int8)
 	// Component 2: no MORE_COMPONENTS, args are int8 offsets (50,0)
-	putU16(&b, 0x0000) // flags: none
+	putU16(&b, 0x0002) // flags: ARGS_ARE_XY_VALUES
 	putU16(&b, comp2)  // glyphIndex
 	b.WriteByte(50)    // arg1 (x offset, int8)
 	b.WriteByte(0)     // arg2 (y offset, int8)
@@ -376,15 +420,251 @@ func buildMaxp(numGlyphs uint16) []byte {
 	return b.Bytes()
 }
 
-func buildName(family, subfamily string) []byte {
+// buildMaxpCFF builds the version 0.5 maxp table of fonts with CFF outlines.
+func buildMaxpCFF(numGlyphs uint16) []byte {
+	var b bytes.Buffer
+	putU32(&b, 0x00005000) // version 0.5
+	putU16(&b, numGlyphs)
+	return b.Bytes()
+}
+
+// ── CFF builder ───────────────────────────────────────────────────────────────
+
+// Type 2 charstring and DICT operators.
+const (
+	csRlineto   = 5
+	csCallsubr  = 10
+	csReturn    = 11
+	csEndchar   = 14
+	csRmoveto   = 21
+	csCallgsubr = 29
+
+	dictFullName    = 2
+	dictFamilyName  = 3
+	dictFontBBox    = 5
+	dictCharset     = 15
+	dictCharStrings = 17
+	dictPrivate     = 18
+	dictSubrs       = 19
+	dictDefaultW    = 20
+	dictNominalW    = 21
+	dictROS         = 12<<8 | 30
+	dictCIDCount    = 12<<8 | 34
+	dictFDArray     = 12<<8 | 36
+	dictFDSelect    = 12<<8 | 37
+	dictFontName    = 12<<8 | 38
+)
+
+// cidOffset is added to the glyph ID to give each glyph's CID in the
+// CID-keyed font, so that CIDs and glyph IDs differ.
+const cidOffset = 100
+
+// buildCFF builds a CFF font program (Adobe Technical Note #5176). Every
+// glyph draws the square through a global subroutine, except .notdef, which
+// draws it through a local one. The name-keyed font names its glyphs uniXXXX;
+// the CID-keyed one has a single Font DICT.
+func buildCFF(fontName string, cp []rune, cidKeyed bool) []byte {
+	numGlyphs := len(cp) + 1
+	var strs [][]byte
+	sid := func(s string) int {
+		strs = append(strs, []byte(s))
+		return 391 + len(strs) - 1 // after the standard strings
+	}
+
+	var square bytes.Buffer
+	putCFFInts(&square, false, 0, 0)
+	square.WriteByte(csRmoveto)
+	putCFFInts(&square, false, glyphWidth, 0)
+	square.WriteByte(csRlineto)
+	putCFFInts(&square, false, 0, glyphHeight)
+	square.WriteByte(csRlineto)
+	putCFFInts(&square, false, -glyphWidth, 0)
+	square.WriteByte(csRlineto)
+	square.WriteByte(csReturn)
+	subrs := [][]byte{square.Bytes()}
+
+	charStrings := make([][]byte, numGlyphs)
+	for i := range charStrings {
+		var b bytes.Buffer
+		putCFFInts(&b, false, -107) // subroutine 0, less the bias for fewer than 1240
+		if i == 0 {
+			b.WriteByte(csCallsubr)
+		} else {
+			b.WriteByte(csCallgsubr)
+		}
+		b.WriteByte(csEndchar)
+		charStrings[i] = b.Bytes()
+	}
+
+	var private bytes.Buffer
+	putCFFInts(&private, false, advanceWidth)
+	putCFFOp(&private, dictDefaultW)
+	putCFFInts(&private, false, 0)
+	putCFFOp(&private, dictNominalW)
+	putCFFInts(&private, true, private.Len()+6)
+	putCFFOp(&private, dictSubrs)
+
+	var charset, fdSelect bytes.Buffer
+	if cidKeyed {
+		charset.WriteByte(2) // format 2: one range of CIDs
+		putU16(&charset, 1+cidOffset)
+		putU16(&charset, uint16(numGlyphs-2))
+		fdSelect.WriteByte(3) // format 3: one range, all in Font DICT 0
+		putU16(&fdSelect, 1)
+		putU16(&fdSelect, 0)
+		fdSelect.WriteByte(0)
+		putU16(&fdSelect, uint16(numGlyphs))
+	} else {
+		charset.WriteByte(0) // format 0: a glyph name SID for each glyph
+		for _, r := range cp {
+			putU16(&charset, uint16(sid(fmt.Sprintf("uni%04X", r))))
+		}
+	}
+
+	// topDict encodes the Top DICT with offsets from the start of the data
+	// following the Global Subr INDEX; offsets are always 5 bytes long.
+	var registry, ordering, fdFontName int
+	if cidKeyed {
+		registry, ordering, fdFontName = sid("Adobe"), sid("Identity"), sid(fontName+"-Regular")
+	}
+	fullName, familyName := sid(fontName), sid(fontName)
+	topDict := func(base int) []byte {
+		var b bytes.Buffer
+		if cidKeyed {
+			putCFFInts(&b, false, registry, ordering, 0)
+			putCFFOp(&b, dictROS)
+		}
+		putCFFInts(&b, false, fullName)
+		putCFFOp(&b, dictFullName)
+		putCFFInts(&b, false, familyName)
+		putCFFOp(&b, dictFamilyName)
+		putCFFInts(&b, false, 0, descent, glyphWidth, ascent)
+		putCFFOp(&b, dictFontBBox)
+		pos := base
+		putCFFInts(&b, true, pos)
+		putCFFOp(&b, dictCharset)
+		pos += charset.Len()
+		if cidKeyed {
+			putCFFInts(&b, false, numGlyphs+cidOffset)
+			putCFFOp(&b, dictCIDCount)
+			putCFFInts(&b, true, pos)
+			putCFFOp(&b, dictFDSelect)
+			pos += fdSelect.Len()
+		}
+		putCFFInts(&b, true, pos)
+		putCFFOp(&b, dictCharStrings)
+		pos += cffIndexLen(charStrings)
+		if cidKeyed {
+			pos += private.Len() + cffIndexLen(subrs)
+			putCFFInts(&b, true, pos)
+			putCFFOp(&b, dictFDArray)
+		} else {
+			putCFFInts(&b, true, private.Len(), pos)
+			putCFFOp(&b, dictPrivate)
+		}
+		return b.Bytes()
+	}
+	fdArray := func(base int) [][]byte {
+		var fd bytes.Buffer
+		putCFFInts(&fd, false, fdFontName)
+		putCFFOp(&fd, dictFontName)
+		putCFFInts(&fd, true, private.Len(), base+charset.Len()+fdSelect.Len()+cffIndexLen(charStrings))
+		putCFFOp(&fd, dictPrivate)
+		return [][]byte{fd.Bytes()}
+	}
+
+	names := [][]byte{[]byte(fontName)}
+	headerLen := 4 + cffIndexLen(names) + cffIndexLen([][]byte{topDict(0)}) + cffIndexLen(strs) + cffIndexLen(subrs)
+
+	var b bytes.Buffer
+	b.Write([]byte{1, 0, 4, 4}) // major, minor, hdrSize, offSize
+	putCFFIndex(&b, names)
+	putCFFIndex(&b, [][]byte{topDict(headerLen)})
+	putCFFIndex(&b, strs)
+	putCFFIndex(&b, subrs) // global
+	b.Write(charset.Bytes())
+	b.Write(fdSelect.Bytes())
+	putCFFIndex(&b, charStrings)
+	b.Write(private.Bytes())
+	putCFFIndex(&b, subrs) // local
+	if cidKeyed {
+		putCFFIndex(&b, fdArray(headerLen))
+	}
+	return b.Bytes()
+}
+
+// putCFFInts encodes integer operands, in the 5-byte form if long is set.
+func putCFFInts(b *bytes.Buffer, long bool, values ...int) {
+	for _, v := range values {
+		switch {
+		case long:
+			b.WriteByte(29)
+			putU32(b, uint32(int32(v)))
+		case v >= -107 && v <= 107:
+			b.WriteByte(byte(v + 139))
+		case v >= 108 && v <= 1131:
+			v -= 108
+			b.WriteByte(byte(v/256 + 247))
+			b.WriteByte(byte(v % 256))
+		case v >= -1131 && v <= -108:
+			v = -v - 108
+			b.WriteByte(byte(v/256 + 251))
+			b.WriteByte(byte(v % 256))
+		default:
+			b.WriteByte(28)
+			putU16(b, uint16(int16(v)))
+		}
+	}
+}
+
+func putCFFOp(b *bytes.Buffer, op int) {
+	if op > 0xFF {
+		b.WriteByte(byte(op >> 8))
+	}
+	b.WriteByte(byte(op))
+}
+
+// putCFFIndex writes an INDEX with 4-byte offsets.
+func putCFFIndex(b *bytes.Buffer, items [][]byte) {
+	putU16(b, uint16(len(items)))
+	if len(items) == 0 {
+		return
+	}
+	b.WriteByte(4)
+	offset := uint32(1)
+	putU32(b, offset)
+	for _, item := range items {
+		offset += uint32(len(item))
+		putU32(b, offset)
+	}
+	for _, item := range items {
+		b.Write(item)
+	}
+}
+
+func cffIndexLen(items [][]byte) int {
+	if len(items) == 0 {
+		return 2
+	}
+	n := 3 + 4*(len(items)+1)
+	for _, item := range items {
+		n += len(item)
+	}
+	return n
+}
+
+type nameEntry struct {
+	id  uint16
+	val string
+}
+
+// buildName builds a name table with the family names and any extra
+// entries, such as the names of the axes and instances of a variable font.
+func buildName(family, subfamily string, extra ...nameEntry) []byte {
 	postscript := family + "-" + subfamily
 	if subfamily == "Regular" {
 		postscript = family
 	}
-	type nameEntry struct {
-		id  uint16
-		val string
-	}
 	entries := []nameEntry{
 		{1, family},
 		{2, subfamily},
@@ -394,6 +674,7 @@ func buildName(family, subfamily string) []byte {
 	if subfamily == "Regular" {
 		entries[2] = nameEntry{4, family}
 	}
+	entries = append(entries, extra...)
 
 	// Platform 3 (Windows), encoding 1 (UCS-2), language 0x0409 (en-US)
 	// Strings stored as UTF-16 BE
@@ -501,12 +782,317 @@ func buildPost() []byte {
 	return b.Bytes()
 }
 
+// ── variable font builder ────────────────────────────────────────────────────
+
+// The variable fixture has a weight axis, along which each square widens to
+// the right, and a width axis, along which it narrows. Named instances and
+// axes have the name IDs below.
+const (
+	wghtMin, wghtDefault, wghtMax = 100, 400, 900
+	wdthMin, wdthDefault, wdthMax = 75, 100, 100
+
+	nameWeight        = 256
+	nameWidth         = 257
+	nameLight         = 258
+	nameRegular       = 259
+	nameBold          = 260
+	nameBoldPS        = 261
+	nameCondensedBold = 262
+
+	boldDelta      = 200  // at wght 900
+	lightDelta     = -100 // at wght 100
+	condensedDelta = -150 // at wdth 75
+)
+
+// buildVariableFont builds a TrueType variable font with the glyphs and
+// metrics of buildFont at its default instance.
+func buildVariableFont() []byte {
+	cp := codepoints()
+	numGlyphs := uint16(len(cp) + 1)
+
+	tables := map[string][]byte{
+		"HVAR": buildHVAR(),
+		"MVAR": buildMVAR(),
+		"avar": buildAvar(),
+		"cmap": buildCmap(cp),
+		"fvar": buildFvar(),
+		"glyf": buildGlyf(numGlyphs),
+		"gvar": buildGvar(numGlyphs),
+		"head": buildHead(),
+		"hhea": buildHhea(numGlyphs),
+		"hmtx": buildHmtx(numGlyphs),
+		"loca": buildLoca(numGlyphs),
+		"maxp": buildMaxp(numGlyphs),
+		"name": buildName("MinimalVar", "Regular",
+			nameEntry{nameWeight, "Weight"},
+			nameEntry{nameWidth, "Width"},
+			nameEntry{nameLight, "Light"},
+			nameEntry{nameRegular, "Regular"},
+			nameEntry{nameBold, "Bold"},
+			nameEntry{nameBoldPS, "MinimalVarBold"},
+			nameEntry{nameCondensedBold, "Condensed Bold"}),
+		"OS/2": buildOS2(400, cp),
+		"post": buildPost(),
+	}
+
+	return assembleTTF(tables)
+}
+
+func buildFvar() []byte {
+	var b bytes.Buffer
+	putU16(&b, 1)  // majorVersion
+	putU16(&b, 0)  // minorVersion
+	putU16(&b, 16) // axesArrayOffset
+	putU16(&b, 2)  // reserved
+	putU16(&b, 2)  // axisCount
+	putU16(&b, 20) // axisSize
+	putU16(&b, 4)  // instanceCount
+	putU16(&b, 14) // instanceSize, with postScriptNameID
+	for _, axis := range []struct {
+		tag           string
+		min, def, max float64
+		nameID        uint16
+	}{
+		{"wght", wghtMin, wghtDefault, wghtMax, nameWeight},
+		{"wdth", wdthMin, wdthDefault, wdthMax, nameWidth},
+	} {
+		b.WriteString(axis.tag)
+		putFixed(&b, axis.min)
+		putFixed(&b, axis.def)
+		putFixed(&b, axis.max)
+		putU16(&b, 0) // flags
+		putU16(&b, axis.nameID)
+	}
+	for _, instance := range []struct {
+		nameID, psNameID uint16
+		wght, wdth       float64
+	}{
+		{nameLight, 0xFFFF, 300, 100},
+		{nameRegular, 0xFFFF, 400, 100},
+		{nameBold, nameBoldPS, 700, 100},
+		{nameCondensedBold, 0xFFFF, 700, 75},
+	} {
+		putU16(&b, instance.nameID)
+		putU16(&b, 0) // flags
+		putFixed(&b, instance.wght)
+		putFixed(&b, instance.wdth)
+		putU16(&b, instance.psNameID)
+	}
+	return b.Bytes()
+}
+
+// buildAvar maps the normalized weight 0.5 (wght 650) to 0.6.
+func buildAvar() []byte {
+	var b bytes.Buffer
+	putU16(&b, 1) // majorVersion
+	putU16(&b, 0) // minorVersion
+	putU16(&b, 0) // reserved
+	putU16(&b, 2) // axisCount
+	for _, segments := range [][][2]float64{
+		{{-1, -1}, {0, 0}, {0.5, 0.6}, {1, 1}},
+		{{-1, -1}, {0, 0}, {1, 1}},
+	} {
+		putU16(&b, uint16(len(segments)))
+		for _, s := range segments {
+			putF2Dot14(&b, s[0])
+			putF2Dot14(&b, s[1])
+		}
+	}
+	return b.Bytes()
+}
+
+// Tuple variation flags of gvar.
+const (
+	tupleEmbeddedPeak  = 0x8000
+	tuplePrivatePoints = 0x2000
+)
+
+type tupleVariation struct {
+	index  uint16    // shared tuple index and flags
+	peak   []float64 // embedded peak, if any
+	points []byte    // packed point numbers
+	dx     []int16
+}
+
+// buildGvar builds the glyph variations. The simple glyphs move points 1
+// and 2 (the right edge) and the advance, leaving the untouched points of
+// the bold variation to be inferred; the composite glyph moves its second
+// component and the advance.
+func buildGvar(numGlyphs uint16) []byte {
+	allPoints := []byte{0}
+	simple := []tupleVariation{
+		// Points 0, 1 and 5 (the advance phantom point).
+		{0 | tuplePrivatePoints, nil, []byte{3, 2, 0, 1, 4}, []int16{0, boldDelta, boldDelta}},
+		{tupleEmbeddedPeak | tuplePrivatePoints, []float64{-1, 0}, allPoints, []int16{0, lightDelta, lightDelta, 0, 0, lightDelta, 0, 0}},
+		{1 | tuplePrivatePoints, nil, allPoints, []int16{0, condensedDelta, condensedDelta, 0, 0, condensedDelta, 0, 0}},
+	}
+	composite := []tupleVariation{
+		{0 | tuplePrivatePoints, nil, allPoints, []int16{0, boldDelta / 2, 0, boldDelta, 0, 0}},
+		{tupleEmbeddedPeak | tuplePrivatePoints, []float64{-1, 0}, allPoints, []int16{0, 0, 0, lightDelta, 0, 0}},
+		{1 | tuplePrivatePoints, nil, allPoints, []int16{0, 0, 0, condensedDelta, 0, 0}},
+	}
+	var data bytes.Buffer
+	offsets := make([]uint32, numGlyphs+1)
+	for i := uint16(0); i < numGlyphs; i++ {
+		offsets[i] = uint32(data.Len())
+		if i == numGlyphs-1 {
+			data.Write(glyphVariationData(composite))
+		} else {
+			data.Write(glyphVariationData(simple))
+		}
+	}
+	offsets[numGlyphs] = uint32(data.Len())
+
+	sharedTuples := [][]float64{{1, 0}, {0, -1}} // bold, condensed
+	const headerSize = 20
+	sharedTuplesOffset := headerSize + 4*len(offsets)
+	dataOffset := sharedTuplesOffset + 2*2*len(sharedTuples)
+	var b bytes.Buffer
+	putU16(&b, 1) // majorVersion
+	putU16(&b, 0) // minorVersion
+	putU16(&b, 2) // axisCount
+	putU16(&b, uint16(len(sharedTuples)))
+	putU32(&b, uint32(sharedTuplesOffset))
+	putU16(&b, numGlyphs)
+	putU16(&b, 1) // flags: long offsets
+	putU32(&b, uint32(dataOffset))
+	for _, off := range offsets {
+		putU32(&b, off)
+	}
+	for _, tuple := range sharedTuples {
+		for _, v := range tuple {
+			putF2Dot14(&b, v)
+		}
+	}
+	b.Write(data.Bytes())
+	return b.Bytes()
+}
+
+// glyphVariationData serializes the tuple variations of a glyph; all y
+// deltas are zero.
+func glyphVariationData(tuples []tupleVariation) []byte {
+	var headers, serialized bytes.Buffer
+	for _, t := range tuples {
+		var d bytes.Buffer
+		d.Write(t.points)
+		d.WriteByte(0x40 | byte(len(t.dx)-1)) // words
+		for _, v := range t.dx {
+			putI16(&d, v)
+		}
+		d.WriteByte(0x80 | byte(len(t.dx)-1)) // zeros
+		putU16(&headers, uint16(d.Len()))
+		putU16(&headers, t.index)
+		for _, v := range t.peak {
+			putF2Dot14(&headers, v)
+		}
+		serialized.Write(d.Bytes())
+	}
+	var b bytes.Buffer
+	putU16(&b, uint16(len(tuples)))
+	putU16(&b, uint16(4+headers.Len())) // dataOffset
+	b.Write(headers.Bytes())
+	b.Write(serialized.Bytes())
+	if b.Len()%2 != 0 {
+		b.WriteByte(0)
+	}
+	return b.Bytes()
+}
+
+// variationRegions are the bold, light and condensed regions of the HVAR
+// and MVAR item variation stores, as start, peak and end on each axis.
+var variationRegions = [][2][3]float64{
+	{{0, 1, 1}, {0, 0, 0}},
+	{{-1, -1, 0}, {0, 0, 0}},
+	{{0, 0, 0}, {-1, -1, 0}},
+}
+
+// buildItemVariationStore builds a store with one item variation data
+// subtable of word deltas for regions.
+func buildItemVariationStore(regions []int, items [][]int16) []byte {
+	var b bytes.Buffer
+	regionListSize := 4 + 6*2*len(variationRegions)
+	putU16(&b, 1)  // format
+	putU32(&b, 12) // variationRegionListOffset
+	putU16(&b, 1)  // itemVariationDataCount
+	putU32(&b, uint32(12+regionListSize))
+	putU16(&b, 2) // axisCount
+	putU16(&b, uint16(len(variationRegions)))
+	for _, region := range variationRegions {
+		for _, axis := range region {
+			for _, v := range axis {
+				putF2Dot14(&b, v)
+			}
+		}
+	}
+	putU16(&b, uint16(len(items)))
+	putU16(&b, uint16(len(regions))) // wordDeltaCount
+	putU16(&b, uint16(len(regions)))
+	for _, r := range regions {
+		putU16(&b, uint16(r))
+	}
+	for _, item := range items {
+		for _, v := range item {
+			putI16(&b, v)
+		}
+	}
+	return b.Bytes()
+}
+
+// buildHVAR gives every glyph the advance deltas of the squares, through
+// a one-entry delta-set index map.
+func buildHVAR() []byte {
+	store := buildItemVariationStore([]int{0, 1, 2}, [][]int16{{boldDelta, lightDelta, condensedDelta}})
+	var b bytes.Buffer
+	putU16(&b, 1)  // majorVersion
+	putU16(&b, 0)  // minorVersion
+	putU32(&b, 20) // itemVariationStoreOffset
+	putU32(&b, uint32(20+len(store)))
+	putU32(&b, 0) // lsbMappingOffset
+	putU32(&b, 0) // rsbMappingOffset
+	b.Write(store)
+	b.WriteByte(0) // format
+	b.WriteByte(0) // entryFormat: 1-byte entries, 1 inner index bit
+	putU16(&b, 1)  // mapCount
+	b.WriteByte(0) // outer 0, inner 0
+	return b.Bytes()
+}
+
+// buildMVAR raises the ascender by 50 and the cap height by 20 at wght 900.
+func buildMVAR() []byte {
+	store := buildItemVariationStore([]int{0}, [][]int16{{50}, {20}})
+	records := []struct {
+		tag          string
+		outer, inner uint16
+	}{
+		{"cpht", 0, 1},
+		{"hasc", 0, 0},
+	}
+	var b bytes.Buffer
+	putU16(&b, 1) // majorVersion
+	putU16(&b, 0) // minorVersion
+	putU16(&b, 0) // reserved
+	putU16(&b, 8) // valueRecordSize
+	putU16(&b, uint16(len(records)))
+	putU16(&b, uint16(12+8*len(records))) // itemVariationStoreOffset
+	for _, r := range records {
+		b.WriteString(r.tag)
+		putU16(&b, r.outer)
+		putU16(&b, r.inner)
+	}
+	b.Write(store)
+	return b.Bytes()
+}
+
 // ── TTF assembler ─────────────────────────────────────────────────────────────
 
 // tableOrder is the recommended table order for TTF files.
 var tableOrder = []string{"OS/2", "cmap", "glyf", "head", "hhea", "hmtx", "loca", "maxp", "name", "post"}
 
 func assembleTTF(tables map[string][]byte) []byte {
+	return assembleFont(0x00010000, tables) // TrueType
+}
+
+func assembleFont(sfVersion uint32, tables map[string][]byte) []byte {
 	// Sort tables by tag (required order for some readers; spec recommends specific order).
 	var tags []string
 	for t := range tables {
@@ -556,7 +1142,7 @@ func assembleTTF(tables map[string][]byte) []byte {
 	var b bytes.Buffer
 
 	// Offset table
-	putU32(&b, 0x00010000) // sfVersion: TrueType
+	putU32(&b, sfVersion)
 	putU16(&b, nTables)
 	putU16(&b, searchRange)
 	putU16(&b, entrySelector)
@@ -699,3 +1285,11 @@ func putU64(b *bytes.Buffer, v uint64) {
 
 // Silence unused import warning for rand (used indirectly via build tag ignore).
 var _ = rand.New
+
+func putFixed(b *bytes.Buffer, v float64) {
+	putU32(b, uint32(int32(math.Round(v*65536))))
+}
+
+func putF2Dot14(b *bytes.Buffer, v float64) {
+	putI16(b, int16(math.Round(v*16384)))
+}
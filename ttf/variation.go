@@ -0,0 +1,250 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ttf
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// instancesMu guards the instances of variable fonts, which may be shared
+// between goroutines through a font cache.
+var instancesMu sync.Mutex
+
+// Instance returns the instance of a variable font at coords, the
+// user-space coordinates of some of its axes keyed by tag, such as
+// {"wght": 650}. Other axes keep their coordinates in font, which are their
+// defaults unless font is itself an instance, and coordinates are clamped
+// to each axis's range. The instance has the metrics of that position in
+// the design space, and its subsets are static fonts with the glyph
+// variations applied. At the default position, the instance is the
+// variable font itself.
+func (font *Font) Instance(coords map[string]float64) (*Font, error) {
+	if !font.IsVariable() {
+		return nil, fmt.Errorf("%s is not a variable font", font.PostScriptName())
+	}
+	base := font
+	if font.base != nil {
+		base = font.base
+	}
+	user := make(map[string]float64, len(font.fvarTable.axes))
+	atDefault := true
+	for _, axis := range font.fvarTable.axes {
+		v, ok := coords[axis.Tag]
+		if !ok {
+			if v, ok = font.variation[axis.Tag]; !ok {
+				v = axis.Default
+			}
+		}
+		user[axis.Tag] = math.Max(axis.Min, math.Min(axis.Max, v))
+		atDefault = atDefault && user[axis.Tag] == axis.Default
+	}
+	if atDefault {
+		return base, nil
+	}
+	key := variationKey(base.fvarTable.axes, user)
+	instancesMu.Lock()
+	instance, ok := base.instances[key]
+	instancesMu.Unlock()
+	if ok {
+		return instance, nil
+	}
+	// Instantiate outside the lock; if another goroutine gets there first,
+	// its instance is kept, so that each position has only one.
+	instance, err := base.instantiate(user)
+	if err != nil {
+		return nil, err
+	}
+	instancesMu.Lock()
+	defer instancesMu.Unlock()
+	if existing, ok := base.instances[key]; ok {
+		return existing, nil
+	}
+	if base.instances == nil {
+		base.instances = make(map[string]*Font)
+	}
+	base.instances[key] = instance
+	return instance, nil
+}
+
+// NamedInstance returns the instance of a variable font that it names
+// name, such as "Bold", ignoring case.
+func (font *Font) NamedInstance(name string) (*Font, error) {
+	for _, instance := range font.fvarTable.instances {
+		if strings.EqualFold(instance.Name, name) {
+			return font.Instance(instance.Coords)
+		}
+	}
+	return nil, fmt.Errorf("%s has no instance named %s", font.Family(), name)
+}
+
+// Variation returns the user-space coordinates of each axis of an instance
+// of a variable font, or nil for a font that is not an instance.
+func (font *Font) Variation() map[string]float64 {
+	return font.variation
+}
+
+func variationKey(axes []VariationAxis, user map[string]float64) string {
+	var sb strings.Builder
+	for _, axis := range axes {
+		fmt.Fprintf(&sb, "%s=%g;", axis.Tag, user[axis.Tag])
+	}
+	return sb.String()
+}
+
+// normalize returns the normalized coordinates of user: -1 at each axis's
+// minimum, 0 at its default and 1 at its maximum, adjusted by the avar
+// table and rounded to 2.14 fixed-point numbers.
+func (font *Font) normalize(user map[string]float64) []float64 {
+	coords := make([]float64, len(font.fvarTable.axes))
+	for i, axis := range font.fvarTable.axes {
+		v := user[axis.Tag]
+		switch {
+		case v < axis.Default && axis.Default > axis.Min:
+			coords[i] = (v - axis.Default) / (axis.Default - axis.Min)
+		case v > axis.Default && axis.Max > axis.Default:
+			coords[i] = (v - axis.Default) / (axis.Max - axis.Default)
+		}
+		coords[i] = font.avarTable.mapCoord(i, coords[i])
+		coords[i] = math.Round(coords[i]*16384) / 16384
+	}
+	return coords
+}
+
+// instantiate returns a copy of font with the metrics of its instance at
+// user, named as the instance.
+func (font *Font) instantiate(user map[string]float64) (*Font, error) {
+	instance := *font
+	instance.base = font
+	instance.instances = nil
+	instance.variation = user
+	instance.coords = font.normalize(user)
+	coords := instance.coords
+
+	numGlyphs := int(font.maxpTable.numGlyphs)
+	hMetrics := make([]longHorMetric, numGlyphs)
+	if font.hvarTable.hasAdvances() {
+		for i := range hMetrics {
+			hMetrics[i] = font.hmtxTable.lookup(i)
+			advance := float64(hMetrics[i].advanceWidth) + font.hvarTable.advanceDelta(i, coords)
+			hMetrics[i].advanceWidth = uint16(max(0, otRound(advance)))
+		}
+	} else if font.tableDir.table("gvar") != nil {
+		raw, err := font.fileBytes()
+		if err != nil {
+			return nil, err
+		}
+		gi, err := font.newGlyphInstancer(raw, coords)
+		if err != nil {
+			return nil, err
+		}
+		for i := range hMetrics {
+			_, advance, lsb, err := gi.glyph(uint16(i))
+			if err != nil {
+				return nil, fmt.Errorf("instance: glyph %d: %w", i, err)
+			}
+			hMetrics[i] = longHorMetric{uint16(max(0, advance)), int16(lsb)}
+		}
+	} else {
+		for i := range hMetrics {
+			hMetrics[i] = font.hmtxTable.lookup(i)
+		}
+	}
+	instance.hmtxTable = hmtxTable{hMetrics: hMetrics}
+
+	mvar := func(tag string, v int16) int16 {
+		return int16(otRound(float64(v) + font.mvarTable.delta(tag, coords)))
+	}
+	instance.hheaTable.ascent = FWord(mvar("hasc", int16(font.hheaTable.ascent)))
+	instance.hheaTable.descent = FWord(mvar("hdsc", int16(font.hheaTable.descent)))
+	instance.hheaTable.lineGap = FWord(mvar("hlgp", int16(font.hheaTable.lineGap)))
+	instance.os2Table.sTypoAscender = mvar("hasc", font.os2Table.sTypoAscender)
+	instance.os2Table.sTypoDescender = mvar("hdsc", font.os2Table.sTypoDescender)
+	instance.os2Table.sTypoLineGap = mvar("hlgp", font.os2Table.sTypoLineGap)
+	instance.os2Table.sCapHeight = mvar("cpht", font.os2Table.sCapHeight)
+	instance.os2Table.sxHeight = mvar("xhgt", font.os2Table.sxHeight)
+	instance.os2Table.yStrikeoutSize = mvar("strs", font.os2Table.yStrikeoutSize)
+	instance.os2Table.yStrikeoutPosition = mvar("stro", font.os2Table.yStrikeoutPosition)
+	instance.postTable.underlinePosition = mvar("undo", font.postTable.underlinePosition)
+	instance.postTable.underlineThickness = mvar("unds", font.postTable.underlineThickness)
+	if wght, ok := user["wght"]; ok {
+		instance.os2Table.usWeightClass = uint16(math.Max(1, math.Min(1000, math.Round(wght))))
+	}
+
+	instance.nameTable.postScriptName, instance.nameTable.fontSubfamily = font.instanceNames(user)
+	instance.nameTable.fullName = font.Family() + " " + instance.nameTable.fontSubfamily
+	return &instance, nil
+}
+
+// instanceNames returns the PostScript and subfamily names of the instance
+// at user: those of the named instance there, if there is one, or else
+// names made from its coordinates, per Adobe Technical Note #5902.
+func (font *Font) instanceNames(user map[string]float64) (postScriptName, subfamily string) {
+	prefix := font.nameTable.getField(variationsPostScriptNamePrefixID)
+	if prefix == "" {
+		prefix = font.nameTable.preferredFamily
+	}
+	if prefix == "" {
+		prefix = font.nameTable.fontFamily
+	}
+	prefix = postScriptChars(prefix)
+	for _, instance := range font.fvarTable.instances {
+		if !sameCoords(instance.Coords, user) {
+			continue
+		}
+		if instance.PostScriptName != "" {
+			return instance.PostScriptName, instance.Name
+		}
+		return prefix + "-" + postScriptChars(instance.Name), instance.Name
+	}
+	var values []string
+	name := prefix
+	for _, axis := range font.fvarTable.axes {
+		if v := user[axis.Tag]; v != axis.Default {
+			value := strconv.FormatFloat(math.Round(v*100000)/100000, 'f', -1, 64)
+			name += "_" + value + strings.TrimRight(axis.Tag, " ")
+			values = append(values, strings.TrimRight(axis.Tag, " ")+" "+value)
+		}
+	}
+	if len(name) > 63 {
+		sum := sha1.Sum([]byte(name))
+		name = fmt.Sprintf("%s-%X...", prefix, sum[:10])
+	}
+	return name, strings.Join(values, " ")
+}
+
+func sameCoords(a, b map[string]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for tag, v := range a {
+		if w, ok := b[tag]; !ok || math.Abs(v-w) > 1e-4 {
+			return false
+		}
+	}
+	return true
+}
+
+// postScriptChars returns s with all but ASCII letters and digits removed.
+func postScriptChars(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x80 && (r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// fileBytes returns the contents of the file font was loaded from.
+func (font *Font) fileBytes() ([]byte, error) {
+	if font.rawBytes != nil {
+		return font.rawBytes, nil
+	}
+	return os.ReadFile(font.filename)
+}
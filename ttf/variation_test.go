@@ -0,0 +1,216 @@
+// Copyright 2026 Brent Rowland.
+// Use of this source code is governed the Apache License, Version 2.0, as described in the LICENSE file.
+
+package ttf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+	"testing"
+)
+
+const minimalVarTTF = "testdata/minimal-var.ttf"
+
+func TestVariableFont_Axes(t *testing.T) {
+	font, err := LoadFont(minimalVarTTF)
+	if err != nil {
+		t.Fatalf("LoadFont: %v", err)
+	}
+	expect(t, "IsVariable", font.IsVariable())
+	axes := font.Axes()
+	expectI(t, "len(axes)", 2, len(axes))
+	if len(axes) == 2 {
+		expectS(t, "axes[0].Tag", "wght", axes[0].Tag)
+		expectS(t, "axes[0].Name", "Weight", axes[0].Name)
+		expectF(t, "axes[0].Min", 100, axes[0].Min)
+		expectF(t, "axes[0].Default", 400, axes[0].Default)
+		expectF(t, "axes[0].Max", 900, axes[0].Max)
+		expectS(t, "axes[1].Tag", "wdth", axes[1].Tag)
+		expectF(t, "axes[1].Min", 75, axes[1].Min)
+	}
+	instances := font.NamedInstances()
+	expectI(t, "len(instances)", 4, len(instances))
+	if len(instances) == 4 {
+		expectS(t, "instances[2].Name", "Bold", instances[2].Name)
+		expectS(t, "instances[2].PostScriptName", "MinimalVarBold", instances[2].PostScriptName)
+		expectF(t, "instances[3].Coords[wdth]", 75, instances[3].Coords["wdth"])
+	}
+
+	static, err := LoadFont(minimalTTF)
+	if err != nil {
+		t.Fatalf("LoadFont: %v", err)
+	}
+	expect(t, "static IsVariable", !static.IsVariable())
+	if _, err := static.Instance(map[string]float64{"wght": 700}); err == nil {
+		t.Error("expected an error for the instance of a static font")
+	}
+}
+
+func TestVariableFont_Instance(t *testing.T) {
+	font, err := LoadFont(minimalVarTTF)
+	if err != nil {
+		t.Fatalf("LoadFont: %v", err)
+	}
+	cases := []struct {
+		coords         map[string]float64
+		postScriptName string
+		advance        int
+		ascent         int
+		capHeight      int
+	}{
+		{map[string]float64{"wght": 300}, "MinimalVar-Light", 479, 800, 800},
+		{map[string]float64{"wght": 650}, "MinimalVar_650wght", 632, 830, 812},
+		{map[string]float64{"wght": 700}, "MinimalVarBold", 648, 834, 814},
+		{map[string]float64{"wght": 2000}, "MinimalVar_900wght", 712, 850, 820},
+	}
+	for _, c := range cases {
+		instance, err := font.Instance(c.coords)
+		if err != nil {
+			t.Fatalf("Instance(%v): %v", c.coords, err)
+		}
+		expectS(t, "PostScriptName", c.postScriptName, instance.PostScriptName())
+		advance, _ := instance.AdvanceWidth('A')
+		expectI(t, c.postScriptName+" advance", c.advance, advance)
+		expectI(t, c.postScriptName+" Ascent", c.ascent, instance.Ascent())
+		expectI(t, c.postScriptName+" CapHeight", c.capHeight, instance.CapHeight())
+	}
+
+	instance, _ := font.Instance(map[string]float64{"wght": 650})
+	expectS(t, "FullName", "MinimalVar wght 650", instance.FullName())
+	expectI(t, "usWeightClass", 650, int(instance.os2Table.usWeightClass))
+	again, _ := font.Instance(map[string]float64{"wght": 650})
+	expect(t, "cached", again == instance)
+	def, _ := font.Instance(map[string]float64{"wght": 400})
+	expect(t, "default is the base font", def == font)
+	expect(t, "instance FontKey", instance.FontKey() != font.FontKey())
+	expectS(t, "FontKey", font.FontKey()+"#wght=650;wdth=100;", instance.FontKey())
+	advance, _ := font.AdvanceWidth('A')
+	expectI(t, "base advance", 512, advance)
+
+	condensed, err := font.NamedInstance("condensed bold")
+	if err != nil {
+		t.Fatalf("NamedInstance: %v", err)
+	}
+	expectS(t, "condensed PostScriptName", "MinimalVar-CondensedBold", condensed.PostScriptName())
+	advance, _ = condensed.AdvanceWidth('A')
+	expectI(t, "condensed advance", 498, advance)
+	// Axes not given keep the instance's coordinates.
+	wide, _ := condensed.Instance(map[string]float64{"wdth": 100})
+	expect(t, "wide is Bold", wide.PostScriptName() == "MinimalVarBold")
+	if _, err := font.NamedInstance("Bogus"); err == nil {
+		t.Error("expected an error for a missing named instance")
+	}
+}
+
+// TestVariableFont_InstanceConcurrent verifies that goroutines sharing a
+// font get the same instance; run with -race to check the instance cache.
+func TestVariableFont_InstanceConcurrent(t *testing.T) {
+	font, err := LoadFont(minimalVarTTF)
+	if err != nil {
+		t.Fatalf("LoadFont: %v", err)
+	}
+	instances := make([]*Font, 8)
+	var wg sync.WaitGroup
+	for i := range instances {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			instances[i], _ = font.Instance(map[string]float64{"wght": 650})
+		}(i)
+	}
+	wg.Wait()
+	for _, instance := range instances {
+		expect(t, "same instance", instance != nil && instance == instances[0])
+	}
+}
+
+// TestVariableFont_GvarAdvances verifies that, without HVAR, advances come
+// from the phantom points of gvar.
+func TestVariableFont_GvarAdvances(t *testing.T) {
+	data := loadRawFont(t, minimalVarTTF)
+	data = bytes.Replace(data, []byte("HVAR"), []byte("hvar"), 1)
+	font, err := LoadFontFromBytes(data)
+	if err != nil {
+		t.Fatalf("LoadFontFromBytes: %v", err)
+	}
+	expect(t, "no HVAR", !font.hvarTable.hasAdvances())
+	for wght, want := range map[float64]int{300: 479, 650: 632, 700: 648} {
+		instance, err := font.Instance(map[string]float64{"wght": wght})
+		if err != nil {
+			t.Fatalf("Instance: %v", err)
+		}
+		advance, _ := instance.AdvanceWidth('A')
+		expectI(t, instance.PostScriptName()+" advance", want, advance)
+	}
+}
+
+func TestVariableFont_Subset(t *testing.T) {
+	font, err := LoadFont(minimalVarTTF)
+	if err != nil {
+		t.Fatalf("LoadFont: %v", err)
+	}
+	bold, err := font.NamedInstance("Bold")
+	if err != nil {
+		t.Fatalf("NamedInstance: %v", err)
+	}
+	gA, gComposite := bold.GlyphIndex('A'), bold.GlyphIndex(0xE001)
+	data, err := bold.Subset([]uint16{gA, gComposite})
+	if err != nil {
+		t.Fatalf("Subset: %v", err)
+	}
+	for _, tag := range []string{"fvar", "gvar", "HVAR", "MVAR", "avar"} {
+		expect(t, "no "+tag, bytes.Index(data[:12+16*int(binary.BigEndian.Uint16(data[4:]))], []byte(tag)) < 0)
+	}
+	sub, err := LoadFontFromBytes(data)
+	if err != nil {
+		t.Fatalf("LoadFontFromBytes: %v", err)
+	}
+	expect(t, "subset is static", !sub.IsVariable())
+	expectI(t, "advance", 648, sub.AdvanceWidthForGlyph(gA))
+
+	glyph := subsetGlyph(t, data, gA)
+	g, err := parseSimpleGlyph(glyph)
+	if err != nil {
+		t.Fatalf("parseSimpleGlyph: %v", err)
+	}
+	expectI(t, "glyph xMax", 536, pointBounds(g.points)[2])
+	expectI(t, "stored xMax", 536, int(int16(binary.BigEndian.Uint16(glyph[6:]))))
+
+	composite := subsetGlyph(t, data, gComposite)
+	expectI(t, "composite xMax", 654, int(int16(binary.BigEndian.Uint16(composite[6:]))))
+}
+
+// subsetGlyph returns the glyf data of glyph id in the font data.
+func subsetGlyph(t *testing.T, data []byte, id uint16) []byte {
+	t.Helper()
+	glyf, loca, head := mustFindTable(t, data, "glyf"), mustFindTable(t, data, "loca"), mustFindTable(t, data, "head")
+	var off0, off1 uint32
+	if binary.BigEndian.Uint16(data[head.offset+50:]) == 1 {
+		off0 = binary.BigEndian.Uint32(data[loca.offset+uint32(id)*4:])
+		off1 = binary.BigEndian.Uint32(data[loca.offset+uint32(id+1)*4:])
+	} else {
+		off0 = uint32(binary.BigEndian.Uint16(data[loca.offset+uint32(id)*2:])) * 2
+		off1 = uint32(binary.BigEndian.Uint16(data[loca.offset+uint32(id+1)*2:])) * 2
+	}
+	return data[glyf.offset+off0 : glyf.offset+off1]
+}
+
+func TestTupleScalar(t *testing.T) {
+	peak := []float64{1, 0}
+	expectF(t, "at peak", 1, tupleScalar(peak, nil, nil, []float64{1, 0.5}))
+	expectF(t, "halfway", 0.5, tupleScalar(peak, nil, nil, []float64{0.5, 0}))
+	expectF(t, "opposite side", 0, tupleScalar(peak, nil, nil, []float64{-0.5, 0}))
+	expectF(t, "intermediate", 0.5,
+		tupleScalar([]float64{0.5}, []float64{0}, []float64{1}, []float64{0.75}))
+}
+
+func TestInterpolateUntouched(t *testing.T) {
+	points := []glyphPoint{{x: 0}, {x: 50}, {x: 100}, {x: 150}}
+	dx, dy := []float64{10, 0, 30, 0}, make([]float64, 4)
+	touched := []bool{true, false, true, false}
+	interpolateUntouched(points, []int{3}, dx, dy, touched)
+	expectF(t, "between", 20, dx[1])
+	// Outside the range of its references, a point takes the nearer delta.
+	expectF(t, "beyond", 30, dx[3])
+}
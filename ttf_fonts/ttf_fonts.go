@@ -6,6 +6,7 @@ package ttf_fonts
 import (
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -32,7 +33,7 @@ var loadSystemFontInfos = func() ([]*ttf.FontInfo, error) {
 	var fc TtfFonts
 	var err error
 	for _, dir := range SystemFontDirs() {
-		for _, ext := range []string{"*.ttf", "*.TTF", "*.ttc", "*.TTC"} {
+		for _, ext := range []string{"*.ttf", "*.TTF", "*.ttc", "*.TTC", "*.otf", "*.OTF"} {
 			// Ignore errors from individual patterns (directory may not exist).
 			if err2 := fc.Add(filepath.Join(dir, ext)); err2 != nil {
 				err = err2
@@ -100,8 +101,8 @@ func NewFromSystemFonts() (*TtfFonts, error) {
 	return &fc, nil
 }
 
-// AddSystemFonts adds all TTF and TTC fonts found in the platform's standard
-// font directories.
+// AddSystemFonts adds all TTF, TTC and OTF fonts found in the platform's
+// standard font directories.
 func (fc *TtfFonts) AddSystemFonts() error {
 	infos, err := cachedSystemFontInfos()
 	fc.FontInfos = append(fc.FontInfos, infos...)
@@ -173,19 +174,17 @@ func (fc *TtfFonts) Select(family, weight, style string, ranges []string) (fontM
 search:
 	for _, f := range fc.FontInfos {
 		if strings.EqualFold(f.Family(), family) && strings.EqualFold(f.Style(), ws) {
-			for _, r := range ranges {
-				cpr, ok := ttf.CodepointRangesByName[r]
-				if !ok || !f.CharRanges().IsSet(int(cpr.Bit)) {
-					continue search
-				}
+			if !hasRanges(f, ranges) {
+				continue search
 			}
-			cacheKey := fmt.Sprintf("%s@%d", f.Filename(), f.TTCOffset())
-			font := fc.fonts[cacheKey]
-			if font == nil {
-				font, err = ttf.LoadFontAtOffset(f.Filename(), f.TTCOffset())
-				fc.fonts[cacheKey] = font
-			}
-			fontMetrics = font
+			return fc.load(f)
+		}
+	}
+	for _, f := range fc.FontInfos {
+		if !f.IsVariable() || !strings.EqualFold(f.Family(), family) || !hasRanges(f, ranges) {
+			continue
+		}
+		if fontMetrics, err = fc.selectInstance(f, weight, style, ws); fontMetrics != nil || err != nil {
 			return
 		}
 	}
@@ -193,6 +192,126 @@ search:
 	return
 }
 
+func hasRanges(f *ttf.FontInfo, ranges []string) bool {
+	for _, r := range ranges {
+		cpr, ok := ttf.CodepointRangesByName[r]
+		if !ok || !f.CharRanges().IsSet(int(cpr.Bit)) {
+			return false
+		}
+	}
+	return true
+}
+
+// load returns the font described by f, loading it only once.
+func (fc *TtfFonts) load(f *ttf.FontInfo) (*ttf.Font, error) {
+	cacheKey := fmt.Sprintf("%s@%d", f.Filename(), f.TTCOffset())
+	font := fc.fonts[cacheKey]
+	if font == nil {
+		var err error
+		if font, err = ttf.LoadFontAtOffset(f.Filename(), f.TTCOffset()); err != nil {
+			return nil, err
+		}
+		fc.fonts[cacheKey] = font
+	}
+	return font, nil
+}
+
+// weightClasses maps weight names to values of the wght axis.
+var weightClasses = map[string]float64{
+	"thin":       100,
+	"extralight": 200,
+	"ultralight": 200,
+	"light":      300,
+	"regular":    400,
+	"normal":     400,
+	"medium":     500,
+	"semibold":   600,
+	"demibold":   600,
+	"bold":       700,
+	"extrabold":  800,
+	"ultrabold":  800,
+	"black":      900,
+	"heavy":      900,
+}
+
+// selectInstance returns the instance of the variable font f named ws, or
+// else the one at weight on its wght axis, where weight is a number or a
+// name such as "SemiBold". An italic style needs an ital axis or an italic
+// default instance. It returns nil metrics when f has no such instance.
+func (fc *TtfFonts) selectInstance(f *ttf.FontInfo, weight, style, ws string) (font.FontMetrics, error) {
+	for _, instance := range f.NamedInstances() {
+		if strings.EqualFold(instance.Name, ws) {
+			base, err := fc.load(f)
+			if err != nil {
+				return nil, err
+			}
+			return base.Instance(instance.Coords)
+		}
+	}
+	coords := make(map[string]float64)
+	hasAxis := func(tag string) bool {
+		for _, axis := range f.Axes() {
+			if axis.Tag == tag {
+				return true
+			}
+		}
+		return false
+	}
+	if weight != "" {
+		w, err := strconv.ParseFloat(weight, 64)
+		if err != nil {
+			var ok bool
+			if w, ok = weightClasses[strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(weight))]; !ok {
+				return nil, nil
+			}
+		}
+		if !hasAxis("wght") {
+			return nil, nil
+		}
+		coords["wght"] = w
+	}
+	italic := strings.EqualFold(style, "Italic") || strings.EqualFold(style, "Oblique")
+	switch {
+	case style != "" && !italic:
+		return nil, nil
+	case hasAxis("ital"):
+		if italic {
+			coords["ital"] = 1
+		} else {
+			coords["ital"] = 0
+		}
+	case italic != strings.Contains(strings.ToLower(f.Style()), "italic"):
+		return nil, nil
+	}
+	base, err := fc.load(f)
+	if err != nil {
+		return nil, err
+	}
+	return base.Instance(coords)
+}
+
+// Instance implements font.VariationSource, returning the instance of the
+// variable font of metrics named name, if any, moved to coords. Other fonts
+// are returned unchanged.
+func (fc *TtfFonts) Instance(metrics font.FontMetrics, name string, coords map[string]float64) (font.FontMetrics, error) {
+	f, ok := metrics.(*ttf.Font)
+	if !ok || !f.IsVariable() {
+		return metrics, nil
+	}
+	var err error
+	if name != "" {
+		if f, err = f.NamedInstance(name); err != nil {
+			return nil, err
+		}
+	}
+	if len(coords) > 0 {
+		if f, err = f.Instance(coords); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
 func (fc *TtfFonts) SubType() string {
 	return "TrueType"
 }
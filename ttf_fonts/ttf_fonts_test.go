@@ -73,6 +73,73 @@ func TestTtfFonts(t *testing.T) {
 	}
 }
 
+func TestTtfFonts_AddOTF(t *testing.T) {
+	fc, err := New("../ttf/testdata/*.otf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, actual := 2, fc.Len(); actual != expected {
+		t.Fatalf("expected %d fonts, got %d", expected, actual)
+	}
+	f, err := fc.Select("MinimalCFF", "", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.PostScriptName() != "MinimalCFF" {
+		t.Errorf("expected MinimalCFF, got %s", f.PostScriptName())
+	}
+	if co, ok := f.(interface{ HasCFFOutlines() bool }); !ok || !co.HasCFFOutlines() {
+		t.Error("expected CFF outlines")
+	}
+}
+
+func TestTtfFonts_SelectVariable(t *testing.T) {
+	fc, err := New("../ttf/testdata/minimal-var.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		weight, style, postScriptName string
+	}{
+		{"", "", "MinimalVar"},
+		{"Bold", "", "MinimalVarBold"},
+		{"SemiBold", "", "MinimalVar_600wght"},
+		{"650", "", "MinimalVar_650wght"},
+		{"Condensed Bold", "", "MinimalVar-CondensedBold"},
+	} {
+		f, err := fc.Select("MinimalVar", c.weight, c.style, nil)
+		if err != nil {
+			t.Errorf("%s: %v", c.weight, err)
+			continue
+		}
+		if f.PostScriptName() != c.postScriptName {
+			t.Errorf("%s: expected %s, got %s", c.weight, c.postScriptName, f.PostScriptName())
+		}
+	}
+	if _, err := fc.Select("MinimalVar", "Bold", "Italic", nil); err == nil {
+		t.Error("expected no italic instance")
+	}
+
+	f, err := fc.Select("MinimalVar", "Bold", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	condensed, err := fc.Instance(f, "", map[string]float64{"wdth": 75})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "MinimalVar-CondensedBold"; condensed.PostScriptName() != expected {
+		t.Errorf("expected %s, got %s", expected, condensed.PostScriptName())
+	}
+	light, err := fc.Instance(f, "Light", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "MinimalVar-Light"; light.PostScriptName() != expected {
+		t.Errorf("expected %s, got %s", expected, light.PostScriptName())
+	}
+}
+
 // 81,980,000 ns
 // 45,763,220 ns
 // 44,562,080 ns